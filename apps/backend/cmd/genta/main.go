@@ -52,6 +52,9 @@ func main() {
 	}
 	handlers := handler.NewHandlers(srv, services)
 
+	// Services subscribe to domain events while being created, so the relay starts after them
+	srv.Job.StartOutboxRelay()
+
 	// Initialize router
 	r := router.NewRouter(srv, handlers, services)
 
@@ -18,6 +18,8 @@ type Config struct {
 	Auth          AuthConfig           `koanf:"auth" validate:"required"`
 	Redis         RedisConfig          `koanf:"redis" validate:"required"`
 	Integration   IntegrationConfig    `koanf:"integration" validate:"required"`
+	LLM           LLMConfig            `koanf:"llm"`
+	Storage       StorageConfig        `koanf:"storage"`
 	Observability *ObservabilityConfig `koanf:"observability"`
 }
 
@@ -55,8 +57,60 @@ type IntegrationConfig struct {
 	OpenAIModel  string `koanf:"openai_model"`
 }
 
+// LLMConfig holds spend limits and resilience settings for LLM usage.
+// Zero budgets mean unlimited; zero resilience settings use the client defaults.
+type LLMConfig struct {
+	DailyBudgetUSD   float64 `koanf:"daily_budget_usd" validate:"min=0"`
+	MonthlyBudgetUSD float64 `koanf:"monthly_budget_usd" validate:"min=0"`
+
+	// Comma-separated models tried in order on the primary provider after OPENAI_MODEL
+	FallbackModels string `koanf:"fallback_models"`
+
+	// Optional secondary OpenAI-compatible provider, tried after the primary provider
+	FallbackBaseURL         string `koanf:"fallback_base_url"`
+	FallbackAPIKey          string `koanf:"fallback_api_key"`
+	FallbackModelsSecondary string `koanf:"fallback_models_secondary"`
+
+	// Feedback quality evaluation: rule checks always run, the LLM judge scores a sample
+	QualityThreshold       float64 `koanf:"quality_threshold" validate:"min=0,max=5"`
+	QualityJudgeSampleRate float64 `koanf:"quality_judge_sample_rate" validate:"min=0,max=1"`
+	QualityAutoRegenerate  bool    `koanf:"quality_auto_regenerate"`
+
+	MaxRetries              int `koanf:"max_retries" validate:"min=0"`
+	RequestTimeoutSeconds   int `koanf:"request_timeout_seconds" validate:"min=0"`
+	BreakerFailureThreshold int `koanf:"breaker_failure_threshold" validate:"min=0"`
+	BreakerCooldownSeconds  int `koanf:"breaker_cooldown_seconds" validate:"min=0"`
+}
+
+// StorageConfig selects where media assets, reports and uploads are stored. Empty values
+// use the filesystem backend under ./data/media.
+type StorageConfig struct {
+	Backend string   `koanf:"backend" validate:"omitempty,oneof=filesystem s3 memory"`
+	Path    string   `koanf:"path"`
+	S3      S3Config `koanf:"s3"`
+
+	// Base URL of this API; the filesystem and memory backends sign URLs to it
+	PublicURL string `koanf:"public_url"`
+	// Key for those signatures (at least 32 characters); required unless the backend is s3
+	SigningKey string `koanf:"signing_key" validate:"required_unless=Backend s3,omitempty,min=32"`
+	// Redirect media downloads to presigned storage URLs instead of streaming them
+	RedirectDownloads bool `koanf:"redirect_downloads"`
+}
+
+// S3Config points at a bucket of an S3-compatible store such as MinIO
+type S3Config struct {
+	Endpoint        string `koanf:"endpoint"`
+	Region          string `koanf:"region"`
+	Bucket          string `koanf:"bucket"`
+	AccessKeyID     string `koanf:"access_key_id"`
+	SecretAccessKey string `koanf:"secret_access_key"`
+	UseSSL          bool   `koanf:"use_ssl"`
+}
+
 type AuthConfig struct {
 	SecretKey string `koanf:"secret_key" validate:"required"`
+	// Signs list pagination cursors; kept apart from the Clerk secret so either can rotate alone
+	CursorSigningKey string `koanf:"cursor_signing_key" validate:"required,min=32"`
 }
 
 func LoadConfig() (*Config, error) {
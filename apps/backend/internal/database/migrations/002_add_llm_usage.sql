@@ -0,0 +1,75 @@
+-- Write your migrate up statements here
+
+-- ============================================
+-- 1. LLM_MODEL_PRICES TABLE
+-- ============================================
+-- Harga per 1 juta token (USD), dipakai untuk menghitung biaya setiap generasi
+CREATE TABLE llm_model_prices (
+    model VARCHAR(50) PRIMARY KEY,
+    input_price_per_million DECIMAL(10, 4) NOT NULL,
+    output_price_per_million DECIMAL(10, 4) NOT NULL,
+    is_active BOOLEAN DEFAULT true,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO llm_model_prices (model, input_price_per_million, output_price_per_million)
+VALUES
+    ('gpt-4o-mini', 0.15, 0.60),
+    ('gpt-4o', 2.50, 10.00),
+    ('gpt-4.1-mini', 0.40, 1.60),
+    ('gpt-4.1-nano', 0.10, 0.40),
+    ('gpt-3.5-turbo', 0.50, 1.50)
+ON CONFLICT DO NOTHING;
+
+-- ============================================
+-- 2. LLM_USAGE_DAILY TABLE (usage ledger)
+-- ============================================
+-- Satu baris per user, model dan hari
+CREATE TABLE llm_usage_daily (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    model VARCHAR(50) NOT NULL,
+    usage_date DATE NOT NULL,
+
+    request_count INTEGER DEFAULT 0,
+    tokens_input BIGINT DEFAULT 0,
+    tokens_output BIGINT DEFAULT 0,
+    cost_usd DECIMAL(12, 6) DEFAULT 0,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, model, usage_date)
+);
+
+CREATE INDEX idx_llm_usage_daily_usage_date ON llm_usage_daily(usage_date DESC);
+CREATE INDEX idx_llm_usage_daily_model ON llm_usage_daily(model);
+
+-- ============================================
+-- 3. ATTEMPT_FEEDBACK: token counts & cost
+-- ============================================
+-- SMALLINT overflows for long prompts
+ALTER TABLE attempt_feedback ALTER COLUMN token_count_input TYPE INTEGER;
+ALTER TABLE attempt_feedback ALTER COLUMN token_count_output TYPE INTEGER;
+ALTER TABLE attempt_feedback ADD COLUMN cost_usd DECIMAL(10, 6);
+
+CREATE TRIGGER trigger_llm_model_prices_updated_at
+BEFORE UPDATE ON llm_model_prices
+FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+CREATE TRIGGER trigger_llm_usage_daily_updated_at
+BEFORE UPDATE ON llm_usage_daily
+FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS trigger_llm_usage_daily_updated_at ON llm_usage_daily;
+DROP TRIGGER IF EXISTS trigger_llm_model_prices_updated_at ON llm_model_prices;
+
+ALTER TABLE attempt_feedback DROP COLUMN IF EXISTS cost_usd;
+ALTER TABLE attempt_feedback ALTER COLUMN token_count_output TYPE SMALLINT;
+ALTER TABLE attempt_feedback ALTER COLUMN token_count_input TYPE SMALLINT;
+
+DROP TABLE IF EXISTS llm_usage_daily;
+DROP TABLE IF EXISTS llm_model_prices;
@@ -0,0 +1,74 @@
+-- Write your migrate up statements here
+
+-- ============================================
+-- 1. FEEDBACK_CACHE TABLE
+-- ============================================
+-- Feedback kanonik per (soal, jawaban, bahasa, versi prompt).
+-- Dipakai ulang untuk semua siswa yang memilih jawaban yang sama.
+CREATE TABLE feedback_cache (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    selected_answer CHAR(1) NOT NULL CHECK (selected_answer IN ('A', 'B', 'C', 'D', 'E')),
+    feedback_lang VARCHAR(10) NOT NULL DEFAULT 'id',
+    prompt_version VARCHAR(20) NOT NULL,
+
+    feedback_text TEXT NOT NULL,
+    model_used VARCHAR(50) NOT NULL,
+
+    hit_count INTEGER DEFAULT 0,
+    last_hit_at TIMESTAMP,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE (question_id, selected_answer, feedback_lang, prompt_version)
+);
+
+CREATE INDEX idx_feedback_cache_question_id ON feedback_cache(question_id);
+
+-- ============================================
+-- 2. ATTEMPT_FEEDBACK: cache source
+-- ============================================
+ALTER TABLE attempt_feedback
+    ADD COLUMN feedback_cache_id UUID REFERENCES feedback_cache(id) ON DELETE SET NULL;
+
+-- ============================================
+-- 3. CACHE INVALIDATION
+-- ============================================
+-- Hapus cache saat isi soal, pilihan, kunci atau pembahasan berubah
+CREATE OR REPLACE FUNCTION invalidate_feedback_cache()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.text IS DISTINCT FROM OLD.text
+        OR NEW.option_a IS DISTINCT FROM OLD.option_a
+        OR NEW.option_b IS DISTINCT FROM OLD.option_b
+        OR NEW.option_c IS DISTINCT FROM OLD.option_c
+        OR NEW.option_d IS DISTINCT FROM OLD.option_d
+        OR NEW.option_e IS DISTINCT FROM OLD.option_e
+        OR NEW.correct_answer IS DISTINCT FROM OLD.correct_answer
+        OR NEW.explanation IS DISTINCT FROM OLD.explanation
+        OR NEW.explanation_en IS DISTINCT FROM OLD.explanation_en
+    THEN
+        DELETE FROM feedback_cache WHERE question_id = NEW.id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_questions_invalidate_feedback_cache
+AFTER UPDATE ON questions
+FOR EACH ROW EXECUTE FUNCTION invalidate_feedback_cache();
+
+CREATE TRIGGER trigger_feedback_cache_updated_at
+BEFORE UPDATE ON feedback_cache
+FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS trigger_feedback_cache_updated_at ON feedback_cache;
+DROP TRIGGER IF EXISTS trigger_questions_invalidate_feedback_cache ON questions;
+DROP FUNCTION IF EXISTS invalidate_feedback_cache();
+
+ALTER TABLE attempt_feedback DROP COLUMN IF EXISTS feedback_cache_id;
+
+DROP TABLE IF EXISTS feedback_cache;
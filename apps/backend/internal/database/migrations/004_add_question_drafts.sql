@@ -0,0 +1,46 @@
+-- Write your migrate up statements here
+
+-- ============================================
+-- 1. QUESTION_DRAFTS TABLE
+-- ============================================
+-- Konten soal hasil LLM yang menunggu review editor.
+-- Draft tidak pernah disajikan ke siswa; approve menerapkan draft ke tabel questions.
+CREATE TABLE question_drafts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('enrichment', 'variant')),
+    source_question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected')),
+
+    -- Format: {"explanation_en": "...", "strategy_tip": "...", "solution_steps": [...]}
+    -- atau soal lengkap untuk variant: {"text": "...", "options": [...], "correct_answer": "A", ...}
+    content JSONB NOT NULL,
+    target_difficulty DECIMAL(4, 2),
+
+    model_used VARCHAR(50),
+    prompt_version VARCHAR(20),
+    tokens_input INTEGER,
+    tokens_output INTEGER,
+    cost_usd DECIMAL(10, 6),
+
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    review_notes TEXT,
+    applied_question_id UUID REFERENCES questions(id) ON DELETE SET NULL,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_question_drafts_status ON question_drafts(status, created_at DESC);
+CREATE INDEX idx_question_drafts_source_question_id ON question_drafts(source_question_id);
+
+CREATE TRIGGER trigger_question_drafts_updated_at
+BEFORE UPDATE ON question_drafts
+FOR EACH ROW EXECUTE FUNCTION update_updated_at();
+
+---- create above / drop below ----
+
+DROP TRIGGER IF EXISTS trigger_question_drafts_updated_at ON question_drafts;
+DROP TABLE IF EXISTS question_drafts;
@@ -0,0 +1,28 @@
+-- Write your migrate up statements here
+
+-- ============================================
+-- 1. ATTEMPT_FEEDBACK: quality evaluation
+-- ============================================
+-- feedback_quality_rating (1.0 - 5.0) diisi oleh job evaluasi.
+-- quality_status: NULL = belum dievaluasi, ok, flagged (perlu regenerasi/review), reviewed (sudah dicek manusia)
+ALTER TABLE attempt_feedback
+    ADD COLUMN quality_status VARCHAR(20)
+        CHECK (quality_status IN ('ok', 'flagged', 'reviewed')),
+    ADD COLUMN quality_flags TEXT[],
+    ADD COLUMN judge_model VARCHAR(50),
+    ADD COLUMN evaluated_at TIMESTAMP,
+    ADD COLUMN regeneration_count SMALLINT DEFAULT 0;
+
+CREATE INDEX idx_feedback_quality_flagged ON attempt_feedback(created_at DESC)
+    WHERE quality_status = 'flagged';
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_feedback_quality_flagged;
+
+ALTER TABLE attempt_feedback
+    DROP COLUMN IF EXISTS regeneration_count,
+    DROP COLUMN IF EXISTS evaluated_at,
+    DROP COLUMN IF EXISTS judge_model,
+    DROP COLUMN IF EXISTS quality_flags,
+    DROP COLUMN IF EXISTS quality_status;
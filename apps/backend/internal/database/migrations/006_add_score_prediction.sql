@@ -0,0 +1,70 @@
+-- Write your migrate up statements here
+
+-- ============================================
+-- 1. TRYOUT_RESULTS TABLE
+-- ============================================
+-- Skor resmi tryout per section, dipasangkan dengan theta siswa saat tryout.
+-- Dipakai untuk kalibrasi theta -> skor UTBK.
+CREATE TABLE tryout_results (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    section VARCHAR(10) NOT NULL CHECK (section IN ('PU', 'PPU', 'PBM', 'PK', 'LBI', 'LBE', 'PM')),
+    tryout_name VARCHAR(100) NOT NULL,
+    scaled_score DECIMAL(6, 2) NOT NULL,
+    taken_at TIMESTAMP NOT NULL,
+
+    -- Estimasi theta dari attempt sebelum taken_at
+    theta_at_tryout DECIMAL(5, 3) NOT NULL,
+    theta_se DECIMAL(5, 3) NOT NULL,
+    attempts_count INTEGER NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE (user_id, section, tryout_name)
+);
+
+CREATE INDEX idx_tryout_results_section ON tryout_results(section);
+
+-- ============================================
+-- 2. SCORE_CALIBRATIONS TABLE
+-- ============================================
+-- Mapping theta -> skor UTBK per section, berversi.
+-- Hanya satu versi yang aktif.
+CREATE TABLE score_calibrations (
+    version VARCHAR(30) NOT NULL,
+    section VARCHAR(10) NOT NULL CHECK (section IN ('PU', 'PPU', 'PBM', 'PK', 'LBI', 'LBE', 'PM')),
+    slope DECIMAL(8, 3) NOT NULL,
+    intercept DECIMAL(8, 3) NOT NULL,
+    residual_sd DECIMAL(8, 3) NOT NULL,
+    sample_size INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (version, section)
+);
+
+CREATE INDEX idx_score_calibrations_active ON score_calibrations(section) WHERE is_active;
+
+-- Kalibrasi default sampai ada data tryout
+INSERT INTO score_calibrations (version, section, slope, intercept, residual_sd, is_active)
+SELECT 'default-v1', s, 100, 500, 40, TRUE
+FROM unnest(ARRAY['PU', 'PPU', 'PBM', 'PK', 'LBI', 'LBE', 'PM']) AS s;
+
+-- ============================================
+-- 3. USER_READINESS: prediction
+-- ============================================
+ALTER TABLE user_readiness
+    ADD COLUMN theta_se DECIMAL(5, 3),
+    ADD COLUMN predicted_score INTEGER,
+    ADD COLUMN prediction_version VARCHAR(30);
+
+---- create above / drop below ----
+
+ALTER TABLE user_readiness
+    DROP COLUMN IF EXISTS prediction_version,
+    DROP COLUMN IF EXISTS predicted_score,
+    DROP COLUMN IF EXISTS theta_se;
+
+DROP TABLE IF EXISTS score_calibrations;
+DROP TABLE IF EXISTS tryout_results;
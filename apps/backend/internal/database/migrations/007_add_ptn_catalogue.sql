@@ -0,0 +1,70 @@
+-- Write your migrate up statements here
+
+-- ============================================
+-- 1. PTN TABLE
+-- ============================================
+-- Katalog perguruan tinggi negeri, diimpor dari CSV
+CREATE TABLE ptn (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(20) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    short_name VARCHAR(50),
+    city VARCHAR(100),
+    province VARCHAR(100),
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ptn_name_trgm ON ptn USING gin (name gin_trgm_ops);
+
+-- ============================================
+-- 2. STUDY_PROGRAMS TABLE
+-- ============================================
+CREATE TABLE study_programs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ptn_id UUID NOT NULL REFERENCES ptn(id) ON DELETE CASCADE,
+    code VARCHAR(20) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    -- saintek / soshum
+    cluster VARCHAR(20) CHECK (cluster IN ('saintek', 'soshum')),
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_study_programs_ptn_id ON study_programs(ptn_id);
+
+-- ============================================
+-- 3. PROGRAM_PASSING_SCORES TABLE
+-- ============================================
+-- Rentang skor komposit UTBK yang lolos per tahun (data historis/estimasi)
+CREATE TABLE program_passing_scores (
+    program_id UUID NOT NULL REFERENCES study_programs(id) ON DELETE CASCADE,
+    year SMALLINT NOT NULL,
+    score_low DECIMAL(6, 2) NOT NULL,
+    score_high DECIMAL(6, 2) NOT NULL CHECK (score_high >= score_low),
+    capacity INTEGER,
+    applicants INTEGER,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (program_id, year)
+);
+
+-- ============================================
+-- 4. USERS: structured target
+-- ============================================
+ALTER TABLE users
+    ADD COLUMN target_ptn_id UUID REFERENCES ptn(id) ON DELETE SET NULL,
+    ADD COLUMN target_program_id UUID REFERENCES study_programs(id) ON DELETE SET NULL;
+
+---- create above / drop below ----
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS target_program_id,
+    DROP COLUMN IF EXISTS target_ptn_id;
+
+DROP TABLE IF EXISTS program_passing_scores;
+DROP TABLE IF EXISTS study_programs;
+DROP TABLE IF EXISTS ptn;
@@ -0,0 +1,51 @@
+-- Write your migrate up statements here
+
+-- ============================================
+-- 1. STUDY_PLANS TABLE
+-- ============================================
+-- Rencana belajar harian per user; satu rencana aktif, rencana lama disimpan sebagai riwayat
+CREATE TABLE study_plans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- active / superseded
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'superseded')),
+    -- initial / manual / behind / expired / profile_changed
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('initial', 'manual', 'behind', 'expired', 'profile_changed')),
+
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL CHECK (end_date >= start_date),
+    -- Snapshot profil saat rencana dibuat, untuk mendeteksi perubahan
+    exam_date DATE,
+    minutes_per_day INTEGER NOT NULL,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_study_plans_active_user ON study_plans(user_id) WHERE status = 'active';
+
+-- ============================================
+-- 2. STUDY_PLAN_ITEMS TABLE
+-- ============================================
+-- Blok latihan/review per hari; progres dihitung dari attempts pada tanggal yang sama
+CREATE TABLE study_plan_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    plan_id UUID NOT NULL REFERENCES study_plans(id) ON DELETE CASCADE,
+    scheduled_date DATE NOT NULL,
+    position SMALLINT NOT NULL,
+    -- practice / review
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('practice', 'review')),
+    section VARCHAR(10) NOT NULL CHECK (section IN ('PU', 'PPU', 'PBM', 'PK', 'LBI', 'LBE', 'PM')),
+    sub_type VARCHAR(50),
+    target_questions INTEGER NOT NULL CHECK (target_questions > 0),
+    minutes INTEGER NOT NULL CHECK (minutes > 0),
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE (plan_id, scheduled_date, position)
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS study_plan_items;
+DROP TABLE IF EXISTS study_plans;
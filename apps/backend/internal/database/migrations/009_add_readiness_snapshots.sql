@@ -0,0 +1,61 @@
+-- Write your migrate up statements here
+
+-- ============================================
+-- 1. READINESS_SNAPSHOTS TABLE
+-- ============================================
+-- Riwayat mentah: satu baris setiap kali readiness section dihitung ulang
+CREATE TABLE readiness_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    section VARCHAR(10) NOT NULL CHECK (section IN ('PU', 'PPU', 'PBM', 'PK', 'LBI', 'LBE', 'PM')),
+
+    theta DECIMAL(5, 3),
+    theta_se DECIMAL(5, 3),
+    readiness_percentage DECIMAL(6, 3),
+    predicted_score INTEGER,
+    total_attempts INTEGER NOT NULL DEFAULT 0,
+
+    recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_readiness_snapshots_user_section ON readiness_snapshots(user_id, section, recorded_at);
+CREATE INDEX idx_readiness_snapshots_recorded_at ON readiness_snapshots(recorded_at);
+
+-- ============================================
+-- 2. READINESS_DAILY TABLE
+-- ============================================
+-- Rollup harian (nilai penutup per hari), diisi job malam; snapshot mentah lama dihapus
+CREATE TABLE readiness_daily (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    section VARCHAR(10) NOT NULL CHECK (section IN ('PU', 'PPU', 'PBM', 'PK', 'LBI', 'LBE', 'PM')),
+    day DATE NOT NULL,
+
+    theta DECIMAL(5, 3),
+    theta_se DECIMAL(5, 3),
+    readiness_percentage DECIMAL(6, 3),
+    predicted_score INTEGER,
+    total_attempts INTEGER NOT NULL DEFAULT 0,
+    snapshot_count INTEGER NOT NULL DEFAULT 0,
+
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, section, day)
+);
+
+-- DECIMAL(5, 3) tidak bisa menampung 100.000 (readiness penuh)
+ALTER TABLE user_readiness
+    ALTER COLUMN readiness_percentage TYPE DECIMAL(6, 3);
+
+-- Titik awal riwayat dari readiness yang sudah ada
+INSERT INTO readiness_snapshots (user_id, section, theta, theta_se, readiness_percentage, predicted_score, total_attempts, recorded_at)
+SELECT user_id, section, current_theta, theta_se, readiness_percentage, predicted_score, COALESCE(total_attempts_count, 0), COALESCE(last_updated, CURRENT_TIMESTAMP)
+FROM user_readiness
+WHERE current_theta IS NOT NULL;
+
+---- create above / drop below ----
+
+ALTER TABLE user_readiness
+    ALTER COLUMN readiness_percentage TYPE DECIMAL(5, 3) USING LEAST(readiness_percentage, 99.999);
+
+DROP TABLE IF EXISTS readiness_daily;
+DROP TABLE IF EXISTS readiness_snapshots;
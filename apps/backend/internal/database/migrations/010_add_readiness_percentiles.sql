@@ -0,0 +1,26 @@
+-- Write your migrate up statements here
+
+-- ============================================
+-- 1. READINESS_PERCENTILES TABLE
+-- ============================================
+-- Peringkat persentil per kohort, dimaterialisasi job malam agar query user_readiness tetap ringan.
+-- Hanya kohort dengan anggota minimal (privasi) yang disimpan; tidak ada data pengguna lain yang diekspos.
+CREATE TABLE readiness_percentiles (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- all: semua pengguna, target_ptn: PTN tujuan yang sama, exam_month: bulan ujian yang sama
+    cohort VARCHAR(20) NOT NULL CHECK (cohort IN ('all', 'target_ptn', 'exam_month')),
+    cohort_key VARCHAR(100) NOT NULL,
+    -- OVERALL: rata-rata theta semua section
+    section VARCHAR(10) NOT NULL CHECK (section IN ('PU', 'PPU', 'PBM', 'PK', 'LBI', 'LBE', 'PM', 'OVERALL')),
+
+    percentile DECIMAL(5, 2) NOT NULL,
+    cohort_size INTEGER NOT NULL,
+
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, cohort, section)
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS readiness_percentiles;
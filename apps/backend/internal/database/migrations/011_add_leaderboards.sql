@@ -0,0 +1,43 @@
+-- Write your migrate up statements here
+
+-- ============================================
+-- 1. LEADERBOARD_ENTRIES TABLE
+-- ============================================
+-- Sumber kebenaran papan peringkat; Redis sorted set dibangun ulang dari tabel ini.
+-- season: 'all_time' atau minggu ISO (WIB), mis. '2026-W42'
+CREATE TABLE leaderboard_entries (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    section VARCHAR(10) NOT NULL CHECK (section IN ('PU', 'PPU', 'PBM', 'PK', 'LBI', 'LBE', 'PM')),
+    season VARCHAR(10) NOT NULL,
+
+    -- Theta di awal musim (penutup sebelum musim dimulai) dan theta terkini
+    start_theta DECIMAL(5, 3) NOT NULL DEFAULT 0,
+    theta DECIMAL(5, 3) NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    score DECIMAL(10, 2) NOT NULL DEFAULT 0,
+
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, section, season)
+);
+
+CREATE INDEX idx_leaderboard_entries_season ON leaderboard_entries(section, season, score DESC);
+
+-- ============================================
+-- 2. LEADERBOARD_SETTINGS TABLE
+-- ============================================
+-- Privasi: pengguna bisa keluar dari papan peringkat atau memakai nama samaran
+CREATE TABLE leaderboard_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    opt_out BOOLEAN NOT NULL DEFAULT FALSE,
+    pseudonym VARCHAR(30),
+
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_leaderboard_settings_pseudonym ON leaderboard_settings(LOWER(pseudonym)) WHERE pseudonym IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS leaderboard_settings;
+DROP TABLE IF EXISTS leaderboard_entries;
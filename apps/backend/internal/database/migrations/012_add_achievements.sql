@@ -0,0 +1,26 @@
+-- Write your migrate up statements here
+
+-- ============================================
+-- 1. USER_ACHIEVEMENTS TABLE
+-- ============================================
+-- Katalog aturan ada di kode; tabel ini hanya mencatat lencana yang sudah terbuka.
+-- Primary key (user_id, code) membuat pemberian lencana idempoten.
+CREATE TABLE user_achievements (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code VARCHAR(50) NOT NULL,
+
+    -- Nilai metrik saat lencana terbuka (mis. jumlah soal, panjang streak)
+    value DECIMAL(10, 3) NOT NULL DEFAULT 0,
+
+    unlocked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- NULL = notifikasi belum dilihat pengguna
+    seen_at TIMESTAMP,
+
+    PRIMARY KEY (user_id, code)
+);
+
+CREATE INDEX idx_user_achievements_unseen ON user_achievements(user_id) WHERE seen_at IS NULL;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS user_achievements;
@@ -0,0 +1,43 @@
+-- Write your migrate up statements here
+
+-- ============================================
+-- 1. OUTBOX_EVENTS TABLE
+-- ============================================
+-- Transactional outbox: event domain ditulis dalam transaksi yang sama dengan perubahan state,
+-- lalu relay mempublikasikannya ke subscriber Asynq. Jika Redis mati, event tetap tersimpan
+-- dan dipublikasikan ulang dengan backoff.
+CREATE TABLE outbox_events (
+    id UUID PRIMARY KEY,
+    seq BIGSERIAL NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    aggregate_id VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    published_at TIMESTAMP,
+    publish_attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_events_pending ON outbox_events(next_attempt_at, seq) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_events_published ON outbox_events(published_at) WHERE published_at IS NOT NULL;
+
+-- ============================================
+-- 2. PROCESSED_EVENTS TABLE
+-- ============================================
+-- Inbox subscriber: mencegah efek ganda saat task yang sama dijalankan ulang
+CREATE TABLE processed_events (
+    event_id UUID NOT NULL,
+    subscriber VARCHAR(50) NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (event_id, subscriber)
+);
+
+CREATE INDEX idx_processed_events_processed_at ON processed_events(processed_at);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS processed_events;
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,13 @@
+-- Write your migrate up statements here
+
+-- Sesi terbuka dipindai oleh job penutupan sesi idle
+CREATE INDEX idx_sessions_open ON user_study_sessions(started_at) WHERE ended_at IS NULL;
+
+-- Nomor urut attempt unik per sesi
+CREATE UNIQUE INDEX idx_attempts_session_number ON attempts(session_id, attempt_number_in_session)
+    WHERE session_id IS NOT NULL AND attempt_number_in_session IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_attempts_session_number;
+DROP INDEX IF EXISTS idx_sessions_open;
@@ -0,0 +1,8 @@
+-- Write your migrate up statements here
+
+-- Riwayat attempt dipaginasi dengan cursor (created_at, id) per user
+CREATE INDEX idx_attempts_user_created_id ON attempts(user_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_attempts_user_created_id;
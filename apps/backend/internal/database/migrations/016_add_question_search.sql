@@ -0,0 +1,40 @@
+-- Write your migrate up statements here
+
+CREATE EXTENSION IF NOT EXISTS "unaccent";
+
+-- unaccent() hanya STABLE, jadi dibungkus agar bisa dipakai di index dan generated column
+CREATE OR REPLACE FUNCTION immutable_unaccent(input TEXT)
+RETURNS TEXT AS $$
+    SELECT public.unaccent('public.unaccent'::regdictionary, input)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+-- Konfigurasi full-text bahasa Indonesia: stemming Snowball + normalisasi aksen
+CREATE TEXT SEARCH CONFIGURATION genta_id (COPY = pg_catalog.indonesian);
+ALTER TEXT SEARCH CONFIGURATION genta_id
+    ALTER MAPPING FOR hword, hword_part, word WITH unaccent, indonesian_stem;
+
+-- Bobot: teks soal (A), opsi jawaban dan konsep (B), pembahasan (C)
+ALTER TABLE questions ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('genta_id'::regconfig, COALESCE(text, '')), 'A') ||
+    setweight(to_tsvector('genta_id'::regconfig,
+        COALESCE(option_a, '') || ' ' || COALESCE(option_b, '') || ' ' || COALESCE(option_c, '') || ' ' ||
+        COALESCE(option_d, '') || ' ' || COALESCE(option_e, '') || ' ' || COALESCE(related_concept, '')), 'B') ||
+    setweight(to_tsvector('genta_id'::regconfig,
+        COALESCE(explanation, '') || ' ' || COALESCE(explanation_en, '')), 'C')
+) STORED;
+
+CREATE INDEX idx_questions_search_vector ON questions USING GIN (search_vector);
+
+-- Trigram untuk pencarian fuzzy (salah ketik, potongan kalimat yang diingat)
+CREATE INDEX idx_questions_text_trgm ON questions USING GIN (immutable_unaccent(lower(text)) gin_trgm_ops);
+CREATE INDEX idx_questions_related_concept_trgm ON questions USING GIN (immutable_unaccent(lower(related_concept)) gin_trgm_ops);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_questions_related_concept_trgm;
+DROP INDEX IF EXISTS idx_questions_text_trgm;
+DROP INDEX IF EXISTS idx_questions_search_vector;
+ALTER TABLE questions DROP COLUMN IF EXISTS search_vector;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS genta_id;
+DROP FUNCTION IF EXISTS immutable_unaccent(TEXT);
+DROP EXTENSION IF EXISTS "unaccent";
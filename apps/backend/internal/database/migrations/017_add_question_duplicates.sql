@@ -0,0 +1,45 @@
+-- Write your migrate up statements here
+
+-- Sidik jari soal untuk deteksi duplikat: teks + opsi, huruf kecil, tanpa aksen, spasi dirapikan
+ALTER TABLE questions ADD COLUMN dedup_text TEXT GENERATED ALWAYS AS (
+    immutable_unaccent(regexp_replace(lower(
+        text || ' ' || option_a || ' ' || option_b || ' ' || option_c || ' ' || option_d || ' ' || option_e
+    ), '\s+', ' ', 'g'))
+) STORED;
+
+CREATE INDEX idx_questions_dedup_text_trgm ON questions USING GIN (dedup_text gin_trgm_ops);
+
+-- Klaster soal yang kemungkinan duplikat, hasil pemindaian untuk ditinjau editor
+CREATE TABLE question_duplicate_clusters (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    section VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'merged', 'deactivated', 'dismissed')),
+    max_similarity DECIMAL(4,3) NOT NULL,
+    -- Saran soal yang dipertahankan: paling banyak dikerjakan, lalu paling lama
+    suggested_keep_id UUID NOT NULL REFERENCES questions(id),
+    kept_question_id UUID REFERENCES questions(id),
+    resolved_by UUID REFERENCES users(id),
+    resolved_at TIMESTAMP,
+    resolution_notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_question_duplicate_clusters_status ON question_duplicate_clusters(status, created_at, id);
+
+CREATE TABLE question_duplicate_members (
+    cluster_id UUID NOT NULL REFERENCES question_duplicate_clusters(id) ON DELETE CASCADE,
+    question_id UUID NOT NULL REFERENCES questions(id),
+    -- Kemiripan tertinggi dengan anggota lain di klaster
+    similarity DECIMAL(4,3) NOT NULL,
+    PRIMARY KEY (cluster_id, question_id)
+);
+
+CREATE INDEX idx_question_duplicate_members_question ON question_duplicate_members(question_id);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS question_duplicate_members;
+DROP TABLE IF EXISTS question_duplicate_clusters;
+DROP INDEX IF EXISTS idx_questions_dedup_text_trgm;
+ALTER TABLE questions DROP COLUMN IF EXISTS dedup_text;
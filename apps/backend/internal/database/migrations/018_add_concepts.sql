@@ -0,0 +1,98 @@
+-- Write your migrate up statements here
+
+-- Taksonomi konsep bertingkat: section -> subtipe -> konsep
+CREATE TABLE concepts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    parent_id UUID REFERENCES concepts(id) ON DELETE CASCADE,
+    level VARCHAR(10) NOT NULL CHECK (level IN ('section', 'subtype', 'concept')),
+    section VARCHAR(10) NOT NULL CHECK (section IN ('PU', 'PPU', 'PBM', 'PK', 'LBI', 'LBE', 'PM')),
+    sub_type VARCHAR(100),
+    -- Kode stabil bertitik, mis. PU.silogisme.modus-ponens
+    code VARCHAR(200) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    name_en VARCHAR(255),
+    description TEXT,
+
+    -- Parameter Bayesian Knowledge Tracing
+    p_init DECIMAL(4,3) NOT NULL DEFAULT 0.2 CHECK (p_init BETWEEN 0 AND 1),
+    p_transit DECIMAL(4,3) NOT NULL DEFAULT 0.1 CHECK (p_transit BETWEEN 0 AND 1),
+    p_slip DECIMAL(4,3) NOT NULL DEFAULT 0.1 CHECK (p_slip BETWEEN 0 AND 0.5),
+    p_guess DECIMAL(4,3) NOT NULL DEFAULT 0.2 CHECK (p_guess BETWEEN 0 AND 0.5),
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CHECK ((level = 'section') = (parent_id IS NULL))
+);
+
+CREATE INDEX idx_concepts_parent ON concepts(parent_id);
+CREATE INDEX idx_concepts_section ON concepts(section, level);
+
+-- Penandaan soal ke konsep (many-to-many)
+CREATE TABLE question_concepts (
+    question_id UUID NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    concept_id UUID NOT NULL REFERENCES concepts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (question_id, concept_id)
+);
+
+CREATE INDEX idx_question_concepts_concept ON question_concepts(concept_id);
+
+-- Estimasi penguasaan per user per konsep (BKT), diperbarui setiap attempt
+CREATE TABLE user_concept_mastery (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    concept_id UUID NOT NULL REFERENCES concepts(id) ON DELETE CASCADE,
+    p_mastery DECIMAL(5,4) NOT NULL,
+    attempts_count INTEGER NOT NULL DEFAULT 0,
+    correct_count INTEGER NOT NULL DEFAULT 0,
+    last_attempt_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, concept_id)
+);
+
+-- Isi awal taksonomi dari kolom teks bebas sub_type dan related_concept
+INSERT INTO concepts (level, section, code, name, name_en) VALUES
+    ('section', 'PU', 'PU', 'Penalaran Umum', 'General Reasoning'),
+    ('section', 'PPU', 'PPU', 'Pengetahuan dan Pemahaman Umum', 'General Knowledge & Understanding'),
+    ('section', 'PBM', 'PBM', 'Pemahaman Bacaan dan Menulis', 'Reading & Writing Comprehension'),
+    ('section', 'PK', 'PK', 'Pengetahuan Kuantitatif', 'Quantitative Knowledge'),
+    ('section', 'LBI', 'LBI', 'Literasi Bahasa Indonesia', 'Indonesian Literacy'),
+    ('section', 'LBE', 'LBE', 'Literasi Bahasa Inggris', 'English Literacy'),
+    ('section', 'PM', 'PM', 'Penalaran Matematika', 'Mathematical Reasoning');
+
+INSERT INTO concepts (parent_id, level, section, sub_type, code, name)
+SELECT s.id, 'subtype', q.section, q.sub_type, q.section || '.' || q.sub_type, initcap(replace(q.sub_type, '_', ' '))
+FROM (
+    SELECT DISTINCT section, sub_type FROM questions
+    WHERE sub_type IS NOT NULL AND deleted_at IS NULL
+) q
+JOIN concepts s ON s.level = 'section' AND s.section = q.section
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO concepts (parent_id, level, section, sub_type, code, name)
+SELECT COALESCE(st.id, s.id), 'concept', q.section, q.sub_type,
+    concat_ws('.', q.section, q.sub_type, trim(BOTH '-' FROM regexp_replace(lower(q.related_concept), '[^a-z0-9]+', '-', 'g'))),
+    q.related_concept
+FROM (
+    SELECT DISTINCT section, sub_type, related_concept FROM questions
+    WHERE related_concept IS NOT NULL AND related_concept <> '' AND deleted_at IS NULL
+) q
+JOIN concepts s ON s.level = 'section' AND s.section = q.section
+LEFT JOIN concepts st ON st.level = 'subtype' AND st.section = q.section AND st.sub_type = q.sub_type
+ON CONFLICT (code) DO NOTHING;
+
+-- Setiap soal ditandai ke node paling spesifik yang ada
+INSERT INTO question_concepts (question_id, concept_id)
+SELECT q.id, COALESCE(c.id, st.id)
+FROM questions q
+LEFT JOIN concepts c ON c.level = 'concept' AND c.section = q.section
+    AND c.sub_type IS NOT DISTINCT FROM q.sub_type AND c.name = q.related_concept
+LEFT JOIN concepts st ON st.level = 'subtype' AND st.section = q.section AND st.sub_type = q.sub_type
+WHERE q.deleted_at IS NULL AND COALESCE(c.id, st.id) IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS user_concept_mastery;
+DROP TABLE IF EXISTS question_concepts;
+DROP TABLE IF EXISTS concepts;
@@ -0,0 +1,104 @@
+-- Write your migrate up statements here
+
+-- Berkas media (gambar soal) di object storage; baris ini menyimpan metadatanya
+CREATE TABLE media_assets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    storage_key VARCHAR(500) NOT NULL UNIQUE,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+    width INTEGER,
+    height INTEGER,
+    -- SHA-256 isi berkas, untuk memakai ulang unggahan yang sama
+    checksum CHAR(64) NOT NULL,
+    original_filename VARCHAR(255),
+    uploaded_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_media_assets_checksum ON media_assets(checksum);
+
+-- Stimulus bersama: bacaan LBI/LBE, tabel atau grafik yang dipakai beberapa soal
+CREATE TABLE stimuli (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    section VARCHAR(10) NOT NULL CHECK (section IN ('PU', 'PPU', 'PBM', 'PK', 'LBI', 'LBE', 'PM')),
+    title VARCHAR(255),
+    -- Sumber Markdown + LaTeX yang sudah disanitasi
+    content TEXT NOT NULL,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX idx_stimuli_section ON stimuli(section) WHERE deleted_at IS NULL;
+
+ALTER TABLE questions ADD COLUMN stimulus_id UUID REFERENCES stimuli(id);
+CREATE INDEX idx_questions_stimulus ON questions(stimulus_id) WHERE stimulus_id IS NOT NULL;
+
+-- Opsi jawaban berisi LaTeX dan tabel tidak muat di VARCHAR(500). Kolom generated yang
+-- bergantung pada opsi harus dihapus dulu agar tipenya bisa diubah, lalu dibuat ulang.
+ALTER TABLE questions DROP COLUMN dedup_text;
+ALTER TABLE questions DROP COLUMN search_vector;
+
+ALTER TABLE questions
+    ALTER COLUMN option_a TYPE TEXT,
+    ALTER COLUMN option_b TYPE TEXT,
+    ALTER COLUMN option_c TYPE TEXT,
+    ALTER COLUMN option_d TYPE TEXT,
+    ALTER COLUMN option_e TYPE TEXT;
+
+ALTER TABLE questions ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('genta_id'::regconfig, COALESCE(text, '')), 'A') ||
+    setweight(to_tsvector('genta_id'::regconfig,
+        COALESCE(option_a, '') || ' ' || COALESCE(option_b, '') || ' ' || COALESCE(option_c, '') || ' ' ||
+        COALESCE(option_d, '') || ' ' || COALESCE(option_e, '') || ' ' || COALESCE(related_concept, '')), 'B') ||
+    setweight(to_tsvector('genta_id'::regconfig,
+        COALESCE(explanation, '') || ' ' || COALESCE(explanation_en, '')), 'C')
+) STORED;
+
+CREATE INDEX idx_questions_search_vector ON questions USING GIN (search_vector);
+
+ALTER TABLE questions ADD COLUMN dedup_text TEXT GENERATED ALWAYS AS (
+    immutable_unaccent(regexp_replace(lower(
+        text || ' ' || option_a || ' ' || option_b || ' ' || option_c || ' ' || option_d || ' ' || option_e
+    ), '\s+', ' ', 'g'))
+) STORED;
+
+CREATE INDEX idx_questions_dedup_text_trgm ON questions USING GIN (dedup_text gin_trgm_ops);
+
+---- create above / drop below ----
+
+-- Opsi yang lebih panjang dari 500 karakter dipotong agar tipe lama bisa dipulihkan
+ALTER TABLE questions DROP COLUMN dedup_text;
+ALTER TABLE questions DROP COLUMN search_vector;
+
+ALTER TABLE questions
+    ALTER COLUMN option_a TYPE VARCHAR(500) USING left(option_a, 500),
+    ALTER COLUMN option_b TYPE VARCHAR(500) USING left(option_b, 500),
+    ALTER COLUMN option_c TYPE VARCHAR(500) USING left(option_c, 500),
+    ALTER COLUMN option_d TYPE VARCHAR(500) USING left(option_d, 500),
+    ALTER COLUMN option_e TYPE VARCHAR(500) USING left(option_e, 500);
+
+ALTER TABLE questions ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('genta_id'::regconfig, COALESCE(text, '')), 'A') ||
+    setweight(to_tsvector('genta_id'::regconfig,
+        COALESCE(option_a, '') || ' ' || COALESCE(option_b, '') || ' ' || COALESCE(option_c, '') || ' ' ||
+        COALESCE(option_d, '') || ' ' || COALESCE(option_e, '') || ' ' || COALESCE(related_concept, '')), 'B') ||
+    setweight(to_tsvector('genta_id'::regconfig,
+        COALESCE(explanation, '') || ' ' || COALESCE(explanation_en, '')), 'C')
+) STORED;
+
+CREATE INDEX idx_questions_search_vector ON questions USING GIN (search_vector);
+
+ALTER TABLE questions ADD COLUMN dedup_text TEXT GENERATED ALWAYS AS (
+    immutable_unaccent(regexp_replace(lower(
+        text || ' ' || option_a || ' ' || option_b || ' ' || option_c || ' ' || option_d || ' ' || option_e
+    ), '\s+', ' ', 'g'))
+) STORED;
+
+CREATE INDEX idx_questions_dedup_text_trgm ON questions USING GIN (dedup_text gin_trgm_ops);
+
+DROP INDEX IF EXISTS idx_questions_stimulus;
+ALTER TABLE questions DROP COLUMN IF EXISTS stimulus_id;
+DROP TABLE IF EXISTS stimuli;
+DROP TABLE IF EXISTS media_assets;
@@ -0,0 +1,8 @@
+-- Write your migrate up statements here
+
+-- Thumbnail (sisi terpanjang 320px) untuk gambar yang lebih besar dari itu
+ALTER TABLE media_assets ADD COLUMN thumbnail_key VARCHAR(500);
+
+---- create above / drop below ----
+
+ALTER TABLE media_assets DROP COLUMN IF EXISTS thumbnail_key;
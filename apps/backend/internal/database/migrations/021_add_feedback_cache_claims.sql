@@ -0,0 +1,12 @@
+-- Write your migrate up statements here
+
+-- Klaim generasi feedback kanonik per kunci cache, supaya miss bersamaan untuk pasangan
+-- soal/jawaban yang sama hanya memanggil LLM sekali tanpa menahan koneksi selama pemanggilan
+CREATE TABLE feedback_cache_claims (
+    cache_key TEXT PRIMARY KEY,
+    claimed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS feedback_cache_claims;
@@ -0,0 +1,18 @@
+-- Write your migrate up statements here
+
+-- User sistem untuk mencatat biaya LLM judge di ledger llm_usage_daily, supaya biaya itu
+-- masuk anggaran global tanpa memakai kuota siswa mana pun
+INSERT INTO users (id, clerk_id, email, full_name, is_active, onboarding_completed)
+VALUES (
+    '00000000-0000-0000-0000-00000000a001',
+    'system:feedback_judge',
+    'feedback-judge@system.genta.invalid',
+    'Feedback judge (system)',
+    false,
+    true
+)
+ON CONFLICT (id) DO NOTHING;
+
+---- create above / drop below ----
+
+DELETE FROM users WHERE id = '00000000-0000-0000-0000-00000000a001';
@@ -0,0 +1,12 @@
+-- Write your migrate up statements here
+
+-- Task asynq yang membuat draft. Retry task yang draft-nya sudah tersimpan dilewati,
+-- supaya tidak ada draft ganda dan LLM tidak dibayar dua kali
+ALTER TABLE question_drafts ADD COLUMN task_id VARCHAR(100);
+
+CREATE INDEX idx_question_drafts_task_id ON question_drafts(task_id) WHERE task_id IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_question_drafts_task_id;
+ALTER TABLE question_drafts DROP COLUMN IF EXISTS task_id;
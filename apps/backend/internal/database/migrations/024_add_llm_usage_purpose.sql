@@ -0,0 +1,34 @@
+-- Write your migrate up statements here
+
+-- Biaya LLM milik sistem (mis. LLM judge) dicatat tanpa user: user_id NULL dan purpose
+-- menyebut untuk apa. Menggantikan user sistem dari migrasi 022, yang ikut muncul di daftar
+-- user, leaderboard dan job yang memproses semua user
+ALTER TABLE llm_usage_daily ADD COLUMN purpose VARCHAR(20) NOT NULL DEFAULT 'user'
+    CHECK (purpose IN ('user', 'feedback_judge'));
+
+ALTER TABLE llm_usage_daily DROP CONSTRAINT llm_usage_daily_pkey;
+ALTER TABLE llm_usage_daily ALTER COLUMN user_id DROP NOT NULL;
+
+UPDATE llm_usage_daily
+SET user_id = NULL, purpose = 'feedback_judge'
+WHERE user_id = '00000000-0000-0000-0000-00000000a001';
+
+DELETE FROM users WHERE id = '00000000-0000-0000-0000-00000000a001';
+
+-- Baris user wajib punya user_id, baris sistem tidak boleh punya
+ALTER TABLE llm_usage_daily ADD CONSTRAINT llm_usage_daily_user_purpose_check
+    CHECK ((purpose = 'user') = (user_id IS NOT NULL));
+
+CREATE UNIQUE INDEX idx_llm_usage_daily_key
+    ON llm_usage_daily(user_id, purpose, model, usage_date) NULLS NOT DISTINCT;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_llm_usage_daily_key;
+ALTER TABLE llm_usage_daily DROP CONSTRAINT IF EXISTS llm_usage_daily_user_purpose_check;
+
+DELETE FROM llm_usage_daily WHERE user_id IS NULL;
+
+ALTER TABLE llm_usage_daily ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE llm_usage_daily ADD PRIMARY KEY (user_id, model, usage_date);
+ALTER TABLE llm_usage_daily DROP COLUMN IF EXISTS purpose;
@@ -0,0 +1,21 @@
+-- Write your migrate up statements here
+
+-- ============================================
+-- 1. PACING_COHORT_MEDIANS TABLE
+-- ============================================
+-- Median waktu pengerjaan per section dari semua pengguna, dimaterialisasi job berkala
+-- agar analitik pacing tidak memindai attempt pengguna lain di setiap request.
+-- Hanya section dengan anggota kohort minimal (privasi) yang disimpan.
+CREATE TABLE pacing_cohort_medians (
+    section VARCHAR(10) PRIMARY KEY CHECK (section IN ('PU', 'PPU', 'PBM', 'PK', 'LBI', 'LBE', 'PM')),
+
+    median_seconds DECIMAL(8, 2) NOT NULL,
+    cohort_size INTEGER NOT NULL,
+    attempts_count INTEGER NOT NULL,
+
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS pacing_cohort_medians;
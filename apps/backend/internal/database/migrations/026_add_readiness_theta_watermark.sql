@@ -0,0 +1,14 @@
+-- Write your migrate up statements here
+
+-- Penanda estimasi theta inkremental: waktu dan jumlah attempt yang sudah masuk ke
+-- current_theta/theta_se. Attempt setelah theta_through diperbarui dari estimasi tersimpan;
+-- NULL (atau jumlah yang tidak cocok) berarti estimasi ulang dari seluruh riwayat.
+ALTER TABLE user_readiness
+    ADD COLUMN theta_through TIMESTAMP,
+    ADD COLUMN theta_responses INTEGER NOT NULL DEFAULT 0;
+
+---- create above / drop below ----
+
+ALTER TABLE user_readiness
+    DROP COLUMN IF EXISTS theta_responses,
+    DROP COLUMN IF EXISTS theta_through;
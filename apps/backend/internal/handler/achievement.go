@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/achievement"
+	"github.com/manikandareas/genta/internal/server"
+	"github.com/manikandareas/genta/internal/service"
+)
+
+type AchievementHandler struct {
+	Handler
+	achievementService *service.AchievementService
+}
+
+func NewAchievementHandler(s *server.Server, achievementService *service.AchievementService) *AchievementHandler {
+	return &AchievementHandler{
+		Handler:            NewHandler(s),
+		achievementService: achievementService,
+	}
+}
+
+// GetAchievements godoc
+// @Summary Get achievements catalogue
+// @Description Get every achievement with its unlock rule, and which ones the caller has unlocked and seen
+// @Tags achievements
+// @Accept json
+// @Produce json
+// @Param lang query string false "Language of titles and descriptions (id, en)" default(id)
+// @Success 200 {object} achievement.CatalogueResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /achievements [get]
+func (h *AchievementHandler) GetAchievements(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *achievement.GetCatalogueRequest) (*achievement.CatalogueResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.achievementService.GetCatalogue(c, userID, req)
+		},
+		http.StatusOK,
+		&achievement.GetCatalogueRequest{},
+	)(c)
+}
+
+// MarkAchievementsSeen godoc
+// @Summary Acknowledge achievement notifications
+// @Description Mark unlocked achievements as seen; without codes every unseen achievement is marked
+// @Tags achievements
+// @Accept json
+// @Produce json
+// @Param body body achievement.MarkSeenRequest false "Achievement codes to mark"
+// @Success 200 {object} achievement.MarkSeenResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /achievements/seen [post]
+func (h *AchievementHandler) MarkAchievementsSeen(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *achievement.MarkSeenRequest) (*achievement.MarkSeenResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.achievementService.MarkSeen(c, userID, req)
+		},
+		http.StatusOK,
+		&achievement.MarkSeenRequest{},
+	)(c)
+}
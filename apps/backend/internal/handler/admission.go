@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/admission"
+	"github.com/manikandareas/genta/internal/server"
+	"github.com/manikandareas/genta/internal/service"
+)
+
+// maxCatalogueUploadBytes caps the size of an uploaded catalogue CSV
+const maxCatalogueUploadBytes = 10 << 20
+
+type AdmissionHandler struct {
+	Handler
+	admissionService *service.AdmissionService
+}
+
+func NewAdmissionHandler(s *server.Server, admissionService *service.AdmissionService) *AdmissionHandler {
+	return &AdmissionHandler{
+		Handler:          NewHandler(s),
+		admissionService: admissionService,
+	}
+}
+
+// SearchPTN godoc
+// @Summary Search PTN catalogue
+// @Description Search state universities by code, short name or name
+// @Tags admission
+// @Accept json
+// @Produce json
+// @Param search query string false "Code, short name or name"
+// @Param limit query int false "Max results" default(20)
+// @Success 200 {array} admission.PTNResponse
+// @Failure 401 {object} errs.HTTPError
+// @Router /ptn [get]
+func (h *AdmissionHandler) SearchPTN(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *admission.ListPTNRequest) ([]admission.PTNResponse, error) {
+			return h.admissionService.SearchPTN(c, req)
+		},
+		http.StatusOK,
+		&admission.ListPTNRequest{},
+	)(c)
+}
+
+// ListPrograms godoc
+// @Summary List study programs
+// @Description List a PTN's study programs with their latest passing-score range
+// @Tags admission
+// @Accept json
+// @Produce json
+// @Param id path string true "PTN ID"
+// @Param cluster query string false "Cluster (saintek, soshum)"
+// @Param search query string false "Program name"
+// @Success 200 {array} admission.StudyProgramResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 401 {object} errs.HTTPError
+// @Router /ptn/{id}/programs [get]
+func (h *AdmissionHandler) ListPrograms(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *admission.ListProgramsRequest) ([]admission.StudyProgramResponse, error) {
+			return h.admissionService.ListPrograms(c, req)
+		},
+		http.StatusOK,
+		&admission.ListProgramsRequest{},
+	)(c)
+}
+
+// GetChance godoc
+// @Summary Estimate admission chance
+// @Description Estimate the probability of passing a program's historical cutoff from the predicted composite score
+// @Tags admission
+// @Accept json
+// @Produce json
+// @Param program_id query string false "Study program ID, defaults to the user's target program"
+// @Success 200 {object} admission.AdmissionChanceResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /admission/chance [get]
+func (h *AdmissionHandler) GetChance(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *admission.GetAdmissionChanceRequest) (*admission.AdmissionChanceResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.admissionService.GetChance(c, userID, req)
+		},
+		http.StatusOK,
+		&admission.GetAdmissionChanceRequest{},
+	)(c)
+}
+
+// ImportCatalogue godoc
+// @Summary Import PTN catalogue
+// @Description Upsert PTNs, study programs and passing scores from a CSV file (admin only).
+// @Description Required columns: ptn_code, ptn_name, program_code, program_name, year, score_low, score_high.
+// @Description Optional columns: ptn_short_name, city, province, cluster, capacity, applicants.
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Catalogue CSV"
+// @Success 200 {object} admission.ImportCatalogueResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Router /admin/ptn-catalogue/import [post]
+func (h *AdmissionHandler) ImportCatalogue(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *admission.ImportCatalogueRequest) (*admission.ImportCatalogueResponse, error) {
+			fileHeader, err := c.FormFile("file")
+			if err != nil {
+				return nil, errs.NewBadRequestError("file is required", false, nil, nil, nil)
+			}
+			if fileHeader.Size > maxCatalogueUploadBytes {
+				return nil, errs.NewBadRequestError("file is too large", false, nil, nil, nil)
+			}
+
+			file, err := fileHeader.Open()
+			if err != nil {
+				return nil, errs.NewBadRequestError("failed to read file", false, nil, nil, nil)
+			}
+			defer file.Close()
+
+			return h.admissionService.ImportCatalogue(c, file)
+		},
+		http.StatusOK,
+		&admission.ImportCatalogueRequest{},
+	)(c)
+}
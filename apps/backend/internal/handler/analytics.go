@@ -24,12 +24,22 @@ func NewAnalyticsHandler(s *server.Server, analyticsService *service.AnalyticsSe
 
 // GetProgress godoc
 // @Summary Get progress analytics
-// @Description Get user's progress analytics including accuracy trend and section breakdown
+// @Description Get user's progress analytics including accuracy trend and section breakdown, with optional
+// @Description period comparison and subtype, difficulty band and time-of-day breakdowns
 // @Tags analytics
 // @Accept json
 // @Produce json
-// @Param days query int false "Period in days (7, 30, or 90)" default(7)
+// @Param days query int false "Period in days ending today, used when from is not set" default(7)
+// @Param from query string false "Period start date (YYYY-MM-DD, inclusive)"
+// @Param to query string false "Period end date (YYYY-MM-DD, inclusive), defaults to today"
+// @Param timezone query string false "Time zone for day bucketing (WIB, WITA, WIT)" default(WIB)
 // @Param section query string false "Filter by section (PU, PPU, PBM, PK, LBI, LBE, PM)"
+// @Param sections query []string false "Filter by several sections (repeated or comma-separated)"
+// @Param granularity query string false "Accuracy trend granularity (day, week)" default(day)
+// @Param compare query string false "Compare with the previous period of equal length (previous)"
+// @Param compare_from query string false "Comparison period start date (YYYY-MM-DD)"
+// @Param compare_to query string false "Comparison period end date (YYYY-MM-DD)"
+// @Param breakdown query []string false "Breakdowns to include (subtype, difficulty, time_of_day)"
 // @Success 200 {object} analytics.ProgressResponse
 // @Failure 400 {object} errs.HTTPError
 // @Failure 401 {object} errs.HTTPError
@@ -46,3 +56,34 @@ func (h *AnalyticsHandler) GetProgress(c echo.Context) error {
 		&analytics.GetProgressRequest{},
 	)(c)
 }
+
+// GetPacing godoc
+// @Summary Get pacing analytics
+// @Description Get time-management analytics per section against UTBK per-question time budgets:
+// @Description share over budget, accuracy by speed band, rushing and overthinking patterns, cohort
+// @Description median time, and structured recommendations
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Param days query int false "Period in days ending today, used when from is not set" default(30)
+// @Param from query string false "Period start date (YYYY-MM-DD, inclusive)"
+// @Param to query string false "Period end date (YYYY-MM-DD, inclusive), defaults to today"
+// @Param timezone query string false "Time zone for day bucketing (WIB, WITA, WIT)" default(WIB)
+// @Param sections query []string false "Filter by sections (repeated or comma-separated)"
+// @Param lang query string false "Language of recommendation messages (id, en)" default(id)
+// @Success 200 {object} analytics.PacingResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /analytics/pacing [get]
+func (h *AnalyticsHandler) GetPacing(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *analytics.GetPacingRequest) (*analytics.PacingResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.analyticsService.GetPacing(c, userID, req)
+		},
+		http.StatusOK,
+		&analytics.GetPacingRequest{},
+	)(c)
+}
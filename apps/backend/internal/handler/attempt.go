@@ -5,6 +5,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model"
 	"github.com/manikandareas/genta/internal/model/attempt"
 	"github.com/manikandareas/genta/internal/server"
 	"github.com/manikandareas/genta/internal/service"
@@ -91,3 +92,65 @@ func (h *AttemptHandler) UpdateFeedbackRating(c echo.Context) error {
 		&attempt.UpdateFeedbackRatingRequest{},
 	)(c)
 }
+
+// ListAttempts godoc
+// @Summary List attempt history
+// @Description Get the current user's past attempts, newest first, with cursor pagination
+// @Tags attempts
+// @Accept json
+// @Produce json
+// @Param section query string false "Section code"
+// @Param sub_type query string false "Question subtype"
+// @Param is_correct query bool false "Only correct or only wrong answers"
+// @Param from query string false "Start date (YYYY-MM-DD, inclusive)"
+// @Param to query string false "End date (YYYY-MM-DD, inclusive)"
+// @Param timezone query string false "Time zone of the dates (WIB, WITA, WIT)" default(WIB)
+// @Param session_id query string false "Session ID"
+// @Param has_feedback query bool false "Only attempts with or without feedback"
+// @Param cursor query string false "Cursor from a previous page's nextCursor or prevCursor"
+// @Param include_total query bool false "Also count all matching items"
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} model.PaginatedResponse[attempt.AttemptHistoryItemResponse]
+// @Failure 400 {object} errs.HTTPError
+// @Failure 401 {object} errs.HTTPError
+// @Router /attempts [get]
+func (h *AttemptHandler) ListAttempts(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *attempt.ListAttemptsRequest) (*model.PaginatedResponse[attempt.AttemptHistoryItemResponse], error) {
+			userID := middleware.GetUserID(c)
+			return h.attemptService.List(c, userID, req)
+		},
+		http.StatusOK,
+		&attempt.ListAttemptsRequest{},
+	)(c)
+}
+
+// GetRetryQueue godoc
+// @Summary Get questions to retry
+// @Description Get questions the user answered wrong and has not answered correctly since, for a "retry wrong answers" practice session
+// @Tags attempts
+// @Accept json
+// @Produce json
+// @Param section query string false "Section code"
+// @Param sub_type query string false "Question subtype"
+// @Param from query string false "Start date (YYYY-MM-DD, inclusive)"
+// @Param to query string false "End date (YYYY-MM-DD, inclusive)"
+// @Param timezone query string false "Time zone of the dates (WIB, WITA, WIT)" default(WIB)
+// @Param session_id query string false "Session ID"
+// @Param limit query int false "Number of questions" default(10)
+// @Success 200 {object} attempt.RetryQueueResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 401 {object} errs.HTTPError
+// @Router /attempts/retry [get]
+func (h *AttemptHandler) GetRetryQueue(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *attempt.GetRetryQueueRequest) (*attempt.RetryQueueResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.attemptService.GetRetryQueue(c, userID, req)
+		},
+		http.StatusOK,
+		&attempt.GetRetryQueueRequest{},
+	)(c)
+}
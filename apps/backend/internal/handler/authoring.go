@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model"
+	"github.com/manikandareas/genta/internal/model/draft"
+	"github.com/manikandareas/genta/internal/server"
+	"github.com/manikandareas/genta/internal/service"
+)
+
+type AuthoringHandler struct {
+	Handler
+	authoringService *service.AuthoringService
+}
+
+func NewAuthoringHandler(s *server.Server, authoringService *service.AuthoringService) *AuthoringHandler {
+	return &AuthoringHandler{
+		Handler:          NewHandler(s),
+		authoringService: authoringService,
+	}
+}
+
+// EnrichQuestion godoc
+// @Summary Draft missing question fields
+// @Description Queue an LLM job that drafts explanation, explanation_en, strategy_tip or solution_steps for a question. Drafts enter the review queue.
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param request body draft.EnrichQuestionRequest false "Fields to draft (default: all missing)"
+// @Success 202 {object} draft.DraftJobResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /editor/questions/{id}/enrich [post]
+func (h *AuthoringHandler) EnrichQuestion(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *draft.EnrichQuestionRequest) (*draft.DraftJobResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.authoringService.EnrichQuestion(c, userID, req)
+		},
+		http.StatusAccepted,
+		&draft.EnrichQuestionRequest{},
+	)(c)
+}
+
+// EnrichBatch godoc
+// @Summary Draft missing fields in bulk
+// @Description Queue a batch job that drafts missing fields for questions without a pending enrichment draft
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param request body draft.EnrichBatchRequest true "Batch selection"
+// @Success 202 {object} draft.DraftJobResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Router /editor/questions/enrich-batch [post]
+func (h *AuthoringHandler) EnrichBatch(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *draft.EnrichBatchRequest) (*draft.DraftJobResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.authoringService.EnrichBatch(c, userID, req)
+		},
+		http.StatusAccepted,
+		&draft.EnrichBatchRequest{},
+	)(c)
+}
+
+// GenerateVariants godoc
+// @Summary Draft variant questions
+// @Description Queue an LLM job that drafts new questions from a template question at a target difficulty
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param id path string true "Template question ID"
+// @Param request body draft.GenerateVariantsRequest true "Variant options"
+// @Success 202 {object} draft.DraftJobResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /editor/questions/{id}/variants [post]
+func (h *AuthoringHandler) GenerateVariants(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *draft.GenerateVariantsRequest) (*draft.DraftJobResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.authoringService.GenerateVariants(c, userID, req)
+		},
+		http.StatusAccepted,
+		&draft.GenerateVariantsRequest{},
+	)(c)
+}
+
+// ListDrafts godoc
+// @Summary List question drafts
+// @Description List LLM-drafted content awaiting or past review
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status (pending, approved, rejected)"
+// @Param kind query string false "Filter by kind (enrichment, variant)"
+// @Param source_question_id query string false "Filter by source question"
+// @Param page query int false "Page number (legacy offset pagination)"
+// @Param cursor query string false "Cursor from a previous page's nextCursor or prevCursor"
+// @Param include_total query bool false "Also count all matching items"
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} model.PaginatedResponse[draft.DraftResponse]
+// @Failure 403 {object} errs.HTTPError
+// @Router /editor/drafts [get]
+func (h *AuthoringHandler) ListDrafts(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *draft.ListDraftsRequest) (*model.PaginatedResponse[draft.DraftResponse], error) {
+			return h.authoringService.ListDrafts(c, req)
+		},
+		http.StatusOK,
+		&draft.ListDraftsRequest{},
+	)(c)
+}
+
+// GetDraft godoc
+// @Summary Get question draft
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param id path string true "Draft ID"
+// @Success 200 {object} draft.DraftResponse
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /editor/drafts/{id} [get]
+func (h *AuthoringHandler) GetDraft(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *draft.GetDraftRequest) (*draft.DraftResponse, error) {
+			return h.authoringService.GetDraft(c, req.ID)
+		},
+		http.StatusOK,
+		&draft.GetDraftRequest{},
+	)(c)
+}
+
+// UpdateDraft godoc
+// @Summary Revise question draft
+// @Description Replace the drafted content of a pending draft before approval
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param id path string true "Draft ID"
+// @Param request body draft.UpdateDraftRequest true "Revised content"
+// @Success 200 {object} draft.DraftResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /editor/drafts/{id} [patch]
+func (h *AuthoringHandler) UpdateDraft(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *draft.UpdateDraftRequest) (*draft.DraftResponse, error) {
+			return h.authoringService.UpdateDraft(c, req)
+		},
+		http.StatusOK,
+		&draft.UpdateDraftRequest{},
+	)(c)
+}
+
+// ApproveDraft godoc
+// @Summary Approve question draft
+// @Description Apply a pending draft: enrichment fills the source question, variant inserts a new question. A variant that likely duplicates an existing question is refused with DUPLICATE_QUESTION unless allow_duplicate is set.
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param id path string true "Draft ID"
+// @Param request body draft.ReviewDraftRequest false "Review notes"
+// @Success 200 {object} draft.DraftResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /editor/drafts/{id}/approve [post]
+func (h *AuthoringHandler) ApproveDraft(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *draft.ReviewDraftRequest) (*draft.DraftResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.authoringService.ApproveDraft(c, userID, req)
+		},
+		http.StatusOK,
+		&draft.ReviewDraftRequest{},
+	)(c)
+}
+
+// RejectDraft godoc
+// @Summary Reject question draft
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param id path string true "Draft ID"
+// @Param request body draft.ReviewDraftRequest false "Review notes"
+// @Success 200 {object} draft.DraftResponse
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /editor/drafts/{id}/reject [post]
+func (h *AuthoringHandler) RejectDraft(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *draft.ReviewDraftRequest) (*draft.DraftResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.authoringService.RejectDraft(c, userID, req)
+		},
+		http.StatusOK,
+		&draft.ReviewDraftRequest{},
+	)(c)
+}
@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"io"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -96,6 +99,53 @@ func (h FileResponseHandler) AddAttributes(txn *newrelic.Transaction, result int
 	}
 }
 
+// Stream is a response body copied from a reader, such as an object from storage
+type Stream struct {
+	Reader      io.ReadCloser
+	ContentType string
+	// Size is the body length in bytes, or -1 when unknown
+	Size    int64
+	Headers map[string]string
+	// RedirectURL, when set, sends the client there to download the body instead
+	RedirectURL string
+}
+
+// StreamResponseHandler handles streamed responses
+type StreamResponseHandler struct {
+	status int
+}
+
+func (h StreamResponseHandler) Handle(c echo.Context, result interface{}) error {
+	stream := result.(*Stream)
+	if stream.Reader != nil {
+		defer stream.Reader.Close()
+	}
+
+	for name, value := range stream.Headers {
+		c.Response().Header().Set(name, value)
+	}
+	if stream.RedirectURL != "" {
+		return c.Redirect(http.StatusFound, stream.RedirectURL)
+	}
+	if stream.Size >= 0 {
+		c.Response().Header().Set(echo.HeaderContentLength, strconv.FormatInt(stream.Size, 10))
+	}
+	return c.Stream(h.status, stream.ContentType, stream.Reader)
+}
+
+func (h StreamResponseHandler) GetOperation() string {
+	return "handler_stream"
+}
+
+func (h StreamResponseHandler) AddAttributes(txn *newrelic.Transaction, result interface{}) {
+	if txn != nil {
+		if stream, ok := result.(*Stream); ok {
+			txn.AddAttribute("stream.content_type", stream.ContentType)
+			txn.AddAttribute("stream.size_bytes", stream.Size)
+		}
+	}
+}
+
 // handleRequest is the unified handler function that eliminates code duplication
 func handleRequest[Req validation.Validatable](
 	c echo.Context,
@@ -239,6 +289,20 @@ func HandleFile[Req validation.Validatable](
 	}
 }
 
+// HandleStream wraps a handler whose response body is streamed from a reader
+func HandleStream[Req validation.Validatable](
+	h Handler,
+	handler HandlerFunc[Req, *Stream],
+	status int,
+	req Req,
+) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return handleRequest(c, req, func(c echo.Context, req Req) (interface{}, error) {
+			return handler(c, req)
+		}, StreamResponseHandler{status: status})
+	}
+}
+
 // HandleNoContent wraps a handler with validation, error handling, logging, metrics, and tracing for endpoints that don't return content
 func HandleNoContent[Req validation.Validatable](
 	h Handler,
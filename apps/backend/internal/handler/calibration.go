@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/calibration"
+	"github.com/manikandareas/genta/internal/server"
+	"github.com/manikandareas/genta/internal/service"
+)
+
+type CalibrationHandler struct {
+	Handler
+	calibrationService *service.CalibrationService
+}
+
+func NewCalibrationHandler(s *server.Server, calibrationService *service.CalibrationService) *CalibrationHandler {
+	return &CalibrationHandler{
+		Handler:            NewHandler(s),
+		calibrationService: calibrationService,
+	}
+}
+
+// ImportTryoutResults godoc
+// @Summary Import tryout results
+// @Description Import official tryout scores used to calibrate theta to UTBK scores (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body calibration.ImportTryoutResultsRequest true "Tryout results"
+// @Success 200 {object} calibration.ImportTryoutResultsResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Router /admin/tryout-results [post]
+func (h *CalibrationHandler) ImportTryoutResults(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *calibration.ImportTryoutResultsRequest) (*calibration.ImportTryoutResultsResponse, error) {
+			return h.calibrationService.ImportTryoutResults(c, req)
+		},
+		http.StatusOK,
+		&calibration.ImportTryoutResultsRequest{},
+	)(c)
+}
+
+// ListCalibrations godoc
+// @Summary List score calibrations
+// @Description List theta to UTBK score calibration versions (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param version query string false "Filter by version"
+// @Success 200 {array} calibration.CalibrationVersionResponse
+// @Failure 403 {object} errs.HTTPError
+// @Router /admin/score-calibrations [get]
+func (h *CalibrationHandler) ListCalibrations(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *calibration.ListCalibrationsRequest) ([]calibration.CalibrationVersionResponse, error) {
+			return h.calibrationService.List(c, req)
+		},
+		http.StatusOK,
+		&calibration.ListCalibrationsRequest{},
+	)(c)
+}
+
+// FitCalibration godoc
+// @Summary Fit score calibration
+// @Description Fit a new calibration version from imported tryout results (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body calibration.FitCalibrationRequest true "Version to create"
+// @Success 201 {object} calibration.CalibrationVersionResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Router /admin/score-calibrations [post]
+func (h *CalibrationHandler) FitCalibration(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *calibration.FitCalibrationRequest) (*calibration.CalibrationVersionResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.calibrationService.Fit(c, userID, req)
+		},
+		http.StatusCreated,
+		&calibration.FitCalibrationRequest{},
+	)(c)
+}
+
+// ActivateCalibration godoc
+// @Summary Activate score calibration
+// @Description Make a calibration version the one used for score predictions (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param version path string true "Calibration version"
+// @Success 200 {object} calibration.CalibrationVersionResponse
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /admin/score-calibrations/{version}/activate [post]
+func (h *CalibrationHandler) ActivateCalibration(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *calibration.ActivateCalibrationRequest) (*calibration.CalibrationVersionResponse, error) {
+			return h.calibrationService.Activate(c, req)
+		},
+		http.StatusOK,
+		&calibration.ActivateCalibrationRequest{},
+	)(c)
+}
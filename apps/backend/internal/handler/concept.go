@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/concept"
+	"github.com/manikandareas/genta/internal/server"
+	"github.com/manikandareas/genta/internal/service"
+)
+
+type ConceptHandler struct {
+	Handler
+	conceptService *service.ConceptService
+}
+
+func NewConceptHandler(s *server.Server, conceptService *service.ConceptService) *ConceptHandler {
+	return &ConceptHandler{
+		Handler:        NewHandler(s),
+		conceptService: conceptService,
+	}
+}
+
+// GetConcepts godoc
+// @Summary Get the concept taxonomy
+// @Description Get the section, subtype and concept hierarchy with the number of active questions under each node
+// @Tags concepts
+// @Accept json
+// @Produce json
+// @Param section query string false "Section filter (PU, PPU, PBM, PK, LBI, LBE, PM)"
+// @Param lang query string false "Name language (id, en)" default(id)
+// @Success 200 {object} concept.TreeResponse
+// @Failure 401 {object} errs.HTTPError
+// @Router /concepts [get]
+func (h *ConceptHandler) GetConcepts(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *concept.GetTreeRequest) (*concept.TreeResponse, error) {
+			return h.conceptService.GetTaxonomy(c, req)
+		},
+		http.StatusOK,
+		&concept.GetTreeRequest{},
+	)(c)
+}
+
+// GetConceptMastery godoc
+// @Summary Get concept mastery
+// @Description Get the taxonomy with the caller's Bayesian Knowledge Tracing mastery estimate for every node; parents show the question-weighted mean of their subtree
+// @Tags concepts
+// @Accept json
+// @Produce json
+// @Param section query string false "Section filter (PU, PPU, PBM, PK, LBI, LBE, PM)"
+// @Param lang query string false "Name language (id, en)" default(id)
+// @Success 200 {object} concept.TreeResponse
+// @Failure 401 {object} errs.HTTPError
+// @Router /concepts/mastery [get]
+func (h *ConceptHandler) GetConceptMastery(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *concept.GetTreeRequest) (*concept.TreeResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.conceptService.GetMastery(c, userID, req)
+		},
+		http.StatusOK,
+		&concept.GetTreeRequest{},
+	)(c)
+}
+
+// GetReadinessBlockers godoc
+// @Summary Get concepts blocking readiness
+// @Description Rank unmastered concepts by how much they hold back readiness: their share of the section's questions, their mastery gap and the section's readiness gap. Sections already ready are left out.
+// @Tags concepts
+// @Accept json
+// @Produce json
+// @Param section query string false "Section filter (PU, PPU, PBM, PK, LBI, LBE, PM)"
+// @Param limit query int false "Max concepts" default(5)
+// @Param lang query string false "Name language (id, en)" default(id)
+// @Success 200 {object} concept.BlockersResponse
+// @Failure 401 {object} errs.HTTPError
+// @Router /concepts/blockers [get]
+func (h *ConceptHandler) GetReadinessBlockers(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *concept.GetBlockersRequest) (*concept.BlockersResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.conceptService.GetBlockers(c, userID, req)
+		},
+		http.StatusOK,
+		&concept.GetBlockersRequest{},
+	)(c)
+}
+
+// CreateConcept godoc
+// @Summary Create a concept
+// @Description Add a subtype under a section or a concept under a subtype
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param request body concept.CreateConceptRequest true "Concept"
+// @Success 201 {object} concept.ConceptResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /editor/concepts [post]
+func (h *ConceptHandler) CreateConcept(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *concept.CreateConceptRequest) (*concept.ConceptResponse, error) {
+			return h.conceptService.CreateConcept(c, req)
+		},
+		http.StatusCreated,
+		&concept.CreateConceptRequest{},
+	)(c)
+}
+
+// SetQuestionConcepts godoc
+// @Summary Tag a question with concepts
+// @Description Replace the concepts a question is tagged with; concepts must belong to the question's section
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param request body concept.SetQuestionConceptsRequest true "Concept IDs"
+// @Success 200 {object} concept.QuestionConceptsResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /editor/questions/{id}/concepts [put]
+func (h *ConceptHandler) SetQuestionConcepts(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *concept.SetQuestionConceptsRequest) (*concept.QuestionConceptsResponse, error) {
+			return h.conceptService.SetQuestionConcepts(c, req)
+		},
+		http.StatusOK,
+		&concept.SetQuestionConceptsRequest{},
+	)(c)
+}
+
+// RebuildConceptMastery godoc
+// @Summary Rebuild concept mastery
+// @Description Queue a replay of attempt history into concept mastery, for one user or everyone (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body concept.RebuildMasteryRequest false "User to rebuild (default: all users)"
+// @Success 202 {object} concept.RebuildJobResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Router /admin/concept-mastery/rebuild [post]
+func (h *ConceptHandler) RebuildConceptMastery(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *concept.RebuildMasteryRequest) (*concept.RebuildJobResponse, error) {
+			return h.conceptService.RebuildMastery(c, req)
+		},
+		http.StatusAccepted,
+		&concept.RebuildMasteryRequest{},
+	)(c)
+}
@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/media"
+	"github.com/manikandareas/genta/internal/model/question"
+	"github.com/manikandareas/genta/internal/model/stimulus"
+	"github.com/manikandareas/genta/internal/server"
+	"github.com/manikandareas/genta/internal/service"
+)
+
+// mediaCacheControl lets clients and CDNs keep media indefinitely: an asset's file never
+// changes, a new upload gets a new ID
+const mediaCacheControl = "public, max-age=31536000, immutable"
+
+type ContentHandler struct {
+	Handler
+	contentService *service.ContentService
+}
+
+func NewContentHandler(s *server.Server, contentService *service.ContentService) *ContentHandler {
+	return &ContentHandler{
+		Handler:        NewHandler(s),
+		contentService: contentService,
+	}
+}
+
+// mediaRedirectCacheControl keeps a redirect to a presigned URL for less time than the URL
+// stays valid
+const mediaRedirectCacheControl = "private, max-age=600"
+
+// UploadMedia godoc
+// @Summary Upload an image
+// @Description Upload a PNG, JPEG, GIF or WebP image (max 5 MB, 4096x4096) for use in question content. Reference it in content with the returned markdown, ![alt](media:<id>). Uploading the same file again returns the existing asset.
+// @Tags editor
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Image"
+// @Success 201 {object} media.AssetResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Router /editor/media [post]
+func (h *ContentHandler) UploadMedia(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *media.UploadMediaRequest) (*media.AssetResponse, error) {
+			fileHeader, err := c.FormFile("file")
+			if err != nil {
+				return nil, errs.NewBadRequestError("file is required", false, nil, nil, nil)
+			}
+			if fileHeader.Size > media.UploadPolicy.MaxBytes {
+				return nil, errs.NewBadRequestError("file is too large", false, nil, nil, nil)
+			}
+
+			file, err := fileHeader.Open()
+			if err != nil {
+				return nil, errs.NewBadRequestError("failed to read file", false, nil, nil, nil)
+			}
+			defer file.Close()
+
+			userID := middleware.GetUserID(c)
+			return h.contentService.UploadMedia(c, userID, file, fileHeader.Filename)
+		},
+		http.StatusCreated,
+		&media.UploadMediaRequest{},
+	)(c)
+}
+
+// CreateMediaUpload godoc
+// @Summary Start a direct image upload
+// @Description Presign an upload of an image straight to storage. Send the returned fields followed by the file as a multipart POST to upload.url within 15 minutes, then complete the upload.
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param request body media.CreateUploadRequest true "Image to upload"
+// @Success 201 {object} media.UploadResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Router /editor/media/uploads [post]
+func (h *ContentHandler) CreateMediaUpload(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *media.CreateUploadRequest) (*media.UploadResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.contentService.CreateMediaUpload(c, userID, req)
+		},
+		http.StatusCreated,
+		&media.CreateUploadRequest{},
+	)(c)
+}
+
+// CompleteMediaUpload godoc
+// @Summary Complete a direct image upload
+// @Description Record an image uploaded with a presigned upload. The file is checked like a direct upload and removed from storage when it is refused.
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param id path string true "Upload ID"
+// @Param request body media.CompleteUploadRequest true "Uploaded key"
+// @Success 201 {object} media.AssetResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /editor/media/uploads/{id}/complete [post]
+func (h *ContentHandler) CompleteMediaUpload(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *media.CompleteUploadRequest) (*media.AssetResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.contentService.CompleteMediaUpload(c, userID, req)
+		},
+		http.StatusCreated,
+		&media.CompleteUploadRequest{},
+	)(c)
+}
+
+// GetMedia godoc
+// @Summary Get a media file
+// @Description Serve an uploaded image referenced from question content, or its thumbnail (at most 320 pixels on the longer side). Public, so it can be used directly as an image source. When storage serves downloads itself, this redirects to a presigned URL.
+// @Tags media
+// @Produce image/png
+// @Produce image/jpeg
+// @Produce image/gif
+// @Produce image/webp
+// @Param id path string true "Asset ID"
+// @Param variant query string false "original (default) or thumbnail"
+// @Success 200 {file} file
+// @Success 302
+// @Failure 404 {object} errs.HTTPError
+// @Router /media/{id} [get]
+func (h *ContentHandler) GetMedia(c echo.Context) error {
+	return HandleStream(
+		h.Handler,
+		func(c echo.Context, req *media.GetMediaRequest) (*Stream, error) {
+			download, err := h.contentService.OpenMedia(c, req.ID, req.Variant)
+			if err != nil {
+				return nil, err
+			}
+
+			if download.RedirectURL != "" {
+				return &Stream{
+					RedirectURL: download.RedirectURL,
+					Headers:     map[string]string{echo.HeaderCacheControl: mediaRedirectCacheControl},
+				}, nil
+			}
+
+			return &Stream{
+				Reader:      download.Body,
+				ContentType: download.ContentType,
+				Size:        download.Size,
+				Headers: map[string]string{
+					echo.HeaderCacheControl: mediaCacheControl,
+					"ETag":                  `"` + download.ETag + `"`,
+				},
+			}, nil
+		},
+		http.StatusOK,
+		&media.GetMediaRequest{},
+	)(c)
+}
+
+// GetStimulus godoc
+// @Summary Get a stimulus
+// @Description Get a shared reading passage, table or figure that questions refer to by stimulus_id
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param id path string true "Stimulus ID"
+// @Success 200 {object} stimulus.StimulusResponse
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /stimuli/{id} [get]
+func (h *ContentHandler) GetStimulus(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *stimulus.GetStimulusRequest) (*stimulus.StimulusResponse, error) {
+			return h.contentService.GetStimulus(c, req.ID)
+		},
+		http.StatusOK,
+		&stimulus.GetStimulusRequest{},
+	)(c)
+}
+
+// CreateStimulus godoc
+// @Summary Create a stimulus
+// @Description Create a shared reading passage, table or figure from Markdown + LaTeX source
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param request body stimulus.CreateStimulusRequest true "Stimulus"
+// @Success 201 {object} stimulus.StimulusResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Router /editor/stimuli [post]
+func (h *ContentHandler) CreateStimulus(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *stimulus.CreateStimulusRequest) (*stimulus.StimulusResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.contentService.CreateStimulus(c, userID, req)
+		},
+		http.StatusCreated,
+		&stimulus.CreateStimulusRequest{},
+	)(c)
+}
+
+// UpdateStimulus godoc
+// @Summary Revise a stimulus
+// @Description Update a stimulus title or content; every linked question shows the new version
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param id path string true "Stimulus ID"
+// @Param request body stimulus.UpdateStimulusRequest true "Changes"
+// @Success 200 {object} stimulus.StimulusResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /editor/stimuli/{id} [patch]
+func (h *ContentHandler) UpdateStimulus(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *stimulus.UpdateStimulusRequest) (*stimulus.StimulusResponse, error) {
+			return h.contentService.UpdateStimulus(c, req)
+		},
+		http.StatusOK,
+		&stimulus.UpdateStimulusRequest{},
+	)(c)
+}
+
+// UpdateQuestionContent godoc
+// @Summary Set question content
+// @Description Replace a question's text and options with Markdown + LaTeX source, and link or unlink a stimulus from the same section. Content is sanitized; forbidden LaTeX commands, unclosed math, external images and unknown media are refused with INVALID_CONTENT.
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param id path string true "Question ID"
+// @Param request body question.UpdateQuestionContentRequest true "Content"
+// @Success 200 {object} question.QuestionDetailResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /editor/questions/{id}/content [put]
+func (h *ContentHandler) UpdateQuestionContent(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *question.UpdateQuestionContentRequest) (*question.QuestionDetailResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.contentService.UpdateQuestionContent(c, userID, req)
+		},
+		http.StatusOK,
+		&question.UpdateQuestionContentRequest{},
+	)(c)
+}
+
+// PreviewContent godoc
+// @Summary Preview content
+// @Description Sanitize and render Markdown + LaTeX source without saving it, listing the problems that would make saving it fail
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param request body question.PreviewContentRequest true "Content source"
+// @Success 200 {object} question.PreviewContentResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Router /editor/content/preview [post]
+func (h *ContentHandler) PreviewContent(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *question.PreviewContentRequest) (*question.PreviewContentResponse, error) {
+			return h.contentService.PreviewContent(c, req)
+		},
+		http.StatusOK,
+		&question.PreviewContentRequest{},
+	)(c)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model"
+	"github.com/manikandareas/genta/internal/model/duplicate"
+	"github.com/manikandareas/genta/internal/server"
+	"github.com/manikandareas/genta/internal/service"
+)
+
+type DuplicateHandler struct {
+	Handler
+	duplicateService *service.DuplicateService
+}
+
+func NewDuplicateHandler(s *server.Server, duplicateService *service.DuplicateService) *DuplicateHandler {
+	return &DuplicateHandler{
+		Handler:          NewHandler(s),
+		duplicateService: duplicateService,
+	}
+}
+
+// ScanDuplicates godoc
+// @Summary Scan for duplicate questions
+// @Description Queue a job that clusters likely duplicate questions by trigram similarity of text and options. Pending clusters are rebuilt; reviewed ones are kept.
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param request body duplicate.ScanRequest false "Section and similarity threshold (default 0.8)"
+// @Success 202 {object} duplicate.ScanJobResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Router /editor/duplicates/scan [post]
+func (h *DuplicateHandler) ScanDuplicates(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *duplicate.ScanRequest) (*duplicate.ScanJobResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.duplicateService.Scan(c, userID, req)
+		},
+		http.StatusAccepted,
+		&duplicate.ScanRequest{},
+	)(c)
+}
+
+// ListDuplicateClusters godoc
+// @Summary List duplicate clusters
+// @Description List clusters of likely duplicate questions with the questions to compare
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param status query string false "Cluster status (pending, merged, deactivated, dismissed)" default(pending)
+// @Param section query string false "Section filter (PU, PPU, PBM, PK, LBI, LBE, PM)"
+// @Param page query int false "Page number (legacy offset pagination)"
+// @Param cursor query string false "Cursor from a previous page's nextCursor or prevCursor"
+// @Param include_total query bool false "Also count all matching items"
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} model.PaginatedResponse[duplicate.ClusterResponse]
+// @Failure 403 {object} errs.HTTPError
+// @Router /editor/duplicates [get]
+func (h *DuplicateHandler) ListDuplicateClusters(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *duplicate.ListClustersRequest) (*model.PaginatedResponse[duplicate.ClusterResponse], error) {
+			return h.duplicateService.ListClusters(c, req)
+		},
+		http.StatusOK,
+		&duplicate.ListClustersRequest{},
+	)(c)
+}
+
+// GetDuplicateCluster godoc
+// @Summary Get a duplicate cluster
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param id path string true "Cluster ID"
+// @Success 200 {object} duplicate.ClusterResponse
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /editor/duplicates/{id} [get]
+func (h *DuplicateHandler) GetDuplicateCluster(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *duplicate.GetClusterRequest) (*duplicate.ClusterResponse, error) {
+			return h.duplicateService.GetCluster(c, req.ID)
+		},
+		http.StatusOK,
+		&duplicate.GetClusterRequest{},
+	)(c)
+}
+
+// ResolveDuplicateCluster godoc
+// @Summary Resolve a duplicate cluster
+// @Description Merge (keep one question, remove the rest), deactivate chosen questions, or dismiss the cluster as not duplicates
+// @Tags editor
+// @Accept json
+// @Produce json
+// @Param id path string true "Cluster ID"
+// @Param request body duplicate.ResolveClusterRequest true "Decision"
+// @Success 200 {object} duplicate.ClusterResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /editor/duplicates/{id}/resolve [post]
+func (h *DuplicateHandler) ResolveDuplicateCluster(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *duplicate.ResolveClusterRequest) (*duplicate.ClusterResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.duplicateService.ResolveCluster(c, userID, req)
+		},
+		http.StatusOK,
+		&duplicate.ResolveClusterRequest{},
+	)(c)
+}
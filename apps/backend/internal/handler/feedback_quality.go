@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/model"
+	"github.com/manikandareas/genta/internal/model/attempt"
+	"github.com/manikandareas/genta/internal/server"
+	"github.com/manikandareas/genta/internal/service"
+)
+
+type FeedbackQualityHandler struct {
+	Handler
+	feedbackQualityService *service.FeedbackQualityService
+}
+
+func NewFeedbackQualityHandler(s *server.Server, feedbackQualityService *service.FeedbackQualityService) *FeedbackQualityHandler {
+	return &FeedbackQualityHandler{
+		Handler:                NewHandler(s),
+		feedbackQualityService: feedbackQualityService,
+	}
+}
+
+// ListFlagged godoc
+// @Summary List flagged feedback
+// @Description List generated feedback flagged as low quality by automated evaluation (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number (legacy offset pagination)"
+// @Param cursor query string false "Cursor from a previous page's nextCursor or prevCursor"
+// @Param include_total query bool false "Also count all matching items"
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} model.PaginatedResponse[attempt.FlaggedFeedbackResponse]
+// @Failure 401 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Router /admin/feedback/flagged [get]
+func (h *FeedbackQualityHandler) ListFlagged(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *attempt.ListFlaggedFeedbackRequest) (*model.PaginatedResponse[attempt.FlaggedFeedbackResponse], error) {
+			return h.feedbackQualityService.ListFlagged(c, req)
+		},
+		http.StatusOK,
+		&attempt.ListFlaggedFeedbackRequest{},
+	)(c)
+}
+
+// Review godoc
+// @Summary Review flagged feedback
+// @Description Accept flagged feedback as is (ok) or queue a regeneration that replaces it (regenerate)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Feedback ID"
+// @Param request body attempt.ReviewFeedbackRequest true "Review action"
+// @Success 200 {object} attempt.ReviewFeedbackResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /admin/feedback/{id}/review [post]
+func (h *FeedbackQualityHandler) Review(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *attempt.ReviewFeedbackRequest) (*attempt.ReviewFeedbackResponse, error) {
+			return h.feedbackQualityService.Review(c, req)
+		},
+		http.StatusOK,
+		&attempt.ReviewFeedbackRequest{},
+	)(c)
+}
@@ -6,29 +6,53 @@ import (
 )
 
 type Handlers struct {
-	Info      *InfoHandler
-	Health    *HealthHandler
-	OpenAPI   *OpenAPIHandler
-	User      *UserHandler
-	Question  *QuestionHandler
-	Attempt   *AttemptHandler
-	Session   *SessionHandler
-	Readiness *ReadinessHandler
-	Analytics *AnalyticsHandler
-	Job       *JobHandler
+	Info            *InfoHandler
+	Health          *HealthHandler
+	OpenAPI         *OpenAPIHandler
+	User            *UserHandler
+	Question        *QuestionHandler
+	Attempt         *AttemptHandler
+	Session         *SessionHandler
+	Readiness       *ReadinessHandler
+	Analytics       *AnalyticsHandler
+	Job             *JobHandler
+	Usage           *UsageHandler
+	Authoring       *AuthoringHandler
+	FeedbackQuality *FeedbackQualityHandler
+	Calibration     *CalibrationHandler
+	Admission       *AdmissionHandler
+	StudyPlan       *StudyPlanHandler
+	Leaderboard     *LeaderboardHandler
+	Achievement     *AchievementHandler
+	Duplicate       *DuplicateHandler
+	Concept         *ConceptHandler
+	Content         *ContentHandler
+	Storage         *StorageHandler
 }
 
 func NewHandlers(s *server.Server, services *service.Services) *Handlers {
 	return &Handlers{
-		Info:      NewInfoHandler(s),
-		Health:    NewHealthHandler(s),
-		OpenAPI:   NewOpenAPIHandler(s),
-		User:      NewUserHandler(s, services.User),
-		Question:  NewQuestionHandler(s, services.Question),
-		Attempt:   NewAttemptHandler(s, services.Attempt),
-		Session:   NewSessionHandler(s, services.Session),
-		Readiness: NewReadinessHandler(s, services.Readiness),
-		Analytics: NewAnalyticsHandler(s, services.Analytics),
-		Job:       NewJobHandler(s, services.Job),
+		Info:            NewInfoHandler(s),
+		Health:          NewHealthHandler(s),
+		OpenAPI:         NewOpenAPIHandler(s),
+		User:            NewUserHandler(s, services.User),
+		Question:        NewQuestionHandler(s, services.Question),
+		Attempt:         NewAttemptHandler(s, services.Attempt),
+		Session:         NewSessionHandler(s, services.Session),
+		Readiness:       NewReadinessHandler(s, services.Readiness),
+		Analytics:       NewAnalyticsHandler(s, services.Analytics),
+		Job:             NewJobHandler(s, services.Job),
+		Usage:           NewUsageHandler(s, services.Usage),
+		Authoring:       NewAuthoringHandler(s, services.Authoring),
+		FeedbackQuality: NewFeedbackQualityHandler(s, services.FeedbackQuality),
+		Calibration:     NewCalibrationHandler(s, services.Calibration),
+		Admission:       NewAdmissionHandler(s, services.Admission),
+		StudyPlan:       NewStudyPlanHandler(s, services.StudyPlan),
+		Leaderboard:     NewLeaderboardHandler(s, services.Leaderboard),
+		Achievement:     NewAchievementHandler(s, services.Achievement),
+		Duplicate:       NewDuplicateHandler(s, services.Duplicate),
+		Concept:         NewConceptHandler(s, services.Concept),
+		Content:         NewContentHandler(s, services.Content),
+		Storage:         NewStorageHandler(s, services.Storage),
 	}
 }
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/leaderboard"
+	"github.com/manikandareas/genta/internal/server"
+	"github.com/manikandareas/genta/internal/service"
+)
+
+type LeaderboardHandler struct {
+	Handler
+	leaderboardService *service.LeaderboardService
+}
+
+func NewLeaderboardHandler(s *server.Server, leaderboardService *service.LeaderboardService) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		Handler:            NewHandler(s),
+		leaderboardService: leaderboardService,
+	}
+}
+
+// GetLeaderboard godoc
+// @Summary Get section leaderboard
+// @Description Get a page of the weekly or all-time leaderboard for a section, ranked by theta gained
+// @Description over the season plus answer volume. Without an offset the page is centered on the caller.
+// @Tags leaderboard
+// @Accept json
+// @Produce json
+// @Param section path string true "Section code (PU, PPU, PBM, PK, LBI, LBE, PM)"
+// @Param season query string false "Season (weekly, all_time)" default(weekly)
+// @Param week query string false "ISO week of a past weekly season (e.g. 2026-W42), defaults to the current week"
+// @Param offset query int false "Zero-based rank to start the page at; centered on the caller when empty"
+// @Param limit query int false "Page size (1-100)" default(20)
+// @Success 200 {object} leaderboard.LeaderboardResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /leaderboards/{section} [get]
+func (h *LeaderboardHandler) GetLeaderboard(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *leaderboard.GetLeaderboardRequest) (*leaderboard.LeaderboardResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.leaderboardService.Get(c, userID, req)
+		},
+		http.StatusOK,
+		&leaderboard.GetLeaderboardRequest{},
+	)(c)
+}
+
+// GetLeaderboardSettings godoc
+// @Summary Get leaderboard settings
+// @Description Get the caller's leaderboard opt-out and pseudonym, and the name shown on leaderboards
+// @Tags leaderboard
+// @Accept json
+// @Produce json
+// @Success 200 {object} leaderboard.LeaderboardSettingsResponse
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /leaderboards/settings [get]
+func (h *LeaderboardHandler) GetLeaderboardSettings(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *leaderboard.GetLeaderboardSettingsRequest) (*leaderboard.LeaderboardSettingsResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.leaderboardService.GetSettings(c, userID)
+		},
+		http.StatusOK,
+		&leaderboard.GetLeaderboardSettingsRequest{},
+	)(c)
+}
+
+// UpdateLeaderboardSettings godoc
+// @Summary Update leaderboard settings
+// @Description Opt out of or back into leaderboards, or set a pseudonym (an empty pseudonym clears it)
+// @Tags leaderboard
+// @Accept json
+// @Produce json
+// @Param body body leaderboard.UpdateLeaderboardSettingsRequest true "Settings to change"
+// @Success 200 {object} leaderboard.LeaderboardSettingsResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Failure 409 {object} errs.HTTPError
+// @Router /leaderboards/settings [patch]
+func (h *LeaderboardHandler) UpdateLeaderboardSettings(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *leaderboard.UpdateLeaderboardSettingsRequest) (*leaderboard.LeaderboardSettingsResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.leaderboardService.UpdateSettings(c, userID, req)
+		},
+		http.StatusOK,
+		&leaderboard.UpdateLeaderboardSettingsRequest{},
+	)(c)
+}
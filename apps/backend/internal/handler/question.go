@@ -51,7 +51,11 @@ func (h *QuestionHandler) GetQuestion(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param section query string false "Section filter (PU, PPU, PBM, PK, LBI, LBE, PM)"
-// @Param page query int false "Page number" default(1)
+// @Param q query string false "Text filter matched against question content"
+// @Param page query int false "Page number (offset pagination, the default)" default(1)
+// @Param pagination query string false "Set to cursor to start cursor pagination without a cursor" Enums(offset, cursor)
+// @Param cursor query string false "Cursor from a previous page's nextCursor or prevCursor"
+// @Param include_total query bool false "Also count all matching items"
 // @Param limit query int false "Items per page" default(10)
 // @Success 200 {object} model.PaginatedResponse[question.QuestionResponse]
 // @Router /questions [get]
@@ -66,6 +70,30 @@ func (h *QuestionHandler) ListQuestions(c echo.Context) error {
 	)(c)
 }
 
+// SearchQuestions godoc
+// @Summary Search questions
+// @Description Full-text and fuzzy search over question text, options, related concept and explanations, ranked by relevance with highlighted snippets
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param q query string true "Search text (words, \"phrases\", -excluded words)"
+// @Param section query string false "Section filter (PU, PPU, PBM, PK, LBI, LBE, PM)"
+// @Param sub_type query string false "Question subtype"
+// @Param limit query int false "Max results" default(20)
+// @Success 200 {object} question.SearchQuestionsResponse
+// @Failure 400 {object} errs.HTTPError
+// @Router /questions/search [get]
+func (h *QuestionHandler) SearchQuestions(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *question.SearchQuestionsRequest) (*question.SearchQuestionsResponse, error) {
+			return h.questionService.Search(c, req)
+		},
+		http.StatusOK,
+		&question.SearchQuestionsRequest{},
+	)(c)
+}
+
 // GetNextQuestion godoc
 // @Summary Get next question for practice
 // @Description Get the next question for a user based on section (adaptive learning)
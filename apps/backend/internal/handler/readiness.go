@@ -44,6 +44,56 @@ func (h *ReadinessHandler) GetReadinessOverview(c echo.Context) error {
 	)(c)
 }
 
+// GetReadinessHistory godoc
+// @Summary Get readiness history
+// @Description Get theta and readiness time series per section, using each period's closing values
+// @Tags readiness
+// @Accept json
+// @Produce json
+// @Param section query string false "Section code (PU, PPU, PBM, PK, LBI, LBE, PM); all sections when empty"
+// @Param granularity query string false "Period granularity (day, week)" default(day)
+// @Param days query int false "Days of history" default(90)
+// @Success 200 {object} readiness.ReadinessHistoryResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /readiness/history [get]
+func (h *ReadinessHandler) GetReadinessHistory(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *readiness.GetReadinessHistoryRequest) (*readiness.ReadinessHistoryResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.readinessService.GetHistory(c, userID, req)
+		},
+		http.StatusOK,
+		&readiness.GetReadinessHistoryRequest{},
+	)(c)
+}
+
+// GetReadinessPercentiles godoc
+// @Summary Get readiness percentiles
+// @Description Get anonymized percentile ranks per section and overall against all users, users with the
+// @Description same target PTN and users with an exam in the same month. Ranks are computed nightly and
+// @Description only published for cohorts of at least min_cohort_size users.
+// @Tags readiness
+// @Accept json
+// @Produce json
+// @Success 200 {object} readiness.ReadinessPercentilesResponse
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /readiness/percentiles [get]
+func (h *ReadinessHandler) GetReadinessPercentiles(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *readiness.GetReadinessPercentilesRequest) (*readiness.ReadinessPercentilesResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.readinessService.GetPercentiles(c, userID)
+		},
+		http.StatusOK,
+		&readiness.GetReadinessPercentilesRequest{},
+	)(c)
+}
+
 // GetSectionReadiness godoc
 // @Summary Get section readiness detail
 // @Description Get detailed readiness for a specific UTBK section including subtype breakdown and trends
@@ -51,6 +101,7 @@ func (h *ReadinessHandler) GetReadinessOverview(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param section path string true "Section code (PU, PPU, PBM, PK, LBI, LBE, PM)"
+// @Param lang query string false "Language of the next-steps message (id, en)" default(id)
 // @Success 200 {object} readiness.SectionDetailResponse
 // @Failure 400 {object} errs.HTTPError
 // @Failure 401 {object} errs.HTTPError
@@ -61,7 +112,7 @@ func (h *ReadinessHandler) GetSectionReadiness(c echo.Context) error {
 		h.Handler,
 		func(c echo.Context, req *readiness.GetSectionReadinessRequest) (*readiness.SectionDetailResponse, error) {
 			userID := middleware.GetUserID(c)
-			return h.readinessService.GetBySection(c, userID, req.Section)
+			return h.readinessService.GetBySection(c, userID, req)
 		},
 		http.StatusOK,
 		&readiness.GetSectionReadinessRequest{},
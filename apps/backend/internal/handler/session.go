@@ -52,7 +52,10 @@ func (h *SessionHandler) CreateSession(c echo.Context) error {
 // @Tags sessions
 // @Accept json
 // @Produce json
-// @Param page query int false "Page number" default(1)
+// @Param page query int false "Page number (offset pagination, the default)" default(1)
+// @Param pagination query string false "Set to cursor to start cursor pagination without a cursor" Enums(offset, cursor)
+// @Param cursor query string false "Cursor from a previous page's nextCursor or prevCursor"
+// @Param include_total query bool false "Also count all matching items"
 // @Param limit query int false "Items per page" default(10)
 // @Success 200 {object} model.PaginatedResponse[session.SessionResponse]
 // @Failure 401 {object} errs.HTTPError
@@ -115,3 +118,51 @@ func (h *SessionHandler) GetSession(c echo.Context) error {
 		&session.GetSessionRequest{},
 	)(c)
 }
+
+// GetSessionSummary godoc
+// @Summary Get a study session summary
+// @Description Get a session report listing every attempt in order with correctness, time, theta change, correct answer and feedback status, plus section and question type breakdowns
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param session_id path string true "Session ID"
+// @Success 200 {object} session.SessionSummaryResponse
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /sessions/{session_id}/summary [get]
+func (h *SessionHandler) GetSessionSummary(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *session.GetSessionSummaryRequest) (*session.SessionSummaryResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.sessionService.GetSummary(c, userID, req)
+		},
+		http.StatusOK,
+		&session.GetSessionSummaryRequest{},
+	)(c)
+}
+
+// DownloadSessionSummary godoc
+// @Summary Download a study session summary as PDF
+// @Description Download the session report as a PDF to review or share with a tutor
+// @Tags sessions
+// @Produce application/pdf
+// @Param session_id path string true "Session ID"
+// @Param lang query string false "Label language (id, en)" default(id)
+// @Success 200 {file} file
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /sessions/{session_id}/summary/pdf [get]
+func (h *SessionHandler) DownloadSessionSummary(c echo.Context) error {
+	return HandleFile(
+		h.Handler,
+		func(c echo.Context, req *session.GetSessionSummaryRequest) ([]byte, error) {
+			userID := middleware.GetUserID(c)
+			return h.sessionService.GetSummaryPDF(c, userID, req)
+		},
+		http.StatusOK,
+		&session.GetSessionSummaryRequest{},
+		"session-summary.pdf",
+		"application/pdf",
+	)(c)
+}
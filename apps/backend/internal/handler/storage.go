@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/model/media"
+	"github.com/manikandareas/genta/internal/server"
+	"github.com/manikandareas/genta/internal/service"
+)
+
+type StorageHandler struct {
+	Handler
+	storageService *service.StorageService
+}
+
+func NewStorageHandler(s *server.Server, storageService *service.StorageService) *StorageHandler {
+	return &StorageHandler{
+		Handler:        NewHandler(s),
+		storageService: storageService,
+	}
+}
+
+// GetObject godoc
+// @Summary Download a stored object
+// @Description Serve an object through a presigned URL. Only used with the filesystem and memory storage backends; S3 URLs point at the bucket.
+// @Tags storage
+// @Produce octet-stream
+// @Param key path string true "Object key"
+// @Param expires query int true "Expiry as a Unix timestamp"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /storage/objects/{key} [get]
+func (h *StorageHandler) GetObject(c echo.Context) error {
+	return HandleStream(
+		h.Handler,
+		func(c echo.Context, req *media.SignedDownloadRequest) (*Stream, error) {
+			body, obj, err := h.storageService.OpenSigned(c, req)
+			if err != nil {
+				return nil, err
+			}
+
+			return &Stream{
+				Reader:      body,
+				ContentType: obj.ContentType,
+				Size:        obj.Size,
+				Headers:     map[string]string{echo.HeaderCacheControl: "private, max-age=600"},
+			}, nil
+		},
+		http.StatusOK,
+		&media.SignedDownloadRequest{},
+	)(c)
+}
+
+// Upload godoc
+// @Summary Upload with a presigned form
+// @Description Store a file sent with the fields of a presigned upload. Only used with the filesystem and memory storage backends; with S3 the form is posted to the bucket.
+// @Tags storage
+// @Accept multipart/form-data
+// @Param key formData string true "Object key"
+// @Param content_type formData string true "Content type"
+// @Param max_bytes formData int true "Maximum size in bytes"
+// @Param expires formData int true "Expiry as a Unix timestamp"
+// @Param signature formData string true "Form signature"
+// @Param file formData file true "File"
+// @Success 204
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Router /storage/uploads [post]
+func (h *StorageHandler) Upload(c echo.Context) error {
+	return HandleNoContent(
+		h.Handler,
+		func(c echo.Context, req *media.SignedUploadRequest) error {
+			fileHeader, err := c.FormFile("file")
+			if err != nil {
+				return errs.NewBadRequestError("file is required", false, nil, nil, nil)
+			}
+
+			file, err := fileHeader.Open()
+			if err != nil {
+				return errs.NewBadRequestError("failed to read file", false, nil, nil, nil)
+			}
+			defer file.Close()
+
+			return h.storageService.AcceptSignedUpload(c, req, file, fileHeader.Size)
+		},
+		http.StatusNoContent,
+		&media.SignedUploadRequest{},
+	)(c)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/studyplan"
+	"github.com/manikandareas/genta/internal/server"
+	"github.com/manikandareas/genta/internal/service"
+)
+
+type StudyPlanHandler struct {
+	Handler
+	studyPlanService *service.StudyPlanService
+}
+
+func NewStudyPlanHandler(s *server.Server, studyPlanService *service.StudyPlanService) *StudyPlanHandler {
+	return &StudyPlanHandler{
+		Handler:          NewHandler(s),
+		studyPlanService: studyPlanService,
+	}
+}
+
+// GetStudyPlan godoc
+// @Summary Get study plan
+// @Description Get the active day-by-day study plan with progress. The plan is generated on first use
+// @Description and regenerated when it expires, the exam date or study hours change, or the user falls behind.
+// @Tags study-plan
+// @Accept json
+// @Produce json
+// @Success 200 {object} studyplan.StudyPlanResponse
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /study-plan [get]
+func (h *StudyPlanHandler) GetStudyPlan(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *studyplan.GetStudyPlanRequest) (*studyplan.StudyPlanResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.studyPlanService.GetActive(c, userID)
+		},
+		http.StatusOK,
+		&studyplan.GetStudyPlanRequest{},
+	)(c)
+}
+
+// GenerateStudyPlan godoc
+// @Summary Regenerate study plan
+// @Description Replace the active study plan with a fresh one from current readiness
+// @Tags study-plan
+// @Accept json
+// @Produce json
+// @Param body body studyplan.GenerateStudyPlanRequest false "Plan options"
+// @Success 201 {object} studyplan.StudyPlanResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 401 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /study-plan [post]
+func (h *StudyPlanHandler) GenerateStudyPlan(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *studyplan.GenerateStudyPlanRequest) (*studyplan.StudyPlanResponse, error) {
+			userID := middleware.GetUserID(c)
+			return h.studyPlanService.Generate(c, userID, req)
+		},
+		http.StatusCreated,
+		&studyplan.GenerateStudyPlanRequest{},
+	)(c)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/model/usage"
+	"github.com/manikandareas/genta/internal/server"
+	"github.com/manikandareas/genta/internal/service"
+)
+
+type UsageHandler struct {
+	Handler
+	usageService *service.UsageService
+}
+
+func NewUsageHandler(s *server.Server, usageService *service.UsageService) *UsageHandler {
+	return &UsageHandler{
+		Handler:      NewHandler(s),
+		usageService: usageService,
+	}
+}
+
+// GetReport godoc
+// @Summary Get LLM usage report
+// @Description Get LLM token usage and spend grouped by day, model or user (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to start of month"
+// @Param to query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param group_by query string false "Group by (day, model, user); system spend groups under its purpose, e.g. feedback_judge" default(day)
+// @Param limit query int false "Max rows" default(100)
+// @Success 200 {object} usage.UsageReportResponse
+// @Failure 400 {object} errs.HTTPError
+// @Failure 401 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Router /admin/llm-usage [get]
+func (h *UsageHandler) GetReport(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, req *usage.GetUsageReportRequest) (*usage.UsageReportResponse, error) {
+			return h.usageService.GetReport(c, req)
+		},
+		http.StatusOK,
+		&usage.GetUsageReportRequest{},
+	)(c)
+}
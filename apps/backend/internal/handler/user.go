@@ -58,3 +58,27 @@ func (h *UserHandler) CompleteOnboarding(c echo.Context) error {
 		&user.CompleteOnboardingRequest{},
 	)(c)
 }
+
+// UpdateSubscription godoc
+// @Summary Set a user's subscription
+// @Description Set a user's subscription tier. Moving onto a paid tier starts a subscription that runs until endDate, or indefinitely without one; the free tier ends the current subscription.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body user.UpdateSubscriptionRequest true "Subscription"
+// @Success 200 {object} user.User
+// @Failure 400 {object} errs.HTTPError
+// @Failure 403 {object} errs.HTTPError
+// @Failure 404 {object} errs.HTTPError
+// @Router /admin/users/{id}/subscription [put]
+func (h *UserHandler) UpdateSubscription(c echo.Context) error {
+	return Handle(
+		h.Handler,
+		func(c echo.Context, request *user.UpdateSubscriptionRequest) (*user.User, error) {
+			return h.userService.UpdateSubscription(c, request)
+		},
+		http.StatusOK,
+		&user.UpdateSubscriptionRequest{},
+	)(c)
+}
@@ -0,0 +1,118 @@
+// Package content parses the Markdown-based source format of questions, options and
+// stimulus passages into a structured document that clients can render without a
+// Markdown or LaTeX parser of their own.
+//
+// The source format is Markdown with three additions:
+//   - inline math between single dollars ($x^2$) and display math between double dollars
+//     ($$\frac{a}{b}$$), which may span lines; a literal dollar is written \$
+//   - images on a line of their own that reference uploaded media: ![alt](media:<asset id>)
+//   - pipe tables with a header delimiter row (| a | b |, then |---|---|)
+package content
+
+import "strings"
+
+// Version is the structured document format version; it changes when block shapes change
+const Version = 1
+
+const (
+	// MediaScheme prefixes asset IDs in image references
+	MediaScheme = "media:"
+	// MediaPath is where the API serves media assets by ID
+	MediaPath = "/api/v1/media/"
+)
+
+type BlockType string
+
+const (
+	BlockParagraph BlockType = "paragraph"
+	BlockMath      BlockType = "math"
+	BlockImage     BlockType = "image"
+	BlockTable     BlockType = "table"
+)
+
+type SpanType string
+
+const (
+	// SpanText is inline Markdown (emphasis, links) without math
+	SpanText SpanType = "text"
+	// SpanMath is inline LaTeX
+	SpanMath SpanType = "math"
+)
+
+// Span is a run of inline content
+type Span struct {
+	Type  SpanType `json:"type"`
+	Value string   `json:"value"`
+}
+
+// Block is one block of a document; which fields are set depends on Type
+type Block struct {
+	Type BlockType `json:"type"`
+
+	// Paragraph
+	Spans []Span `json:"spans,omitempty"`
+
+	// Display math
+	Latex string `json:"latex,omitempty"`
+
+	// Image
+	AssetID string `json:"asset_id,omitempty"`
+	URL     string `json:"url,omitempty"`
+	Alt     string `json:"alt,omitempty"`
+
+	// Table: one span list per cell
+	Header [][]Span   `json:"header,omitempty"`
+	Rows   [][][]Span `json:"rows,omitempty"`
+}
+
+// Document is the structured form of a piece of content
+type Document struct {
+	Version int     `json:"version"`
+	Blocks  []Block `json:"blocks"`
+}
+
+// Rich carries content in both forms: the stored source and the parsed document
+type Rich struct {
+	Raw string   `json:"raw"`
+	Doc Document `json:"doc"`
+}
+
+// Render parses stored content for an API response. Content is sanitized on write, but
+// rendering cleans it again so older rows and bulk-loaded questions are safe to display.
+func Render(raw string) Rich {
+	cleaned := Clean(raw)
+	return Rich{Raw: cleaned, Doc: Parse(cleaned)}
+}
+
+// RenderPtr renders optional content
+func RenderPtr(raw *string) *Rich {
+	if raw == nil {
+		return nil
+	}
+	r := Render(*raw)
+	return &r
+}
+
+// MediaURL is the API path serving an asset
+func MediaURL(assetID string) string {
+	return MediaPath + assetID
+}
+
+// AssetIDs lists the media assets a document references, in order of first use
+func AssetIDs(doc Document) []string {
+	seen := map[string]bool{}
+	ids := []string{}
+	for _, b := range doc.Blocks {
+		if b.Type == BlockImage && !seen[b.AssetID] {
+			seen[b.AssetID] = true
+			ids = append(ids, b.AssetID)
+		}
+	}
+	return ids
+}
+
+// normalizeNewlines turns CRLF and CR line endings into LF
+func normalizeNewlines(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\r", "\n")
+}
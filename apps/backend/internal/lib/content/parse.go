@@ -0,0 +1,246 @@
+package content
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// imageLine is an image reference that makes up a whole line
+	imageLine = regexp.MustCompile(`^!\[([^\]]*)\]\(\s*([^)\s]*)\s*\)$`)
+	// tableDelimiter is the row separating a table header from its body, e.g. |---|:--:|
+	tableDelimiter = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+)
+
+// segment is a run of text or math in content source
+type segment struct {
+	math    bool
+	display bool
+	// text is the segment content without math delimiters
+	text string
+	// source is the segment as written, delimiters included
+	source string
+}
+
+// Parse turns cleaned content source into a document. It never fails: anything it cannot
+// read as math, an image or a table is kept as paragraph text.
+func Parse(src string) Document {
+	lines := strings.Split(normalizeNewlines(src), "\n")
+	doc := Document{Version: Version, Blocks: []Block{}}
+
+	var para []string
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(para, "\n"))
+		para = nil
+		if text != "" {
+			doc.Blocks = append(doc.Blocks, Block{Type: BlockParagraph, Spans: parseInline(text)})
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		switch {
+		case line == "":
+			flush()
+
+		case strings.HasPrefix(line, "$$"):
+			latex, end, ok := readDisplayMath(lines, i)
+			if !ok {
+				para = append(para, lines[i])
+				continue
+			}
+			flush()
+			doc.Blocks = append(doc.Blocks, Block{Type: BlockMath, Latex: latex})
+			i = end
+
+		case imageLine.MatchString(line):
+			flush()
+			m := imageLine.FindStringSubmatch(line)
+			if assetID, ok := mediaAssetID(m[2]); ok {
+				doc.Blocks = append(doc.Blocks, Block{
+					Type:    BlockImage,
+					AssetID: assetID,
+					URL:     MediaURL(assetID),
+					Alt:     strings.TrimSpace(m[1]),
+				})
+			}
+
+		case strings.HasPrefix(line, "|") && i+1 < len(lines) && tableDelimiter.MatchString(strings.TrimSpace(lines[i+1])):
+			flush()
+			header := splitRow(line)
+			table := Block{Type: BlockTable, Header: make([][]Span, len(header)), Rows: [][][]Span{}}
+			for c, cell := range header {
+				table.Header[c] = parseInline(cell)
+			}
+
+			i += 2
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				cells := splitRow(strings.TrimSpace(lines[i]))
+				// Rows are padded or cut to the header width, as in GitHub Flavored Markdown
+				row := make([][]Span, len(header))
+				for c := range row {
+					row[c] = []Span{}
+					if c < len(cells) {
+						row[c] = parseInline(cells[c])
+					}
+				}
+				table.Rows = append(table.Rows, row)
+			}
+			i--
+
+			doc.Blocks = append(doc.Blocks, table)
+
+		default:
+			para = append(para, lines[i])
+		}
+	}
+	flush()
+
+	return doc
+}
+
+// readDisplayMath reads a $$ block starting at line i, returning its LaTeX and the line it
+// ends on. Blocks with text after the closing $$ are left to the paragraph parser.
+func readDisplayMath(lines []string, i int) (string, int, bool) {
+	first := strings.TrimPrefix(strings.TrimSpace(lines[i]), "$$")
+	if idx := strings.Index(first, "$$"); idx >= 0 {
+		if strings.TrimSpace(first[idx+2:]) != "" {
+			return "", i, false
+		}
+		return strings.TrimSpace(first[:idx]), i, true
+	}
+
+	body := []string{first}
+	for j := i + 1; j < len(lines); j++ {
+		if idx := strings.Index(lines[j], "$$"); idx >= 0 {
+			if strings.TrimSpace(lines[j][idx+2:]) != "" {
+				return "", i, false
+			}
+			body = append(body, lines[j][:idx])
+			return strings.TrimSpace(strings.Join(body, "\n")), j, true
+		}
+		body = append(body, lines[j])
+	}
+
+	return "", i, false
+}
+
+// parseInline splits paragraph or cell text into text and math spans
+func parseInline(text string) []Span {
+	segs, _ := splitMath(text)
+
+	spans := []Span{}
+	for _, seg := range segs {
+		if seg.math {
+			if latex := strings.TrimSpace(seg.text); latex != "" {
+				spans = append(spans, Span{Type: SpanMath, Value: latex})
+			}
+			continue
+		}
+		if seg.text != "" {
+			spans = append(spans, Span{Type: SpanText, Value: seg.text})
+		}
+	}
+	return spans
+}
+
+// splitMath splits text into text and math segments. A backslash escapes the next
+// character, and inline math does not run past a blank line. An unclosed delimiter is kept
+// as text, and reported so that Sanitize can reject it.
+func splitMath(s string) ([]segment, bool) {
+	var segs []segment
+	start := 0
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '$':
+			delim := "$"
+			if i+1 < len(s) && s[i+1] == '$' {
+				delim = "$$"
+			}
+
+			end := closingDelimiter(s, i+len(delim), delim)
+			if end < 0 {
+				segs = append(segs, segment{text: s[start:], source: s[start:]})
+				return segs, true
+			}
+
+			if i > start {
+				segs = append(segs, segment{text: s[start:i], source: s[start:i]})
+			}
+			stop := end + len(delim)
+			segs = append(segs, segment{
+				math:    true,
+				display: delim == "$$",
+				text:    s[i+len(delim) : end],
+				source:  s[i:stop],
+			})
+			i = stop - 1
+			start = stop
+		}
+	}
+
+	if start < len(s) {
+		segs = append(segs, segment{text: s[start:], source: s[start:]})
+	}
+	return segs, false
+}
+
+func closingDelimiter(s string, from int, delim string) int {
+	for i := from; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case delim == "$" && strings.HasPrefix(s[i:], "\n\n"):
+			return -1
+		case strings.HasPrefix(s[i:], delim):
+			return i
+		}
+	}
+	return -1
+}
+
+// splitRow splits a table row into cells. Pipes inside math (|x|) and escaped pipes do not
+// split cells.
+func splitRow(line string) []string {
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = strings.TrimSuffix(line, "|")
+	}
+
+	var cells []string
+	inMath := false
+	start := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '$':
+			inMath = !inMath
+		case '|':
+			if !inMath {
+				cells = append(cells, strings.TrimSpace(line[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(cells, strings.TrimSpace(line[start:]))
+}
+
+// mediaAssetID extracts the asset ID from a media:<id> reference
+func mediaAssetID(target string) (string, bool) {
+	id, ok := strings.CutPrefix(target, MediaScheme)
+	if !ok {
+		return "", false
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return "", false
+	}
+	return parsed.String(), true
+}
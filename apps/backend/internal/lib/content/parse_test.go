@@ -0,0 +1,90 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func textSpan(v string) Span { return Span{Type: SpanText, Value: v} }
+func mathSpan(v string) Span { return Span{Type: SpanMath, Value: v} }
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []Block
+	}{
+		{name: "empty", src: "", want: []Block{}},
+		{
+			name: "paragraphs",
+			src:  "baris satu\nbaris dua\n\nparagraf dua",
+			want: []Block{
+				{Type: BlockParagraph, Spans: []Span{textSpan("baris satu\nbaris dua")}},
+				{Type: BlockParagraph, Spans: []Span{textSpan("paragraf dua")}},
+			},
+		},
+		{
+			name: "inline math",
+			src:  `Jika $x^2 = 4$ dan harga \$5`,
+			want: []Block{
+				{Type: BlockParagraph, Spans: []Span{textSpan("Jika "), mathSpan("x^2 = 4"), textSpan(` dan harga \$5`)}},
+			},
+		},
+		{
+			name: "display math on one line",
+			src:  "teks\n$$\\frac{a}{b}$$\nlagi",
+			want: []Block{
+				{Type: BlockParagraph, Spans: []Span{textSpan("teks")}},
+				{Type: BlockMath, Latex: `\frac{a}{b}`},
+				{Type: BlockParagraph, Spans: []Span{textSpan("lagi")}},
+			},
+		},
+		{
+			name: "display math over lines",
+			src:  "$$\na + b\n= c\n$$",
+			want: []Block{{Type: BlockMath, Latex: "a + b\n= c"}},
+		},
+		{
+			name: "image",
+			src:  "![ grafik ](media:" + testAssetID + ")",
+			want: []Block{{Type: BlockImage, AssetID: testAssetID, URL: MediaPath + testAssetID, Alt: "grafik"}},
+		},
+		{
+			name: "image with unknown target is dropped",
+			src:  "![x](https://example.com/a.png)",
+			want: []Block{},
+		},
+		{
+			name: "table",
+			src:  "| x | $|x|$ |\n|---|:-:|\n| 1 | 2 | 3 |\n| 4 |",
+			want: []Block{{
+				Type:   BlockTable,
+				Header: [][]Span{{textSpan("x")}, {mathSpan("|x|")}},
+				Rows: [][][]Span{
+					{{textSpan("1")}, {textSpan("2")}},
+					{{textSpan("4")}, {}},
+				},
+			}},
+		},
+		{
+			name: "pipe line without delimiter is text",
+			src:  "| bukan tabel |",
+			want: []Block{{Type: BlockParagraph, Spans: []Span{textSpan("| bukan tabel |")}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := Parse(tt.src)
+			assert.Equal(t, Version, doc.Version)
+			assert.Equal(t, tt.want, doc.Blocks)
+		})
+	}
+}
+
+func TestRenderCleansRaw(t *testing.T) {
+	r := Render("<script>x</script>[a](javascript:b)\n\n![g](media:" + testAssetID + ")")
+	assert.Equal(t, "xa\n\n![g](media:"+testAssetID+")", r.Raw)
+	assert.Equal(t, []string{testAssetID}, AssetIDs(r.Doc))
+}
@@ -0,0 +1,174 @@
+package content
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Length limits of content source, in characters
+const (
+	MaxQuestionLength    = 10000
+	MaxOptionLength      = 2000
+	MaxExplanationLength = 10000
+	MaxStimulusLength    = 20000
+)
+
+var (
+	htmlComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlTag     = regexp.MustCompile(`</?[a-zA-Z][a-zA-Z0-9-]*(\s[^<>]*)?/?>`)
+	// linkOrImage matches Markdown links and images, allowing one level of parentheses in the
+	// target; group 1 is "!" for images
+	linkOrImage  = regexp.MustCompile(`(!?)\[([^\]]*)\]\(\s*((?:[^()\s]|\([^()]*\))*)[^)]*\)`)
+	latexCommand = regexp.MustCompile(`\\([a-zA-Z]+)`)
+	// autolink matches a CommonMark URI autolink such as <https://example.com>
+	autolink = regexp.MustCompile(`<[a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^<>\s]*>`)
+	// referenceDefinition matches a link reference definition line such as [r]: https://example.com
+	referenceDefinition = regexp.MustCompile(`(?m)^ {0,3}\[[^\]]+\]:.*$`)
+)
+
+// forbiddenLatex are commands renderers must not run: they load files or URLs, emit HTML,
+// or define macros that can hide the others
+var forbiddenLatex = map[string]bool{
+	"href": true, "url": true, "includegraphics": true, "input": true, "include": true,
+	"def": true, "gdef": true, "edef": true, "xdef": true, "let": true, "futurelet": true,
+	"newcommand": true, "renewcommand": true, "providecommand": true, "newenvironment": true,
+	"write": true, "immediate": true, "openout": true, "openin": true, "read": true, "catcode": true,
+	"htmlClass": true, "htmlId": true, "htmlStyle": true, "htmlData": true,
+}
+
+// Clean normalizes content source and removes what must never reach a renderer: control
+// characters, raw HTML, link reference definitions, images that are not uploaded media,
+// and links and autolinks other than http, https and mailto (their text is kept). Math is
+// left untouched.
+func Clean(raw string) string {
+	s := norm.NFC.String(normalizeNewlines(raw))
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, s)
+
+	segs, _ := splitMath(s)
+
+	var b strings.Builder
+	for _, seg := range segs {
+		if seg.math {
+			b.WriteString(seg.source)
+			continue
+		}
+		b.WriteString(cleanText(seg.text))
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+func cleanText(s string) string {
+	// Clean until nothing changes, so removing one tag or link cannot join its neighbours
+	// into another, as in <<b>script> or [[x](javascript:a)](javascript:b)
+	for {
+		cleaned := cleanOnce(s)
+		if cleaned == s {
+			return s
+		}
+		s = cleaned
+	}
+}
+
+func cleanOnce(s string) string {
+	s = htmlComment.ReplaceAllString(s, "")
+	s = htmlTag.ReplaceAllString(s, "")
+
+	// Reference links and images would resolve through definitions the checks below never
+	// see, so definitions are dropped and references stay plain text
+	s = referenceDefinition.ReplaceAllString(s, "")
+
+	// Autolinks keep their brackets only with a safe scheme; otherwise the URI is plain text
+	s = autolink.ReplaceAllStringFunc(s, func(match string) string {
+		uri := match[1 : len(match)-1]
+		if safeLink(uri) {
+			return match
+		}
+		return uri
+	})
+
+	return linkOrImage.ReplaceAllStringFunc(s, func(match string) string {
+		m := linkOrImage.FindStringSubmatch(match)
+		if m[1] == "!" {
+			if _, ok := mediaAssetID(m[3]); ok {
+				return match
+			}
+			return ""
+		}
+		if safeLink(m[3]) {
+			return match
+		}
+		return m[2]
+	})
+}
+
+func safeLink(target string) bool {
+	target = strings.ToLower(target)
+	return strings.HasPrefix(target, "https://") || strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "mailto:")
+}
+
+// Sanitize cleans submitted content source and reports the problems cleaning cannot fix
+// without changing what the author meant. Content with problems must be rejected.
+func Sanitize(raw string, maxLength int) (string, []string) {
+	var problems []string
+
+	for _, m := range linkOrImage.FindAllStringSubmatch(raw, -1) {
+		if m[1] == "!" {
+			if _, ok := mediaAssetID(m[3]); !ok {
+				problems = append(problems, "images must reference uploaded media as media:<asset id>")
+				break
+			}
+		}
+	}
+
+	if referenceDefinition.MatchString(raw) {
+		problems = append(problems, "link reference definitions are not supported; write links inline as [text](url)")
+	}
+
+	s := Clean(raw)
+	if s == "" {
+		problems = append(problems, "must not be empty")
+	}
+
+	if n := utf8.RuneCountInString(s); n > maxLength {
+		problems = append(problems, fmt.Sprintf("must be at most %d characters, got %d", maxLength, n))
+	}
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.Contains(line, "![") && linkOrImage.MatchString(line) && !imageLine.MatchString(line) {
+			problems = append(problems, "images must be on a line of their own")
+			break
+		}
+	}
+
+	segs, unclosed := splitMath(s)
+	if unclosed {
+		problems = append(problems, `has an unclosed $ math delimiter (write \$ for a literal dollar sign)`)
+	}
+
+	reported := map[string]bool{}
+	for _, seg := range segs {
+		if !seg.math {
+			continue
+		}
+		for _, m := range latexCommand.FindAllStringSubmatch(seg.text, -1) {
+			if forbiddenLatex[m[1]] && !reported[m[1]] {
+				reported[m[1]] = true
+				problems = append(problems, fmt.Sprintf(`uses the LaTeX command \%s, which is not allowed`, m[1]))
+			}
+		}
+	}
+
+	return s, problems
+}
@@ -0,0 +1,121 @@
+package content
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testAssetID = "3f1c2b7a-8e4d-4c5b-9a6f-1d2e3c4b5a69"
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "plain text", raw: "  Berapakah nilai x?  ", want: "Berapakah nilai x?"},
+		{name: "crlf and control characters", raw: "a\r\nb\rc\x00d\x07", want: "a\nb\ncd"},
+		{name: "html tags and comments", raw: "<b>tebal</b> <!-- catatan --><script>alert(1)</script>", want: "tebal alert(1)"},
+		{name: "nested tags", raw: "<<b>script>alert(1)<</b>/script>", want: "alert(1)"},
+		{name: "safe link kept", raw: "[sumber](https://example.com/a)", want: "[sumber](https://example.com/a)"},
+		{name: "mailto link kept", raw: "[surel](mailto:a@example.com)", want: "[surel](mailto:a@example.com)"},
+		{name: "javascript link keeps text", raw: "[klik](javascript:alert(1))", want: "klik"},
+		{name: "nested unsafe links", raw: "[[x](javascript:a)](javascript:b)", want: "x"},
+		{name: "uppercase scheme", raw: "[klik](JaVaScRiPt:alert(1))", want: "klik"},
+		{name: "safe autolink kept", raw: "<https://example.com>", want: "<https://example.com>"},
+		{name: "unsafe autolink unwrapped", raw: "<javascript:alert(1)>", want: "javascript:alert(1)"},
+		{name: "reference definition dropped", raw: "[klik][r]\n\n[r]: javascript:alert(1)", want: "[klik][r]"},
+		{name: "indented reference definition dropped", raw: "teks\n   [r]: https://example.com", want: "teks"},
+		{name: "media image kept", raw: "![grafik](media:" + testAssetID + ")", want: "![grafik](media:" + testAssetID + ")"},
+		{name: "external image removed", raw: "a ![x](https://evil.example/p.png) b", want: "a  b"},
+		{name: "math untouched", raw: "$a<b>c$ dan <i>x</i>", want: "$a<b>c$ dan x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Clean(tt.raw))
+		})
+	}
+}
+
+func TestCleanLeavesNoUnsafeTargets(t *testing.T) {
+	inputs := []string{
+		"[a](javascript:x)",
+		"[[a](javascript:x)](javascript:y)",
+		"[a](<javascript:x>)",
+		"<javascript:x>",
+		"<vbscript:msgbox(1)>",
+		"[a][r]\n[r]: javascript:x",
+		"[a]\n\n[a]: data:text/html,<script>x</script>",
+		"<scr<script>ipt>x</script>",
+		"![x](javascript:x)",
+	}
+
+	for _, raw := range inputs {
+		t.Run(raw, func(t *testing.T) {
+			cleaned := Clean(raw)
+			assert.NotContains(t, cleaned, "<script")
+			assert.NotRegexp(t, `\]\(\s*<?(javascript|vbscript|data):`, cleaned)
+			assert.NotRegexp(t, `<(javascript|vbscript|data):`, cleaned)
+			assert.False(t, referenceDefinition.MatchString(cleaned), "no reference definitions survive")
+			assert.Equal(t, cleaned, Clean(cleaned), "cleaning is idempotent")
+		})
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name         string
+		raw          string
+		maxLength    int
+		wantProblems []string
+	}{
+		{name: "valid", raw: "Jika $x^2 = 4$, maka x = ...", maxLength: 100},
+		{name: "empty after cleaning", raw: "<b></b>", maxLength: 100, wantProblems: []string{"must not be empty"}},
+		{name: "too long", raw: strings.Repeat("a", 11), maxLength: 10, wantProblems: []string{"must be at most 10 characters, got 11"}},
+		{
+			name:         "external image",
+			raw:          "![x](https://example.com/a.png)\n\nteks",
+			maxLength:    100,
+			wantProblems: []string{"images must reference uploaded media as media:<asset id>"},
+		},
+		{
+			name:         "inline image",
+			raw:          "lihat ![x](media:" + testAssetID + ") ini",
+			maxLength:    200,
+			wantProblems: []string{"images must be on a line of their own"},
+		},
+		{
+			name:         "reference definition",
+			raw:          "[klik][r]\n\n[r]: https://example.com",
+			maxLength:    100,
+			wantProblems: []string{"link reference definitions are not supported; write links inline as [text](url)"},
+		},
+		{
+			name:         "unclosed math",
+			raw:          "harga $5",
+			maxLength:    100,
+			wantProblems: []string{`has an unclosed $ math delimiter (write \$ for a literal dollar sign)`},
+		},
+		{name: "escaped dollar", raw: `harga \$5`, maxLength: 100},
+		{
+			name:      "forbidden latex reported once each",
+			raw:       `$\href{x}{y}$ dan $\def\a{b} \href{z}{w}$`,
+			maxLength: 100,
+			wantProblems: []string{
+				`uses the LaTeX command \href, which is not allowed`,
+				`uses the LaTeX command \def, which is not allowed`,
+			},
+		},
+		{name: "forbidden command outside math is text", raw: `teks \href biasa`, maxLength: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, problems := Sanitize(tt.raw, tt.maxLength)
+			assert.Equal(t, tt.wantProblems, problems)
+		})
+	}
+}
@@ -0,0 +1,425 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/manikandareas/genta/internal/lib/llm"
+	"github.com/manikandareas/genta/internal/model/draft"
+)
+
+// authoringQuestionData holds a question and which enrichable fields it is missing
+type authoringQuestionData struct {
+	llm.AuthoringQuestion
+	Missing []string
+}
+
+func (j *JobService) handleQuestionEnrichmentTask(ctx context.Context, t *asynq.Task) error {
+	var p QuestionEnrichmentPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal question enrichment payload: %w", err)
+	}
+
+	if llmClient == nil || !llmClient.IsConfigured() {
+		j.logger.Warn().Str("question_id", p.QuestionID).Msg("LLM client not configured, skipping question enrichment")
+		return nil
+	}
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	taskID, _ := asynq.GetTaskID(ctx)
+	if done, err := j.draftsSavedByTask(ctx, taskID); err != nil {
+		return fmt.Errorf("failed to check saved drafts: %w", err)
+	} else if done {
+		j.logger.Info().Str("question_id", p.QuestionID).Str("task_id", taskID).Msg("Enrichment draft already saved, skipping")
+		return nil
+	}
+
+	// 1. Fetch question and decide which fields to draft
+	q, err := j.fetchAuthoringQuestion(ctx, p.QuestionID)
+	if err != nil {
+		j.logger.Error().Err(err).Str("question_id", p.QuestionID).Msg("Failed to fetch question")
+		return fmt.Errorf("failed to fetch question: %w", err)
+	}
+
+	fields := p.Fields
+	if len(fields) == 0 {
+		fields = q.Missing
+	} else if p.OnlyMissing {
+		fields = slices.DeleteFunc(slices.Clone(fields), func(f string) bool {
+			return !slices.Contains(q.Missing, f)
+		})
+	}
+	if len(fields) == 0 {
+		j.logger.Info().Str("question_id", p.QuestionID).Msg("Question has no missing fields, skipping enrichment")
+		return nil
+	}
+
+	// 2. Generate drafted fields
+	result, err := llmClient.GenerateJSON(ctx, llm.SystemPromptAuthoring(), llm.BuildEnrichmentPrompt(q.AuthoringQuestion, fields))
+	if err != nil {
+		j.logger.Error().Err(err).Str("question_id", p.QuestionID).Msg("Failed to generate enrichment draft")
+		return generationError("enrichment draft", err)
+	}
+
+	var generated draft.Content
+	if err := json.Unmarshal([]byte(result.Text), &generated); err != nil {
+		return fmt.Errorf("failed to parse enrichment draft: %w", err)
+	}
+
+	// Keep only the requested fields
+	content := draft.Content{}
+	if slices.Contains(fields, draft.FieldExplanation) {
+		content.Explanation = nonEmpty(generated.Explanation)
+	}
+	if slices.Contains(fields, draft.FieldExplanationEn) {
+		content.ExplanationEn = nonEmpty(generated.ExplanationEn)
+	}
+	if slices.Contains(fields, draft.FieldStrategyTip) {
+		content.StrategyTip = nonEmpty(generated.StrategyTip)
+	}
+	if slices.Contains(fields, draft.FieldSolutionSteps) && generated.SolutionSteps != nil && len(*generated.SolutionSteps) > 0 {
+		content.SolutionSteps = generated.SolutionSteps
+	}
+	if content.IsEmpty() {
+		return fmt.Errorf("enrichment draft for question %s contained none of the requested fields", p.QuestionID)
+	}
+
+	// 3. Save draft for review and record usage
+	costUSD := j.generationCost(ctx, result)
+	draftID, err := j.saveDraft(ctx, db.Pool, taskID, draft.KindEnrichment, p.QuestionID, content, nil, result, costUSD, p.RequestedBy)
+	if err != nil {
+		j.logger.Error().Err(err).Str("question_id", p.QuestionID).Msg("Failed to save enrichment draft")
+		return fmt.Errorf("failed to save enrichment draft: %w", err)
+	}
+
+	if p.RequestedBy != "" {
+		if err := j.recordUsage(ctx, p.RequestedBy, result.Model, result.TokensInput, result.TokensOutput, costUSD); err != nil {
+			j.logger.Error().Err(err).Str("question_id", p.QuestionID).Msg("Failed to record LLM usage")
+		}
+	}
+
+	j.logger.Info().
+		Str("type", "question_enrichment").
+		Str("question_id", p.QuestionID).
+		Str("draft_id", draftID.String()).
+		Strs("fields", fields).
+		Str("model", result.Model).
+		Float64("cost_usd", costUSD).
+		Msg("Saved enrichment draft for review")
+
+	return nil
+}
+
+func (j *JobService) handleQuestionEnrichmentBatchTask(ctx context.Context, t *asynq.Task) error {
+	var p QuestionEnrichmentBatchPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal question enrichment batch payload: %w", err)
+	}
+
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	fields := p.Fields
+	if len(fields) == 0 {
+		fields = draft.EnrichableFields
+	}
+
+	questionIDs, err := j.fetchQuestionsMissingFields(ctx, p.Section, fields, p.Limit)
+	if err != nil {
+		j.logger.Error().Err(err).Msg("Failed to select questions for enrichment")
+		return fmt.Errorf("failed to select questions for enrichment: %w", err)
+	}
+
+	enqueued := 0
+	for _, questionID := range questionIDs {
+		task, err := NewQuestionEnrichmentTask(questionID, fields, true, p.RequestedBy)
+		if err != nil {
+			j.logger.Warn().Err(err).Str("question_id", questionID).Msg("Failed to create question enrichment task")
+			continue
+		}
+		if _, err := j.Client.EnqueueContext(ctx, task); err != nil {
+			j.logger.Warn().Err(err).Str("question_id", questionID).Msg("Failed to enqueue question enrichment task")
+			continue
+		}
+		enqueued++
+	}
+
+	j.logger.Info().
+		Str("type", "question_enrichment_batch").
+		Str("section", p.Section).
+		Int("selected", len(questionIDs)).
+		Int("enqueued", enqueued).
+		Msg("Enqueued question enrichment tasks")
+
+	return nil
+}
+
+func (j *JobService) handleQuestionVariantsTask(ctx context.Context, t *asynq.Task) error {
+	var p QuestionVariantsPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal question variants payload: %w", err)
+	}
+
+	if llmClient == nil || !llmClient.IsConfigured() {
+		j.logger.Warn().Str("question_id", p.QuestionID).Msg("LLM client not configured, skipping variant generation")
+		return nil
+	}
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	// A retry after the drafts were saved must not generate (and pay for) them again
+	taskID, _ := asynq.GetTaskID(ctx)
+	if done, err := j.draftsSavedByTask(ctx, taskID); err != nil {
+		return fmt.Errorf("failed to check saved drafts: %w", err)
+	} else if done {
+		j.logger.Info().Str("question_id", p.QuestionID).Str("task_id", taskID).Msg("Variant drafts already saved, skipping")
+		return nil
+	}
+
+	// 1. Fetch template question
+	q, err := j.fetchAuthoringQuestion(ctx, p.QuestionID)
+	if err != nil {
+		j.logger.Error().Err(err).Str("question_id", p.QuestionID).Msg("Failed to fetch question")
+		return fmt.Errorf("failed to fetch question: %w", err)
+	}
+
+	// 2. Generate variants
+	result, err := llmClient.GenerateJSON(ctx, llm.SystemPromptAuthoring(), llm.BuildVariantPrompt(q.AuthoringQuestion, p.Count, p.TargetDifficulty))
+	if err != nil {
+		j.logger.Error().Err(err).Str("question_id", p.QuestionID).Msg("Failed to generate variant drafts")
+		return generationError("variant drafts", err)
+	}
+
+	var generated struct {
+		Variants []draft.Content `json:"variants"`
+	}
+	if err := json.Unmarshal([]byte(result.Text), &generated); err != nil {
+		return fmt.Errorf("failed to parse variant drafts: %w", err)
+	}
+
+	// 3. Save each well-formed variant as its own draft; cost is split between them
+	var valid []draft.Content
+	for _, v := range generated.Variants {
+		if v.IsCompleteQuestion() {
+			if q.SubType != "" {
+				subType := q.SubType
+				v.SubType = &subType
+			}
+			valid = append(valid, v)
+		}
+	}
+	if len(valid) == 0 {
+		return fmt.Errorf("variant generation for question %s returned no well-formed questions", p.QuestionID)
+	}
+
+	// All variants are saved together, so a retry finds either all of them or none
+	costUSD := j.generationCost(ctx, result)
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	draftIDs := make([]string, 0, len(valid))
+	for _, v := range valid {
+		draftID, err := j.saveDraft(ctx, tx, taskID, draft.KindVariant, p.QuestionID, v, p.TargetDifficulty, result, costUSD/float64(len(valid)), p.RequestedBy)
+		if err != nil {
+			j.logger.Error().Err(err).Str("question_id", p.QuestionID).Msg("Failed to save variant draft")
+			return fmt.Errorf("failed to save variant draft: %w", err)
+		}
+		draftIDs = append(draftIDs, draftID.String())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit variant drafts: %w", err)
+	}
+
+	if p.RequestedBy != "" {
+		if err := j.recordUsage(ctx, p.RequestedBy, result.Model, result.TokensInput, result.TokensOutput, costUSD); err != nil {
+			j.logger.Error().Err(err).Str("question_id", p.QuestionID).Msg("Failed to record LLM usage")
+		}
+	}
+
+	j.logger.Info().
+		Str("type", "question_variants").
+		Str("question_id", p.QuestionID).
+		Strs("draft_ids", draftIDs).
+		Int("requested", p.Count).
+		Int("discarded", len(generated.Variants)-len(valid)).
+		Str("model", result.Model).
+		Float64("cost_usd", costUSD).
+		Msg("Saved variant drafts for review")
+
+	return nil
+}
+
+func (j *JobService) fetchAuthoringQuestion(ctx context.Context, questionID string) (*authoringQuestionData, error) {
+	var (
+		q                            authoringQuestionData
+		optA, optB, optC, optD, optE string
+		explanationEn, strategyTip   string
+		hasSolutionSteps             bool
+	)
+	err := db.Pool.QueryRow(ctx, `
+		SELECT section, COALESCE(sub_type, ''), text,
+			option_a, option_b, option_c, option_d, option_e, correct_answer,
+			COALESCE(explanation, ''), COALESCE(explanation_en, ''), COALESCE(strategy_tip, ''),
+			solution_steps IS NOT NULL AND jsonb_array_length(solution_steps) > 0,
+			difficulty_irt::FLOAT8
+		FROM questions WHERE id = $1 AND deleted_at IS NULL
+	`, questionID).Scan(
+		&q.Section, &q.SubType, &q.Text,
+		&optA, &optB, &optC, &optD, &optE, &q.CorrectAnswer,
+		&q.Explanation, &explanationEn, &strategyTip,
+		&hasSolutionSteps, &q.DifficultyIRT,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	q.Options = []string{optA, optB, optC, optD, optE}
+
+	if strings.TrimSpace(q.Explanation) == "" {
+		q.Missing = append(q.Missing, draft.FieldExplanation)
+	}
+	if strings.TrimSpace(explanationEn) == "" {
+		q.Missing = append(q.Missing, draft.FieldExplanationEn)
+	}
+	if strings.TrimSpace(strategyTip) == "" {
+		q.Missing = append(q.Missing, draft.FieldStrategyTip)
+	}
+	if !hasSolutionSteps {
+		q.Missing = append(q.Missing, draft.FieldSolutionSteps)
+	}
+
+	return &q, nil
+}
+
+// fetchQuestionsMissingFields selects active questions missing any of the fields that have
+// no pending enrichment draft, most-attempted first
+func (j *JobService) fetchQuestionsMissingFields(ctx context.Context, section string, fields []string, limit int) ([]string, error) {
+	var missing []string
+	for _, field := range fields {
+		switch field {
+		case draft.FieldExplanation:
+			missing = append(missing, "COALESCE(q.explanation, '') = ''")
+		case draft.FieldExplanationEn:
+			missing = append(missing, "COALESCE(q.explanation_en, '') = ''")
+		case draft.FieldStrategyTip:
+			missing = append(missing, "COALESCE(q.strategy_tip, '') = ''")
+		case draft.FieldSolutionSteps:
+			missing = append(missing, "(q.solution_steps IS NULL OR jsonb_array_length(q.solution_steps) = 0)")
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	rows, err := db.Pool.Query(ctx, `
+		SELECT q.id::TEXT
+		FROM questions q
+		WHERE q.deleted_at IS NULL AND q.is_active = true
+			AND ($1 = '' OR q.section = $1)
+			AND (`+strings.Join(missing, " OR ")+`)
+			AND NOT EXISTS (
+				SELECT 1 FROM question_drafts d
+				WHERE d.source_question_id = q.id AND d.kind = 'enrichment' AND d.status = 'pending'
+			)
+		ORDER BY q.attempt_count DESC NULLS LAST, q.created_at
+		LIMIT $2
+	`, section, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// draftExecer is satisfied by both the pool and a transaction
+type draftExecer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// draftsSavedByTask reports whether a task already saved its drafts
+func (j *JobService) draftsSavedByTask(ctx context.Context, taskID string) (bool, error) {
+	if taskID == "" {
+		return false, nil
+	}
+	var exists bool
+	err := db.Pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM question_drafts WHERE task_id = $1)`, taskID).Scan(&exists)
+	return exists, err
+}
+
+func (j *JobService) saveDraft(ctx context.Context, exec draftExecer, taskID string, kind draft.Kind, questionID string, content draft.Content, targetDifficulty *float64, result *llm.GenerationResult, costUSD float64, requestedBy string) (uuid.UUID, error) {
+	contentJSON, err := json.Marshal(content)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to marshal draft content: %w", err)
+	}
+
+	var requester, task *string
+	if requestedBy != "" {
+		requester = &requestedBy
+	}
+	if taskID != "" {
+		task = &taskID
+	}
+
+	draftID := uuid.New()
+	_, err = exec.Exec(ctx, `
+		INSERT INTO question_drafts (
+			id, kind, source_question_id, status, content, target_difficulty,
+			model_used, prompt_version, tokens_input, tokens_output, cost_usd, requested_by, task_id
+		) VALUES ($1, $2, $3, 'pending', $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, draftID, kind, questionID, contentJSON, targetDifficulty,
+		result.Model, llm.AuthoringPromptVersion(), result.TokensInput, result.TokensOutput, costUSD, requester, task)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return draftID, nil
+}
+
+// generationCost returns the USD cost of a generation, or zero when the model has no price
+func (j *JobService) generationCost(ctx context.Context, result *llm.GenerationResult) float64 {
+	price, err := j.fetchModelPrice(ctx, result.Model)
+	if err != nil {
+		j.logger.Warn().Err(err).Str("model", result.Model).Msg("No price found for model, recording zero cost")
+		return 0
+	}
+	return price.Cost(result.TokensInput, result.TokensOutput)
+}
+
+// generationError wraps a failed authoring generation. Retrying is pointless once the
+// spend budget is used up, so those failures skip retry and the editor asks again later.
+func generationError(what string, err error) error {
+	if errors.Is(err, llm.ErrBudgetExceeded) {
+		return fmt.Errorf("failed to generate %s: %w: %w", what, err, asynq.SkipRetry)
+	}
+	return fmt.Errorf("failed to generate %s: %w", what, err)
+}
+
+func nonEmpty(s *string) *string {
+	if s == nil || strings.TrimSpace(*s) == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,99 @@
+package job
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskQuestionEnrichment      = "authoring:question_enrichment"
+	TaskQuestionEnrichmentBatch = "authoring:question_enrichment_batch"
+	TaskQuestionVariants        = "authoring:question_variants"
+)
+
+// QuestionEnrichmentPayload contains data needed to draft missing fields of a question
+type QuestionEnrichmentPayload struct {
+	QuestionID string   `json:"question_id"`
+	Fields     []string `json:"fields,omitempty"`
+	// OnlyMissing restricts Fields to those the question is missing (used by batch runs)
+	OnlyMissing bool   `json:"only_missing,omitempty"`
+	RequestedBy string `json:"requested_by"`
+}
+
+// QuestionEnrichmentBatchPayload selects questions missing fields and drafts them one task each
+type QuestionEnrichmentBatchPayload struct {
+	Section     string   `json:"section,omitempty"`
+	Fields      []string `json:"fields,omitempty"`
+	Limit       int      `json:"limit"`
+	RequestedBy string   `json:"requested_by"`
+}
+
+// QuestionVariantsPayload contains data needed to draft variant questions from a template
+type QuestionVariantsPayload struct {
+	QuestionID       string   `json:"question_id"`
+	Count            int      `json:"count"`
+	TargetDifficulty *float64 `json:"target_difficulty,omitempty"`
+	RequestedBy      string   `json:"requested_by"`
+}
+
+// NewQuestionEnrichmentTask creates a task that drafts missing fields of a question
+func NewQuestionEnrichmentTask(questionID string, fields []string, onlyMissing bool, requestedBy string) (*asynq.Task, error) {
+	payload, err := json.Marshal(QuestionEnrichmentPayload{
+		QuestionID:  questionID,
+		Fields:      fields,
+		OnlyMissing: onlyMissing,
+		RequestedBy: requestedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskQuestionEnrichment, payload,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(3*time.Minute),
+		asynq.Retention(24*time.Hour),
+	), nil
+}
+
+// NewQuestionEnrichmentBatchTask creates a task that fans out enrichment tasks
+func NewQuestionEnrichmentBatchTask(section string, fields []string, limit int, requestedBy string) (*asynq.Task, error) {
+	payload, err := json.Marshal(QuestionEnrichmentBatchPayload{
+		Section:     section,
+		Fields:      fields,
+		Limit:       limit,
+		RequestedBy: requestedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskQuestionEnrichmentBatch, payload,
+		asynq.MaxRetry(1),
+		asynq.Queue("low"),
+		asynq.Timeout(2*time.Minute),
+		asynq.Retention(24*time.Hour),
+	), nil
+}
+
+// NewQuestionVariantsTask creates a task that drafts variant questions from a template
+func NewQuestionVariantsTask(questionID string, count int, targetDifficulty *float64, requestedBy string) (*asynq.Task, error) {
+	payload, err := json.Marshal(QuestionVariantsPayload{
+		QuestionID:       questionID,
+		Count:            count,
+		TargetDifficulty: targetDifficulty,
+		RequestedBy:      requestedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskQuestionVariants, payload,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(3*time.Minute),
+		asynq.Retention(24*time.Hour),
+	), nil
+}
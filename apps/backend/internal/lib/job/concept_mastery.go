@@ -0,0 +1,263 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/lib/scoring"
+	"github.com/manikandareas/genta/internal/model/event"
+)
+
+// conceptObservation is one answer to a question tagged with a concept, with the concept's
+// BKT parameters. The question's own guessing parameter overrides the concept's when set.
+type conceptObservation struct {
+	ConceptID string    `db:"concept_id"`
+	PInit     float64   `db:"p_init"`
+	PTransit  float64   `db:"p_transit"`
+	PSlip     float64   `db:"p_slip"`
+	PGuess    float64   `db:"p_guess"`
+	IsCorrect bool      `db:"is_correct"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (o conceptObservation) params() scoring.BKTParams {
+	return scoring.BKTParams{Init: o.PInit, Transit: o.PTransit, Slip: o.PSlip, Guess: o.PGuess}
+}
+
+// lockUserMastery serializes mastery updates and rebuilds of one user
+func lockUserMastery(ctx context.Context, tx pgx.Tx, userID string) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('concept_mastery:' || $1))`, userID); err != nil {
+		return fmt.Errorf("failed to lock concept mastery: %w", err)
+	}
+	return nil
+}
+
+func (j *JobService) handleConceptMasteryTask(ctx context.Context, t *asynq.Task) error {
+	var evt event.Event
+	if err := json.Unmarshal(t.Payload(), &evt); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	var p event.AttemptRecorded
+	if err := evt.Decode(&p); err != nil {
+		return err
+	}
+
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	first, err := markProcessed(ctx, tx, evt.ID.String(), TaskConceptMastery)
+	if err != nil {
+		return err
+	}
+	if !first {
+		return nil
+	}
+
+	if err := lockUserMastery(ctx, tx, p.UserID.String()); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT c.id::TEXT AS concept_id, c.p_init::FLOAT8, c.p_transit::FLOAT8, c.p_slip::FLOAT8,
+			COALESCE(q.guessing_param, c.p_guess)::FLOAT8 AS p_guess,
+			$2::BOOLEAN AS is_correct, $3::TIMESTAMP AS created_at
+		FROM question_concepts qc
+		JOIN concepts c ON c.id = qc.concept_id
+		JOIN questions q ON q.id = qc.question_id
+		WHERE qc.question_id = $1
+	`, p.QuestionID, p.IsCorrect, evt.OccurredAt)
+	if err != nil {
+		return fmt.Errorf("failed to get question concepts: %w", err)
+	}
+
+	observations, err := pgx.CollectRows(rows, pgx.RowToStructByName[conceptObservation])
+	if err != nil {
+		return fmt.Errorf("failed to collect question concepts: %w", err)
+	}
+
+	for _, o := range observations {
+		prior := o.PInit
+		err := tx.QueryRow(ctx, `
+			SELECT p_mastery::FLOAT8 FROM user_concept_mastery WHERE user_id = $1 AND concept_id = $2
+		`, p.UserID, o.ConceptID).Scan(&prior)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("failed to get concept mastery: %w", err)
+		}
+
+		if err := upsertMastery(ctx, tx, p.UserID.String(), o, o.params().UpdateMastery(prior, o.IsCorrect)); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit concept mastery: %w", err)
+	}
+
+	return nil
+}
+
+// upsertMastery stores a concept's mastery after one observed answer
+func upsertMastery(ctx context.Context, tx pgx.Tx, userID string, o conceptObservation, mastery float64) error {
+	correct := 0
+	if o.IsCorrect {
+		correct = 1
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO user_concept_mastery (user_id, concept_id, p_mastery, attempts_count, correct_count, last_attempt_at)
+		VALUES ($1, $2, $3, 1, $4, $5)
+		ON CONFLICT (user_id, concept_id) DO UPDATE SET
+			p_mastery = EXCLUDED.p_mastery,
+			attempts_count = user_concept_mastery.attempts_count + 1,
+			correct_count = user_concept_mastery.correct_count + EXCLUDED.correct_count,
+			last_attempt_at = GREATEST(user_concept_mastery.last_attempt_at, EXCLUDED.last_attempt_at),
+			updated_at = NOW()
+	`, userID, o.ConceptID, mastery, correct, o.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update concept mastery: %w", err)
+	}
+	return nil
+}
+
+// handleConceptMasteryRebuildTask replays a user's attempt history through BKT, replacing
+// their stored mastery. Run it after tagging changes or to backfill existing attempts.
+func (j *JobService) handleConceptMasteryRebuildTask(ctx context.Context, t *asynq.Task) error {
+	var p ConceptMasteryRebuildPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal concept mastery rebuild payload: %w", err)
+	}
+
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	if p.UserID == "" {
+		return j.fanOutConceptMasteryRebuild(ctx)
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockUserMastery(ctx, tx, p.UserID); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT c.id::TEXT AS concept_id, c.p_init::FLOAT8, c.p_transit::FLOAT8, c.p_slip::FLOAT8,
+			COALESCE(q.guessing_param, c.p_guess)::FLOAT8 AS p_guess,
+			a.is_correct, a.created_at
+		FROM attempts a
+		JOIN questions q ON q.id = a.question_id
+		JOIN question_concepts qc ON qc.question_id = a.question_id
+		JOIN concepts c ON c.id = qc.concept_id
+		WHERE a.user_id = $1 AND a.deleted_at IS NULL
+		ORDER BY a.created_at, a.id
+	`, p.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get attempt history: %w", err)
+	}
+
+	observations, err := pgx.CollectRows(rows, pgx.RowToStructByName[conceptObservation])
+	if err != nil {
+		return fmt.Errorf("failed to collect attempt history: %w", err)
+	}
+
+	type replayed struct {
+		mastery  float64
+		attempts int
+		correct  int
+		last     time.Time
+	}
+	byConcept := make(map[string]*replayed)
+	for _, o := range observations {
+		r, ok := byConcept[o.ConceptID]
+		if !ok {
+			r = &replayed{mastery: o.PInit}
+			byConcept[o.ConceptID] = r
+		}
+		r.mastery = o.params().UpdateMastery(r.mastery, o.IsCorrect)
+		r.attempts++
+		if o.IsCorrect {
+			r.correct++
+		}
+		r.last = o.CreatedAt
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_concept_mastery WHERE user_id = $1`, p.UserID); err != nil {
+		return fmt.Errorf("failed to clear concept mastery: %w", err)
+	}
+
+	for conceptID, r := range byConcept {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO user_concept_mastery (user_id, concept_id, p_mastery, attempts_count, correct_count, last_attempt_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, p.UserID, conceptID, r.mastery, r.attempts, r.correct, r.last)
+		if err != nil {
+			return fmt.Errorf("failed to insert concept mastery: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit concept mastery rebuild: %w", err)
+	}
+
+	j.logger.Info().
+		Str("type", "concept_mastery_rebuild").
+		Str("user_id", p.UserID).
+		Int("observations", len(observations)).
+		Int("concepts", len(byConcept)).
+		Msg("Concept mastery rebuilt")
+
+	return nil
+}
+
+// fanOutConceptMasteryRebuild enqueues one rebuild per user with attempts
+func (j *JobService) fanOutConceptMasteryRebuild(ctx context.Context) error {
+	rows, err := db.Pool.Query(ctx, `SELECT DISTINCT user_id::TEXT FROM attempts WHERE deleted_at IS NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to select users with attempts: %w", err)
+	}
+
+	userIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("failed to collect users with attempts: %w", err)
+	}
+
+	enqueued := 0
+	for _, userID := range userIDs {
+		task, err := NewConceptMasteryRebuildTask(userID)
+		if err != nil {
+			j.logger.Warn().Err(err).Str("user_id", userID).Msg("Failed to create concept mastery rebuild task")
+			continue
+		}
+		if _, err := j.Client.EnqueueContext(ctx, task); err != nil {
+			j.logger.Warn().Err(err).Str("user_id", userID).Msg("Failed to enqueue concept mastery rebuild task")
+			continue
+		}
+		enqueued++
+	}
+
+	j.logger.Info().
+		Str("type", "concept_mastery_rebuild_batch").
+		Int("users", len(userIDs)).
+		Int("enqueued", enqueued).
+		Msg("Enqueued concept mastery rebuild tasks")
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package job
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/manikandareas/genta/internal/model/event"
+)
+
+const (
+	TaskConceptMastery        = "event:concept_mastery"
+	TaskConceptMasteryRebuild = "concept:mastery_rebuild"
+)
+
+// ConceptMasteryRebuildPayload selects whose mastery to replay; empty means every user
+// with attempts, one task each
+type ConceptMasteryRebuildPayload struct {
+	UserID string `json:"user_id,omitempty"`
+}
+
+// NewConceptMasteryTask creates a task that folds an attempt into the user's concept mastery
+func NewConceptMasteryTask(evt *event.Event) (*asynq.Task, error) {
+	return newEventTask(TaskConceptMastery, evt,
+		asynq.MaxRetry(10),
+		asynq.Queue("default"),
+		asynq.Timeout(30*time.Second),
+	)
+}
+
+// NewConceptMasteryRebuildTask creates a task that recomputes concept mastery from attempt history
+func NewConceptMasteryRebuildTask(userID string) (*asynq.Task, error) {
+	payload, err := json.Marshal(ConceptMasteryRebuildPayload{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskConceptMasteryRebuild, payload,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(5*time.Minute),
+		asynq.Retention(24*time.Hour),
+	), nil
+}
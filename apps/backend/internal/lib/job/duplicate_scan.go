@@ -0,0 +1,180 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/model/duplicate"
+)
+
+// duplicatePair is two active questions in a section whose fingerprints are similar
+type duplicatePair struct {
+	Section    string  `db:"section"`
+	A          string  `db:"a"`
+	B          string  `db:"b"`
+	Similarity float64 `db:"similarity"`
+}
+
+// handleQuestionDuplicateScanTask rebuilds the pending duplicate clusters. Similar pairs are
+// found with a trigram self-join and joined transitively into clusters. Pairs an editor
+// already reviewed together are skipped, so resolved clusters do not come back.
+func (j *JobService) handleQuestionDuplicateScanTask(ctx context.Context, t *asynq.Task) error {
+	var p QuestionDuplicateScanPayload
+	if len(t.Payload()) > 0 {
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return fmt.Errorf("failed to unmarshal question duplicate scan payload: %w", err)
+		}
+	}
+
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	threshold := p.Threshold
+	if threshold == 0 {
+		threshold = duplicate.DefaultThreshold
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// The % operator uses this threshold, which lets the trigram index prune candidates
+	if _, err := tx.Exec(ctx, `SELECT set_config('pg_trgm.similarity_threshold', $1, true)`, fmt.Sprint(threshold)); err != nil {
+		return fmt.Errorf("failed to set similarity threshold: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT a.section, a.id::TEXT AS a, b.id::TEXT AS b,
+			similarity(a.dedup_text, b.dedup_text)::FLOAT8 AS similarity
+		FROM questions a
+		JOIN questions b ON b.section = a.section AND b.id > a.id AND b.dedup_text % a.dedup_text
+		WHERE a.deleted_at IS NULL AND a.is_active = true
+			AND b.deleted_at IS NULL AND b.is_active = true
+			AND ($1 = '' OR a.section = $1)
+			AND NOT EXISTS (
+				SELECT 1
+				FROM question_duplicate_members ma
+				JOIN question_duplicate_members mb ON mb.cluster_id = ma.cluster_id
+				JOIN question_duplicate_clusters c ON c.id = ma.cluster_id
+				WHERE ma.question_id = a.id AND mb.question_id = b.id AND c.status <> 'pending'
+			)
+	`, p.Section)
+	if err != nil {
+		return fmt.Errorf("failed to find duplicate pairs: %w", err)
+	}
+
+	pairs, err := pgx.CollectRows(rows, pgx.RowToStructByName[duplicatePair])
+	if err != nil {
+		return fmt.Errorf("failed to collect duplicate pairs: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM question_duplicate_clusters
+		WHERE status = 'pending' AND ($1 = '' OR section = $1)
+	`, p.Section); err != nil {
+		return fmt.Errorf("failed to clear pending duplicate clusters: %w", err)
+	}
+
+	clusters := clusterPairs(pairs)
+	for _, c := range clusters {
+		ids := make([]string, 0, len(c.similarity))
+		similarities := make([]float64, 0, len(c.similarity))
+		for id, sim := range c.similarity {
+			ids = append(ids, id)
+			similarities = append(similarities, sim)
+		}
+
+		// The suggested keeper is the most practised question, then the oldest
+		var clusterID string
+		err := tx.QueryRow(ctx, `
+			INSERT INTO question_duplicate_clusters (section, max_similarity, suggested_keep_id)
+			SELECT $1, $2, id FROM questions
+			WHERE id = ANY($3::UUID[])
+			ORDER BY COALESCE(attempt_count, 0) DESC, created_at, id
+			LIMIT 1
+			RETURNING id
+		`, c.section, c.maxSimilarity, ids).Scan(&clusterID)
+		if err != nil {
+			return fmt.Errorf("failed to insert duplicate cluster: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO question_duplicate_members (cluster_id, question_id, similarity)
+			SELECT $1, UNNEST($2::UUID[]), UNNEST($3::DECIMAL[])
+		`, clusterID, ids, similarities); err != nil {
+			return fmt.Errorf("failed to insert duplicate cluster members: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit duplicate clusters: %w", err)
+	}
+
+	j.logger.Info().
+		Str("event", "question_duplicate_scan_completed").
+		Str("section", p.Section).
+		Float64("threshold", threshold).
+		Int("pairs", len(pairs)).
+		Int("clusters", len(clusters)).
+		Str("requested_by", p.RequestedBy).
+		Msg("Question duplicate scan completed")
+
+	return nil
+}
+
+// duplicateCluster is a connected group of similar questions
+type duplicateCluster struct {
+	section       string
+	maxSimilarity float64
+	// similarity holds each member's highest similarity to another member
+	similarity map[string]float64
+}
+
+// clusterPairs joins similar pairs transitively (union-find) into clusters
+func clusterPairs(pairs []duplicatePair) []duplicateCluster {
+	parent := make(map[string]string)
+	var find func(id string) string
+	find = func(id string) string {
+		if parent[id] == id {
+			return id
+		}
+		parent[id] = find(parent[id])
+		return parent[id]
+	}
+
+	for _, p := range pairs {
+		for _, id := range []string{p.A, p.B} {
+			if _, ok := parent[id]; !ok {
+				parent[id] = id
+			}
+		}
+		parent[find(p.A)] = find(p.B)
+	}
+
+	byRoot := make(map[string]*duplicateCluster)
+	var clusters []*duplicateCluster
+	for _, p := range pairs {
+		root := find(p.A)
+		c, ok := byRoot[root]
+		if !ok {
+			c = &duplicateCluster{section: p.Section, similarity: make(map[string]float64)}
+			byRoot[root] = c
+			clusters = append(clusters, c)
+		}
+		c.maxSimilarity = max(c.maxSimilarity, p.Similarity)
+		c.similarity[p.A] = max(c.similarity[p.A], p.Similarity)
+		c.similarity[p.B] = max(c.similarity[p.B], p.Similarity)
+	}
+
+	result := make([]duplicateCluster, len(clusters))
+	for i, c := range clusters {
+		result[i] = *c
+	}
+	return result
+}
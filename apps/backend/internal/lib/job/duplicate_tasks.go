@@ -0,0 +1,41 @@
+package job
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskQuestionDuplicateScan = "authoring:question_duplicate_scan"
+
+	// duplicateScanSchedule rescans the whole bank nightly, after imports settle
+	duplicateScanSchedule = "30 4 * * *"
+)
+
+// QuestionDuplicateScanPayload limits a duplicate scan to a section and sets its threshold
+type QuestionDuplicateScanPayload struct {
+	Section     string  `json:"section,omitempty"`
+	Threshold   float64 `json:"threshold,omitempty"`
+	RequestedBy string  `json:"requested_by,omitempty"`
+}
+
+// NewQuestionDuplicateScanTask creates a task that clusters likely duplicate questions
+func NewQuestionDuplicateScanTask(section string, threshold float64, requestedBy string) (*asynq.Task, error) {
+	payload, err := json.Marshal(QuestionDuplicateScanPayload{
+		Section:     section,
+		Threshold:   threshold,
+		RequestedBy: requestedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskQuestionDuplicateScan, payload,
+		asynq.MaxRetry(1),
+		asynq.Queue("low"),
+		asynq.Timeout(15*time.Minute),
+		asynq.Retention(24*time.Hour),
+	), nil
+}
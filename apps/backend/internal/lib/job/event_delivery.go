@@ -0,0 +1,127 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/manikandareas/genta/internal/model/event"
+)
+
+// TaskEventDelivery delivers an event to a subscriber registered with Subscribe
+const TaskEventDelivery = "event:deliver"
+
+// EventHandlerFunc applies an event's effects that belong to a service, which the job
+// package cannot import
+type EventHandlerFunc func(ctx context.Context, evt *event.Event) error
+
+// eventDeliveryPayload names the subscriber an event is delivered to
+type eventDeliveryPayload struct {
+	Subscriber string      `json:"subscriber"`
+	Event      event.Event `json:"event"`
+}
+
+// Subscribe routes events of type t to fn. Each event is delivered as its own task, so a
+// failure is retried rather than lost, and fn runs at most once per event after it
+// succeeds. Subscribe before StartOutboxRelay, so no event is published without fn.
+func (j *JobService) Subscribe(t event.Type, name string, fn EventHandlerFunc) {
+	j.subscribersMu.Lock()
+	defer j.subscribersMu.Unlock()
+
+	if j.eventHandlers == nil {
+		j.eventHandlers = make(map[string]EventHandlerFunc)
+		j.eventSubscribers = make(map[event.Type][]subscriber)
+	}
+	j.eventHandlers[name] = fn
+	j.eventSubscribers[t] = append(j.eventSubscribers[t], subscriber{
+		name: name,
+		task: func(evt *event.Event) (*asynq.Task, error) {
+			return NewEventDeliveryTask(name, evt)
+		},
+	})
+}
+
+// subscribersFor returns the audit subscriber followed by every subscriber of t
+func (j *JobService) subscribersFor(t event.Type) []subscriber {
+	j.subscribersMu.RLock()
+	defer j.subscribersMu.RUnlock()
+
+	subs := append([]subscriber{auditSubscriber}, subscribers[t]...)
+	return append(subs, j.eventSubscribers[t]...)
+}
+
+// Once runs fn unless it already succeeded for the event under name, for effects that
+// must not be applied twice when a delivery is retried
+func (j *JobService) Once(ctx context.Context, evt *event.Event, name string, fn func() error) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	var done bool
+	err := db.Pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM processed_events WHERE event_id = $1 AND subscriber = $2)
+	`, evt.ID, name).Scan(&done)
+	if err != nil {
+		return fmt.Errorf("failed to check processed event: %w", err)
+	}
+	if done {
+		return nil
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	_, err = db.Pool.Exec(ctx, `
+		INSERT INTO processed_events (event_id, subscriber)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, evt.ID, name)
+	if err != nil {
+		return fmt.Errorf("failed to mark event processed: %w", err)
+	}
+	return nil
+}
+
+// NewEventDeliveryTask creates a task that delivers an event to a registered subscriber
+func NewEventDeliveryTask(subscriberName string, evt *event.Event) (*asynq.Task, error) {
+	payload, err := json.Marshal(eventDeliveryPayload{Subscriber: subscriberName, Event: *evt})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskEventDelivery, payload,
+		asynq.MaxRetry(10),
+		asynq.Queue("default"),
+		asynq.Timeout(time.Minute),
+	), nil
+}
+
+func (j *JobService) handleEventDeliveryTask(ctx context.Context, t *asynq.Task) error {
+	var p eventDeliveryPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal event delivery payload: %w", err)
+	}
+
+	j.subscribersMu.RLock()
+	fn, ok := j.eventHandlers[p.Subscriber]
+	j.subscribersMu.RUnlock()
+	if !ok {
+		// Retried later: the subscriber may not be registered yet during startup
+		return fmt.Errorf("no handler for event subscriber %s", p.Subscriber)
+	}
+
+	if err := j.Once(ctx, &p.Event, p.Subscriber, func() error { return fn(ctx, &p.Event) }); err != nil {
+		j.logger.Warn().
+			Err(err).
+			Str("subscriber", p.Subscriber).
+			Str("event", string(p.Event.Type)).
+			Str("event_id", p.Event.ID.String()).
+			Msg("Event subscriber failed, will retry")
+		return err
+	}
+
+	return nil
+}
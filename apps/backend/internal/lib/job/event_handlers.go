@@ -0,0 +1,123 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/model/event"
+)
+
+// markProcessed records that a subscriber handled an event; it returns false when the
+// event was already handled, so a redelivered task does not apply its effects twice
+func markProcessed(ctx context.Context, tx pgx.Tx, eventID, subscriber string) (bool, error) {
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO processed_events (event_id, subscriber)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, eventID, subscriber)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark event processed: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (j *JobService) handleAttemptStatsTask(ctx context.Context, t *asynq.Task) error {
+	var evt event.Event
+	if err := json.Unmarshal(t.Payload(), &evt); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	var p event.AttemptRecorded
+	if err := evt.Decode(&p); err != nil {
+		return err
+	}
+
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	first, err := markProcessed(ctx, tx, evt.ID.String(), TaskAttemptStats)
+	if err != nil {
+		return err
+	}
+	if !first {
+		return nil
+	}
+
+	correct := 0
+	if p.IsCorrect {
+		correct = 1
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE questions
+		SET
+			attempt_count = COALESCE(attempt_count, 0) + 1,
+			correct_rate = (
+				(COALESCE(correct_rate, 0) * COALESCE(attempt_count, 0)) + @correct
+			) / (COALESCE(attempt_count, 0) + 1),
+			avg_time_seconds = (
+				(COALESCE(avg_time_seconds, 0) * COALESCE(attempt_count, 0)) + @time_spent
+			) / (COALESCE(attempt_count, 0) + 1),
+			updated_at = NOW()
+		WHERE id = @question_id
+	`, pgx.NamedArgs{
+		"question_id": p.QuestionID,
+		"correct":     correct,
+		"time_spent":  p.TimeSpentSeconds,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update question stats: %w", err)
+	}
+
+	// Accuracy is refreshed too, in case the session ended before this event was handled
+	if p.SessionID != nil && *p.SessionID != "" {
+		_, err = tx.Exec(ctx, `
+			UPDATE user_study_sessions
+			SET questions_attempted = questions_attempted + 1,
+				questions_correct = questions_correct + @correct,
+				accuracy_in_session = (questions_correct + @correct)::DECIMAL / (questions_attempted + 1),
+				updated_at = NOW()
+			WHERE id = @session_id AND user_id = @user_id
+		`, pgx.NamedArgs{
+			"session_id": *p.SessionID,
+			"user_id":    p.UserID,
+			"correct":    correct,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update session stats: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit attempt stats: %w", err)
+	}
+
+	return nil
+}
+
+func (j *JobService) handleEventAuditTask(ctx context.Context, t *asynq.Task) error {
+	var evt event.Event
+	if err := json.Unmarshal(t.Payload(), &evt); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	j.logger.Info().
+		Str("event", string(evt.Type)).
+		Str("event_id", evt.ID.String()).
+		Str("aggregate_id", evt.AggregateID).
+		Time("occurred_at", evt.OccurredAt).
+		RawJSON("payload", evt.Payload).
+		Msg("Domain event")
+
+	return nil
+}
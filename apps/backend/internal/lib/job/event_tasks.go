@@ -0,0 +1,62 @@
+package job
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/manikandareas/genta/internal/model/event"
+)
+
+const (
+	TaskAttemptStats = "event:attempt_stats"
+	TaskEventAudit   = "event:audit"
+	TaskOutboxPrune  = "outbox:prune"
+
+	// outboxPruneSchedule removes old published outbox rows at 04:00 server time
+	outboxPruneSchedule = "0 4 * * *"
+)
+
+// FeedbackTaskID is the deterministic Asynq task ID of the feedback generation task for an
+// attempt, so the API can hand out a job ID before the outbox relay has enqueued the task
+func FeedbackTaskID(attemptID string) string {
+	return "feedback:" + attemptID
+}
+
+// newEventTask creates a task whose payload is the full event envelope
+func newEventTask(taskType string, evt *event.Event, opts ...asynq.Option) (*asynq.Task, error) {
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(taskType, payload, opts...), nil
+}
+
+// NewAttemptStatsTask creates a task that folds an attempt into question and session stats
+func NewAttemptStatsTask(evt *event.Event) (*asynq.Task, error) {
+	return newEventTask(TaskAttemptStats, evt,
+		asynq.MaxRetry(10),
+		asynq.Queue("default"),
+		asynq.Timeout(30*time.Second),
+	)
+}
+
+// NewEventAuditTask creates a task that writes a domain event to the audit log
+func NewEventAuditTask(evt *event.Event) (*asynq.Task, error) {
+	return newEventTask(TaskEventAudit, evt,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(30*time.Second),
+	)
+}
+
+// NewOutboxPruneTask creates a task that deletes published outbox rows past retention
+func NewOutboxPruneTask() *asynq.Task {
+	return asynq.NewTask(TaskOutboxPrune, nil,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(10*time.Minute),
+		asynq.Unique(time.Hour),
+	)
+}
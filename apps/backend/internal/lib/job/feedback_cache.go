@@ -0,0 +1,166 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/lib/llm"
+	"github.com/manikandareas/genta/internal/model/attempt"
+)
+
+const (
+	// feedbackClaimTTL is how long a claim on a cache key holds before another worker may
+	// take it over, in case the claiming worker died; it outlasts an LLM call with retries
+	feedbackClaimTTL = 5 * time.Minute
+	// feedbackClaimWait bounds how long tasks wait for another worker's generation before
+	// generating themselves
+	feedbackClaimWait = time.Minute
+	// feedbackClaimRetryDelay is how soon a task that found the key claimed runs again
+	feedbackClaimRetryDelay = 2 * time.Second
+)
+
+// errFeedbackPending is returned by feedback generation while another worker generates
+// canonical feedback for the same pair. The task is retried shortly without counting as a
+// failure, so waiting does not hold a worker.
+var errFeedbackPending = errors.New("canonical feedback is being generated by another worker")
+
+// FeedbackCache reads canonical cached feedback and serves it to attempts. The attempt
+// repository implements it; it is set with SetFeedbackCache because the job package
+// cannot import the repositories.
+type FeedbackCache interface {
+	GetCachedFeedback(ctx context.Context, questionID uuid.UUID, selectedAnswer, lang, promptVersion string) (*attempt.FeedbackCacheEntry, error)
+	CreateFeedbackFromCache(ctx context.Context, attemptID uuid.UUID, entry *attempt.FeedbackCacheEntry) (*attempt.AttemptFeedback, error)
+}
+
+// SetFeedbackCache sets the feedback cache used by feedback generation; without one every
+// attempt is generated
+func (j *JobService) SetFeedbackCache(cache FeedbackCache) {
+	j.feedbackCache = cache
+}
+
+// feedbackCacheKey identifies canonical feedback for a question/answer pair
+func feedbackCacheKey(questionID, selectedAnswer, lang, promptVersion string) string {
+	return fmt.Sprintf("feedback_cache:%s:%s:%s:%s", questionID, selectedAnswer, lang, promptVersion)
+}
+
+// claimFeedbackCacheKey claims the generation of canonical feedback for a cache key, so
+// concurrent misses for the same pair wait for a single LLM generation instead of each
+// calling the LLM. The claim is a row rather than a lock, so no connection is held while
+// the LLM runs. When another worker holds a live claim it returns false and how long that
+// claim has been held.
+func (j *JobService) claimFeedbackCacheKey(ctx context.Context, key string) (bool, time.Duration, error) {
+	tag, err := db.Pool.Exec(ctx, `
+		INSERT INTO feedback_cache_claims (cache_key, claimed_at)
+		VALUES ($1, NOW())
+		ON CONFLICT (cache_key) DO UPDATE SET claimed_at = NOW()
+		WHERE feedback_cache_claims.claimed_at < NOW() - make_interval(secs => $2)
+	`, key, feedbackClaimTTL.Seconds())
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to claim feedback cache key: %w", err)
+	}
+	if tag.RowsAffected() == 1 {
+		return true, 0, nil
+	}
+
+	var heldSeconds float64
+	err = db.Pool.QueryRow(ctx, `
+		SELECT COALESCE(EXTRACT(EPOCH FROM NOW() - MAX(claimed_at)), 0)::FLOAT8
+		FROM feedback_cache_claims WHERE cache_key = $1
+	`, key).Scan(&heldSeconds)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to read feedback cache claim: %w", err)
+	}
+	return false, time.Duration(heldSeconds * float64(time.Second)), nil
+}
+
+// releaseFeedbackCacheKey drops a claim taken with claimFeedbackCacheKey
+func (j *JobService) releaseFeedbackCacheKey(key string) {
+	// Use a fresh context: the task context may already be cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := db.Pool.Exec(ctx, `DELETE FROM feedback_cache_claims WHERE cache_key = $1`, key); err != nil {
+		j.logger.Warn().Err(err).Str("key", key).Msg("Failed to release feedback cache key")
+	}
+}
+
+// lookupCachedFeedback returns canonical feedback for the pair when it is cached. On a miss
+// it claims the key and returns a release function; the caller generates, caches and
+// releases. While another worker holds the claim it returns errFeedbackPending, and once
+// that claim is older than feedbackClaimWait it gives up waiting and lets the caller
+// generate without a claim.
+func (j *JobService) lookupCachedFeedback(ctx context.Context, questionID uuid.UUID, selectedAnswer, lang, promptVersion string) (*attempt.FeedbackCacheEntry, func(), error) {
+	key := feedbackCacheKey(questionID.String(), selectedAnswer, lang, promptVersion)
+
+	entry, err := j.feedbackCache.GetCachedFeedback(ctx, questionID, selectedAnswer, lang, promptVersion)
+	if err != nil || entry != nil {
+		return entry, nil, err
+	}
+
+	claimed, heldFor, err := j.claimFeedbackCacheKey(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !claimed {
+		if heldFor < feedbackClaimWait {
+			return nil, nil, errFeedbackPending
+		}
+		j.logger.Warn().Str("key", key).Dur("held_for", heldFor).Msg("Timed out waiting for feedback cache key, generating without dedup")
+		return nil, nil, nil
+	}
+
+	release := func() { j.releaseFeedbackCacheKey(key) }
+
+	// The previous holder may have cached its feedback just before releasing the claim
+	entry, err = j.feedbackCache.GetCachedFeedback(ctx, questionID, selectedAnswer, lang, promptVersion)
+	if err != nil || entry != nil {
+		release()
+		return entry, nil, err
+	}
+
+	return nil, release, nil
+}
+
+// storeCachedFeedback saves generated feedback as the canonical feedback for the pair
+func (j *JobService) storeCachedFeedback(ctx context.Context, questionID, selectedAnswer, lang, promptVersion string, result *llm.GenerationResult) error {
+	_, err := db.Pool.Exec(ctx, `
+		INSERT INTO feedback_cache (
+			question_id, selected_answer, feedback_lang, prompt_version, feedback_text, model_used
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (question_id, selected_answer, feedback_lang, prompt_version) DO NOTHING
+	`, questionID, selectedAnswer, lang, promptVersion, result.Text, result.Model)
+	return err
+}
+
+// fetchStudentContext loads the student's theta and recent accuracy in a section for personalized feedback
+func (j *JobService) fetchStudentContext(ctx context.Context, userID, section string) (*llm.StudentContext, error) {
+	var sc llm.StudentContext
+	err := db.Pool.QueryRow(ctx, `
+		SELECT
+			COALESCE(u.irt_theta, 0)::FLOAT8,
+			COUNT(r.id)::INTEGER,
+			COUNT(r.id) FILTER (WHERE r.is_correct)::INTEGER
+		FROM users u
+		LEFT JOIN LATERAL (
+			SELECT a.id, a.is_correct
+			FROM attempts a
+			JOIN questions q ON q.id = a.question_id
+			WHERE a.user_id = u.id AND q.section = $2 AND a.deleted_at IS NULL
+			ORDER BY a.created_at DESC
+			LIMIT 20
+		) r ON true
+		WHERE u.id = $1
+		GROUP BY u.irt_theta
+	`, userID, section).Scan(&sc.Theta, &sc.RecentAttempts, &sc.RecentCorrect)
+	if err != nil {
+		return nil, err
+	}
+
+	if sc.RecentAttempts > 0 {
+		sc.SectionAccuracyPct = float64(sc.RecentCorrect) / float64(sc.RecentAttempts) * 100
+	}
+	return &sc, nil
+}
@@ -0,0 +1,250 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/lib/llm"
+	"github.com/manikandareas/genta/internal/model/attempt"
+	"github.com/manikandareas/genta/internal/model/usage"
+)
+
+const (
+	defaultQualityThreshold = 3.0
+	// maxAutoRegenerations caps how often flagged feedback is regenerated before
+	// it is left for human review
+	maxAutoRegenerations = 1
+)
+
+// evaluationData holds the feedback and the attempt/question facts it is checked against
+type evaluationData struct {
+	AttemptID         string
+	UserID            string
+	QuestionID        string
+	IsCorrect         bool
+	SelectedAnswer    string
+	FeedbackText      string
+	FeedbackLang      string
+	ModelUsed         string
+	RegenerationCount int16
+	Question          questionData
+}
+
+func (j *JobService) handleFeedbackEvaluationTask(ctx context.Context, t *asynq.Task) error {
+	var p FeedbackEvaluationPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal feedback evaluation payload: %w", err)
+	}
+
+	j.logger.Info().
+		Str("type", "feedback_evaluation").
+		Str("feedback_id", p.FeedbackID).
+		Msg("Processing feedback evaluation task")
+
+	data, err := j.fetchEvaluationData(ctx, p.FeedbackID)
+	if err != nil {
+		j.logger.Error().Err(err).Str("feedback_id", p.FeedbackID).Msg("Failed to fetch feedback for evaluation")
+		return fmt.Errorf("failed to fetch feedback: %w", err)
+	}
+
+	input := llm.FeedbackEvalInput{
+		FeedbackText:      data.FeedbackText,
+		Language:          llm.Language(data.FeedbackLang),
+		IsCorrect:         data.IsCorrect,
+		SelectedAnswer:    data.SelectedAnswer,
+		CorrectAnswer:     data.Question.CorrectAnswer,
+		CorrectOptionText: data.Question.option(data.Question.CorrectAnswer),
+		QuestionText:      data.Question.Text,
+	}
+
+	threshold := llmSettings.QualityThreshold
+	if threshold <= 0 {
+		threshold = defaultQualityThreshold
+	}
+
+	// 1. Rule checks always run
+	evaluation := llm.EvaluateFeedbackRules(input)
+
+	// 2. LLM judge on a sample; judge failures keep the rule-based result
+	var judgeModel *string
+	if llmClient.IsConfigured() && rand.Float64() < llmSettings.QualityJudgeSampleRate {
+		result, err := llmClient.GenerateJSON(ctx, llm.SystemPromptFeedbackJudge(), llm.BuildFeedbackJudgePrompt(input))
+		if err != nil {
+			j.logger.Warn().Err(err).Str("feedback_id", p.FeedbackID).Msg("LLM judge failed, using rule checks only")
+		} else {
+			// Judge spend is platform overhead: it counts toward the global budget as system
+			// spend, not toward the student's quota (non-fatal)
+			costUSD := j.generationCost(ctx, result)
+			if err := j.recordSystemUsage(ctx, usage.PurposeFeedbackJudge, result.Model, result.TokensInput, result.TokensOutput, costUSD); err != nil {
+				j.logger.Error().Err(err).Str("feedback_id", p.FeedbackID).Msg("Failed to record LLM judge usage")
+			}
+
+			var verdict llm.JudgeVerdict
+			if err := json.Unmarshal([]byte(result.Text), &verdict); err != nil {
+				j.logger.Warn().Err(err).Str("feedback_id", p.FeedbackID).Msg("Failed to parse LLM judge verdict")
+			} else {
+				evaluation = llm.CombineJudgeVerdict(evaluation, verdict, threshold)
+				judgeModel = &result.Model
+				j.logger.Info().
+					Str("type", "feedback_evaluation").
+					Str("feedback_id", p.FeedbackID).
+					Str("model", result.Model).
+					Int("tokens_input", result.TokensInput).
+					Int("tokens_output", result.TokensOutput).
+					Float64("cost_usd", costUSD).
+					Msg("LLM judge evaluated feedback")
+			}
+		}
+	}
+
+	status := attempt.QualityStatusOK
+	if evaluation.Score < threshold {
+		status = attempt.QualityStatusFlagged
+	}
+
+	// 3. Store rating
+	if err := j.saveEvaluation(ctx, p.FeedbackID, evaluation, status, judgeModel); err != nil {
+		j.logger.Error().Err(err).Str("feedback_id", p.FeedbackID).Msg("Failed to save feedback evaluation")
+		return fmt.Errorf("failed to save feedback evaluation: %w", err)
+	}
+
+	if status == attempt.QualityStatusFlagged {
+		j.handleFlaggedFeedback(ctx, p, data)
+	}
+
+	j.logger.Info().
+		Str("type", "feedback_evaluation").
+		Str("feedback_id", p.FeedbackID).
+		Str("attempt_id", data.AttemptID).
+		Float64("score", evaluation.Score).
+		Strs("flags", evaluation.Flags).
+		Str("status", status).
+		Msg("Feedback evaluated")
+
+	return nil
+}
+
+// handleFlaggedFeedback keeps flagged feedback out of the cache and queues a regeneration.
+// Failures are logged only; the feedback stays flagged for human review.
+func (j *JobService) handleFlaggedFeedback(ctx context.Context, p FeedbackEvaluationPayload, data *evaluationData) {
+	if !p.Personalized {
+		if err := j.invalidateFlaggedCache(ctx, data); err != nil {
+			j.logger.Error().Err(err).Str("attempt_id", data.AttemptID).Msg("Failed to invalidate flagged cached feedback")
+		}
+	}
+
+	if !llmSettings.QualityAutoRegenerate || data.RegenerationCount >= maxAutoRegenerations {
+		return
+	}
+
+	task, err := NewFeedbackRegenerationTask(data.AttemptID, data.UserID, data.QuestionID, data.IsCorrect, data.FeedbackLang, p.Personalized)
+	if err != nil {
+		j.logger.Error().Err(err).Str("attempt_id", data.AttemptID).Msg("Failed to create feedback regeneration task")
+		return
+	}
+	if _, err := j.Client.EnqueueContext(ctx, task); err != nil {
+		j.logger.Error().Err(err).Str("attempt_id", data.AttemptID).Msg("Failed to enqueue feedback regeneration task")
+		return
+	}
+
+	j.logger.Info().
+		Str("type", "feedback_evaluation").
+		Str("attempt_id", data.AttemptID).
+		Msg("Queued regeneration for flagged feedback")
+}
+
+func (j *JobService) fetchEvaluationData(ctx context.Context, feedbackID string) (*evaluationData, error) {
+	var d evaluationData
+	q := &d.Question
+	err := db.Pool.QueryRow(ctx, `
+		SELECT a.id::text, a.user_id::text, a.question_id::text, a.is_correct, a.selected_answer,
+			f.feedback_text, COALESCE(f.feedback_lang, 'id'), f.model_used, COALESCE(f.regeneration_count, 0),
+			q.text, q.option_a, q.option_b, q.option_c, q.option_d, q.option_e, q.correct_answer
+		FROM attempt_feedback f
+		JOIN attempts a ON a.id = f.attempt_id
+		JOIN questions q ON q.id = a.question_id
+		WHERE f.id = $1
+	`, feedbackID).Scan(
+		&d.AttemptID, &d.UserID, &d.QuestionID, &d.IsCorrect, &d.SelectedAnswer,
+		&d.FeedbackText, &d.FeedbackLang, &d.ModelUsed, &d.RegenerationCount,
+		&q.Text, &q.OptionA, &q.OptionB, &q.OptionC, &q.OptionD, &q.OptionE, &q.CorrectAnswer,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (j *JobService) saveEvaluation(ctx context.Context, feedbackID string, evaluation llm.QualityEvaluation, status string, judgeModel *string) error {
+	_, err := db.Pool.Exec(ctx, `
+		UPDATE attempt_feedback
+		SET feedback_quality_rating = $2,
+			quality_flags = $3,
+			quality_status = $4,
+			judge_model = $5,
+			evaluated_at = $6,
+			updated_at = NOW()
+		WHERE id = $1
+	`, feedbackID, evaluation.Score, evaluation.Flags, status, judgeModel, time.Now())
+	return err
+}
+
+// invalidateFlaggedCache flags every attempt already served the same cached text and
+// removes the cache entry so the next miss generates fresh feedback
+func (j *JobService) invalidateFlaggedCache(ctx context.Context, data *evaluationData) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var cacheID *string
+	err = tx.QueryRow(ctx, `
+		SELECT id::text FROM feedback_cache
+		WHERE question_id = $1 AND selected_answer = $2 AND feedback_lang = $3 AND feedback_text = $4
+	`, data.QuestionID, data.SelectedAnswer, data.FeedbackLang, data.FeedbackText).Scan(&cacheID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE attempt_feedback
+		SET quality_status = $2, updated_at = NOW()
+		WHERE feedback_cache_id = $1 AND (quality_status IS NULL OR quality_status = $3)
+	`, cacheID, attempt.QualityStatusFlagged, attempt.QualityStatusOK); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM feedback_cache WHERE id = $1`, cacheID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// option returns the text of the option with the given letter
+func (q *questionData) option(letter string) string {
+	switch strings.ToUpper(letter) {
+	case "A":
+		return q.OptionA
+	case "B":
+		return q.OptionB
+	case "C":
+		return q.OptionC
+	case "D":
+		return q.OptionD
+	case "E":
+		return q.OptionE
+	}
+	return ""
+}
@@ -9,6 +9,7 @@ import (
 
 const (
 	TaskFeedbackGeneration = "feedback:generation"
+	TaskFeedbackEvaluation = "feedback:evaluation"
 )
 
 // FeedbackGenerationPayload contains data needed to generate feedback
@@ -17,6 +18,17 @@ type FeedbackGenerationPayload struct {
 	UserID     string `json:"user_id"`
 	QuestionID string `json:"question_id"`
 	IsCorrect  bool   `json:"is_correct"`
+	Language   string `json:"language,omitempty"`
+	// Personalized feedback uses the student's profile and bypasses the feedback cache
+	Personalized bool `json:"personalized,omitempty"`
+	// Regenerate replaces existing feedback flagged as low quality, bypassing the cache read
+	Regenerate bool `json:"regenerate,omitempty"`
+}
+
+// FeedbackEvaluationPayload contains data needed to evaluate generated feedback
+type FeedbackEvaluationPayload struct {
+	FeedbackID   string `json:"feedback_id"`
+	Personalized bool   `json:"personalized,omitempty"`
 }
 
 // FeedbackGenerationResult contains the result of feedback generation
@@ -29,12 +41,14 @@ type FeedbackGenerationResult struct {
 }
 
 // NewFeedbackGenerationTask creates a new feedback generation task
-func NewFeedbackGenerationTask(attemptID, userID, questionID string, isCorrect bool) (*asynq.Task, error) {
+func NewFeedbackGenerationTask(attemptID, userID, questionID string, isCorrect bool, language string, personalized bool) (*asynq.Task, error) {
 	payload, err := json.Marshal(FeedbackGenerationPayload{
-		AttemptID:  attemptID,
-		UserID:     userID,
-		QuestionID: questionID,
-		IsCorrect:  isCorrect,
+		AttemptID:    attemptID,
+		UserID:       userID,
+		QuestionID:   questionID,
+		IsCorrect:    isCorrect,
+		Language:     language,
+		Personalized: personalized,
 	})
 	if err != nil {
 		return nil, err
@@ -47,3 +61,43 @@ func NewFeedbackGenerationTask(attemptID, userID, questionID string, isCorrect b
 		asynq.Retention(24*time.Hour), // Keep completed tasks for 24h for status checks
 	), nil
 }
+
+// NewFeedbackRegenerationTask creates a task that replaces low-quality feedback for an attempt
+func NewFeedbackRegenerationTask(attemptID, userID, questionID string, isCorrect bool, language string, personalized bool) (*asynq.Task, error) {
+	payload, err := json.Marshal(FeedbackGenerationPayload{
+		AttemptID:    attemptID,
+		UserID:       userID,
+		QuestionID:   questionID,
+		IsCorrect:    isCorrect,
+		Language:     language,
+		Personalized: personalized,
+		Regenerate:   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskFeedbackGeneration, payload,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(60*time.Second),
+		asynq.Retention(24*time.Hour),
+	), nil
+}
+
+// NewFeedbackEvaluationTask creates a task that scores generated feedback
+func NewFeedbackEvaluationTask(feedbackID string, personalized bool) (*asynq.Task, error) {
+	payload, err := json.Marshal(FeedbackEvaluationPayload{
+		FeedbackID:   feedbackID,
+		Personalized: personalized,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskFeedbackEvaluation, payload,
+		asynq.MaxRetry(2),
+		asynq.Queue("low"),
+		asynq.Timeout(60*time.Second),
+	), nil
+}
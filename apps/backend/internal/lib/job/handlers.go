@@ -3,6 +3,7 @@ package job
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -11,18 +12,39 @@ import (
 	"github.com/manikandareas/genta/internal/database"
 	"github.com/manikandareas/genta/internal/lib/email"
 	"github.com/manikandareas/genta/internal/lib/llm"
+	"github.com/manikandareas/genta/internal/model/usage"
 	"github.com/rs/zerolog"
 )
 
 var (
 	emailClient *email.Client
 	llmClient   *llm.Client
+	llmSettings config.LLMConfig
 	db          *database.Database
 )
 
 func (j *JobService) InitHandlers(config *config.Config, logger *zerolog.Logger) {
 	emailClient = email.NewClient(config, logger)
 	llmClient = llm.NewClient(config, logger)
+	llmSettings = config.LLM
+}
+
+// SetBudgetCheck makes every LLM generation in job handlers check the global spend budget
+// first. The usage service implements the check; the job package cannot import it.
+func (j *JobService) SetBudgetCheck(check func(ctx context.Context) (*usage.ExceededReason, error)) {
+	if llmClient == nil {
+		return
+	}
+	llmClient.SetBudgetGuard(func(ctx context.Context) error {
+		reason, err := check(ctx)
+		if err != nil {
+			return err
+		}
+		if reason != nil {
+			return fmt.Errorf("%w: %s", llm.ErrBudgetExceeded, *reason)
+		}
+		return nil
+	})
 }
 
 // SetDatabase sets the database connection for job handlers
@@ -74,19 +96,20 @@ func (j *JobService) handleFeedbackGenerationTask(ctx context.Context, t *asynq.
 		Bool("is_correct", p.IsCorrect).
 		Msg("Processing feedback generation task")
 
-	// Check if LLM client is configured
-	if llmClient == nil || !llmClient.IsConfigured() {
-		j.logger.Warn().
-			Str("attempt_id", p.AttemptID).
-			Msg("LLM client not configured, skipping feedback generation")
-		return nil
-	}
-
 	// Check if database is available
 	if db == nil {
 		return fmt.Errorf("database not initialized for job handlers")
 	}
 
+	attemptID, err := uuid.Parse(p.AttemptID)
+	if err != nil {
+		return fmt.Errorf("invalid attempt id: %w", err)
+	}
+	questionID, err := uuid.Parse(p.QuestionID)
+	if err != nil {
+		return fmt.Errorf("invalid question id: %w", err)
+	}
+
 	// 1. Fetch question details from DB
 	question, err := j.fetchQuestion(ctx, p.QuestionID)
 	if err != nil {
@@ -101,7 +124,52 @@ func (j *JobService) handleFeedbackGenerationTask(ctx context.Context, t *asynq.
 		return fmt.Errorf("failed to fetch attempt: %w", err)
 	}
 
-	// 3. Build prompt and generate feedback
+	lang := llm.LangIndonesian // Default to Indonesian
+	if p.Language != "" {
+		lang = llm.Language(p.Language)
+	}
+	promptVersion := llm.PromptVersion()
+
+	// 3. Serve canonical feedback from cache unless personalization or regeneration is needed.
+	// Concurrent misses for the same pair are retried until the claiming worker has cached
+	// its feedback, so only one of them calls the LLM.
+	cacheable := !p.Personalized
+	if cacheable && !p.Regenerate && j.feedbackCache != nil {
+		cached, release, err := j.lookupCachedFeedback(ctx, questionID, attempt.SelectedAnswer, string(lang), promptVersion)
+		if errors.Is(err, errFeedbackPending) {
+			return err
+		}
+		if err != nil {
+			j.logger.Warn().Err(err).Str("attempt_id", p.AttemptID).Msg("Failed to read feedback cache, generating without dedup")
+		}
+		if release != nil {
+			defer release()
+		}
+
+		if cached != nil {
+			if _, err := j.feedbackCache.CreateFeedbackFromCache(ctx, attemptID, cached); err != nil {
+				j.logger.Error().Err(err).Str("attempt_id", p.AttemptID).Msg("Failed to serve cached feedback")
+				return fmt.Errorf("failed to serve cached feedback: %w", err)
+			}
+
+			j.logger.Info().
+				Str("type", "feedback_generation").
+				Str("attempt_id", p.AttemptID).
+				Str("feedback_cache_id", cached.ID.String()).
+				Msg("Served feedback from cache")
+			return nil
+		}
+	}
+
+	// Check if LLM client is configured
+	if llmClient == nil || !llmClient.IsConfigured() {
+		j.logger.Warn().
+			Str("attempt_id", p.AttemptID).
+			Msg("LLM client not configured, serving static explanation")
+		return j.serveStaticFeedback(ctx, p, question, lang, promptVersion)
+	}
+
+	// 4. Build prompt and generate feedback
 	promptData := llm.FeedbackPromptData{
 		QuestionText:   question.Text,
 		Options:        []string{question.OptionA, question.OptionB, question.OptionC, question.OptionD, question.OptionE},
@@ -111,7 +179,16 @@ func (j *JobService) handleFeedbackGenerationTask(ctx context.Context, t *asynq.
 		Explanation:    question.Explanation,
 		Section:        question.Section,
 		SubType:        question.SubType,
-		Language:       llm.LangIndonesian, // Default to Indonesian
+		Language:       lang,
+	}
+
+	if p.Personalized {
+		student, err := j.fetchStudentContext(ctx, p.UserID, question.Section)
+		if err != nil {
+			j.logger.Warn().Err(err).Str("attempt_id", p.AttemptID).Msg("Failed to load student context, using generic feedback")
+		} else {
+			promptData.Student = student
+		}
 	}
 
 	systemPrompt := llm.SystemPromptFeedback(promptData.Language)
@@ -119,30 +196,48 @@ func (j *JobService) handleFeedbackGenerationTask(ctx context.Context, t *asynq.
 
 	result, err := llmClient.GenerateText(ctx, systemPrompt, userPrompt)
 	if err != nil {
-		j.logger.Error().Err(err).Str("attempt_id", p.AttemptID).Msg("Failed to generate feedback")
-		return fmt.Errorf("failed to generate feedback: %w", err)
+		// The client already retried and tried every fallback model; students still get
+		// the reference explanation instead of nothing
+		j.logger.Error().Err(err).Str("attempt_id", p.AttemptID).Msg("Failed to generate feedback, serving static explanation")
+		return j.serveStaticFeedback(ctx, p, question, lang, promptVersion)
 	}
 
-	// 4. Save feedback to attempt_feedback table
-	feedbackID := uuid.New()
-	lang := string(promptData.Language)
-	promptVersion := llm.PromptVersion()
-	tokensInput := int16(result.TokensInput)
-	tokensOutput := int16(result.TokensOutput)
+	// 5. Calculate generation cost from the model price table
+	costUSD := j.generationCost(ctx, result)
 
-	err = j.saveFeedback(ctx, feedbackID, p.AttemptID, result.Text, lang, result.Model, promptVersion, result.GenerationTimeMs, tokensInput, tokensOutput)
+	// 6. Save feedback to attempt_feedback table
+	feedbackID, err := j.saveFeedback(ctx, p.AttemptID, result.Text, string(lang), result.Model, promptVersion, result.GenerationTimeMs, result.TokensInput, result.TokensOutput, costUSD)
 	if err != nil {
 		j.logger.Error().Err(err).Str("attempt_id", p.AttemptID).Msg("Failed to save feedback")
 		return fmt.Errorf("failed to save feedback: %w", err)
 	}
 
-	// 5. Update attempt.feedback_generated = true
+	// 7. Update attempt.feedback_generated = true
 	err = j.markFeedbackGenerated(ctx, p.AttemptID, result.Model, result.GenerationTimeMs)
 	if err != nil {
 		j.logger.Error().Err(err).Str("attempt_id", p.AttemptID).Msg("Failed to mark feedback generated")
 		return fmt.Errorf("failed to mark feedback generated: %w", err)
 	}
 
+	// 8. Record usage in the ledger (non-fatal, feedback is already saved)
+	if err := j.recordUsage(ctx, p.UserID, result.Model, result.TokensInput, result.TokensOutput, costUSD); err != nil {
+		j.logger.Error().Err(err).Str("attempt_id", p.AttemptID).Msg("Failed to record LLM usage")
+	}
+
+	// 9. Store as canonical feedback for later attempts (non-fatal)
+	if cacheable {
+		if err := j.storeCachedFeedback(ctx, p.QuestionID, attempt.SelectedAnswer, string(lang), promptVersion, result); err != nil {
+			j.logger.Error().Err(err).Str("attempt_id", p.AttemptID).Msg("Failed to store feedback in cache")
+		}
+	}
+
+	// 10. Queue quality evaluation (non-fatal)
+	if task, err := NewFeedbackEvaluationTask(feedbackID.String(), p.Personalized); err != nil {
+		j.logger.Warn().Err(err).Str("attempt_id", p.AttemptID).Msg("Failed to create feedback evaluation task")
+	} else if _, err := j.Client.EnqueueContext(ctx, task); err != nil {
+		j.logger.Warn().Err(err).Str("attempt_id", p.AttemptID).Msg("Failed to enqueue feedback evaluation task")
+	}
+
 	j.logger.Info().
 		Str("type", "feedback_generation").
 		Str("attempt_id", p.AttemptID).
@@ -151,6 +246,9 @@ func (j *JobService) handleFeedbackGenerationTask(ctx context.Context, t *asynq.
 		Int("generation_time_ms", result.GenerationTimeMs).
 		Int("tokens_input", result.TokensInput).
 		Int("tokens_output", result.TokensOutput).
+		Float64("cost_usd", costUSD).
+		Bool("fallback_used", result.FallbackUsed).
+		Bool("regenerated", p.Regenerate).
 		Msg("Successfully generated and saved feedback")
 
 	return nil
@@ -166,6 +264,7 @@ type questionData struct {
 	OptionE       string
 	CorrectAnswer string
 	Explanation   string
+	ExplanationEn string
 	Section       string
 	SubType       string
 }
@@ -179,11 +278,12 @@ func (j *JobService) fetchQuestion(ctx context.Context, questionID string) (*que
 	var q questionData
 	err := db.Pool.QueryRow(ctx, `
 		SELECT text, option_a, option_b, option_c, option_d, option_e, 
-			correct_answer, COALESCE(explanation, ''), section, COALESCE(sub_type, '')
+			correct_answer, COALESCE(explanation, ''), COALESCE(explanation_en, ''),
+			section, COALESCE(sub_type, '')
 		FROM questions WHERE id = $1
 	`, questionID).Scan(
 		&q.Text, &q.OptionA, &q.OptionB, &q.OptionC, &q.OptionD, &q.OptionE,
-		&q.CorrectAnswer, &q.Explanation, &q.Section, &q.SubType,
+		&q.CorrectAnswer, &q.Explanation, &q.ExplanationEn, &q.Section, &q.SubType,
 	)
 	if err != nil {
 		return nil, err
@@ -191,6 +291,36 @@ func (j *JobService) fetchQuestion(ctx context.Context, questionID string) (*que
 	return &q, nil
 }
 
+// serveStaticFeedback saves the question's reference explanation as the attempt's feedback.
+// Static feedback is never cached, so the LLM is tried again for later attempts.
+func (j *JobService) serveStaticFeedback(ctx context.Context, p FeedbackGenerationPayload, question *questionData, lang llm.Language, promptVersion string) error {
+	explanation := question.Explanation
+	if lang == llm.LangEnglish && question.ExplanationEn != "" {
+		explanation = question.ExplanationEn
+	}
+	text := llm.StaticFeedback(lang, p.IsCorrect, question.CorrectAnswer, explanation)
+
+	feedbackID, err := j.saveFeedback(ctx, p.AttemptID, text, string(lang), llm.ModelStaticExplanation, promptVersion, 0, 0, 0, 0)
+	if err != nil {
+		j.logger.Error().Err(err).Str("attempt_id", p.AttemptID).Msg("Failed to save static feedback")
+		return fmt.Errorf("failed to save static feedback: %w", err)
+	}
+
+	if err := j.markFeedbackGenerated(ctx, p.AttemptID, llm.ModelStaticExplanation, 0); err != nil {
+		j.logger.Error().Err(err).Str("attempt_id", p.AttemptID).Msg("Failed to mark feedback generated")
+		return fmt.Errorf("failed to mark feedback generated: %w", err)
+	}
+
+	j.logger.Info().
+		Str("type", "feedback_generation").
+		Str("attempt_id", p.AttemptID).
+		Str("feedback_id", feedbackID.String()).
+		Str("model", llm.ModelStaticExplanation).
+		Msg("Saved static explanation as feedback")
+
+	return nil
+}
+
 func (j *JobService) fetchAttempt(ctx context.Context, attemptID string) (*attemptData, error) {
 	var a attemptData
 	err := db.Pool.QueryRow(ctx, `
@@ -202,14 +332,78 @@ func (j *JobService) fetchAttempt(ctx context.Context, attemptID string) (*attem
 	return &a, nil
 }
 
-func (j *JobService) saveFeedback(ctx context.Context, feedbackID uuid.UUID, attemptID, feedbackText, lang, model, promptVersion string, generationMs int, tokensInput, tokensOutput int16) error {
-	_, err := db.Pool.Exec(ctx, `
+// saveFeedback stores feedback for an attempt. Existing feedback (e.g. flagged as low quality)
+// is replaced and its quality evaluation and rating reset.
+func (j *JobService) saveFeedback(ctx context.Context, attemptID, feedbackText, lang, model, promptVersion string, generationMs int, tokensInput, tokensOutput int, costUSD float64) (uuid.UUID, error) {
+	var feedbackID uuid.UUID
+	err := db.Pool.QueryRow(ctx, `
 		INSERT INTO attempt_feedback (
 			id, attempt_id, feedback_text, feedback_lang,
 			model_used, prompt_version, generation_time_ms,
-			token_count_input, token_count_output, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
-	`, feedbackID, attemptID, feedbackText, lang, model, promptVersion, generationMs, tokensInput, tokensOutput)
+			token_count_input, token_count_output, cost_usd, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+		ON CONFLICT (attempt_id) DO UPDATE SET
+			feedback_text = EXCLUDED.feedback_text,
+			feedback_lang = EXCLUDED.feedback_lang,
+			model_used = EXCLUDED.model_used,
+			prompt_version = EXCLUDED.prompt_version,
+			generation_time_ms = EXCLUDED.generation_time_ms,
+			token_count_input = EXCLUDED.token_count_input,
+			token_count_output = EXCLUDED.token_count_output,
+			cost_usd = EXCLUDED.cost_usd,
+			feedback_cache_id = NULL,
+			feedback_quality_rating = NULL,
+			is_helpful = NULL,
+			helpful_rating = NULL,
+			quality_status = NULL,
+			quality_flags = NULL,
+			judge_model = NULL,
+			evaluated_at = NULL,
+			regeneration_count = COALESCE(attempt_feedback.regeneration_count, 0) + 1
+		RETURNING id
+	`, uuid.New(), attemptID, feedbackText, lang, model, promptVersion, generationMs, tokensInput, tokensOutput, costUSD).Scan(&feedbackID)
+	return feedbackID, err
+}
+
+// fetchModelPrice looks up the price for a model, matching dated snapshots
+// (e.g. gpt-4o-mini-2024-07-18) to their base model entry
+func (j *JobService) fetchModelPrice(ctx context.Context, model string) (*llm.ModelPrice, error) {
+	var p llm.ModelPrice
+	err := db.Pool.QueryRow(ctx, `
+		SELECT model, input_price_per_million, output_price_per_million
+		FROM llm_model_prices
+		WHERE is_active = true AND $1 LIKE model || '%'
+		ORDER BY LENGTH(model) DESC
+		LIMIT 1
+	`, model).Scan(&p.Model, &p.InputPricePerMillion, &p.OutputPricePerMillion)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// recordUsage adds a generation to the per user, model and day usage ledger. Days are UTC
+// days, whatever the database session's time zone, so they line up with the budget windows.
+func (j *JobService) recordUsage(ctx context.Context, userID, model string, tokensInput, tokensOutput int, costUSD float64) error {
+	return j.recordLedger(ctx, &userID, usage.PurposeUser, model, tokensInput, tokensOutput, costUSD)
+}
+
+// recordSystemUsage records spend that belongs to no user, such as the LLM judge
+func (j *JobService) recordSystemUsage(ctx context.Context, purpose, model string, tokensInput, tokensOutput int, costUSD float64) error {
+	return j.recordLedger(ctx, nil, purpose, model, tokensInput, tokensOutput, costUSD)
+}
+
+func (j *JobService) recordLedger(ctx context.Context, userID *string, purpose, model string, tokensInput, tokensOutput int, costUSD float64) error {
+	_, err := db.Pool.Exec(ctx, `
+		INSERT INTO llm_usage_daily (
+			user_id, purpose, model, usage_date, request_count, tokens_input, tokens_output, cost_usd
+		) VALUES ($1, $2, $3, (NOW() AT TIME ZONE 'UTC')::DATE, 1, $4, $5, $6)
+		ON CONFLICT (user_id, purpose, model, usage_date) DO UPDATE SET
+			request_count = llm_usage_daily.request_count + 1,
+			tokens_input = llm_usage_daily.tokens_input + EXCLUDED.tokens_input,
+			tokens_output = llm_usage_daily.tokens_output + EXCLUDED.tokens_output,
+			cost_usd = llm_usage_daily.cost_usd + EXCLUDED.cost_usd
+	`, userID, purpose, model, tokensInput, tokensOutput, costUSD)
 	return err
 }
 
@@ -1,10 +1,14 @@
 package job
 
 import (
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/hibiken/asynq"
 	"github.com/manikandareas/genta/internal/config"
+	"github.com/manikandareas/genta/internal/model/event"
 	"github.com/rs/zerolog"
 )
 
@@ -32,8 +36,21 @@ type JobService struct {
 	Client    *asynq.Client
 	Inspector *asynq.Inspector
 	server    *asynq.Server
+	scheduler *asynq.Scheduler
 	logger    *zerolog.Logger
 	redisAddr string
+
+	feedbackCache FeedbackCache
+
+	// Subscribers registered with Subscribe
+	subscribersMu    sync.RWMutex
+	eventHandlers    map[string]EventHandlerFunc
+	eventSubscribers map[event.Type][]subscriber
+
+	// Outbox relay lifecycle
+	relayWake chan struct{}
+	relayStop chan struct{}
+	relayDone chan struct{}
 }
 
 func NewJobService(logger *zerolog.Logger, cfg *config.Config) *JobService {
@@ -52,15 +69,32 @@ func NewJobService(logger *zerolog.Logger, cfg *config.Config) *JobService {
 				"default":  3, // Default priority for most tasks
 				"low":      1, // Lower priority for non-urgent tasks
 			},
+			// Feedback waiting on another worker's generation is retried soon and does not
+			// use up its retries
+			IsFailure: func(err error) bool {
+				return !errors.Is(err, errFeedbackPending)
+			},
+			RetryDelayFunc: func(n int, err error, t *asynq.Task) time.Duration {
+				if errors.Is(err, errFeedbackPending) {
+					return feedbackClaimRetryDelay
+				}
+				return asynq.DefaultRetryDelayFunc(n, err, t)
+			},
 		},
 	)
 
+	// Periodic tasks are enqueued by the scheduler and processed by the server like any other task
+	scheduler := asynq.NewScheduler(redisOpt, nil)
+
 	return &JobService{
 		Client:    client,
 		Inspector: inspector,
 		server:    server,
+		scheduler: scheduler,
 		logger:    logger,
 		redisAddr: redisAddr,
+		relayWake: make(chan struct{}, 1),
+		relayStop: make(chan struct{}),
 	}
 }
 
@@ -69,17 +103,80 @@ func (j *JobService) Start() error {
 	mux := asynq.NewServeMux()
 	mux.HandleFunc(TaskWelcome, j.handleWelcomeEmailTask)
 	mux.HandleFunc(TaskFeedbackGeneration, j.handleFeedbackGenerationTask)
+	mux.HandleFunc(TaskFeedbackEvaluation, j.handleFeedbackEvaluationTask)
+	mux.HandleFunc(TaskQuestionEnrichment, j.handleQuestionEnrichmentTask)
+	mux.HandleFunc(TaskQuestionEnrichmentBatch, j.handleQuestionEnrichmentBatchTask)
+	mux.HandleFunc(TaskQuestionVariants, j.handleQuestionVariantsTask)
+	mux.HandleFunc(TaskReadinessForecast, j.handleReadinessForecastTask)
+	mux.HandleFunc(TaskReadinessForecastBatch, j.handleReadinessForecastBatchTask)
+	mux.HandleFunc(TaskReadinessRollup, j.handleReadinessRollupTask)
+	mux.HandleFunc(TaskReadinessPercentiles, j.handleReadinessPercentilesTask)
+	mux.HandleFunc(TaskPacingCohort, j.handlePacingCohortTask)
+	mux.HandleFunc(TaskAttemptStats, j.handleAttemptStatsTask)
+	mux.HandleFunc(TaskEventAudit, j.handleEventAuditTask)
+	mux.HandleFunc(TaskEventDelivery, j.handleEventDeliveryTask)
+	mux.HandleFunc(TaskOutboxPrune, j.handleOutboxPruneTask)
+	mux.HandleFunc(TaskSessionCloseIdle, j.handleSessionCloseIdleTask)
+	mux.HandleFunc(TaskQuestionDuplicateScan, j.handleQuestionDuplicateScanTask)
+	mux.HandleFunc(TaskConceptMastery, j.handleConceptMasteryTask)
+	mux.HandleFunc(TaskConceptMasteryRebuild, j.handleConceptMasteryRebuildTask)
 
 	j.logger.Info().Msg("Starting background job server")
 	if err := j.server.Start(mux); err != nil {
 		return err
 	}
 
+	// Register periodic tasks
+	if _, err := j.scheduler.Register(readinessForecastSchedule, NewReadinessForecastBatchTask()); err != nil {
+		return fmt.Errorf("failed to register readiness forecast schedule: %w", err)
+	}
+	if _, err := j.scheduler.Register(readinessRollupSchedule, NewReadinessRollupTask()); err != nil {
+		return fmt.Errorf("failed to register readiness rollup schedule: %w", err)
+	}
+	if _, err := j.scheduler.Register(readinessPercentilesSchedule, NewReadinessPercentilesTask()); err != nil {
+		return fmt.Errorf("failed to register readiness percentiles schedule: %w", err)
+	}
+	if _, err := j.scheduler.Register(pacingCohortSchedule, NewPacingCohortTask()); err != nil {
+		return fmt.Errorf("failed to register pacing cohort schedule: %w", err)
+	}
+	if _, err := j.scheduler.Register(outboxPruneSchedule, NewOutboxPruneTask()); err != nil {
+		return fmt.Errorf("failed to register outbox prune schedule: %w", err)
+	}
+	if _, err := j.scheduler.Register(sessionCloseIdleSchedule, NewSessionCloseIdleTask()); err != nil {
+		return fmt.Errorf("failed to register session close schedule: %w", err)
+	}
+	duplicateScanTask, err := NewQuestionDuplicateScanTask("", 0, "")
+	if err != nil {
+		return fmt.Errorf("failed to create duplicate scan task: %w", err)
+	}
+	if _, err := j.scheduler.Register(duplicateScanSchedule, duplicateScanTask); err != nil {
+		return fmt.Errorf("failed to register duplicate scan schedule: %w", err)
+	}
+
+	j.logger.Info().Msg("Starting background job scheduler")
+	if err := j.scheduler.Start(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// StartOutboxRelay starts publishing outbox events. It is started after the services have
+// subscribed, so no event is published before all of its subscribers exist.
+func (j *JobService) StartOutboxRelay() {
+	// The relay reads the outbox, so it only runs once the database is set
+	if db == nil {
+		return
+	}
+
+	j.logger.Info().Msg("Starting outbox relay")
+	j.startOutboxRelay()
+}
+
 func (j *JobService) Stop() {
 	j.logger.Info().Msg("Stopping background job server")
+	j.stopOutboxRelay()
+	j.scheduler.Shutdown()
 	j.server.Shutdown()
 	j.Client.Close()
 	j.Inspector.Close()
@@ -98,6 +195,11 @@ func (j *JobService) GetTaskInfo(taskID string) (*TaskInfo, error) {
 		}
 	}
 
+	// A feedback task whose event still waits in the outbox has not reached Asynq yet
+	if j.pendingInOutbox(taskID) {
+		return &TaskInfo{TaskID: taskID, Status: TaskStatusQueued, Queue: "default"}, nil
+	}
+
 	return nil, fmt.Errorf("task not found: %s", taskID)
 }
 
@@ -0,0 +1,300 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/model/event"
+)
+
+const (
+	// outboxPollInterval bounds how long an event waits when nobody wakes the relay
+	outboxPollInterval = time.Second
+	outboxBatchSize    = 100
+	// outboxMaxBackoffSeconds caps the delay between publish retries while Redis is down
+	outboxMaxBackoffSeconds = 300
+	outboxPublishTimeout    = 30 * time.Second
+	// outboxRetentionDays is how long published events and processed markers are kept
+	outboxRetentionDays = 7
+)
+
+// subscriber turns an event into the Asynq task that handles it; a nil task means the
+// subscriber has nothing to do for this event
+type subscriber struct {
+	name   string
+	task   func(evt *event.Event) (*asynq.Task, error)
+	taskID func(evt *event.Event) (string, error)
+}
+
+// subscribers routes each event type to its handlers; every event is also audited
+var subscribers = map[event.Type][]subscriber{
+	event.TypeAttemptRecorded: {
+		{name: "attempt_stats", task: NewAttemptStatsTask},
+		{name: "concept_mastery", task: NewConceptMasteryTask},
+		{name: "feedback", task: feedbackSubscriberTask, taskID: feedbackSubscriberTaskID},
+	},
+	event.TypeOnboardingCompleted: {
+		{name: "welcome_email", task: welcomeEmailSubscriberTask},
+	},
+}
+
+var auditSubscriber = subscriber{name: "audit", task: NewEventAuditTask}
+
+func feedbackSubscriberTask(evt *event.Event) (*asynq.Task, error) {
+	var p event.AttemptRecorded
+	if err := evt.Decode(&p); err != nil {
+		return nil, err
+	}
+	if !p.GenerateFeedback {
+		return nil, nil
+	}
+
+	return NewFeedbackGenerationTask(p.AttemptID.String(), p.UserID.String(), p.QuestionID.String(),
+		p.IsCorrect, p.FeedbackLanguage, p.Personalized)
+}
+
+func feedbackSubscriberTaskID(evt *event.Event) (string, error) {
+	var p event.AttemptRecorded
+	if err := evt.Decode(&p); err != nil {
+		return "", err
+	}
+	return FeedbackTaskID(p.AttemptID.String()), nil
+}
+
+func welcomeEmailSubscriberTask(evt *event.Event) (*asynq.Task, error) {
+	var p event.OnboardingCompleted
+	if err := evt.Decode(&p); err != nil {
+		return nil, err
+	}
+
+	firstName := ""
+	if p.FullName != nil {
+		if fields := strings.Fields(*p.FullName); len(fields) > 0 {
+			firstName = fields[0]
+		}
+	}
+
+	return NewWelcomeEmailTask(p.Email, firstName)
+}
+
+// appendOutboxEvent writes a domain event raised by a job to the outbox, inside the job's
+// transaction
+func appendOutboxEvent(ctx context.Context, tx pgx.Tx, p event.Payload) error {
+	evt, err := event.New(p)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO outbox_events (id, type, aggregate_id, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, NOW())
+	`, evt.ID, evt.Type, evt.AggregateID, evt.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to append %s event: %w", evt.Type, err)
+	}
+	return nil
+}
+
+// pendingInOutbox reports whether taskID is a feedback task whose AttemptRecorded event
+// has not been published yet
+func (j *JobService) pendingInOutbox(taskID string) bool {
+	attemptID, ok := strings.CutPrefix(taskID, FeedbackTaskID(""))
+	if !ok || db == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var pending bool
+	err := db.Pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM outbox_events
+			WHERE type = $1 AND aggregate_id = $2 AND published_at IS NULL
+		)
+	`, event.TypeAttemptRecorded, attemptID).Scan(&pending)
+	if err != nil {
+		j.logger.Warn().Err(err).Str("task_id", taskID).Msg("failed to check outbox for pending task")
+		return false
+	}
+	return pending
+}
+
+// WakeOutboxRelay asks the relay to publish right away instead of at its next poll
+func (j *JobService) WakeOutboxRelay() {
+	select {
+	case j.relayWake <- struct{}{}:
+	default:
+	}
+}
+
+func (j *JobService) startOutboxRelay() {
+	j.relayDone = make(chan struct{})
+	go j.runOutboxRelay()
+}
+
+func (j *JobService) stopOutboxRelay() {
+	if j.relayDone == nil {
+		return
+	}
+	close(j.relayStop)
+	<-j.relayDone
+}
+
+func (j *JobService) runOutboxRelay() {
+	defer close(j.relayDone)
+
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-j.relayStop:
+			return
+		case <-ticker.C:
+		case <-j.relayWake:
+		}
+
+		// Drain full batches before waiting again
+		for {
+			n, err := j.publishOutbox(context.Background())
+			if err != nil {
+				j.logger.Warn().Err(err).Msg("failed to publish outbox events")
+				break
+			}
+			if n < outboxBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// publishOutbox publishes one batch of due events and returns how many rows it settled.
+// Rows are locked with SKIP LOCKED so several API instances can relay side by side.
+func (j *JobService) publishOutbox(ctx context.Context) (int, error) {
+	if db == nil {
+		return 0, fmt.Errorf("database not initialized for job handlers")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+	defer cancel()
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, type, aggregate_id, payload, occurred_at
+		FROM outbox_events
+		WHERE published_at IS NULL AND next_attempt_at <= NOW()
+		ORDER BY seq
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`, outboxBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query outbox events: %w", err)
+	}
+
+	events, err := pgx.CollectRows(rows, pgx.RowToStructByName[event.Event])
+	if err != nil {
+		return 0, fmt.Errorf("failed to collect outbox events: %w", err)
+	}
+
+	settled := 0
+	for i := range events {
+		evt := &events[i]
+
+		if publishErr := j.publishEvent(evt); publishErr != nil {
+			// Exponential backoff keeps a Redis outage from turning into a hot loop; the
+			// rest of the batch stays due and is retried together with this event
+			_, err := tx.Exec(ctx, `
+				UPDATE outbox_events
+				SET publish_attempts = publish_attempts + 1,
+					last_error = $2,
+					next_attempt_at = NOW() + LEAST(POWER(2, publish_attempts), $3) * INTERVAL '1 second'
+				WHERE id = $1
+			`, evt.ID, publishErr.Error(), outboxMaxBackoffSeconds)
+			if err != nil {
+				return settled, fmt.Errorf("failed to record outbox publish failure: %w", err)
+			}
+
+			j.logger.Warn().
+				Err(publishErr).
+				Str("event_id", evt.ID.String()).
+				Str("event_type", string(evt.Type)).
+				Msg("failed to publish outbox event, will retry")
+
+			settled++
+			break
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE outbox_events SET published_at = NOW(), last_error = NULL WHERE id = $1`, evt.ID); err != nil {
+			return settled, fmt.Errorf("failed to mark outbox event published: %w", err)
+		}
+		settled++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit outbox batch: %w", err)
+	}
+
+	return settled, nil
+}
+
+// publishEvent enqueues one task per subscriber. Task IDs are derived from the event, so
+// re-publishing after a partial failure does not enqueue a subscriber twice.
+func (j *JobService) publishEvent(evt *event.Event) error {
+	for _, sub := range j.subscribersFor(evt.Type) {
+		task, err := sub.task(evt)
+		if err != nil {
+			return fmt.Errorf("failed to create %s task: %w", sub.name, err)
+		}
+		if task == nil {
+			continue
+		}
+
+		taskID := evt.ID.String() + ":" + sub.name
+		if sub.taskID != nil {
+			if taskID, err = sub.taskID(evt); err != nil {
+				return fmt.Errorf("failed to derive %s task id: %w", sub.name, err)
+			}
+		}
+
+		if _, err := j.Client.Enqueue(task, asynq.TaskID(taskID)); err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+			return fmt.Errorf("failed to enqueue %s task: %w", sub.name, err)
+		}
+	}
+
+	return nil
+}
+
+func (j *JobService) handleOutboxPruneTask(ctx context.Context, t *asynq.Task) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	events, err := db.Pool.Exec(ctx, `DELETE FROM outbox_events WHERE published_at < NOW() - make_interval(days => $1)`, outboxRetentionDays)
+	if err != nil {
+		return fmt.Errorf("failed to prune outbox events: %w", err)
+	}
+
+	processed, err := db.Pool.Exec(ctx, `DELETE FROM processed_events WHERE processed_at < NOW() - make_interval(days => $1)`, outboxRetentionDays)
+	if err != nil {
+		return fmt.Errorf("failed to prune processed events: %w", err)
+	}
+
+	j.logger.Info().
+		Str("type", "outbox_prune").
+		Int64("events", events.RowsAffected()).
+		Int64("processed", processed.RowsAffected()).
+		Msg("Pruned outbox")
+
+	return nil
+}
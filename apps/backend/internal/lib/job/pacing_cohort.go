@@ -0,0 +1,56 @@
+package job
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/manikandareas/genta/internal/model/analytics"
+)
+
+func (j *JobService) handlePacingCohortTask(ctx context.Context, t *asynq.Task) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Rebuilt from scratch so a section whose cohort shrank below the minimum loses its median
+	if _, err := tx.Exec(ctx, `DELETE FROM pacing_cohort_medians`); err != nil {
+		return fmt.Errorf("failed to clear pacing cohort medians: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO pacing_cohort_medians (section, median_seconds, cohort_size, attempts_count)
+		SELECT
+			q.section,
+			ROUND(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY a.time_spent_seconds)::NUMERIC, 2),
+			COUNT(DISTINCT a.user_id),
+			COUNT(*)
+		FROM attempts a
+		JOIN questions q ON a.question_id = q.id
+		WHERE a.created_at >= NOW() - make_interval(days => $1)
+			AND a.deleted_at IS NULL
+		GROUP BY q.section
+		HAVING COUNT(DISTINCT a.user_id) >= $2
+	`, analytics.PacingCohortWindowDays, analytics.MinCohortUsers)
+	if err != nil {
+		j.logger.Error().Err(err).Msg("Failed to compute pacing cohort medians")
+		return fmt.Errorf("failed to compute pacing cohort medians: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit pacing cohort medians: %w", err)
+	}
+
+	j.logger.Info().
+		Str("type", "pacing_cohort").
+		Int64("sections", tag.RowsAffected()).
+		Msg("Pacing cohort medians materialized")
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package job
+
+import (
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskPacingCohort = "analytics:pacing_cohort"
+
+	// pacingCohortSchedule refreshes the cohort medians every hour
+	pacingCohortSchedule = "20 * * * *"
+)
+
+// NewPacingCohortTask creates a task that materializes the cohort median time per section
+func NewPacingCohortTask() *asynq.Task {
+	return asynq.NewTask(TaskPacingCohort, nil,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(5*time.Minute),
+		asynq.Unique(50*time.Minute),
+	)
+}
@@ -0,0 +1,212 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/lib/scoring"
+	"github.com/manikandareas/genta/internal/model/readiness"
+)
+
+const (
+	// forecastWindowWeeks is how far back theta checkpoints are taken for the trend
+	forecastWindowWeeks = 8
+	// minForecastResponses is the number of answers a checkpoint needs to be estimated
+	minForecastResponses = 5
+)
+
+// forecastSection holds the stored readiness facts a section forecast starts from
+type forecastSection struct {
+	Section     string
+	TargetTheta *float64
+}
+
+func (j *JobService) handleReadinessForecastBatchTask(ctx context.Context, t *asynq.Task) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	since := time.Now().AddDate(0, 0, -7*forecastWindowWeeks)
+	rows, err := db.Pool.Query(ctx, `
+		SELECT DISTINCT user_id::text
+		FROM attempts
+		WHERE created_at >= $1 AND deleted_at IS NULL
+	`, since)
+	if err != nil {
+		return fmt.Errorf("failed to select active users: %w", err)
+	}
+	userIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("failed to collect active users: %w", err)
+	}
+
+	enqueued := 0
+	for _, userID := range userIDs {
+		task, err := NewReadinessForecastTask(userID)
+		if err != nil {
+			j.logger.Warn().Err(err).Str("user_id", userID).Msg("Failed to create readiness forecast task")
+			continue
+		}
+		if _, err := j.Client.EnqueueContext(ctx, task); err != nil {
+			j.logger.Warn().Err(err).Str("user_id", userID).Msg("Failed to enqueue readiness forecast task")
+			continue
+		}
+		enqueued++
+	}
+
+	j.logger.Info().
+		Str("type", "readiness_forecast_batch").
+		Int("selected", len(userIDs)).
+		Int("enqueued", enqueued).
+		Msg("Enqueued readiness forecast tasks")
+
+	return nil
+}
+
+func (j *JobService) handleReadinessForecastTask(ctx context.Context, t *asynq.Task) error {
+	var p ReadinessForecastPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal readiness forecast payload: %w", err)
+	}
+
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	sections, err := j.fetchForecastSections(ctx, p.UserID)
+	if err != nil {
+		j.logger.Error().Err(err).Str("user_id", p.UserID).Msg("Failed to fetch readiness for forecast")
+		return fmt.Errorf("failed to fetch readiness: %w", err)
+	}
+
+	taskID, _ := asynq.GetTaskID(ctx)
+	now := time.Now()
+	checkpoints := forecastCheckpoints(now)
+
+	for _, section := range sections {
+		responses, err := j.fetchTimedResponses(ctx, p.UserID, section.Section)
+		if err != nil {
+			return fmt.Errorf("failed to fetch responses for %s: %w", section.Section, err)
+		}
+
+		// The current estimate uses every answer; the trend only the checkpoint history
+		current, _ := scoring.EstimateTheta(timedToResponses(responses))
+		history := scoring.ThetaHistory(responses, checkpoints, minForecastResponses)
+
+		target := readiness.DefaultTargetTheta
+		if section.TargetTheta != nil {
+			target = *section.TargetTheta
+		}
+
+		var forecast *scoring.Forecast
+		if rate, ok := scoring.TrendPerWeek(history); ok {
+			f := scoring.ForecastReady(current, readiness.ReadyTheta(target), rate, now)
+			forecast = &f
+		}
+
+		if err := j.saveForecast(ctx, p.UserID, section.Section, forecast, taskID); err != nil {
+			return fmt.Errorf("failed to save forecast for %s: %w", section.Section, err)
+		}
+
+		event := j.logger.Debug().
+			Str("type", "readiness_forecast").
+			Str("user_id", p.UserID).
+			Str("section", section.Section).
+			Int("checkpoints", len(history))
+		if forecast != nil {
+			event = event.Float64("rate_per_week", forecast.RatePerWeek)
+			if forecast.DaysToReady != nil {
+				event = event.Int("days_to_ready", *forecast.DaysToReady)
+			}
+		}
+		event.Msg("Readiness forecast updated")
+	}
+
+	j.logger.Info().
+		Str("type", "readiness_forecast").
+		Str("user_id", p.UserID).
+		Int("sections", len(sections)).
+		Msg("Readiness forecast completed")
+
+	return nil
+}
+
+// forecastCheckpoints returns weekly checkpoints over the forecast window, oldest first,
+// ending now
+func forecastCheckpoints(now time.Time) []time.Time {
+	checkpoints := make([]time.Time, 0, forecastWindowWeeks+1)
+	for week := forecastWindowWeeks; week >= 0; week-- {
+		checkpoints = append(checkpoints, now.AddDate(0, 0, -7*week))
+	}
+	return checkpoints
+}
+
+func (j *JobService) fetchForecastSections(ctx context.Context, userID string) ([]forecastSection, error) {
+	rows, err := db.Pool.Query(ctx, `
+		SELECT section, target_theta::float8
+		FROM user_readiness
+		WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (forecastSection, error) {
+		var s forecastSection
+		err := row.Scan(&s.Section, &s.TargetTheta)
+		return s, err
+	})
+}
+
+// fetchTimedResponses returns the user's scored answers in a section, oldest first
+func (j *JobService) fetchTimedResponses(ctx context.Context, userID, section string) ([]scoring.TimedResponse, error) {
+	rows, err := db.Pool.Query(ctx, `
+		SELECT COALESCE(q.difficulty_irt, 0)::float8, COALESCE(q.discrimination, 1)::float8, a.is_correct, a.created_at
+		FROM attempts a
+		JOIN questions q ON q.id = a.question_id
+		WHERE a.user_id = $1 AND q.section = $2 AND a.deleted_at IS NULL
+		ORDER BY a.created_at
+	`, userID, section)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (scoring.TimedResponse, error) {
+		var r scoring.TimedResponse
+		err := row.Scan(&r.Difficulty, &r.Discrimination, &r.Correct, &r.At)
+		return r, err
+	})
+}
+
+// saveForecast stores the forecast; a nil forecast clears fields the history can no longer support
+func (j *JobService) saveForecast(ctx context.Context, userID, section string, forecast *scoring.Forecast, taskID string) error {
+	var rate *float64
+	var daysToReady *int
+	var readyBy *time.Time
+	if forecast != nil {
+		rate = &forecast.RatePerWeek
+		daysToReady = forecast.DaysToReady
+		readyBy = forecast.ReadyBy
+	}
+
+	_, err := db.Pool.Exec(ctx, `
+		UPDATE user_readiness
+		SET improvement_rate_per_week = $3,
+			days_to_ready = $4,
+			ready_by_date = $5,
+			updated_by_job_id = NULLIF($6, ''),
+			last_updated = NOW()
+		WHERE user_id = $1 AND section = $2
+	`, userID, section, rate, daysToReady, readyBy, taskID)
+	return err
+}
+
+func timedToResponses(timed []scoring.TimedResponse) []scoring.Response {
+	responses := make([]scoring.Response, len(timed))
+	for i, r := range timed {
+		responses[i] = r.Response
+	}
+	return responses
+}
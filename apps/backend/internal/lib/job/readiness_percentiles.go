@@ -0,0 +1,83 @@
+package job
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/manikandareas/genta/internal/model/readiness"
+)
+
+// percentileMinAttempts keeps sections with too few answers for a stable theta out of the ranking
+const percentileMinAttempts = 5
+
+func (j *JobService) handleReadinessPercentilesTask(ctx context.Context, t *asynq.Task) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Ranks are rebuilt from scratch so users who left a cohort, or whose cohort shrank
+	// below the minimum size, lose their stale ranks
+	if _, err := tx.Exec(ctx, `DELETE FROM readiness_percentiles`); err != nil {
+		return fmt.Errorf("failed to clear readiness percentiles: %w", err)
+	}
+
+	// Percentile is the share of the cohort with a lower theta; cohorts below the minimum
+	// size are never written so no rank can single out a small group of users
+	tag, err := tx.Exec(ctx, `
+		WITH scores AS (
+			SELECT user_id, section, current_theta AS theta
+			FROM user_readiness
+			WHERE current_theta IS NOT NULL AND total_attempts_count >= $1
+			UNION ALL
+			SELECT user_id, $3::VARCHAR, AVG(current_theta)
+			FROM user_readiness
+			WHERE current_theta IS NOT NULL AND total_attempts_count >= $1
+			GROUP BY user_id
+		),
+		members AS (
+			SELECT s.user_id, s.section, s.theta, c.cohort, c.cohort_key
+			FROM scores s
+			JOIN users u ON u.id = s.user_id
+			CROSS JOIN LATERAL (VALUES
+				($4::TEXT, 'all'),
+				($5::TEXT, COALESCE(u.target_ptn_id::TEXT, NULLIF(LOWER(TRIM(u.target_ptn)), ''))),
+				($6::TEXT, CASE WHEN u.exam_date >= CURRENT_DATE THEN TO_CHAR(u.exam_date, 'YYYY-MM') END)
+			) AS c(cohort, cohort_key)
+			WHERE c.cohort_key IS NOT NULL
+		),
+		ranked AS (
+			SELECT user_id, section, cohort, cohort_key,
+				PERCENT_RANK() OVER cohort_window * 100 AS percentile,
+				COUNT(*) OVER (PARTITION BY cohort, cohort_key, section) AS cohort_size
+			FROM members
+			WINDOW cohort_window AS (PARTITION BY cohort, cohort_key, section ORDER BY theta)
+		)
+		INSERT INTO readiness_percentiles (user_id, cohort, cohort_key, section, percentile, cohort_size)
+		SELECT user_id, cohort, cohort_key, section, ROUND(percentile::NUMERIC, 2), cohort_size
+		FROM ranked
+		WHERE cohort_size >= $2
+	`, percentileMinAttempts, readiness.MinCohortSize, readiness.SectionOverall,
+		readiness.CohortAll, readiness.CohortTargetPTN, readiness.CohortExamMonth)
+	if err != nil {
+		j.logger.Error().Err(err).Msg("Failed to compute readiness percentiles")
+		return fmt.Errorf("failed to compute readiness percentiles: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit readiness percentiles: %w", err)
+	}
+
+	j.logger.Info().
+		Str("type", "readiness_percentiles").
+		Int64("ranks", tag.RowsAffected()).
+		Msg("Readiness percentiles materialized")
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	// rollupLookbackDays re-rolls recent days so a missed nightly run is caught up
+	rollupLookbackDays = 3
+	// snapshotRetentionDays is how long raw snapshots are kept once rolled up
+	snapshotRetentionDays = 90
+)
+
+func (j *JobService) handleReadinessRollupTask(ctx context.Context, t *asynq.Task) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := today.AddDate(0, 0, -rollupLookbackDays)
+
+	// Each day keeps its closing snapshot; today is left to the raw snapshots until tomorrow
+	tag, err := db.Pool.Exec(ctx, `
+		INSERT INTO readiness_daily (
+			user_id, section, day, theta, theta_se, readiness_percentage, predicted_score, total_attempts, snapshot_count
+		)
+		SELECT DISTINCT ON (user_id, section, DATE(recorded_at))
+			user_id, section, DATE(recorded_at), theta, theta_se, readiness_percentage, predicted_score, total_attempts,
+			COUNT(*) OVER (PARTITION BY user_id, section, DATE(recorded_at))
+		FROM readiness_snapshots
+		WHERE recorded_at >= $1 AND recorded_at < $2
+		ORDER BY user_id, section, DATE(recorded_at), recorded_at DESC
+		ON CONFLICT (user_id, section, day) DO UPDATE SET
+			theta = EXCLUDED.theta,
+			theta_se = EXCLUDED.theta_se,
+			readiness_percentage = EXCLUDED.readiness_percentage,
+			predicted_score = EXCLUDED.predicted_score,
+			total_attempts = EXCLUDED.total_attempts,
+			snapshot_count = EXCLUDED.snapshot_count,
+			updated_at = NOW()
+	`, from, today)
+	if err != nil {
+		j.logger.Error().Err(err).Msg("Failed to roll up readiness snapshots")
+		return fmt.Errorf("failed to roll up readiness snapshots: %w", err)
+	}
+
+	// Raw snapshots past retention are only deleted once their day has been rolled up
+	deleted, err := db.Pool.Exec(ctx, `
+		DELETE FROM readiness_snapshots s
+		WHERE s.recorded_at < $1
+			AND EXISTS (
+				SELECT 1 FROM readiness_daily d
+				WHERE d.user_id = s.user_id AND d.section = s.section AND d.day = DATE(s.recorded_at)
+			)
+	`, today.AddDate(0, 0, -snapshotRetentionDays))
+	if err != nil {
+		j.logger.Error().Err(err).Msg("Failed to prune readiness snapshots")
+		return fmt.Errorf("failed to prune readiness snapshots: %w", err)
+	}
+
+	j.logger.Info().
+		Str("type", "readiness_rollup").
+		Int64("days_rolled_up", tag.RowsAffected()).
+		Int64("snapshots_pruned", deleted.RowsAffected()).
+		Msg("Readiness snapshots rolled up")
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package job
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskReadinessForecast      = "readiness:forecast"
+	TaskReadinessForecastBatch = "readiness:forecast_batch"
+	TaskReadinessRollup        = "readiness:rollup"
+	TaskReadinessPercentiles   = "readiness:percentiles"
+
+	// readinessForecastSchedule runs the nightly forecast batch at 02:00 server time
+	readinessForecastSchedule = "0 2 * * *"
+	// readinessRollupSchedule rolls up the previous days' snapshots at 01:30 server time
+	readinessRollupSchedule = "30 1 * * *"
+	// readinessPercentilesSchedule ranks users after the forecast batch, at 03:00 server time
+	readinessPercentilesSchedule = "0 3 * * *"
+)
+
+// ReadinessForecastPayload contains data needed to forecast a user's readiness
+type ReadinessForecastPayload struct {
+	UserID string `json:"user_id"`
+}
+
+// NewReadinessForecastTask creates a task that forecasts readiness for every section of a user
+func NewReadinessForecastTask(userID string) (*asynq.Task, error) {
+	payload, err := json.Marshal(ReadinessForecastPayload{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskReadinessForecast, payload,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(2*time.Minute),
+		asynq.Retention(24*time.Hour),
+	), nil
+}
+
+// NewReadinessForecastBatchTask creates a task that fans out forecasts for recently active users
+func NewReadinessForecastBatchTask() *asynq.Task {
+	return asynq.NewTask(TaskReadinessForecastBatch, nil,
+		asynq.MaxRetry(1),
+		asynq.Queue("low"),
+		asynq.Timeout(10*time.Minute),
+		asynq.Unique(time.Hour),
+	)
+}
+
+// NewReadinessRollupTask creates a task that rolls readiness snapshots up into daily history
+func NewReadinessRollupTask() *asynq.Task {
+	return asynq.NewTask(TaskReadinessRollup, nil,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(10*time.Minute),
+		asynq.Unique(time.Hour),
+	)
+}
+
+// NewReadinessPercentilesTask creates a task that materializes cohort percentile ranks
+func NewReadinessPercentilesTask() *asynq.Task {
+	return asynq.NewTask(TaskReadinessPercentiles, nil,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(10*time.Minute),
+		asynq.Unique(time.Hour),
+	)
+}
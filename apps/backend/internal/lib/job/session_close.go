@@ -0,0 +1,123 @@
+package job
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/model/event"
+	"github.com/manikandareas/genta/internal/model/session"
+)
+
+func (j *JobService) handleSessionCloseIdleTask(ctx context.Context, t *asynq.Task) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized for job handlers")
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Find idle sessions first, without locks, so open sessions in use are never touched
+	rows, err := tx.Query(ctx, `
+		SELECT s.id
+		FROM user_study_sessions s
+		LEFT JOIN attempts a ON a.session_id = s.id AND a.deleted_at IS NULL
+		WHERE s.ended_at IS NULL
+		GROUP BY s.id, s.started_at
+		HAVING GREATEST(s.started_at, MAX(a.created_at)) < NOW() - make_interval(mins => $1)
+	`, session.IdleTimeoutMinutes)
+	if err != nil {
+		return fmt.Errorf("failed to find idle sessions: %w", err)
+	}
+
+	candidates, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("failed to collect idle sessions: %w", err)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// Lock only the candidates. Locked ones are skipped; an attempt is being recorded in them right now.
+	rows, err = tx.Query(ctx, `
+		SELECT id FROM user_study_sessions
+		WHERE id = ANY($1::text[]) AND ended_at IS NULL
+		FOR UPDATE SKIP LOCKED
+	`, candidates)
+	if err != nil {
+		return fmt.Errorf("failed to lock idle sessions: %w", err)
+	}
+
+	locked, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("failed to collect locked sessions: %w", err)
+	}
+	if len(locked) == 0 {
+		return nil
+	}
+
+	// Idleness is checked again under the locks, since an attempt may have landed after the
+	// first query. A session ends at its last activity, so idle time does not inflate its duration.
+	rows, err = tx.Query(ctx, `
+		WITH idle AS (
+			SELECT s.id, GREATEST(s.started_at, MAX(a.created_at)) AS last_activity
+			FROM user_study_sessions s
+			LEFT JOIN attempts a ON a.session_id = s.id AND a.deleted_at IS NULL
+			WHERE s.id = ANY($2::text[]) AND s.ended_at IS NULL
+			GROUP BY s.id, s.started_at
+			HAVING GREATEST(s.started_at, MAX(a.created_at)) < NOW() - make_interval(mins => $1)
+		)
+		UPDATE user_study_sessions s
+		SET ended_at = idle.last_activity,
+			duration_minutes = FLOOR(EXTRACT(EPOCH FROM idle.last_activity - s.started_at) / 60)::SMALLINT,
+			accuracy_in_session = CASE
+				WHEN s.questions_attempted > 0 THEN s.questions_correct::DECIMAL / s.questions_attempted
+			END,
+			updated_at = NOW()
+		FROM idle
+		WHERE s.id = idle.id
+		RETURNING s.id, s.user_id, s.started_at, s.ended_at, s.duration_minutes,
+			s.questions_attempted, s.questions_correct, s.accuracy_in_session,
+			s.section, s.created_at, s.updated_at
+	`, session.IdleTimeoutMinutes, locked)
+	if err != nil {
+		return fmt.Errorf("failed to close idle sessions: %w", err)
+	}
+
+	closed, err := pgx.CollectRows(rows, pgx.RowToStructByName[session.Session])
+	if err != nil {
+		return fmt.Errorf("failed to collect closed sessions: %w", err)
+	}
+
+	for _, sess := range closed {
+		ended := event.SessionEnded{
+			SessionID:          sess.ID,
+			UserID:             sess.UserID,
+			Section:            sess.Section,
+			DurationMinutes:    sess.DurationMinutes,
+			QuestionsAttempted: sess.QuestionsAttempted,
+			QuestionsCorrect:   sess.QuestionsCorrect,
+		}
+		if err := appendOutboxEvent(ctx, tx, ended); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit closed sessions: %w", err)
+	}
+
+	if len(closed) > 0 {
+		j.logger.Info().
+			Str("type", "session_close_idle").
+			Int("closed", len(closed)).
+			Int("idle_timeout_minutes", session.IdleTimeoutMinutes).
+			Msg("Closed idle sessions")
+	}
+
+	return nil
+}
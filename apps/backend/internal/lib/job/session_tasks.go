@@ -0,0 +1,24 @@
+package job
+
+import (
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskSessionCloseIdle = "session:close_idle"
+
+	// sessionCloseIdleSchedule checks for idle sessions every 5 minutes
+	sessionCloseIdleSchedule = "*/5 * * * *"
+)
+
+// NewSessionCloseIdleTask creates a task that ends sessions idle past the timeout
+func NewSessionCloseIdleTask() *asynq.Task {
+	return asynq.NewTask(TaskSessionCloseIdle, nil,
+		asynq.MaxRetry(1),
+		asynq.Queue("low"),
+		asynq.Timeout(2*time.Minute),
+		asynq.Unique(4*time.Minute),
+	)
+}
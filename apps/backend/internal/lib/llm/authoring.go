@@ -0,0 +1,108 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AuthoringQuestion contains the question an authoring prompt is based on
+type AuthoringQuestion struct {
+	Section       string
+	SubType       string
+	Text          string
+	Options       []string
+	CorrectAnswer string
+	Explanation   string
+	DifficultyIRT *float64
+}
+
+// SystemPromptAuthoring returns the system prompt for drafting question content
+func SystemPromptAuthoring() string {
+	return `Kamu adalah penulis soal UTBK SNBT yang berpengalaman dan teliti.
+Tugasmu adalah menyusun konten soal (pembahasan, tips, langkah penyelesaian, atau soal baru) untuk direview editor.
+
+Aturan:
+- Konten harus akurat secara fakta dan konsisten dengan kunci jawaban
+- Gunakan Bahasa Indonesia baku, kecuali field yang diminta dalam Bahasa Inggris
+- Jangan menyalin soal sumber secara verbatim saat membuat soal baru
+- Selalu jawab dengan SATU objek JSON yang valid sesuai format yang diminta, tanpa teks lain`
+}
+
+// BuildEnrichmentPrompt builds the prompt for drafting missing fields of a question
+func BuildEnrichmentPrompt(q AuthoringQuestion, fields []string) string {
+	var prompt strings.Builder
+
+	writeAuthoringQuestion(&prompt, q)
+
+	prompt.WriteString("\nSusun field berikut untuk soal di atas:\n")
+	for _, field := range fields {
+		switch field {
+		case "explanation":
+			prompt.WriteString(`- "explanation": pembahasan dalam Bahasa Indonesia (3-6 kalimat) yang menjelaskan mengapa kunci jawaban benar dan pilihan lain salah` + "\n")
+		case "explanation_en":
+			prompt.WriteString(`- "explanation_en": the explanation in English (3-6 sentences)` + "\n")
+		case "strategy_tip":
+			prompt.WriteString(`- "strategy_tip": satu tips strategi singkat (1-2 kalimat) untuk soal sejenis` + "\n")
+		case "solution_steps":
+			prompt.WriteString(`- "solution_steps": array langkah penyelesaian [{"order": 1, "title": "...", "content": "..."}], 2-6 langkah` + "\n")
+		}
+	}
+
+	prompt.WriteString("\nJawab dengan objek JSON yang hanya berisi field di atas.")
+	return prompt.String()
+}
+
+// BuildVariantPrompt builds the prompt for generating new questions from a template question
+func BuildVariantPrompt(q AuthoringQuestion, count int, targetDifficulty *float64) string {
+	var prompt strings.Builder
+
+	prompt.WriteString("Soal template:\n")
+	writeAuthoringQuestion(&prompt, q)
+
+	prompt.WriteString(fmt.Sprintf("\nBuat %d soal baru yang menguji konsep dan keterampilan yang sama dengan soal template, ", count))
+	prompt.WriteString("dengan konteks, angka, atau teks yang berbeda.\n")
+
+	if targetDifficulty != nil {
+		prompt.WriteString(fmt.Sprintf("Target tingkat kesulitan (skala IRT -3 sangat mudah sampai 3 sangat sulit): %.1f", *targetDifficulty))
+		if q.DifficultyIRT != nil {
+			prompt.WriteString(fmt.Sprintf(" (soal template: %.1f)", *q.DifficultyIRT))
+		}
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString(`
+Setiap soal harus memiliki tepat 5 pilihan dan tepat satu jawaban benar.
+Format JSON:
+{"variants": [{"text": "...", "options": ["...", "...", "...", "...", "..."], "correct_answer": "A",
+"explanation": "...", "explanation_en": "...", "strategy_tip": "...",
+"solution_steps": [{"order": 1, "title": "...", "content": "..."}]}]}
+Isi "options" tanpa label huruf.`)
+
+	return prompt.String()
+}
+
+// AuthoringPromptVersion returns the current version of the authoring prompt templates
+func AuthoringPromptVersion() string {
+	return "authoring-v1.0.0"
+}
+
+func writeAuthoringQuestion(prompt *strings.Builder, q AuthoringQuestion) {
+	optionLabels := []string{"A", "B", "C", "D", "E"}
+
+	prompt.WriteString(fmt.Sprintf("Subtes: %s", q.Section))
+	if q.SubType != "" {
+		prompt.WriteString(fmt.Sprintf(" (%s)", q.SubType))
+	}
+	prompt.WriteString("\n\n")
+	prompt.WriteString(fmt.Sprintf("Soal:\n%s\n\n", q.Text))
+	prompt.WriteString("Pilihan:\n")
+	for i, opt := range q.Options {
+		if i < len(optionLabels) {
+			prompt.WriteString(fmt.Sprintf("%s. %s\n", optionLabels[i], opt))
+		}
+	}
+	prompt.WriteString(fmt.Sprintf("Jawaban Benar: %s\n", q.CorrectAnswer))
+	if q.Explanation != "" {
+		prompt.WriteString(fmt.Sprintf("Pembahasan: %s\n", q.Explanation))
+	}
+}
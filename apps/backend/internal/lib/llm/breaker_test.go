@@ -0,0 +1,96 @@
+package llm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		run       func(b *CircuitBreaker)
+		wantState BreakerState
+		wantAllow bool
+	}{
+		{
+			name:      "starts closed",
+			threshold: 3,
+			run:       func(b *CircuitBreaker) {},
+			wantState: BreakerClosed,
+			wantAllow: true,
+		},
+		{
+			name:      "stays closed below the threshold",
+			threshold: 3,
+			run: func(b *CircuitBreaker) {
+				b.RecordFailure()
+				b.RecordFailure()
+			},
+			wantState: BreakerClosed,
+			wantAllow: true,
+		},
+		{
+			name:      "opens at the threshold",
+			threshold: 3,
+			run: func(b *CircuitBreaker) {
+				b.RecordFailure()
+				b.RecordFailure()
+				b.RecordFailure()
+			},
+			wantState: BreakerOpen,
+			wantAllow: false,
+		},
+		{
+			name:      "success resets the count",
+			threshold: 3,
+			run: func(b *CircuitBreaker) {
+				b.RecordFailure()
+				b.RecordFailure()
+				b.RecordSuccess()
+				b.RecordFailure()
+				b.RecordFailure()
+			},
+			wantState: BreakerClosed,
+			wantAllow: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCircuitBreaker(tt.threshold, time.Hour)
+			tt.run(b)
+			assert.Equal(t, tt.wantState, b.State())
+			assert.Equal(t, tt.wantAllow, b.Allow())
+		})
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name      string
+		probe     func(b *CircuitBreaker)
+		wantState BreakerState
+	}{
+		{name: "successful probe closes", probe: (*CircuitBreaker).RecordSuccess, wantState: BreakerClosed},
+		{name: "failed probe reopens", probe: (*CircuitBreaker).RecordFailure, wantState: BreakerOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCircuitBreaker(1, 10*time.Millisecond)
+			b.RecordFailure()
+			assert.False(t, b.Allow())
+
+			time.Sleep(20 * time.Millisecond)
+			assert.True(t, b.Allow(), "one probe passes after the cooldown")
+			assert.Equal(t, BreakerHalfOpen, b.State())
+			assert.False(t, b.Allow(), "only one probe at a time")
+
+			tt.probe(b)
+			assert.Equal(t, tt.wantState, b.State())
+		})
+	}
+}
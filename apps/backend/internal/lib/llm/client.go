@@ -2,7 +2,10 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"strings"
 	"time"
 
 	"github.com/manikandareas/genta/internal/config"
@@ -10,11 +13,52 @@ import (
 	"github.com/sashabaranov/go-openai"
 )
 
-// Client wraps OpenAI client with logging and metrics
+const (
+	ProviderOpenAI    = "openai"
+	ProviderSecondary = "secondary"
+
+	// ModelStaticExplanation marks feedback served from questions.explanation
+	// because no LLM provider was available
+	ModelStaticExplanation = "static_explanation"
+
+	defaultMaxRetries              = 2
+	defaultRequestTimeout          = 20 * time.Second
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerCooldown         = 30 * time.Second
+)
+
+// provider is an OpenAI-compatible API endpoint with its own circuit breaker
+type provider struct {
+	name    string
+	client  *openai.Client
+	breaker *CircuitBreaker
+}
+
+// route is a model on a provider, tried in order until one succeeds
+type route struct {
+	provider *provider
+	model    string
+}
+
+// BudgetGuard is checked before every generation. An error wrapping ErrBudgetExceeded
+// blocks the generation; other errors are logged and the generation goes ahead, so a
+// ledger read failure does not stop all LLM use.
+type BudgetGuard func(ctx context.Context) error
+
+// Client wraps OpenAI-compatible clients with retries, circuit breakers, fallback models,
+// logging and metrics
 type Client struct {
-	client *openai.Client
-	model  string
-	logger *zerolog.Logger
+	routes         []route
+	model          string
+	maxRetries     int
+	requestTimeout time.Duration
+	budgetGuard    BudgetGuard
+	logger         *zerolog.Logger
+}
+
+// SetBudgetGuard sets the spend check every generation goes through
+func (c *Client) SetBudgetGuard(guard BudgetGuard) {
+	c.budgetGuard = guard
 }
 
 // NewClient creates a new LLM client
@@ -26,65 +70,243 @@ func NewClient(cfg *config.Config, logger *zerolog.Logger) *Client {
 		model = openai.GPT4oMini // Default to cost-effective model
 	}
 
-	var client *openai.Client
+	llmCfg := cfg.LLM
+	maxRetries := llmCfg.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultMaxRetries
+	}
+	requestTimeout := time.Duration(llmCfg.RequestTimeoutSeconds) * time.Second
+	if requestTimeout == 0 {
+		requestTimeout = defaultRequestTimeout
+	}
+	threshold := llmCfg.BreakerFailureThreshold
+	if threshold == 0 {
+		threshold = defaultBreakerFailureThreshold
+	}
+	cooldown := time.Duration(llmCfg.BreakerCooldownSeconds) * time.Second
+	if cooldown == 0 {
+		cooldown = defaultBreakerCooldown
+	}
+
+	var routes []route
+
 	if apiKey != "" {
-		client = openai.NewClient(apiKey)
+		primary := &provider{
+			name:    ProviderOpenAI,
+			client:  openai.NewClientWithConfig(withHintingDoer(openai.DefaultConfig(apiKey))),
+			breaker: NewCircuitBreaker(threshold, cooldown),
+		}
+		for _, m := range append([]string{model}, splitModels(llmCfg.FallbackModels)...) {
+			routes = append(routes, route{provider: primary, model: m})
+		}
+	}
+
+	if llmCfg.FallbackBaseURL != "" && llmCfg.FallbackAPIKey != "" {
+		secondaryCfg := openai.DefaultConfig(llmCfg.FallbackAPIKey)
+		secondaryCfg.BaseURL = llmCfg.FallbackBaseURL
+		secondary := &provider{
+			name:    ProviderSecondary,
+			client:  openai.NewClientWithConfig(withHintingDoer(secondaryCfg)),
+			breaker: NewCircuitBreaker(threshold, cooldown),
+		}
+		for _, m := range splitModels(llmCfg.FallbackModelsSecondary) {
+			routes = append(routes, route{provider: secondary, model: m})
+		}
 	}
 
 	return &Client{
-		client: client,
-		model:  model,
-		logger: logger,
+		routes:         dedupRoutes(routes),
+		model:          model,
+		maxRetries:     maxRetries,
+		requestTimeout: requestTimeout,
+		logger:         logger,
 	}
 }
 
-// IsConfigured returns true if the client has valid API key
+// IsConfigured returns true if at least one provider has a valid API key
 func (c *Client) IsConfigured() bool {
-	return c.client != nil
+	return len(c.routes) > 0
 }
 
 // GenerationResult contains the result of a text generation
 type GenerationResult struct {
 	Text             string
 	Model            string
+	Provider         string
 	GenerationTimeMs int
 	TokensInput      int
 	TokensOutput     int
+	// FallbackUsed is true when the primary model did not produce the result
+	FallbackUsed bool
 }
 
-// GenerateText generates text using the configured model
+// generationRequest holds per-call generation options
+type generationRequest struct {
+	systemPrompt string
+	userPrompt   string
+	jsonMode     bool
+	maxTokens    int
+	timeout      time.Duration
+}
+
+// GenerateText generates text with the primary model, retrying transient failures with
+// backoff and falling back to the next model when a model keeps failing or its provider's
+// circuit breaker is open. Returns an error wrapping ErrUnavailable when every model failed.
 func (c *Client) GenerateText(ctx context.Context, systemPrompt, userPrompt string) (*GenerationResult, error) {
+	return c.generateWithFallback(ctx, generationRequest{
+		systemPrompt: systemPrompt,
+		userPrompt:   userPrompt,
+		maxTokens:    500,
+		timeout:      c.requestTimeout,
+	})
+}
+
+// GenerateJSON is like GenerateText but asks the model for a single JSON object.
+// The prompt must describe the expected JSON shape.
+func (c *Client) GenerateJSON(ctx context.Context, systemPrompt, userPrompt string) (*GenerationResult, error) {
+	return c.generateWithFallback(ctx, generationRequest{
+		systemPrompt: systemPrompt,
+		userPrompt:   userPrompt,
+		jsonMode:     true,
+		maxTokens:    2500,
+		// Structured authoring output is much longer than feedback
+		timeout: 3 * c.requestTimeout,
+	})
+}
+
+func (c *Client) generateWithFallback(ctx context.Context, req generationRequest) (*GenerationResult, error) {
 	if !c.IsConfigured() {
 		return nil, fmt.Errorf("LLM client not configured: missing API key")
 	}
 
+	if c.budgetGuard != nil {
+		if err := c.budgetGuard(ctx); errors.Is(err, ErrBudgetExceeded) {
+			return nil, err
+		} else if err != nil {
+			c.logger.Warn().Err(err).Msg("Failed to check LLM spend budget, generating anyway")
+		}
+	}
+
+	var lastErr error
+	for i, rt := range c.routes {
+		if !rt.provider.breaker.Allow() {
+			c.logger.Warn().
+				Str("provider", rt.provider.name).
+				Str("model", rt.model).
+				Msg("LLM circuit breaker open, skipping model")
+			continue
+		}
+
+		result, err := c.generateWithRetry(ctx, rt, req)
+		if err == nil {
+			result.FallbackUsed = i > 0
+			return result, nil
+		}
+		lastErr = err
+
+		// Request-level failures (e.g. content filter) would fail on every model
+		var llmErr *Error
+		if errors.As(err, &llmErr) && !llmErr.ProviderFailure() {
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnavailable, ctx.Err())
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = errors.New("all circuit breakers open")
+	}
+	return nil, fmt.Errorf("%w: %w", ErrUnavailable, lastErr)
+}
+
+// generateWithRetry calls a single model, retrying retryable errors with kind-aware backoff.
+// A wait requested by the provider replaces the backoff; one longer than maxRetryAfter
+// gives up on the model so the caller falls back to the next one.
+func (c *Client) generateWithRetry(ctx context.Context, rt route, req generationRequest) (*GenerationResult, error) {
+	var lastErr *Error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := backoff(lastErr.Kind, attempt)
+			if lastErr.RetryAfter > 0 {
+				delay = lastErr.RetryAfter
+			}
+			c.logger.Warn().
+				Str("provider", rt.provider.name).
+				Str("model", rt.model).
+				Str("error_kind", string(lastErr.Kind)).
+				Int("attempt", attempt).
+				Dur("backoff", delay).
+				Msg("Retrying LLM generation")
+
+			select {
+			case <-ctx.Done():
+				return nil, lastErr
+			case <-time.After(delay):
+			}
+		}
+
+		callCtx, hint := withRetryHint(ctx)
+		result, err := c.generate(callCtx, rt, req)
+		if err == nil {
+			rt.provider.breaker.RecordSuccess()
+			return result, nil
+		}
+
+		lastErr = classifyError(err, rt.provider.name, rt.model)
+		lastErr.RetryAfter = hint.after
+		if !lastErr.ProviderFailure() {
+			// The provider answered; the request itself was rejected
+			rt.provider.breaker.RecordSuccess()
+			return nil, lastErr
+		}
+
+		rt.provider.breaker.RecordFailure()
+		if !lastErr.Retryable() || rt.provider.breaker.State() == BreakerOpen || lastErr.RetryAfter > maxRetryAfter {
+			return nil, lastErr
+		}
+	}
+
+	return nil, lastErr
+}
+
+// generate makes a single chat completion call
+func (c *Client) generate(ctx context.Context, rt route, req generationRequest) (*GenerationResult, error) {
+	callCtx, cancel := context.WithTimeout(ctx, req.timeout)
+	defer cancel()
+
 	start := time.Now()
 
-	resp, err := c.client.CreateChatCompletion(
-		ctx,
-		openai.ChatCompletionRequest{
-			Model: c.model,
-			Messages: []openai.ChatCompletionMessage{
-				{
-					Role:    openai.ChatMessageRoleSystem,
-					Content: systemPrompt,
-				},
-				{
-					Role:    openai.ChatMessageRoleUser,
-					Content: userPrompt,
-				},
+	chatReq := openai.ChatCompletionRequest{
+		Model: rt.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: req.systemPrompt,
+			},
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: req.userPrompt,
 			},
-			Temperature:         0.7,
-			MaxCompletionTokens: 500,
 		},
-	)
+		Temperature:         0.7,
+		MaxCompletionTokens: req.maxTokens,
+	}
+	if req.jsonMode {
+		chatReq.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		}
+	}
+
+	resp, err := rt.provider.client.CreateChatCompletion(callCtx, chatReq)
 
 	generationTime := int(time.Since(start).Milliseconds())
 
 	if err != nil {
 		c.logger.Error().
 			Err(err).
-			Str("model", c.model).
+			Str("provider", rt.provider.name).
+			Str("model", rt.model).
 			Int("generation_time_ms", generationTime).
 			Msg("LLM generation failed")
 		return nil, fmt.Errorf("failed to generate text: %w", err)
@@ -94,16 +316,22 @@ func (c *Client) GenerateText(ctx context.Context, systemPrompt, userPrompt stri
 		return nil, fmt.Errorf("no response from LLM")
 	}
 
+	if resp.Choices[0].FinishReason == openai.FinishReasonContentFilter {
+		return nil, &Error{Kind: ErrorKindContentFilter, Provider: rt.provider.name, Model: rt.model, Err: errors.New("response blocked by content filter")}
+	}
+
 	result := &GenerationResult{
 		Text:             resp.Choices[0].Message.Content,
-		Model:            c.model,
+		Model:            rt.model,
+		Provider:         rt.provider.name,
 		GenerationTimeMs: generationTime,
 		TokensInput:      resp.Usage.PromptTokens,
 		TokensOutput:     resp.Usage.CompletionTokens,
 	}
 
 	c.logger.Info().
-		Str("model", c.model).
+		Str("provider", rt.provider.name).
+		Str("model", rt.model).
 		Int("generation_time_ms", generationTime).
 		Int("tokens_input", result.TokensInput).
 		Int("tokens_output", result.TokensOutput).
@@ -111,3 +339,45 @@ func (c *Client) GenerateText(ctx context.Context, systemPrompt, userPrompt stri
 
 	return result, nil
 }
+
+// backoff returns the delay before retry number attempt (1-based) using exponential
+// backoff with full jitter. Rate limits back off longer than server errors.
+func backoff(kind ErrorKind, attempt int) time.Duration {
+	base, maxDelay := 500*time.Millisecond, 5*time.Second
+	switch kind {
+	case ErrorKindRateLimit:
+		base, maxDelay = 2*time.Second, 20*time.Second
+	case ErrorKindTimeout:
+		base, maxDelay = time.Second, 8*time.Second
+	}
+
+	delay := base << (attempt - 1)
+	if delay > maxDelay || delay <= 0 {
+		delay = maxDelay
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+func splitModels(s string) []string {
+	var models []string
+	for _, m := range strings.Split(s, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			models = append(models, m)
+		}
+	}
+	return models
+}
+
+func dedupRoutes(routes []route) []route {
+	seen := make(map[string]bool, len(routes))
+	out := routes[:0]
+	for _, rt := range routes {
+		key := rt.provider.name + "/" + rt.model
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, rt)
+	}
+	return out
+}
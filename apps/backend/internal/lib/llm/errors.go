@@ -0,0 +1,135 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// ErrUnavailable is returned when every configured model failed or was skipped by its circuit breaker
+var ErrUnavailable = errors.New("all LLM providers unavailable")
+
+// ErrBudgetExceeded is returned without calling a provider when the spend budget is used up
+var ErrBudgetExceeded = errors.New("LLM spend budget exceeded")
+
+// ErrorKind classifies an LLM failure to decide whether to retry, back off or fall back
+type ErrorKind string
+
+const (
+	ErrorKindRateLimit     ErrorKind = "rate_limit"
+	ErrorKindTimeout       ErrorKind = "timeout"
+	ErrorKindContentFilter ErrorKind = "content_filter"
+	ErrorKindServer        ErrorKind = "server_error"
+	ErrorKindAuth          ErrorKind = "auth"
+	ErrorKindBadRequest    ErrorKind = "bad_request"
+	ErrorKindUnknown       ErrorKind = "unknown"
+)
+
+// Error is a classified LLM call failure
+type Error struct {
+	Kind       ErrorKind
+	Provider   string
+	Model      string
+	StatusCode int
+	// RetryAfter is how long the provider asked to wait before retrying, when it said
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("llm %s/%s %s (status %d): %v", e.Provider, e.Model, e.Kind, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("llm %s/%s %s: %v", e.Provider, e.Model, e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the same call may succeed if repeated after a backoff
+func (e *Error) Retryable() bool {
+	switch e.Kind {
+	case ErrorKindRateLimit, ErrorKindTimeout, ErrorKindServer, ErrorKindUnknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProviderFailure reports whether the error says something about the provider's health
+// (and so should count towards its circuit breaker) rather than about the request
+func (e *Error) ProviderFailure() bool {
+	switch e.Kind {
+	case ErrorKindContentFilter, ErrorKindBadRequest:
+		return false
+	default:
+		return true
+	}
+}
+
+// IsKind reports whether err is a classified LLM error of the given kind
+func IsKind(err error, kind ErrorKind) bool {
+	var llmErr *Error
+	return errors.As(err, &llmErr) && llmErr.Kind == kind
+}
+
+// classifyError maps a go-openai or transport error to an Error. Errors that are already
+// classified keep their kind.
+func classifyError(err error, provider, model string) *Error {
+	var classified *Error
+	if errors.As(err, &classified) {
+		return classified
+	}
+
+	e := &Error{Kind: ErrorKindUnknown, Provider: provider, Model: model, Err: err}
+
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		e.Kind = ErrorKindTimeout
+	case errors.As(err, &apiErr):
+		e.StatusCode = apiErr.HTTPStatusCode
+		e.Kind = kindFromStatus(apiErr.HTTPStatusCode)
+		if isContentFilterCode(fmt.Sprint(apiErr.Code)) || (apiErr.InnerError != nil && isContentFilterCode(apiErr.InnerError.Code)) {
+			e.Kind = ErrorKindContentFilter
+		}
+	case errors.As(err, &reqErr):
+		e.StatusCode = reqErr.HTTPStatusCode
+		e.Kind = kindFromStatus(reqErr.HTTPStatusCode)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		e.Kind = ErrorKindTimeout
+	}
+
+	return e
+}
+
+func kindFromStatus(status int) ErrorKind {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrorKindRateLimit
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		return ErrorKindTimeout
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrorKindAuth
+	case status >= 500:
+		return ErrorKindServer
+	case status >= 400:
+		return ErrorKindBadRequest
+	default:
+		return ErrorKindUnknown
+	}
+}
+
+func isContentFilterCode(code string) bool {
+	return strings.Contains(code, "content_filter") || strings.Contains(code, "content_policy")
+}
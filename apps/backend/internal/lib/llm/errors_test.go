@@ -0,0 +1,140 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantKind   ErrorKind
+		wantStatus int
+		retryable  bool
+		provider   bool
+	}{
+		{
+			name:      "deadline exceeded",
+			err:       fmt.Errorf("call: %w", context.DeadlineExceeded),
+			wantKind:  ErrorKindTimeout,
+			retryable: true,
+			provider:  true,
+		},
+		{
+			name:       "rate limited",
+			err:        &openai.APIError{HTTPStatusCode: 429, Message: "slow down"},
+			wantKind:   ErrorKindRateLimit,
+			wantStatus: 429,
+			retryable:  true,
+			provider:   true,
+		},
+		{
+			name:       "server error",
+			err:        &openai.APIError{HTTPStatusCode: 503},
+			wantKind:   ErrorKindServer,
+			wantStatus: 503,
+			retryable:  true,
+			provider:   true,
+		},
+		{
+			name:       "gateway timeout",
+			err:        &openai.RequestError{HTTPStatusCode: 504, Err: errors.New("upstream")},
+			wantKind:   ErrorKindTimeout,
+			wantStatus: 504,
+			retryable:  true,
+			provider:   true,
+		},
+		{
+			name:       "unauthorized",
+			err:        &openai.APIError{HTTPStatusCode: 401},
+			wantKind:   ErrorKindAuth,
+			wantStatus: 401,
+			provider:   true,
+		},
+		{
+			name:       "bad request",
+			err:        &openai.APIError{HTTPStatusCode: 400, Code: "invalid_request"},
+			wantKind:   ErrorKindBadRequest,
+			wantStatus: 400,
+		},
+		{
+			name:       "content filter code",
+			err:        &openai.APIError{HTTPStatusCode: 400, Code: "content_filter"},
+			wantKind:   ErrorKindContentFilter,
+			wantStatus: 400,
+		},
+		{
+			name:       "azure content policy",
+			err:        &openai.APIError{HTTPStatusCode: 400, InnerError: &openai.InnerError{Code: "ResponsibleAIPolicyViolation content_policy"}},
+			wantKind:   ErrorKindContentFilter,
+			wantStatus: 400,
+		},
+		{
+			name:      "network timeout",
+			err:       fmt.Errorf("dial: %w", timeoutError{}),
+			wantKind:  ErrorKindTimeout,
+			retryable: true,
+			provider:  true,
+		},
+		{
+			name:      "anything else",
+			err:       errors.New("boom"),
+			wantKind:  ErrorKindUnknown,
+			retryable: true,
+			provider:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := classifyError(tt.err, "openai", "gpt")
+			assert.Equal(t, tt.wantKind, e.Kind)
+			assert.Equal(t, tt.wantStatus, e.StatusCode)
+			assert.Equal(t, "openai", e.Provider)
+			assert.Equal(t, "gpt", e.Model)
+			assert.Equal(t, tt.retryable, e.Retryable())
+			assert.Equal(t, tt.provider, e.ProviderFailure())
+			assert.ErrorIs(t, e, tt.err)
+			assert.True(t, IsKind(e, tt.wantKind))
+		})
+	}
+}
+
+func TestClassifyErrorKeepsClassifiedErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "classified error",
+			err:  &Error{Kind: ErrorKindContentFilter, Provider: "azure", Model: "gpt", Err: errors.New("filtered")},
+		},
+		{
+			name: "wrapped classified error",
+			err:  fmt.Errorf("parse: %w", &Error{Kind: ErrorKindContentFilter, Provider: "azure", Model: "gpt", Err: errors.New("filtered")}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := classifyError(tt.err, "openai", "other")
+			require.NotNil(t, e)
+			assert.Equal(t, ErrorKindContentFilter, e.Kind)
+			assert.Equal(t, "azure", e.Provider)
+			assert.False(t, e.ProviderFailure())
+		})
+	}
+}
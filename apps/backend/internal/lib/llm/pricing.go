@@ -0,0 +1,16 @@
+package llm
+
+// tokensPerPriceUnit is the number of tokens a model price refers to
+const tokensPerPriceUnit = 1_000_000
+
+// ModelPrice contains the USD price per 1M tokens for a model
+type ModelPrice struct {
+	Model                 string
+	InputPricePerMillion  float64
+	OutputPricePerMillion float64
+}
+
+// Cost returns the USD cost of a generation with the given token counts
+func (p ModelPrice) Cost(tokensInput, tokensOutput int) float64 {
+	return (float64(tokensInput)*p.InputPricePerMillion + float64(tokensOutput)*p.OutputPricePerMillion) / tokensPerPriceUnit
+}
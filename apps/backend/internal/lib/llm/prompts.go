@@ -24,6 +24,17 @@ type FeedbackPromptData struct {
 	Section        string
 	SubType        string
 	Language       Language
+
+	// Student is set for personalized feedback; personalized prompts are never cached
+	Student *StudentContext
+}
+
+// StudentContext describes the student's current level for personalized feedback
+type StudentContext struct {
+	Theta              float64
+	RecentAttempts     int
+	RecentCorrect      int
+	SectionAccuracyPct float64
 }
 
 // SystemPromptFeedback returns the system prompt for feedback generation
@@ -97,6 +108,12 @@ func BuildFeedbackPrompt(data FeedbackPromptData) string {
 			prompt.WriteString(fmt.Sprintf("\nReference Explanation: %s\n", data.Explanation))
 		}
 
+		if data.Student != nil {
+			prompt.WriteString(fmt.Sprintf("\nStudent Profile: ability (theta) %.2f, %d of the last %d answers in this section correct (%.0f%%).\n",
+				data.Student.Theta, data.Student.RecentCorrect, data.Student.RecentAttempts, data.Student.SectionAccuracyPct))
+			prompt.WriteString("Adjust the depth of the explanation to the student's level.\n")
+		}
+
 		prompt.WriteString("\nProvide brief, helpful feedback for the student.")
 	} else {
 		prompt.WriteString(fmt.Sprintf("Subtes: %s", data.Section))
@@ -113,6 +130,12 @@ func BuildFeedbackPrompt(data FeedbackPromptData) string {
 			prompt.WriteString(fmt.Sprintf("\nPenjelasan Referensi: %s\n", data.Explanation))
 		}
 
+		if data.Student != nil {
+			prompt.WriteString(fmt.Sprintf("\nProfil Siswa: kemampuan (theta) %.2f, %d dari %d jawaban terakhir di subtes ini benar (%.0f%%).\n",
+				data.Student.Theta, data.Student.RecentCorrect, data.Student.RecentAttempts, data.Student.SectionAccuracyPct))
+			prompt.WriteString("Sesuaikan kedalaman penjelasan dengan level siswa.\n")
+		}
+
 		prompt.WriteString("\nBerikan feedback singkat dan membantu untuk siswa.")
 	}
 
@@ -123,3 +146,35 @@ func BuildFeedbackPrompt(data FeedbackPromptData) string {
 func PromptVersion() string {
 	return "v1.0.0"
 }
+
+// StaticFeedback builds feedback from the question's reference explanation, used when
+// no LLM provider is available
+func StaticFeedback(lang Language, isCorrect bool, correctAnswer, explanation string) string {
+	var b strings.Builder
+
+	if lang == LangEnglish {
+		if isCorrect {
+			b.WriteString("Correct! ")
+		} else {
+			b.WriteString(fmt.Sprintf("Not quite. The correct answer is %s. ", correctAnswer))
+		}
+		if explanation != "" {
+			b.WriteString(explanation)
+		} else {
+			b.WriteString("Review the question once more and compare each option carefully.")
+		}
+		return b.String()
+	}
+
+	if isCorrect {
+		b.WriteString("Benar! ")
+	} else {
+		b.WriteString(fmt.Sprintf("Belum tepat. Jawaban yang benar adalah %s. ", correctAnswer))
+	}
+	if explanation != "" {
+		b.WriteString(explanation)
+	} else {
+		b.WriteString("Coba baca ulang soalnya dan bandingkan setiap pilihan dengan teliti.")
+	}
+	return b.String()
+}
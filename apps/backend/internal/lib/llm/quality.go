@@ -0,0 +1,220 @@
+package llm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Quality flags raised by feedback evaluation
+const (
+	QualityFlagTooShort          = "too_short"
+	QualityFlagTooLong           = "too_long"
+	QualityFlagWrongLanguage     = "wrong_language"
+	QualityFlagWrongAnswer       = "wrong_answer_claimed"
+	QualityFlagWrongCorrectness  = "wrong_correctness"
+	QualityFlagMissingAnswer     = "missing_correct_answer"
+	QualityFlagHarshTone         = "harsh_tone"
+	QualityFlagJudgeFactualError = "judge_factual_error"
+	QualityFlagJudgeLowScore     = "judge_low_score"
+)
+
+const (
+	minFeedbackWords = 15
+	maxFeedbackWords = 250
+
+	// QualityMaxScore is the top of the feedback_quality_rating scale
+	QualityMaxScore = 5.0
+	// QualityMinScore is the bottom of the feedback_quality_rating scale
+	QualityMinScore = 1.0
+)
+
+// qualityFlagPenalty is how much each rule flag lowers the rating
+var qualityFlagPenalty = map[string]float64{
+	QualityFlagTooShort:         1.0,
+	QualityFlagTooLong:          0.5,
+	QualityFlagWrongLanguage:    2.0,
+	QualityFlagWrongAnswer:      3.0,
+	QualityFlagWrongCorrectness: 3.0,
+	QualityFlagMissingAnswer:    0.5,
+	QualityFlagHarshTone:        1.5,
+}
+
+var (
+	idStopwords = []string{"yang", "dan", "adalah", "untuk", "dengan", "ini", "itu", "dari", "karena", "jawaban", "tidak", "bisa"}
+	enStopwords = []string{"the", "and", "is", "for", "with", "this", "that", "from", "because", "answer", "not", "can"}
+
+	harshWords = []string{"bodoh", "payah", "goblok", "tolol", "malas", "stupid", "dumb", "lazy", "careless", "idiot"}
+
+	// Matches claims like "jawaban yang benar adalah C" or "the correct answer is C"
+	claimedAnswerPattern = regexp.MustCompile(`(?i)(?:jawaban(?:\s+yang)?\s+(?:benar|tepat)(?:nya)?(?:\s+adalah)?|correct\s+answer\s+is)\s*:?\s*\(?([A-E])\b`)
+	wordPattern          = regexp.MustCompile(`[\p{L}\p{N}']+`)
+
+	// answerLetterPatterns match each option letter as a whole word
+	answerLetterPatterns = func() map[string]*regexp.Regexp {
+		patterns := map[string]*regexp.Regexp{}
+		for _, letter := range []string{"A", "B", "C", "D", "E"} {
+			patterns[letter] = regexp.MustCompile(`\b` + letter + `\b`)
+		}
+		return patterns
+	}()
+)
+
+// FeedbackEvalInput contains the feedback and the question facts it must agree with
+type FeedbackEvalInput struct {
+	FeedbackText      string
+	Language          Language
+	IsCorrect         bool
+	SelectedAnswer    string
+	CorrectAnswer     string
+	CorrectOptionText string
+	QuestionText      string
+}
+
+// QualityEvaluation is the outcome of evaluating a piece of feedback
+type QualityEvaluation struct {
+	Score float64
+	Flags []string
+}
+
+// EvaluateFeedbackRules scores feedback with deterministic checks for factual consistency
+// with the answer key, language, length and tone
+func EvaluateFeedbackRules(in FeedbackEvalInput) QualityEvaluation {
+	var flags []string
+	text := strings.TrimSpace(in.FeedbackText)
+	lower := strings.ToLower(text)
+	words := wordPattern.FindAllString(lower, -1)
+
+	// Length
+	if len(words) < minFeedbackWords {
+		flags = append(flags, QualityFlagTooShort)
+	} else if len(words) > maxFeedbackWords {
+		flags = append(flags, QualityFlagTooLong)
+	}
+
+	// Language
+	idHits, enHits := countWords(words, idStopwords), countWords(words, enStopwords)
+	if in.Language == LangEnglish && idHits > enHits {
+		flags = append(flags, QualityFlagWrongLanguage)
+	} else if in.Language != LangEnglish && enHits > idHits {
+		flags = append(flags, QualityFlagWrongLanguage)
+	}
+
+	// Factual consistency: every stated answer must be the key
+	for _, m := range claimedAnswerPattern.FindAllStringSubmatch(text, -1) {
+		if !strings.EqualFold(m[1], in.CorrectAnswer) {
+			flags = append(flags, QualityFlagWrongAnswer)
+			break
+		}
+	}
+
+	// Correctness verdict must match the attempt
+	if !in.IsCorrect && startsWithPraise(lower) {
+		flags = append(flags, QualityFlagWrongCorrectness)
+	}
+
+	// Wrong answers should point to the correct one
+	if !in.IsCorrect && !mentionsAnswer(text, in.CorrectAnswer, in.CorrectOptionText) {
+		flags = append(flags, QualityFlagMissingAnswer)
+	}
+
+	// Tone
+	if countWords(words, harshWords) > 0 {
+		flags = append(flags, QualityFlagHarshTone)
+	}
+
+	score := QualityMaxScore
+	for _, f := range flags {
+		score -= qualityFlagPenalty[f]
+	}
+
+	return QualityEvaluation{Score: clampQuality(score), Flags: flags}
+}
+
+// SystemPromptFeedbackJudge returns the system prompt for the LLM feedback judge
+func SystemPromptFeedbackJudge() string {
+	return `You review tutor feedback written for Indonesian UTBK practice questions.
+Rate the feedback from 1 (unusable) to 5 (excellent) on factual consistency with the answer key,
+clarity, helpfulness and an encouraging tone. Feedback that contradicts the answer key is never above 2.
+Respond with a single JSON object: {"score": 1-5, "factual": true|false, "issues": ["..."]}`
+}
+
+// BuildFeedbackJudgePrompt builds the user prompt for the LLM feedback judge
+func BuildFeedbackJudgePrompt(in FeedbackEvalInput) string {
+	var prompt strings.Builder
+	prompt.WriteString(fmt.Sprintf("Question:\n%s\n\n", in.QuestionText))
+	prompt.WriteString(fmt.Sprintf("Correct answer: %s (%s)\n", in.CorrectAnswer, in.CorrectOptionText))
+	prompt.WriteString(fmt.Sprintf("Student's answer: %s\n", in.SelectedAnswer))
+	prompt.WriteString(fmt.Sprintf("Expected feedback language: %s\n\n", in.Language))
+	prompt.WriteString(fmt.Sprintf("Feedback to review:\n%s", in.FeedbackText))
+	return prompt.String()
+}
+
+// JudgeVerdict is the LLM judge's JSON response
+type JudgeVerdict struct {
+	Score   float64  `json:"score"`
+	Factual bool     `json:"factual"`
+	Issues  []string `json:"issues"`
+}
+
+// CombineJudgeVerdict merges the LLM judge verdict into a rule-based evaluation.
+// The rating is the lower of the two scores so either check can hold feedback back.
+func CombineJudgeVerdict(rules QualityEvaluation, verdict JudgeVerdict, threshold float64) QualityEvaluation {
+	combined := QualityEvaluation{Score: rules.Score, Flags: append([]string(nil), rules.Flags...)}
+
+	judgeScore := clampQuality(verdict.Score)
+	if judgeScore < combined.Score {
+		combined.Score = judgeScore
+	}
+	if !verdict.Factual {
+		combined.Flags = append(combined.Flags, QualityFlagJudgeFactualError)
+		if combined.Score > 2.0 {
+			combined.Score = 2.0
+		}
+	}
+	if judgeScore < threshold {
+		combined.Flags = append(combined.Flags, QualityFlagJudgeLowScore)
+	}
+
+	return combined
+}
+
+func countWords(words, vocabulary []string) int {
+	n := 0
+	for _, w := range words {
+		for _, v := range vocabulary {
+			if w == v {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+func startsWithPraise(lower string) bool {
+	for _, p := range []string{"benar!", "tepat sekali", "jawabanmu benar", "correct!", "well done", "great job"} {
+		if strings.HasPrefix(lower, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func mentionsAnswer(text, letter, optionText string) bool {
+	if pattern, ok := answerLetterPatterns[letter]; ok && pattern.MatchString(text) {
+		return true
+	}
+	optionText = strings.TrimSpace(optionText)
+	return optionText != "" && strings.Contains(strings.ToLower(text), strings.ToLower(optionText))
+}
+
+func clampQuality(score float64) float64 {
+	if score < QualityMinScore {
+		return QualityMinScore
+	}
+	if score > QualityMaxScore {
+		return QualityMaxScore
+	}
+	return score
+}
@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// maxRetryAfter is the longest provider-requested wait a call sits out before retrying the
+// same model; longer waits fall back to the next model instead
+const maxRetryAfter = 30 * time.Second
+
+// retryHint carries the wait a provider asked for from a failed response to the retry
+// loop. go-openai drops response headers from its errors, so the HTTP client records it.
+type retryHint struct {
+	after time.Duration
+}
+
+type retryHintKey struct{}
+
+func withRetryHint(ctx context.Context) (context.Context, *retryHint) {
+	hint := &retryHint{}
+	return context.WithValue(ctx, retryHintKey{}, hint), hint
+}
+
+// hintingDoer records the rate-limit headers of failed responses into the request's
+// retry hint
+type hintingDoer struct {
+	next openai.HTTPDoer
+}
+
+func (d hintingDoer) Do(req *http.Request) (*http.Response, error) {
+	resp, err := d.next.Do(req)
+	if err != nil || resp.StatusCode < http.StatusBadRequest {
+		return resp, err
+	}
+
+	if hint, ok := req.Context().Value(retryHintKey{}).(*retryHint); ok {
+		hint.after = retryAfter(resp.Header, time.Now())
+	}
+	return resp, err
+}
+
+// withHintingDoer makes a client config record retry hints
+func withHintingDoer(cfg openai.ClientConfig) openai.ClientConfig {
+	cfg.HTTPClient = hintingDoer{next: cfg.HTTPClient}
+	return cfg
+}
+
+// retryAfter reads how long the provider asked callers to wait from the standard
+// Retry-After header or OpenAI-style rate-limit headers; zero when it gave no hint
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	if ms, err := strconv.ParseFloat(h.Get("Retry-After-Ms"), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+
+	if v := strings.TrimSpace(h.Get("Retry-After")); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil && secs > 0 {
+			return time.Duration(secs * float64(time.Second))
+		}
+		if at, err := http.ParseTime(v); err == nil && at.After(now) {
+			return at.Sub(now)
+		}
+	}
+
+	// A reset only matters for the limit that is exhausted
+	var after time.Duration
+	for _, limit := range []string{"requests", "tokens"} {
+		if h.Get("X-Ratelimit-Remaining-"+limit) != "0" {
+			continue
+		}
+		if d, err := time.ParseDuration(h.Get("X-Ratelimit-Reset-" + limit)); err == nil && d > after {
+			after = d
+		}
+	}
+	return after
+}
@@ -0,0 +1,231 @@
+package planner
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	KindPractice = "practice"
+	KindReview   = "review"
+
+	// DefaultHoursPerWeek is used when the user has not set their study hours
+	DefaultHoursPerWeek = 7
+	// DefaultHorizonDays is the plan length without an upcoming exam date
+	DefaultHorizonDays = 14
+	// MaxHorizonDays caps the plan length; later days are planned when the plan expires
+	MaxHorizonDays = 28
+	// MinutesPerQuestion is the practice time budgeted per question
+	MinutesPerQuestion = 2
+	// BlockMinutes is the target length of a practice or review block
+	BlockMinutes = 20
+	// MaxBlocksPerDay caps the blocks scheduled on a single day
+	MaxBlocksPerDay = 8
+	// ReviewEvery makes every Nth day of the plan a review day
+	ReviewEvery = 7
+
+	// spacedReviewLag is how many days after practice a section is reviewed
+	spacedReviewLag = 2
+	// minSectionWeight keeps ready sections in rotation for maintenance
+	minSectionWeight = 10.0
+	// minSubtypeWeight keeps mastered subtypes in rotation
+	minSubtypeWeight = 0.1
+	// unattemptedAccuracy is assumed for subtypes the student has not practiced yet
+	unattemptedAccuracy = 0.5
+)
+
+// SubtypeStat is the student's record on a question subtype of a section
+type SubtypeStat struct {
+	SubType  string
+	Attempts int
+	Correct  int
+}
+
+// SectionStat is the readiness of a section and the subtypes it has questions for
+type SectionStat struct {
+	Section             string
+	ReadinessPercentage float64
+	Subtypes            []SubtypeStat
+}
+
+// Input holds what a plan is generated from
+type Input struct {
+	Start        time.Time
+	ExamDate     *time.Time
+	HoursPerWeek int
+	Sections     []SectionStat
+}
+
+// Block is one scheduled practice or review slot
+type Block struct {
+	Date            time.Time
+	Position        int
+	Kind            string
+	Section         string
+	SubType         *string
+	TargetQuestions int
+	Minutes         int
+}
+
+// MinutesPerDay spreads the weekly study hours over every day of the week
+func MinutesPerDay(hoursPerWeek int) int {
+	if hoursPerWeek <= 0 {
+		hoursPerWeek = DefaultHoursPerWeek
+	}
+	return max(hoursPerWeek*60/7, BlockMinutes)
+}
+
+// HorizonDays returns how many days a plan starting on start covers: every day up to the
+// exam, capped at MaxHorizonDays, or DefaultHorizonDays without an upcoming exam
+func HorizonDays(start time.Time, examDate *time.Time) int {
+	if examDate == nil {
+		return DefaultHorizonDays
+	}
+	days := int(Date(*examDate).Sub(Date(start)).Hours() / 24)
+	if days <= 0 {
+		return DefaultHorizonDays
+	}
+	return min(days, MaxHorizonDays)
+}
+
+// Date truncates t to its calendar day
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Generate builds a day-by-day schedule. Practice blocks rotate through sections in
+// proportion to their readiness gap and, within a section, through subtypes in proportion
+// to the student's error rate. Sections are revisited in a spaced review slot two days after
+// practice, and every ReviewEvery-th day reviews the week's weakest sections.
+func Generate(in Input) []Block {
+	start := Date(in.Start)
+	horizon := HorizonDays(start, in.ExamDate)
+	minutesPerDay := MinutesPerDay(in.HoursPerWeek)
+	blocksPerDay := min(max(minutesPerDay/BlockMinutes, 1), MaxBlocksPerDay)
+	blockMinutes := minutesPerDay / blocksPerDay
+	questionsPerBlock := max(blockMinutes/MinutesPerQuestion, 1)
+
+	gaps := make(map[string]float64, len(in.Sections))
+	sectionKeys := make([]string, 0, len(in.Sections))
+	sectionWeights := make([]float64, 0, len(in.Sections))
+	subtypes := make(map[string]*rotation, len(in.Sections))
+	for _, s := range in.Sections {
+		gap := max(100-s.ReadinessPercentage, 0)
+		gaps[s.Section] = gap
+		sectionKeys = append(sectionKeys, s.Section)
+		sectionWeights = append(sectionWeights, gap+minSectionWeight)
+		subtypes[s.Section] = subtypeRotation(s.Subtypes)
+	}
+	if len(sectionKeys) == 0 {
+		return nil
+	}
+	sections := newRotation(sectionKeys, sectionWeights)
+
+	blocks := make([]Block, 0, horizon*blocksPerDay)
+	practiced := make([][]string, horizon)
+
+	for day := 0; day < horizon; day++ {
+		date := start.AddDate(0, 0, day)
+		reviewDay := (day+1)%ReviewEvery == 0
+		var reviewSections []string
+		if reviewDay {
+			reviewSections = weakestPracticed(practiced[max(day-ReviewEvery+1, 0):day], gaps)
+		}
+
+		for position := 1; position <= blocksPerDay; position++ {
+			block := Block{
+				Date:            date,
+				Position:        position,
+				Kind:            KindPractice,
+				TargetQuestions: questionsPerBlock,
+				Minutes:         blockMinutes,
+			}
+
+			switch {
+			case reviewDay && len(reviewSections) > 0:
+				block.Kind = KindReview
+				block.Section = reviewSections[(position-1)%len(reviewSections)]
+			case position == blocksPerDay && blocksPerDay >= 3 && day >= spacedReviewLag && len(practiced[day-spacedReviewLag]) > 0:
+				block.Kind = KindReview
+				block.Section = practiced[day-spacedReviewLag][0]
+			default:
+				block.Section = sections.next()
+				if subType := subtypes[block.Section].next(); subType != "" {
+					block.SubType = &subType
+				}
+				practiced[day] = append(practiced[day], block.Section)
+			}
+
+			blocks = append(blocks, block)
+		}
+	}
+
+	return blocks
+}
+
+// weakestPracticed returns the distinct sections practiced over the given days, largest
+// readiness gap first
+func weakestPracticed(days [][]string, gaps map[string]float64) []string {
+	seen := make(map[string]bool)
+	var sections []string
+	for _, day := range days {
+		for _, section := range day {
+			if !seen[section] {
+				seen[section] = true
+				sections = append(sections, section)
+			}
+		}
+	}
+	sort.SliceStable(sections, func(i, j int) bool {
+		return gaps[sections[i]] > gaps[sections[j]]
+	})
+	return sections
+}
+
+func subtypeRotation(stats []SubtypeStat) *rotation {
+	keys := make([]string, 0, len(stats))
+	weights := make([]float64, 0, len(stats))
+	for _, s := range stats {
+		accuracy := unattemptedAccuracy
+		if s.Attempts > 0 {
+			accuracy = float64(s.Correct) / float64(s.Attempts)
+		}
+		keys = append(keys, s.SubType)
+		weights = append(weights, 1-accuracy+minSubtypeWeight)
+	}
+	return newRotation(keys, weights)
+}
+
+// rotation is a smooth weighted round robin: keys are picked in proportion to their
+// weights while staying interleaved
+type rotation struct {
+	keys    []string
+	weights []float64
+	current []float64
+	total   float64
+}
+
+func newRotation(keys []string, weights []float64) *rotation {
+	r := &rotation{keys: keys, weights: weights, current: make([]float64, len(keys))}
+	for _, w := range weights {
+		r.total += w
+	}
+	return r
+}
+
+// next returns the next key, or "" when the rotation is empty
+func (r *rotation) next() string {
+	if len(r.keys) == 0 {
+		return ""
+	}
+
+	best := 0
+	for i := range r.keys {
+		r.current[i] += r.weights[i]
+		if r.current[i] > r.current[best] {
+			best = i
+		}
+	}
+	r.current[best] -= r.total
+	return r.keys[best]
+}
@@ -0,0 +1,189 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var planStart = time.Date(2026, 3, 2, 15, 30, 0, 0, time.UTC)
+
+func examIn(days int) *time.Time {
+	exam := planStart.AddDate(0, 0, days)
+	return &exam
+}
+
+func TestMinutesPerDay(t *testing.T) {
+	tests := []struct {
+		name         string
+		hoursPerWeek int
+		want         int
+	}{
+		{name: "unset uses the default", hoursPerWeek: 0, want: 60},
+		{name: "spread over the week", hoursPerWeek: 14, want: 120},
+		{name: "at least one block", hoursPerWeek: 1, want: BlockMinutes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MinutesPerDay(tt.hoursPerWeek))
+		})
+	}
+}
+
+func TestHorizonDays(t *testing.T) {
+	tests := []struct {
+		name     string
+		examDate *time.Time
+		want     int
+	}{
+		{name: "no exam date", examDate: nil, want: DefaultHorizonDays},
+		{name: "exam in ten days", examDate: examIn(10), want: 10},
+		{name: "distant exam is capped", examDate: examIn(90), want: MaxHorizonDays},
+		{name: "past exam uses the default", examDate: examIn(-3), want: DefaultHorizonDays},
+		{name: "exam today uses the default", examDate: examIn(0), want: DefaultHorizonDays},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HorizonDays(planStart, tt.examDate))
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	sections := []SectionStat{
+		{Section: "PU", ReadinessPercentage: 20, Subtypes: []SubtypeStat{
+			{SubType: "silogisme", Attempts: 10, Correct: 2},
+			{SubType: "analogi", Attempts: 10, Correct: 9},
+		}},
+		{Section: "PK", ReadinessPercentage: 90},
+	}
+
+	tests := []struct {
+		name         string
+		in           Input
+		wantDays     int
+		wantPerDay   int
+		wantMinutes  int
+		wantQuestion int
+	}{
+		{
+			name:         "default week",
+			in:           Input{Start: planStart, Sections: sections},
+			wantDays:     DefaultHorizonDays,
+			wantPerDay:   3,
+			wantMinutes:  20,
+			wantQuestion: 10,
+		},
+		{
+			name:         "short plan up to the exam",
+			in:           Input{Start: planStart, ExamDate: examIn(5), HoursPerWeek: 14, Sections: sections},
+			wantDays:     5,
+			wantPerDay:   6,
+			wantMinutes:  20,
+			wantQuestion: 10,
+		},
+		{
+			name:         "little time gives one longer block",
+			in:           Input{Start: planStart, HoursPerWeek: 3, Sections: sections},
+			wantDays:     DefaultHorizonDays,
+			wantPerDay:   1,
+			wantMinutes:  25,
+			wantQuestion: 12,
+		},
+		{
+			name:         "blocks per day are capped",
+			in:           Input{Start: planStart, HoursPerWeek: 70, Sections: sections},
+			wantDays:     DefaultHorizonDays,
+			wantPerDay:   MaxBlocksPerDay,
+			wantMinutes:  75,
+			wantQuestion: 37,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks := Generate(tt.in)
+			require.Len(t, blocks, tt.wantDays*tt.wantPerDay)
+
+			start := Date(planStart)
+			for i, b := range blocks {
+				assert.Equal(t, start.AddDate(0, 0, i/tt.wantPerDay), b.Date)
+				assert.Equal(t, i%tt.wantPerDay+1, b.Position)
+				assert.Equal(t, tt.wantMinutes, b.Minutes)
+				assert.Equal(t, tt.wantQuestion, b.TargetQuestions)
+				assert.Contains(t, []string{"PU", "PK"}, b.Section)
+			}
+		})
+	}
+}
+
+func TestGenerateWithoutSections(t *testing.T) {
+	assert.Nil(t, Generate(Input{Start: planStart}))
+}
+
+func TestGenerateFavoursGaps(t *testing.T) {
+	blocks := Generate(Input{
+		Start: planStart,
+		Sections: []SectionStat{
+			{Section: "PU", ReadinessPercentage: 20, Subtypes: []SubtypeStat{
+				{SubType: "silogisme", Attempts: 10, Correct: 2},
+				{SubType: "analogi", Attempts: 10, Correct: 9},
+			}},
+			{Section: "PK", ReadinessPercentage: 90},
+		},
+	})
+
+	practice := make(map[string]int)
+	subtypes := make(map[string]int)
+	for _, b := range blocks {
+		if b.Kind != KindPractice {
+			continue
+		}
+		practice[b.Section]++
+		if b.SubType != nil {
+			subtypes[*b.SubType]++
+		}
+	}
+
+	assert.Greater(t, practice["PU"], practice["PK"])
+	assert.Positive(t, practice["PK"], "ready sections stay in rotation")
+	assert.Greater(t, subtypes["silogisme"], subtypes["analogi"])
+	assert.Positive(t, subtypes["analogi"], "mastered subtypes stay in rotation")
+}
+
+func TestGenerateReviews(t *testing.T) {
+	blocks := Generate(Input{
+		Start:    planStart,
+		Sections: []SectionStat{{Section: "PU", ReadinessPercentage: 40}, {Section: "PBM", ReadinessPercentage: 60}},
+	})
+
+	byDay := make(map[int][]Block)
+	start := Date(planStart)
+	for _, b := range blocks {
+		day := int(b.Date.Sub(start).Hours() / 24)
+		byDay[day] = append(byDay[day], b)
+	}
+
+	// Every ReviewEvery-th day reviews the week's sections, weakest first
+	reviewDay := byDay[ReviewEvery-1]
+	for _, b := range reviewDay {
+		assert.Equal(t, KindReview, b.Kind)
+	}
+	assert.Equal(t, "PU", reviewDay[0].Section)
+
+	// Other days end with a spaced review of what was practiced two days earlier
+	for day := spacedReviewLag; day < ReviewEvery-1; day++ {
+		last := byDay[day][len(byDay[day])-1]
+		assert.Equal(t, KindReview, last.Kind, "day %d", day)
+		assert.Equal(t, byDay[day-spacedReviewLag][0].Section, last.Section, "day %d", day)
+	}
+	for day := 0; day < spacedReviewLag; day++ {
+		for _, b := range byDay[day] {
+			assert.Equal(t, KindPractice, b.Kind, "day %d", day)
+		}
+	}
+}
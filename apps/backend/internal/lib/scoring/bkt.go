@@ -0,0 +1,37 @@
+package scoring
+
+// MasteryThreshold is the BKT probability from which a concept counts as mastered
+const MasteryThreshold = 0.95
+
+// BKTParams are the Bayesian Knowledge Tracing parameters of a concept
+type BKTParams struct {
+	// Init is the probability the concept is known before any practice
+	Init float64
+	// Transit is the probability of learning the concept from one practice opportunity
+	Transit float64
+	// Slip is the probability of answering wrong despite knowing the concept
+	Slip float64
+	// Guess is the probability of answering right without knowing the concept
+	Guess float64
+}
+
+// DefaultBKT suits five-option multiple choice items
+var DefaultBKT = BKTParams{Init: 0.2, Transit: 0.1, Slip: 0.1, Guess: 0.2}
+
+// UpdateMastery returns the probability the concept is known after observing one answer:
+// the Bayesian posterior given the answer, followed by the chance of learning from it
+func (p BKTParams) UpdateMastery(prior float64, correct bool) float64 {
+	var known, unknown float64
+	if correct {
+		known, unknown = prior*(1-p.Slip), (1-prior)*p.Guess
+	} else {
+		known, unknown = prior*p.Slip, (1-prior)*(1-p.Guess)
+	}
+
+	posterior := prior
+	if known+unknown > 0 {
+		posterior = known / (known + unknown)
+	}
+
+	return clamp(posterior+(1-posterior)*p.Transit, 0, 1)
+}
@@ -0,0 +1,71 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateMastery(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  BKTParams
+		prior   float64
+		correct bool
+		want    float64
+	}{
+		{
+			name:    "correct answer from the initial prior",
+			params:  DefaultBKT,
+			prior:   0.2,
+			correct: true,
+			// posterior 0.18/(0.18+0.16), then learning
+			want: 0.18/0.34 + (1-0.18/0.34)*0.1,
+		},
+		{
+			name:    "wrong answer from the initial prior",
+			params:  DefaultBKT,
+			prior:   0.2,
+			correct: false,
+			want:    0.02/0.66 + (1-0.02/0.66)*0.1,
+		},
+		{
+			name:    "mastered concept stays mastered",
+			params:  DefaultBKT,
+			prior:   1,
+			correct: false,
+			want:    1,
+		},
+		{
+			name:    "impossible answer keeps the prior",
+			params:  BKTParams{Transit: 0, Slip: 1, Guess: 0},
+			prior:   0.5,
+			correct: true,
+			want:    0.5,
+		},
+		{
+			name:    "impossible answer still learns",
+			params:  BKTParams{Transit: 0.1, Slip: 1, Guess: 0},
+			prior:   0,
+			correct: true,
+			want:    0.1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.params.UpdateMastery(tt.prior, tt.correct), 1e-9)
+		})
+	}
+}
+
+func TestUpdateMasteryReachesThreshold(t *testing.T) {
+	mastery := DefaultBKT.Init
+	answers := 0
+	for mastery < MasteryThreshold && answers < 100 {
+		mastery = DefaultBKT.UpdateMastery(mastery, true)
+		answers++
+	}
+	assert.GreaterOrEqual(t, mastery, MasteryThreshold)
+	assert.Less(t, answers, 10)
+}
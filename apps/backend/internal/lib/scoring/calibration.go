@@ -0,0 +1,61 @@
+package scoring
+
+import (
+	"errors"
+	"math"
+)
+
+// MinCalibrationSamples is the number of tryout results needed to fit a section
+const MinCalibrationSamples = 30
+
+// ErrInsufficientSamples is returned when a section has too few tryout results to fit
+var ErrInsufficientSamples = errors.New("insufficient tryout samples for calibration")
+
+// CalibrationPoint pairs an estimated theta with the scaled score from a tryout
+type CalibrationPoint struct {
+	Theta float64
+	Score float64
+}
+
+// Fit estimates a calibration by ordinary least squares of score on theta
+func Fit(version, section string, points []CalibrationPoint) (Calibration, error) {
+	n := float64(len(points))
+	if len(points) < MinCalibrationSamples {
+		return Calibration{}, ErrInsufficientSamples
+	}
+
+	var meanTheta, meanScore float64
+	for _, p := range points {
+		meanTheta += p.Theta
+		meanScore += p.Score
+	}
+	meanTheta /= n
+	meanScore /= n
+
+	var sxx, sxy float64
+	for _, p := range points {
+		dx := p.Theta - meanTheta
+		sxx += dx * dx
+		sxy += dx * (p.Score - meanScore)
+	}
+	if sxx == 0 {
+		return Calibration{}, errors.New("tryout thetas have no variance")
+	}
+
+	slope := sxy / sxx
+	intercept := meanScore - slope*meanTheta
+
+	var sse float64
+	for _, p := range points {
+		residual := p.Score - (intercept + slope*p.Theta)
+		sse += residual * residual
+	}
+
+	return Calibration{
+		Version:    version,
+		Section:    section,
+		Slope:      slope,
+		Intercept:  intercept,
+		ResidualSD: math.Sqrt(sse / (n - 2)),
+	}, nil
+}
@@ -0,0 +1,87 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func linePoints(n int, intercept, slope float64, noise func(i int) float64) []CalibrationPoint {
+	points := make([]CalibrationPoint, n)
+	for i := range points {
+		theta := -2 + 4*float64(i)/float64(n-1)
+		points[i] = CalibrationPoint{Theta: theta, Score: intercept + slope*theta + noise(i)}
+	}
+	return points
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name          string
+		points        []CalibrationPoint
+		wantErr       error
+		wantAnyErr    bool
+		wantSlope     float64
+		wantIntercept float64
+		wantResidual  float64
+	}{
+		{
+			name:          "exact line",
+			points:        linePoints(MinCalibrationSamples, 480, 120, func(int) float64 { return 0 }),
+			wantSlope:     120,
+			wantIntercept: 480,
+			wantResidual:  0,
+		},
+		{
+			name: "symmetric noise keeps the line",
+			points: linePoints(40, 500, 100, func(i int) float64 {
+				// +10 and -10 alternate within each adjacent pair
+				if i%2 == 0 {
+					return 10
+				}
+				return -10
+			}),
+			wantSlope:     100,
+			wantIntercept: 500,
+			wantResidual:  10,
+		},
+		{
+			name:    "too few samples",
+			points:  linePoints(MinCalibrationSamples-1, 500, 100, func(int) float64 { return 0 }),
+			wantErr: ErrInsufficientSamples,
+		},
+		{
+			name: "no theta variance",
+			points: func() []CalibrationPoint {
+				points := make([]CalibrationPoint, MinCalibrationSamples)
+				for i := range points {
+					points[i] = CalibrationPoint{Theta: 1, Score: float64(400 + i)}
+				}
+				return points
+			}(),
+			wantAnyErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal, err := Fit("v2", "PU", tt.points)
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			case tt.wantAnyErr:
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "v2", cal.Version)
+			assert.Equal(t, "PU", cal.Section)
+			assert.InDelta(t, tt.wantSlope, cal.Slope, 1.5)
+			assert.InDelta(t, tt.wantIntercept, cal.Intercept, 1e-6)
+			assert.InDelta(t, tt.wantResidual, cal.ResidualSD, 0.5)
+		})
+	}
+}
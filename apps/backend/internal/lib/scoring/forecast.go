@@ -0,0 +1,133 @@
+package scoring
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// MinTrendPoints is the number of theta checkpoints needed to fit a trend
+	MinTrendPoints = 3
+	// MaxForecastDays is the horizon beyond which a target is treated as unreachable
+	MaxForecastDays = 365
+
+	// minTrendSpan is the shortest history a trend is fitted over
+	minTrendSpan = 7 * 24 * time.Hour
+	// minRatePerWeek is the smallest weekly improvement treated as progress
+	minRatePerWeek = 0.005
+	// maxRatePerWeek bounds the fitted rate so sparse early history cannot explode the forecast
+	maxRatePerWeek = 1.0
+)
+
+// TimedResponse is a scored answer with the time it was given
+type TimedResponse struct {
+	Response
+	At time.Time
+}
+
+// ThetaPoint is an ability estimate at a point in time
+type ThetaPoint struct {
+	At    time.Time
+	Theta float64
+	SE    float64
+}
+
+// Forecast is the projected progress of a section toward its target ability
+type Forecast struct {
+	// RatePerWeek is the fitted change in theta per week
+	RatePerWeek float64
+	// DaysToReady and ReadyBy are nil when the target is not reachable within MaxForecastDays
+	DaysToReady *int
+	ReadyBy     *time.Time
+}
+
+// ThetaHistory estimates theta at each checkpoint from the responses given before it.
+// Responses must be sorted by time; checkpoints with fewer than minResponses are skipped.
+func ThetaHistory(responses []TimedResponse, checkpoints []time.Time, minResponses int) []ThetaPoint {
+	points := make([]ThetaPoint, 0, len(checkpoints))
+	scored := make([]Response, 0, len(responses))
+	next := 0
+
+	for _, checkpoint := range checkpoints {
+		for next < len(responses) && responses[next].At.Before(checkpoint) {
+			scored = append(scored, responses[next].Response)
+			next++
+		}
+		if len(scored) < minResponses {
+			continue
+		}
+		theta, se := EstimateTheta(scored)
+		points = append(points, ThetaPoint{At: checkpoint, Theta: theta, SE: se})
+	}
+
+	return points
+}
+
+// TrendPerWeek fits theta against time by least squares weighted by estimate precision
+// and returns the slope in theta per week. It reports false when the history is too
+// short to fit.
+func TrendPerWeek(points []ThetaPoint) (float64, bool) {
+	if len(points) < MinTrendPoints {
+		return 0, false
+	}
+
+	first, last := points[0].At, points[len(points)-1].At
+	if last.Sub(first) < minTrendSpan {
+		return 0, false
+	}
+
+	var sw, sx, sy float64
+	for _, p := range points {
+		w := trendWeight(p.SE)
+		x := weeksBetween(first, p.At)
+		sw += w
+		sx += w * x
+		sy += w * p.Theta
+	}
+	meanX, meanY := sx/sw, sy/sw
+
+	var sxx, sxy float64
+	for _, p := range points {
+		w := trendWeight(p.SE)
+		dx := weeksBetween(first, p.At) - meanX
+		sxx += w * dx * dx
+		sxy += w * dx * (p.Theta - meanY)
+	}
+	if sxx == 0 {
+		return 0, false
+	}
+
+	return clamp(sxy/sxx, -maxRatePerWeek, maxRatePerWeek), true
+}
+
+// ForecastReady projects when theta reaches readyTheta at the given weekly rate
+func ForecastReady(current, readyTheta, ratePerWeek float64, now time.Time) Forecast {
+	forecast := Forecast{RatePerWeek: ratePerWeek}
+
+	days := 0
+	if current < readyTheta {
+		if ratePerWeek < minRatePerWeek {
+			return forecast
+		}
+		days = int(math.Ceil((readyTheta - current) / ratePerWeek * 7))
+		if days > MaxForecastDays {
+			return forecast
+		}
+	}
+
+	readyBy := now.AddDate(0, 0, days)
+	forecast.DaysToReady = &days
+	forecast.ReadyBy = &readyBy
+	return forecast
+}
+
+func trendWeight(se float64) float64 {
+	if se <= 0 {
+		se = PriorSD
+	}
+	return 1 / (se * se)
+}
+
+func weeksBetween(from, to time.Time) float64 {
+	return to.Sub(from).Hours() / (24 * 7)
+}
@@ -0,0 +1,120 @@
+package scoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var forecastNow = time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+func weeklyPoints(thetas ...float64) []ThetaPoint {
+	points := make([]ThetaPoint, len(thetas))
+	for i, theta := range thetas {
+		points[i] = ThetaPoint{At: forecastNow.AddDate(0, 0, 7*i), Theta: theta, SE: 0.3}
+	}
+	return points
+}
+
+func TestTrendPerWeek(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []ThetaPoint
+		want   float64
+		wantOK bool
+	}{
+		{name: "steady gain", points: weeklyPoints(0, 0.1, 0.2, 0.3), want: 0.1, wantOK: true},
+		{name: "steady loss", points: weeklyPoints(0.5, 0.3, 0.1), want: -0.2, wantOK: true},
+		{name: "flat", points: weeklyPoints(0.4, 0.4, 0.4), want: 0, wantOK: true},
+		{name: "rate is bounded", points: weeklyPoints(-3, 0, 3), want: maxRatePerWeek, wantOK: true},
+		{name: "too few points", points: weeklyPoints(0, 0.1), wantOK: false},
+		{
+			name: "span too short",
+			points: []ThetaPoint{
+				{At: forecastNow, Theta: 0, SE: 0.3},
+				{At: forecastNow.Add(24 * time.Hour), Theta: 0.1, SE: 0.3},
+				{At: forecastNow.Add(48 * time.Hour), Theta: 0.2, SE: 0.3},
+			},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, ok := TrendPerWeek(tt.points)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.InDelta(t, tt.want, rate, 1e-9)
+			}
+		})
+	}
+}
+
+func TestForecastReady(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  float64
+		ready    float64
+		rate     float64
+		wantDays *int
+	}{
+		{name: "already ready", current: 1.2, ready: 1, rate: -0.1, wantDays: intPtr(0)},
+		{name: "reachable", current: 0, ready: 0.5, rate: 0.1, wantDays: intPtr(35)},
+		{name: "rounds up to whole days", current: 0, ready: 0.11, rate: 0.1, wantDays: intPtr(8)},
+		{name: "no progress", current: 0, ready: 0.5, rate: 0.001},
+		{name: "declining", current: 0, ready: 0.5, rate: -0.2},
+		{name: "beyond the horizon", current: -2, ready: 2, rate: 0.05},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := ForecastReady(tt.current, tt.ready, tt.rate, forecastNow)
+			assert.Equal(t, tt.rate, f.RatePerWeek)
+			if tt.wantDays == nil {
+				assert.Nil(t, f.DaysToReady)
+				assert.Nil(t, f.ReadyBy)
+				return
+			}
+			require.NotNil(t, f.DaysToReady)
+			require.NotNil(t, f.ReadyBy)
+			assert.Equal(t, *tt.wantDays, *f.DaysToReady)
+			assert.Equal(t, forecastNow.AddDate(0, 0, *tt.wantDays), *f.ReadyBy)
+		})
+	}
+}
+
+func TestThetaHistory(t *testing.T) {
+	var timed []TimedResponse
+	for i := 0; i < 12; i++ {
+		timed = append(timed, TimedResponse{
+			Response: Response{Difficulty: 0, Discrimination: 1, Correct: i%3 != 0},
+			At:       forecastNow.Add(time.Duration(i) * 24 * time.Hour),
+		})
+	}
+
+	checkpoints := []time.Time{
+		forecastNow.Add(2 * 24 * time.Hour),  // 2 answers, skipped
+		forecastNow.Add(6 * 24 * time.Hour),  // 6 answers
+		forecastNow.Add(30 * 24 * time.Hour), // all answers
+	}
+
+	points := ThetaHistory(timed, checkpoints, 5)
+	require.Len(t, points, 2)
+	assert.Equal(t, checkpoints[1], points[0].At)
+	assert.Equal(t, checkpoints[2], points[1].At)
+
+	full := make([]Response, len(timed))
+	for i, r := range timed {
+		full[i] = r.Response
+	}
+	theta, se := EstimateTheta(full)
+	assert.InDelta(t, theta, points[1].Theta, 1e-9)
+	assert.InDelta(t, se, points[1].SE, 1e-9)
+	assert.Less(t, points[1].SE, points[0].SE)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
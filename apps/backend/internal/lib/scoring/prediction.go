@@ -0,0 +1,99 @@
+package scoring
+
+import "math"
+
+const (
+	// DefaultVersion is the built-in calibration used until one is fitted from tryout data
+	DefaultVersion = "default-v1"
+
+	// ScoreMin and ScoreMax bound the UTBK scaled score of a subtest
+	ScoreMin = 200
+	ScoreMax = 1000
+
+	// zScore90 is the two-sided z value of a 90% interval
+	zScore90 = 1.645
+)
+
+// Calibration maps a section theta to a UTBK scaled score: score = intercept + slope*theta.
+// ResidualSD is the spread of real scores around that line.
+type Calibration struct {
+	Version    string
+	Section    string
+	Slope      float64
+	Intercept  float64
+	ResidualSD float64
+}
+
+// DefaultCalibration returns the built-in mapping (mean 500, 100 points per logit)
+func DefaultCalibration(section string) Calibration {
+	return Calibration{
+		Version:    DefaultVersion,
+		Section:    section,
+		Slope:      100,
+		Intercept:  500,
+		ResidualSD: 40,
+	}
+}
+
+// Prediction is a predicted scaled score with its 90% interval
+type Prediction struct {
+	Score float64
+	SD    float64
+	Low   int
+	High  int
+}
+
+// Predict maps theta and its standard error to a scaled score. Uncertainty from the
+// ability estimate and the calibration residual are combined.
+func Predict(cal Calibration, theta, thetaSE float64) Prediction {
+	score := cal.Intercept + cal.Slope*theta
+	sd := math.Sqrt(math.Pow(cal.Slope*thetaSE, 2) + math.Pow(cal.ResidualSD, 2))
+	return newPrediction(score, sd)
+}
+
+// Composite averages section predictions into the overall UTBK score, treating the
+// sections as independent
+func Composite(predictions []Prediction) Prediction {
+	if len(predictions) == 0 {
+		return Prediction{}
+	}
+
+	var sum, variance float64
+	for _, p := range predictions {
+		sum += p.Score
+		variance += p.SD * p.SD
+	}
+	n := float64(len(predictions))
+	return newPrediction(sum/n, math.Sqrt(variance)/n)
+}
+
+// ProbabilityAtLeast returns the probability that the true score reaches target
+func (p Prediction) ProbabilityAtLeast(target float64) float64 {
+	if p.SD <= 0 {
+		if p.Score >= target {
+			return 1
+		}
+		return 0
+	}
+	z := (target - p.Score) / p.SD
+	return 0.5 * math.Erfc(z/math.Sqrt2)
+}
+
+func newPrediction(score, sd float64) Prediction {
+	score = clamp(score, ScoreMin, ScoreMax)
+	return Prediction{
+		Score: score,
+		SD:    sd,
+		Low:   int(math.Round(clamp(score-zScore90*sd, ScoreMin, ScoreMax))),
+		High:  int(math.Round(clamp(score+zScore90*sd, ScoreMin, ScoreMax))),
+	}
+}
+
+// AdmissionProbability estimates the chance that the predicted score beats a program's
+// passing score, whose historical range [low, high] is treated as a 90% interval
+func AdmissionProbability(p Prediction, low, high float64) float64 {
+	passing := (low + high) / 2
+	passingSD := (high - low) / (2 * zScore90)
+	combined := Prediction{Score: p.Score, SD: math.Sqrt(p.SD*p.SD + passingSD*passingSD)}
+	return combined.ProbabilityAtLeast(passing)
+}
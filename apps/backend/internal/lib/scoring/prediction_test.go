@@ -0,0 +1,154 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPredict(t *testing.T) {
+	tests := []struct {
+		name      string
+		theta     float64
+		thetaSE   float64
+		wantScore float64
+		wantSD    float64
+		wantLow   int
+		wantHigh  int
+	}{
+		{
+			name:      "average ability",
+			theta:     0,
+			thetaSE:   0.3,
+			wantScore: 500,
+			wantSD:    50,
+			wantLow:   418,
+			wantHigh:  582,
+		},
+		{
+			name:      "high ability",
+			theta:     1,
+			thetaSE:   0.3,
+			wantScore: 600,
+			wantSD:    50,
+			wantLow:   518,
+			wantHigh:  682,
+		},
+		{
+			name:      "score is clamped to the scale",
+			theta:     10,
+			thetaSE:   0,
+			wantScore: ScoreMax,
+			wantSD:    40,
+			wantLow:   934,
+			wantHigh:  ScoreMax,
+		},
+	}
+
+	cal := DefaultCalibration("PU")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Predict(cal, tt.theta, tt.thetaSE)
+			assert.InDelta(t, tt.wantScore, p.Score, 1e-9)
+			assert.InDelta(t, tt.wantSD, p.SD, 1e-9)
+			assert.Equal(t, tt.wantLow, p.Low)
+			assert.Equal(t, tt.wantHigh, p.High)
+		})
+	}
+}
+
+func TestComposite(t *testing.T) {
+	tests := []struct {
+		name        string
+		predictions []Prediction
+		wantScore   float64
+		wantSD      float64
+	}{
+		{name: "no sections", predictions: nil, wantScore: 0, wantSD: 0},
+		{
+			name:        "independent sections average",
+			predictions: []Prediction{{Score: 400, SD: 30}, {Score: 600, SD: 40}},
+			wantScore:   500,
+			wantSD:      25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Composite(tt.predictions)
+			assert.InDelta(t, tt.wantScore, p.Score, 1e-9)
+			assert.InDelta(t, tt.wantSD, p.SD, 1e-9)
+		})
+	}
+}
+
+func TestProbabilityAtLeast(t *testing.T) {
+	tests := []struct {
+		name   string
+		p      Prediction
+		target float64
+		want   float64
+	}{
+		{name: "target at the mean", p: Prediction{Score: 600, SD: 50}, target: 600, want: 0.5},
+		{name: "target one sd below", p: Prediction{Score: 600, SD: 50}, target: 550, want: 0.8413},
+		{name: "target one sd above", p: Prediction{Score: 600, SD: 50}, target: 650, want: 0.1587},
+		{name: "certain score reaches target", p: Prediction{Score: 600}, target: 600, want: 1},
+		{name: "certain score misses target", p: Prediction{Score: 599}, target: 600, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.p.ProbabilityAtLeast(tt.target), 1e-4)
+		})
+	}
+}
+
+func TestAdmissionProbability(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Prediction
+		low  float64
+		high float64
+		want float64
+	}{
+		{
+			name: "predicted score at the passing midpoint",
+			p:    Prediction{Score: 650, SD: 40},
+			low:  600,
+			high: 700,
+			want: 0.5,
+		},
+		{
+			name: "certain score above a fixed passing score",
+			p:    Prediction{Score: 700},
+			low:  650,
+			high: 650,
+			want: 1,
+		},
+		{
+			name: "below the range",
+			p:    Prediction{Score: 600, SD: 20},
+			low:  650,
+			high: 700,
+			// passing 675 with sd 50/(2*1.645), combined with the prediction sd of 20
+			want: 0.5 * math.Erfc((675-600)/math.Sqrt(20*20+math.Pow(50/(2*zScore90), 2))/math.Sqrt2),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AdmissionProbability(tt.p, tt.low, tt.high)
+			assert.InDelta(t, tt.want, got, 1e-9)
+			assert.GreaterOrEqual(t, got, 0.0)
+			assert.LessOrEqual(t, got, 1.0)
+		})
+	}
+}
+
+func TestAdmissionProbabilityWiderRangeIsLessCertain(t *testing.T) {
+	p := Prediction{Score: 700, SD: 20}
+	narrow := AdmissionProbability(p, 640, 660)
+	wide := AdmissionProbability(p, 550, 750)
+	assert.Greater(t, narrow, wide)
+}
@@ -0,0 +1,82 @@
+package scoring
+
+import "math"
+
+const (
+	// PriorSD is the standard deviation of the N(0, 1) ability prior
+	PriorSD = 1.0
+
+	minTheta       = -4.0
+	maxTheta       = 4.0
+	maxIterations  = 25
+	convergenceEps = 1e-4
+)
+
+// Response is a single scored answer used for ability estimation
+type Response struct {
+	Difficulty     float64
+	Discrimination float64
+	Correct        bool
+}
+
+// EstimateTheta returns the MAP ability estimate under the 2PL model with a
+// N(0, 1) prior, along with its standard error. With no responses it returns
+// the prior (0, PriorSD).
+func EstimateTheta(responses []Response) (theta float64, se float64) {
+	return UpdateTheta(0, PriorSD, responses)
+}
+
+// UpdateTheta folds new responses into an earlier estimate, using it as a normal
+// prior N(priorTheta, priorSE²). Updating answer by answer approximates
+// EstimateTheta over the full history without rereading it. With no responses it
+// returns the prior.
+func UpdateTheta(priorTheta, priorSE float64, responses []Response) (theta float64, se float64) {
+	if len(responses) == 0 {
+		return priorTheta, priorSE
+	}
+
+	theta = priorTheta
+	for i := 0; i < maxIterations; i++ {
+		gradient, information := logPosteriorDerivatives(theta, priorTheta, priorSE, responses)
+		step := gradient / information
+		theta = clamp(theta+step, minTheta, maxTheta)
+		if math.Abs(step) < convergenceEps {
+			break
+		}
+	}
+
+	_, information := logPosteriorDerivatives(theta, priorTheta, priorSE, responses)
+	return theta, 1 / math.Sqrt(information)
+}
+
+// logPosteriorDerivatives returns the gradient and the (positive) information of the
+// log posterior at theta under a N(priorTheta, priorSE²) prior
+func logPosteriorDerivatives(theta, priorTheta, priorSE float64, responses []Response) (gradient float64, information float64) {
+	gradient = -(theta - priorTheta) / (priorSE * priorSE)
+	information = 1 / (priorSE * priorSE)
+
+	for _, r := range responses {
+		a := r.Discrimination
+		if a <= 0 {
+			a = 1
+		}
+		p := probability(theta, r.Difficulty, a)
+		u := 0.0
+		if r.Correct {
+			u = 1
+		}
+		gradient += a * (u - p)
+		information += a * a * p * (1 - p)
+	}
+
+	return gradient, information
+}
+
+// probability is the 2PL probability of a correct answer
+func probability(theta, difficulty, discrimination float64) float64 {
+	return 1 / (1 + math.Exp(-discrimination*(theta-difficulty)))
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
@@ -0,0 +1,121 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func responses(difficulty float64, correct, wrong int) []Response {
+	rs := make([]Response, 0, correct+wrong)
+	for i := 0; i < correct; i++ {
+		rs = append(rs, Response{Difficulty: difficulty, Discrimination: 1, Correct: true})
+	}
+	for i := 0; i < wrong; i++ {
+		rs = append(rs, Response{Difficulty: difficulty, Discrimination: 1, Correct: false})
+	}
+	return rs
+}
+
+func TestEstimateTheta(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []Response
+		check     func(t *testing.T, theta, se float64)
+	}{
+		{
+			name:      "no responses returns the prior",
+			responses: nil,
+			check: func(t *testing.T, theta, se float64) {
+				assert.Equal(t, 0.0, theta)
+				assert.Equal(t, PriorSD, se)
+			},
+		},
+		{
+			name:      "balanced answers at difficulty zero stay at zero",
+			responses: responses(0, 5, 5),
+			check: func(t *testing.T, theta, se float64) {
+				assert.InDelta(t, 0, theta, 1e-3)
+				assert.Less(t, se, PriorSD)
+			},
+		},
+		{
+			name:      "mostly correct answers move theta up",
+			responses: responses(0, 8, 2),
+			check: func(t *testing.T, theta, se float64) {
+				assert.Greater(t, theta, 0.5)
+			},
+		},
+		{
+			name:      "mostly wrong answers move theta down",
+			responses: responses(0, 2, 8),
+			check: func(t *testing.T, theta, se float64) {
+				assert.Less(t, theta, -0.5)
+			},
+		},
+		{
+			name:      "all correct answers stay within bounds",
+			responses: responses(3, 200, 0),
+			check: func(t *testing.T, theta, se float64) {
+				assert.LessOrEqual(t, theta, maxTheta)
+				assert.False(t, math.IsNaN(se))
+			},
+		},
+		{
+			name:      "zero discrimination is treated as one",
+			responses: []Response{{Difficulty: 0, Discrimination: 0, Correct: true}},
+			check: func(t *testing.T, theta, se float64) {
+				want, _ := EstimateTheta([]Response{{Difficulty: 0, Discrimination: 1, Correct: true}})
+				assert.InDelta(t, want, theta, 1e-9)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theta, se := EstimateTheta(tt.responses)
+			tt.check(t, theta, se)
+		})
+	}
+}
+
+func TestEstimateThetaMoreAnswersNarrowSE(t *testing.T) {
+	_, few := EstimateTheta(responses(0, 3, 3))
+	_, many := EstimateTheta(responses(0, 30, 30))
+	assert.Less(t, many, few)
+}
+
+func TestUpdateTheta(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []Response
+		batches int
+	}{
+		{name: "one answer at a time", history: append(responses(-0.5, 6, 4), responses(1, 3, 5)...), batches: 18},
+		{name: "two batches", history: append(responses(0.5, 10, 4), responses(-1, 7, 1)...), batches: 2},
+		{name: "single batch", history: responses(0, 4, 6), batches: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			full, fullSE := EstimateTheta(tt.history)
+
+			theta, se := 0.0, PriorSD
+			size := (len(tt.history) + tt.batches - 1) / tt.batches
+			for start := 0; start < len(tt.history); start += size {
+				end := min(start+size, len(tt.history))
+				theta, se = UpdateTheta(theta, se, tt.history[start:end])
+			}
+
+			assert.InDelta(t, full, theta, 0.05)
+			assert.InDelta(t, fullSE, se, 0.05)
+		})
+	}
+}
+
+func TestUpdateThetaWithoutResponsesKeepsPrior(t *testing.T) {
+	theta, se := UpdateTheta(1.2, 0.3, nil)
+	assert.Equal(t, 1.2, theta)
+	assert.Equal(t, 0.3, se)
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Filesystem stores objects as files under a root directory. Its presigned URLs are served
+// by the API.
+type Filesystem struct {
+	*signer
+	root string
+}
+
+func NewFilesystem(root string, signer *signer) (*Filesystem, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &Filesystem{signer: signer, root: root}, nil
+}
+
+func (f *Filesystem) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (*Object, error) {
+	name, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create object directory: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial object
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create object file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write object: %w", err)
+	}
+	if size >= 0 && written != size {
+		return nil, fmt.Errorf("failed to write object: got %d bytes, expected %d", written, size)
+	}
+
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return nil, fmt.Errorf("failed to store object: %w", err)
+	}
+
+	return f.Stat(ctx, key)
+}
+
+func (f *Filesystem) Open(ctx context.Context, key string) (io.ReadCloser, *Object, error) {
+	name, err := f.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to open object: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to stat object: %w", err)
+	}
+
+	return file, fileObject(key, info), nil
+}
+
+func (f *Filesystem) Stat(ctx context.Context, key string) (*Object, error) {
+	name, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to stat object: %w", err)
+	}
+
+	return fileObject(key, info), nil
+}
+
+func (f *Filesystem) Delete(ctx context.Context, key string) error {
+	name, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	return nil
+}
+
+// path maps a key to a file under the root
+func (f *Filesystem) path(key string) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(f.root, filepath.FromSlash(key)), nil
+}
+
+// fileObject describes a file. Files carry no metadata, so the content type comes from
+// the key's extension.
+func fileObject(key string, info fs.FileInfo) *Object {
+	return &Object{
+		Key:         key,
+		ContentType: mime.TypeByExtension(path.Ext(key)),
+		Size:        info.Size(),
+		ModTime:     info.ModTime(),
+	}
+}
+
+// validKey refuses keys that are empty, absolute, not in clean form or naming the root
+// itself, so no key can escape a filesystem root or alias another key
+func validKey(key string) error {
+	if key == "" || key == "." || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return ErrInvalidKey
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		wantErr bool
+	}{
+		{key: "media/3f1c2b7a.png"},
+		{key: "a"},
+		{key: "media/thumbs/a.webp"},
+		{key: "..file"},
+		{key: "", wantErr: true},
+		{key: "/etc/passwd", wantErr: true},
+		{key: "..", wantErr: true},
+		{key: "../secret", wantErr: true},
+		{key: "media/../../secret", wantErr: true},
+		{key: "media/../other", wantErr: true},
+		{key: "media//a.png", wantErr: true},
+		{key: "media/./a.png", wantErr: true},
+		{key: "media/", wantErr: true},
+		{key: ".", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			err := validKey(tt.key)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidKey)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFilesystemStaysUnderRoot(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	fsys, err := NewFilesystem(root, nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	obj, err := fsys.Put(ctx, "media/a.txt", strings.NewReader("isi"), 3, "text/plain")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), obj.Size)
+
+	r, _, err := fsys.Open(ctx, "media/a.txt")
+	require.NoError(t, err)
+	body, err := io.ReadAll(r)
+	r.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "isi", string(body))
+
+	_, err = fsys.Put(ctx, "../escaped.txt", strings.NewReader("x"), 1, "text/plain")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = os.Stat(filepath.Join(parent, "escaped.txt"))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = fsys.Stat(ctx, "media/missing.txt")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, fsys.Delete(ctx, "media/a.txt"))
+	_, err = fsys.Stat(ctx, "media/a.txt")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Memory keeps objects in process memory. Objects are lost on restart and not shared
+// between API instances, so it is meant for development and tests. Its presigned URLs are
+// served by the API.
+type Memory struct {
+	*signer
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+type memoryObject struct {
+	data        []byte
+	contentType string
+	modTime     time.Time
+}
+
+func NewMemory(signer *signer) *Memory {
+	return &Memory{signer: signer, objects: map[string]memoryObject{}}
+}
+
+func (m *Memory) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (*Object, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	if size >= 0 && int64(len(data)) != size {
+		return nil, fmt.Errorf("failed to write object: got %d bytes, expected %d", len(data), size)
+	}
+
+	obj := memoryObject{data: data, contentType: contentType, modTime: time.Now()}
+
+	m.mu.Lock()
+	m.objects[key] = obj
+	m.mu.Unlock()
+
+	return obj.describe(key), nil
+}
+
+func (m *Memory) Open(ctx context.Context, key string) (io.ReadCloser, *Object, error) {
+	m.mu.RLock()
+	obj, ok := m.objects[key]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+
+	// Stored slices are never modified, only replaced, so readers can share them
+	return io.NopCloser(bytes.NewReader(obj.data)), obj.describe(key), nil
+}
+
+func (m *Memory) Stat(ctx context.Context, key string) (*Object, error) {
+	m.mu.RLock()
+	obj, ok := m.objects[key]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return obj.describe(key), nil
+}
+
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	delete(m.objects, key)
+	m.mu.Unlock()
+	return nil
+}
+
+func (o memoryObject) describe(key string) *Object {
+	return &Object{Key: key, ContentType: o.contentType, Size: int64(len(o.data)), ModTime: o.modTime}
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/manikandareas/genta/internal/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3 stores objects in a bucket of an S3-compatible store such as MinIO or AWS S3. Its
+// presigned URLs point at the store, so downloads and uploads bypass the API.
+type S3 struct {
+	client *minio.Client
+	bucket string
+}
+
+func NewS3(cfg config.S3Config) (*S3, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	return &S3{client: client, bucket: cfg.Bucket}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (*Object, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+
+	info, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return nil, fmt.Errorf("failed to put object: %w", err)
+	}
+
+	return &Object{Key: key, ContentType: contentType, Size: info.Size, ModTime: info.LastModified}, nil
+}
+
+func (s *S3) Open(ctx context.Context, key string) (io.ReadCloser, *Object, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, s.error("get", err)
+	}
+
+	// GetObject is lazy; Stat makes the request and surfaces a missing key
+	info, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, nil, s.error("get", err)
+	}
+
+	return obj, s3Object(info), nil
+}
+
+func (s *S3) Stat(ctx context.Context, key string) (*Object, error) {
+	info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, s.error("stat", err)
+	}
+	return s3Object(info), nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return s.error("delete", err)
+	}
+	return nil
+}
+
+func (s *S3) PresignGet(ctx context.Context, key string, expires time.Duration) (string, error) {
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, expires, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign download: %w", err)
+	}
+	return u.String(), nil
+}
+
+// PresignPost uses a POST policy rather than a presigned PUT, so the store itself enforces
+// the content type and size limit
+func (s *S3) PresignPost(ctx context.Context, key, contentType string, maxBytes int64, expires time.Duration) (*PresignedUpload, error) {
+	expiresAt := time.Now().Add(expires)
+
+	policy := minio.NewPostPolicy()
+	for _, err := range []error{
+		policy.SetBucket(s.bucket),
+		policy.SetKey(key),
+		policy.SetExpires(expiresAt.UTC()),
+		policy.SetContentType(contentType),
+		policy.SetContentLengthRange(1, maxBytes),
+	} {
+		if err != nil {
+			return nil, fmt.Errorf("failed to build upload policy: %w", err)
+		}
+	}
+
+	u, fields, err := s.client.PresignedPostPolicy(ctx, policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign upload: %w", err)
+	}
+
+	return &PresignedUpload{URL: u.String(), Method: http.MethodPost, Fields: fields, ExpiresAt: expiresAt}, nil
+}
+
+func (s *S3) error(op string, err error) error {
+	if resp := minio.ToErrorResponse(err); resp.Code == "NoSuchKey" || resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	return fmt.Errorf("failed to %s object: %w", op, err)
+}
+
+func s3Object(info minio.ObjectInfo) *Object {
+	return &Object{Key: info.Key, ContentType: info.ContentType, Size: info.Size, ModTime: info.LastModified}
+}
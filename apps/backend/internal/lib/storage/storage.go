@@ -0,0 +1,92 @@
+// Package storage keeps media assets, exported reports and user uploads in an object
+// store addressed by slash-separated keys. Backends: the local filesystem, S3-compatible
+// stores such as MinIO, and memory (for development and tests).
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/manikandareas/genta/internal/config"
+)
+
+const (
+	BackendFilesystem = "filesystem"
+	BackendS3         = "s3"
+	BackendMemory     = "memory"
+
+	// DefaultPath is where the filesystem backend stores objects when no path is configured
+	DefaultPath = "./data/media"
+)
+
+var (
+	ErrNotFound   = errors.New("storage: object not found")
+	ErrInvalidKey = errors.New("storage: invalid object key")
+)
+
+// Object describes a stored object
+type Object struct {
+	Key         string
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+// PresignedUpload lets a client upload one object straight to storage with a multipart
+// POST: the fields first, then the file in a field named "file"
+type PresignedUpload struct {
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Fields    map[string]string `json:"fields"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// Storage is an object store
+type Storage interface {
+	// Put stores size bytes read from r under key, replacing any existing object. A size of
+	// -1 means unknown.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (*Object, error)
+	// Open returns the object's content; the caller closes it
+	Open(ctx context.Context, key string) (io.ReadCloser, *Object, error)
+	// Stat describes the object without reading it
+	Stat(ctx context.Context, key string) (*Object, error)
+	// Delete removes the object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+
+	// PresignGet returns a URL that downloads the object until it expires
+	PresignGet(ctx context.Context, key string, expires time.Duration) (string, error)
+	// PresignPost returns a form upload that stores one object of the given type and at most
+	// maxBytes under key until it expires
+	PresignPost(ctx context.Context, key, contentType string, maxBytes int64, expires time.Duration) (*PresignedUpload, error)
+}
+
+// New creates the storage backend selected in the config
+func New(cfg *config.Config) (Storage, error) {
+	sc := cfg.Storage
+
+	switch sc.Backend {
+	case "", BackendFilesystem:
+		signer, err := newSigner(sc)
+		if err != nil {
+			return nil, err
+		}
+		path := sc.Path
+		if path == "" {
+			path = DefaultPath
+		}
+		return NewFilesystem(path, signer)
+	case BackendMemory:
+		signer, err := newSigner(sc)
+		if err != nil {
+			return nil, err
+		}
+		return NewMemory(signer), nil
+	case BackendS3:
+		return NewS3(sc.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", sc.Backend)
+	}
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+
+	// Decoders for the formats ImagePolicy admits
+	_ "golang.org/x/image/webp"
+	_ "image/gif"
+)
+
+const (
+	// ThumbnailSize bounds the longer side of a thumbnail in pixels
+	ThumbnailSize = 320
+	// MaxImageDimension caps the width and height of images accepted for decoding, which
+	// keeps a small compressed file from expanding into gigabytes of pixels
+	MaxImageDimension = 4096
+
+	thumbnailJPEGQuality = 85
+)
+
+// DecodeImage decodes an image after checking its dimensions from the header
+func DecodeImage(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, &ValidationError{Message: "is not a readable image"}
+	}
+	if cfg.Width > MaxImageDimension || cfg.Height > MaxImageDimension {
+		return nil, &ValidationError{Message: fmt.Sprintf("must be at most %dx%d pixels", MaxImageDimension, MaxImageDimension)}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, &ValidationError{Message: "is not a readable image"}
+	}
+	return img, nil
+}
+
+// Thumbnail scales img to fit within size x size, keeping its aspect ratio. It returns
+// false when img already fits and needs no thumbnail.
+func Thumbnail(img image.Image, size int) (image.Image, bool) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return img, false
+	}
+
+	if w >= h {
+		h = max(1, h*size/w)
+		w = size
+	} else {
+		w = max(1, w*size/h)
+		h = size
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst, true
+}
+
+// EncodeThumbnail encodes a thumbnail as JPEG, or as PNG when it has transparency, and
+// returns the encoded bytes with their content type and file extension
+func EncodeThumbnail(img image.Image) ([]byte, string, string, error) {
+	var buf bytes.Buffer
+
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && !opaque.Opaque() {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", "", fmt.Errorf("failed to encode thumbnail: %w", err)
+		}
+		return buf.Bytes(), "image/png", ".png", nil
+	}
+
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: thumbnailJPEGQuality}); err != nil {
+		return nil, "", "", fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), "image/jpeg", ".jpg", nil
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// sniffLen is how many bytes content type detection looks at
+const sniffLen = 512
+
+// Policy limits the content type and size of objects of one kind
+type Policy struct {
+	MaxBytes     int64
+	ContentTypes []string
+}
+
+var (
+	// ImagePolicy admits images that browsers render and Go can decode for thumbnails. SVG
+	// is left out: it can carry scripts.
+	ImagePolicy = Policy{
+		MaxBytes:     5 << 20,
+		ContentTypes: []string{"image/png", "image/jpeg", "image/gif", "image/webp"},
+	}
+	// DocumentPolicy admits exported reports
+	DocumentPolicy = Policy{
+		MaxBytes:     20 << 20,
+		ContentTypes: []string{"application/pdf", "text/csv"},
+	}
+)
+
+// ValidationError is an object refused by a policy
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Check refuses a content type the policy does not admit or a size over its limit
+func (p Policy) Check(contentType string, size int64) error {
+	if !p.Allows(contentType) {
+		return &ValidationError{Message: "must be one of " + strings.Join(p.ContentTypes, ", ")}
+	}
+	if size > p.MaxBytes {
+		return &ValidationError{Message: fmt.Sprintf("must be at most %s", formatBytes(p.MaxBytes))}
+	}
+	return nil
+}
+
+// Allows reports whether the policy admits a content type; parameters such as charset are
+// ignored
+func (p Policy) Allows(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return slices.Contains(p.ContentTypes, strings.ToLower(strings.TrimSpace(mediaType)))
+}
+
+// Sniff detects the content type of data from its first bytes rather than trusting the
+// name or type a client claims. The returned reader yields the complete data.
+func Sniff(r io.Reader) (string, io.Reader, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", nil, err
+	}
+	head = head[:n]
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	return contentType, io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+func formatBytes(n int64) string {
+	if n >= 1<<20 && n%(1<<20) == 0 {
+		return fmt.Sprintf("%d MB", n>>20)
+	}
+	if n >= 1<<10 && n%(1<<10) == 0 {
+		return fmt.Sprintf("%d KB", n>>10)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
@@ -72,3 +72,32 @@ func (auth *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc
 		return next(c)
 	})
 }
+
+// Clerk organization roles allowed to use restricted endpoints
+const (
+	RoleAdmin  = "org:admin"
+	RoleEditor = "org:editor"
+)
+
+// RequireRole allows the request only when the authenticated user has one of the given
+// Clerk organization roles. Must run after RequireAuth.
+func (auth *AuthMiddleware) RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role := GetUserRole(c)
+			for _, r := range roles {
+				if role == r {
+					return next(c)
+				}
+			}
+
+			auth.server.Logger.Warn().
+				Str("function", "RequireRole").
+				Str("user_id", GetUserID(c)).
+				Str("user_role", role).
+				Str("request_id", GetRequestID(c)).
+				Msg("user lacks required role")
+			return errs.NewForbiddenError("Forbidden", false)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package achievement
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/lib/llm"
+	"github.com/manikandareas/genta/internal/model/readiness"
+)
+
+// EventType names a domain event achievements are evaluated against
+type EventType string
+
+const (
+	EventAttemptRecorded  EventType = "attempt_recorded"
+	EventSessionEnded     EventType = "session_ended"
+	EventReadinessUpdated EventType = "readiness_updated"
+)
+
+// Event is something a user did that may unlock achievements
+type Event struct {
+	Type      EventType
+	UserID    uuid.UUID
+	Section   string
+	SessionID string
+}
+
+// Metric is what a rule measures
+type Metric string
+
+const (
+	// MetricQuestionsAnswered counts answered questions, in one section when the rule has one
+	MetricQuestionsAnswered Metric = "questions_answered"
+	// MetricStreakDays counts consecutive days (WIB) with at least one answer, ending today
+	MetricStreakDays Metric = "streak_days"
+	// MetricReadinessPercentage is a section's readiness percentage
+	MetricReadinessPercentage Metric = "readiness_percentage"
+	// MetricPerfectSession is the question count of an ended session answered without mistakes
+	MetricPerfectSession Metric = "perfect_session"
+)
+
+// Text is a localized string
+type Text map[llm.Language]string
+
+// Get returns the text in lang, falling back to Indonesian
+func (t Text) Get(lang llm.Language) string {
+	if s, ok := t[lang]; ok {
+		return s
+	}
+	return t[llm.LangIndonesian]
+}
+
+// Definition is a catalogue entry: the rule that unlocks an achievement
+type Definition struct {
+	Code        string
+	Title       Text
+	Description Text
+	Metric      Metric
+	// Section scopes section metrics; empty means all sections
+	Section   string
+	Threshold float64
+	// Triggers are the events that re-evaluate the rule
+	Triggers []EventType
+}
+
+// TriggeredBy reports whether the rule is evaluated on an event
+func (d *Definition) TriggeredBy(event Event) bool {
+	if d.Section != "" && event.Section != "" && d.Section != event.Section {
+		return false
+	}
+	for _, t := range d.Triggers {
+		if t == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// UserAchievement is an unlocked achievement
+type UserAchievement struct {
+	UserID     uuid.UUID  `db:"user_id"`
+	Code       string     `db:"code"`
+	Value      float64    `db:"value"`
+	UnlockedAt time.Time  `db:"unlocked_at"`
+	SeenAt     *time.Time `db:"seen_at"`
+}
+
+const (
+	// StreakUTCOffset is the UTC offset (WIB) in which streak days turn over
+	StreakUTCOffset = 7
+
+	// perfectSessionMinQuestions keeps tiny sessions from counting as perfect
+	perfectSessionMinQuestions = 10
+)
+
+var catalogue = buildCatalogue()
+
+// Catalogue returns every achievement, in display order
+func Catalogue() []Definition {
+	return catalogue
+}
+
+// Find returns the catalogue entry with the code
+func Find(code string) (Definition, bool) {
+	for _, d := range catalogue {
+		if d.Code == code {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+func buildCatalogue() []Definition {
+	answered := []EventType{EventAttemptRecorded}
+
+	defs := []Definition{
+		{
+			Code:        "first_question",
+			Title:       Text{llm.LangIndonesian: "Langkah Pertama", llm.LangEnglish: "First Step"},
+			Description: Text{llm.LangIndonesian: "Jawab soal pertamamu", llm.LangEnglish: "Answer your first question"},
+			Metric:      MetricQuestionsAnswered, Threshold: 1, Triggers: answered,
+		},
+		{
+			Code:        "questions_100",
+			Title:       Text{llm.LangIndonesian: "Seratus Soal", llm.LangEnglish: "Hundred Club"},
+			Description: Text{llm.LangIndonesian: "Jawab 100 soal", llm.LangEnglish: "Answer 100 questions"},
+			Metric:      MetricQuestionsAnswered, Threshold: 100, Triggers: answered,
+		},
+		{
+			Code:        "questions_1000",
+			Title:       Text{llm.LangIndonesian: "Seribu Soal", llm.LangEnglish: "Thousand Club"},
+			Description: Text{llm.LangIndonesian: "Jawab 1.000 soal", llm.LangEnglish: "Answer 1,000 questions"},
+			Metric:      MetricQuestionsAnswered, Threshold: 1000, Triggers: answered,
+		},
+		{
+			Code:        "streak_3",
+			Title:       Text{llm.LangIndonesian: "Mulai Konsisten", llm.LangEnglish: "Getting Consistent"},
+			Description: Text{llm.LangIndonesian: "Latihan 3 hari berturut-turut", llm.LangEnglish: "Practice 3 days in a row"},
+			Metric:      MetricStreakDays, Threshold: 3, Triggers: answered,
+		},
+		{
+			Code:        "streak_7",
+			Title:       Text{llm.LangIndonesian: "Seminggu Penuh", llm.LangEnglish: "Full Week"},
+			Description: Text{llm.LangIndonesian: "Latihan 7 hari berturut-turut", llm.LangEnglish: "Practice 7 days in a row"},
+			Metric:      MetricStreakDays, Threshold: 7, Triggers: answered,
+		},
+		{
+			Code:        "streak_30",
+			Title:       Text{llm.LangIndonesian: "Sebulan Tanpa Absen", llm.LangEnglish: "Unbroken Month"},
+			Description: Text{llm.LangIndonesian: "Latihan 30 hari berturut-turut", llm.LangEnglish: "Practice 30 days in a row"},
+			Metric:      MetricStreakDays, Threshold: 30, Triggers: answered,
+		},
+		{
+			Code:        "perfect_session",
+			Title:       Text{llm.LangIndonesian: "Sesi Sempurna", llm.LangEnglish: "Perfect Session"},
+			Description: Text{llm.LangIndonesian: fmt.Sprintf("Selesaikan sesi minimal %d soal tanpa salah", perfectSessionMinQuestions), llm.LangEnglish: fmt.Sprintf("Finish a session of at least %d questions without a mistake", perfectSessionMinQuestions)},
+			Metric:      MetricPerfectSession, Threshold: perfectSessionMinQuestions, Triggers: []EventType{EventSessionEnded},
+		},
+	}
+
+	for _, section := range readiness.DefaultSections() {
+		defs = append(defs, Definition{
+			Code:        "questions_100_" + section,
+			Title:       Text{llm.LangIndonesian: "100 Soal " + section, llm.LangEnglish: "100 " + section + " Questions"},
+			Description: Text{llm.LangIndonesian: "Jawab 100 soal " + section, llm.LangEnglish: "Answer 100 " + section + " questions"},
+			Metric:      MetricQuestionsAnswered, Section: section, Threshold: 100, Triggers: answered,
+		})
+	}
+
+	for _, section := range readiness.DefaultSections() {
+		defs = append(defs, Definition{
+			Code:        "ready_" + section,
+			Title:       Text{llm.LangIndonesian: "Siap " + section, llm.LangEnglish: section + " Ready"},
+			Description: Text{llm.LangIndonesian: fmt.Sprintf("Capai kesiapan %.0f%% di %s", readiness.ReadyPercentage, section), llm.LangEnglish: fmt.Sprintf("Reach %.0f%% readiness in %s", readiness.ReadyPercentage, section)},
+			Metric:      MetricReadinessPercentage, Section: section, Threshold: readiness.ReadyPercentage,
+			Triggers: []EventType{EventReadinessUpdated},
+		})
+	}
+
+	return defs
+}
@@ -0,0 +1,99 @@
+package achievement
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/manikandareas/genta/internal/lib/llm"
+)
+
+// === Request DTOs ===
+
+// GetCatalogueRequest represents query params for the achievements catalogue
+type GetCatalogueRequest struct {
+	Lang string `query:"lang" validate:"omitempty,oneof=id en"`
+}
+
+func (r *GetCatalogueRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// MarkSeenRequest represents the body for acknowledging unlock notifications.
+// Without codes every unseen achievement is marked seen.
+type MarkSeenRequest struct {
+	Codes []string `json:"codes,omitempty" validate:"omitempty,max=100,dive,required,max=50"`
+}
+
+func (r *MarkSeenRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// === Response DTOs ===
+
+// CatalogueResponse represents every achievement with the caller's progress on it
+type CatalogueResponse struct {
+	Unlocked     int                   `json:"unlocked"`
+	Total        int                   `json:"total"`
+	Unseen       int                   `json:"unseen"`
+	Achievements []AchievementResponse `json:"achievements"`
+}
+
+// AchievementResponse represents one catalogue entry
+type AchievementResponse struct {
+	Code        string  `json:"code"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Metric      string  `json:"metric"`
+	Section     *string `json:"section,omitempty"`
+	Threshold   float64 `json:"threshold"`
+	Unlocked    bool    `json:"unlocked"`
+	UnlockedAt  *string `json:"unlocked_at,omitempty"`
+	Seen        bool    `json:"seen"`
+}
+
+// UnlockedResponse represents an achievement unlocked by the current request, for notification
+type UnlockedResponse struct {
+	Code        string `json:"code"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	UnlockedAt  string `json:"unlocked_at"`
+}
+
+// MarkSeenResponse represents how many notifications were acknowledged
+type MarkSeenResponse struct {
+	Marked int64 `json:"marked"`
+}
+
+// === Converters ===
+
+// ToResponse converts a definition to its catalogue entry, with the user's unlock if any
+func (d *Definition) ToResponse(lang llm.Language, unlocked *UserAchievement) AchievementResponse {
+	resp := AchievementResponse{
+		Code:        d.Code,
+		Title:       d.Title.Get(lang),
+		Description: d.Description.Get(lang),
+		Metric:      string(d.Metric),
+		Threshold:   d.Threshold,
+	}
+	if d.Section != "" {
+		section := d.Section
+		resp.Section = &section
+	}
+	if unlocked != nil {
+		at := unlocked.UnlockedAt.Format("2006-01-02T15:04:05Z")
+		resp.Unlocked = true
+		resp.UnlockedAt = &at
+		resp.Seen = unlocked.SeenAt != nil
+	}
+	return resp
+}
+
+// ToUnlockedResponse converts an unlock to its notification
+func (d *Definition) ToUnlockedResponse(lang llm.Language, unlocked *UserAchievement) UnlockedResponse {
+	return UnlockedResponse{
+		Code:        d.Code,
+		Title:       d.Title.Get(lang),
+		Description: d.Description.Get(lang),
+		UnlockedAt:  unlocked.UnlockedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
@@ -0,0 +1,70 @@
+package admission
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PTN is a state university (ptn table)
+type PTN struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Code      string    `json:"code" db:"code"`
+	Name      string    `json:"name" db:"name"`
+	ShortName *string   `json:"shortName" db:"short_name"`
+	City      *string   `json:"city" db:"city"`
+	Province  *string   `json:"province" db:"province"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// StudyProgram is a program at a PTN with its most recent passing-score range
+type StudyProgram struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	PtnID      uuid.UUID `json:"ptnId" db:"ptn_id"`
+	PtnName    string    `json:"ptnName" db:"ptn_name"`
+	Code       string    `json:"code" db:"code"`
+	Name       string    `json:"name" db:"name"`
+	Cluster    *string   `json:"cluster" db:"cluster"`
+	Year       *int16    `json:"year" db:"year"`
+	ScoreLow   *float64  `json:"scoreLow" db:"score_low"`
+	ScoreHigh  *float64  `json:"scoreHigh" db:"score_high"`
+	Capacity   *int      `json:"capacity" db:"capacity"`
+	Applicants *int      `json:"applicants" db:"applicants"`
+}
+
+// CatalogueRow is one line of the PTN/program CSV import
+type CatalogueRow struct {
+	PtnCode     string
+	PtnName     string
+	PtnShort    string
+	City        string
+	Province    string
+	ProgramCode string
+	ProgramName string
+	Cluster     string
+	Year        int16
+	ScoreLow    float64
+	ScoreHigh   float64
+	Capacity    *int
+	Applicants  *int
+}
+
+// Chance categories for an admission estimate
+const (
+	ChanceSafe        = "safe"        // probability >= 0.7
+	ChanceCompetitive = "competitive" // probability >= 0.4
+	ChanceReach       = "reach"       // probability < 0.4
+)
+
+// ChanceCategory buckets an admission probability
+func ChanceCategory(probability float64) string {
+	switch {
+	case probability >= 0.7:
+		return ChanceSafe
+	case probability >= 0.4:
+		return ChanceCompetitive
+	default:
+		return ChanceReach
+	}
+}
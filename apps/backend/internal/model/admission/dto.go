@@ -0,0 +1,135 @@
+package admission
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// === Request DTOs ===
+
+// ListPTNRequest represents query params for searching the PTN catalogue
+type ListPTNRequest struct {
+	Search *string `query:"search" validate:"omitempty,max=100"`
+	Limit  int     `query:"limit" validate:"min=1,max=100"`
+}
+
+func (r *ListPTNRequest) Validate() error {
+	// Set defaults
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// ListProgramsRequest represents params for listing a PTN's study programs
+type ListProgramsRequest struct {
+	PtnID   string  `param:"id" validate:"required,uuid"`
+	Cluster *string `query:"cluster" validate:"omitempty,oneof=saintek soshum"`
+	Search  *string `query:"search" validate:"omitempty,max=100"`
+}
+
+func (r *ListProgramsRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// GetAdmissionChanceRequest represents query params for an admission estimate.
+// Without program_id the user's target program is used.
+type GetAdmissionChanceRequest struct {
+	ProgramID *string `query:"program_id" validate:"omitempty,uuid"`
+}
+
+func (r *GetAdmissionChanceRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// ImportCatalogueRequest represents the multipart CSV upload of the PTN catalogue.
+// The file itself is read from the "file" form field.
+type ImportCatalogueRequest struct{}
+
+func (r *ImportCatalogueRequest) Validate() error {
+	return nil
+}
+
+// === Response DTOs ===
+
+// PTNResponse represents a PTN in the catalogue
+type PTNResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	ShortName *string   `json:"short_name,omitempty"`
+	City      *string   `json:"city,omitempty"`
+	Province  *string   `json:"province,omitempty"`
+}
+
+// StudyProgramResponse represents a study program with its latest passing-score range
+type StudyProgramResponse struct {
+	ID         uuid.UUID `json:"id"`
+	PtnID      uuid.UUID `json:"ptn_id"`
+	PtnName    string    `json:"ptn_name"`
+	Code       string    `json:"code"`
+	Name       string    `json:"name"`
+	Cluster    *string   `json:"cluster,omitempty"`
+	Year       *int16    `json:"year,omitempty"`
+	ScoreLow   *float64  `json:"score_low,omitempty"`
+	ScoreHigh  *float64  `json:"score_high,omitempty"`
+	Capacity   *int      `json:"capacity,omitempty"`
+	Applicants *int      `json:"applicants,omitempty"`
+}
+
+// AdmissionChanceResponse represents the estimated admission probability for a program
+type AdmissionChanceResponse struct {
+	Program            StudyProgramResponse `json:"program"`
+	PredictedComposite int                  `json:"predicted_composite"`
+	PredictedLow       int                  `json:"predicted_low"`
+	PredictedHigh      int                  `json:"predicted_high"`
+	PredictionVersion  string               `json:"prediction_version"`
+	PassingScore       float64              `json:"passing_score"`
+	GapToPassing       int                  `json:"gap_to_passing"`
+	Probability        float64              `json:"probability"`
+	Category           string               `json:"category"`
+	// Selectivity is applicants per seat in the reference year, when known
+	Selectivity *float64 `json:"selectivity,omitempty"`
+}
+
+// ImportCatalogueResponse summarizes a catalogue import
+type ImportCatalogueResponse struct {
+	Rows     int `json:"rows"`
+	PTNs     int `json:"ptns"`
+	Programs int `json:"programs"`
+}
+
+// === Converters ===
+
+// ToResponse converts PTN to PTNResponse
+func (p *PTN) ToResponse() PTNResponse {
+	return PTNResponse{
+		ID:        p.ID,
+		Code:      p.Code,
+		Name:      p.Name,
+		ShortName: p.ShortName,
+		City:      p.City,
+		Province:  p.Province,
+	}
+}
+
+// ToResponse converts StudyProgram to StudyProgramResponse
+func (p *StudyProgram) ToResponse() StudyProgramResponse {
+	return StudyProgramResponse{
+		ID:         p.ID,
+		PtnID:      p.PtnID,
+		PtnName:    p.PtnName,
+		Code:       p.Code,
+		Name:       p.Name,
+		Cluster:    p.Cluster,
+		Year:       p.Year,
+		ScoreLow:   p.ScoreLow,
+		ScoreHigh:  p.ScoreHigh,
+		Capacity:   p.Capacity,
+		Applicants: p.Applicants,
+	}
+}
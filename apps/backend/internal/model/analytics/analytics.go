@@ -21,13 +21,56 @@ type SectionBreakdown struct {
 
 // ProgressAnalytics represents the aggregated progress data
 type ProgressAnalytics struct {
-	PeriodDays              int                `json:"period_days"`
-	TotalQuestionsAttempted int                `json:"total_questions_attempted"`
-	TotalCorrect            int                `json:"total_correct"`
-	AverageAccuracy         float64            `json:"average_accuracy"`
-	AccuracyTrend           []DailyAccuracy    `json:"accuracy_trend"`
-	SectionBreakdown        []SectionBreakdown `json:"section_breakdown"`
-	ImprovementThisWeek     float64            `json:"improvement_this_week"`
+	PeriodDays              int                    `json:"period_days"`
+	TotalQuestionsAttempted int                    `json:"total_questions_attempted"`
+	TotalCorrect            int                    `json:"total_correct"`
+	AverageAccuracy         float64                `json:"average_accuracy"`
+	AccuracyTrend           []DailyAccuracy        `json:"accuracy_trend"`
+	SectionBreakdown        []SectionBreakdown     `json:"section_breakdown"`
+	ImprovementThisWeek     float64                `json:"improvement_this_week"`
+	Breakdowns              map[string][]Breakdown `json:"breakdowns"`
+}
+
+// Breakdown dimensions
+const (
+	BreakdownSubtype    = "subtype"
+	BreakdownDifficulty = "difficulty"
+	BreakdownTimeOfDay  = "time_of_day"
+)
+
+// TimezoneOffsets maps Indonesian time zones to their UTC offset in hours (no DST)
+var TimezoneOffsets = map[string]int{
+	"WIB":  7,
+	"WITA": 8,
+	"WIT":  9,
+}
+
+// Filter selects the attempts an analytics query covers. From and To are UTC instants
+// (To exclusive); days are bucketed by the UTC offset of the user's time zone.
+type Filter struct {
+	From        time.Time
+	To          time.Time
+	UTCOffset   int
+	Sections    []string
+	Granularity string
+}
+
+// PeriodStats represents aggregate stats over a period
+type PeriodStats struct {
+	Attempts       int     `db:"attempts"`
+	Correct        int     `db:"correct"`
+	Accuracy       float64 `db:"accuracy"`
+	AvgTimeSeconds float64 `db:"avg_time_seconds"`
+}
+
+// Breakdown represents accuracy for one bucket of a breakdown dimension
+type Breakdown struct {
+	Key            string  `db:"key"`
+	Section        *string `db:"section"`
+	Attempts       int     `db:"attempts"`
+	Correct        int     `db:"correct"`
+	Accuracy       float64 `db:"accuracy"`
+	AvgTimeSeconds float64 `db:"avg_time_seconds"`
 }
 
 // SectionNameMap maps section codes to full names
@@ -1,18 +1,33 @@
 package analytics
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 )
 
 // === Request DTOs ===
 
-// GetProgressRequest represents query params for getting progress analytics
+// GetProgressRequest represents query params for getting progress analytics.
+// A range is either the last Days days or From..To (inclusive local dates).
 type GetProgressRequest struct {
-	Days    int    `query:"days" validate:"omitempty,oneof=7 30 90"`
-	Section string `query:"section" validate:"omitempty,oneof=PU PPU PBM PK LBI LBE PM"`
+	Days        int      `query:"days" validate:"omitempty,min=1,max=366"`
+	From        string   `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To          string   `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Timezone    string   `query:"timezone" validate:"omitempty,oneof=WIB WITA WIT"`
+	Section     string   `query:"section" validate:"omitempty,oneof=PU PPU PBM PK LBI LBE PM"`
+	Sections    []string `query:"sections" validate:"omitempty,max=7,dive,oneof=PU PPU PBM PK LBI LBE PM"`
+	Granularity string   `query:"granularity" validate:"omitempty,oneof=day week"`
+	// Compare against the previous period of equal length, or an explicit range
+	Compare     string   `query:"compare" validate:"omitempty,oneof=previous"`
+	CompareFrom string   `query:"compare_from" validate:"omitempty,datetime=2006-01-02,required_with=CompareTo"`
+	CompareTo   string   `query:"compare_to" validate:"omitempty,datetime=2006-01-02,required_with=CompareFrom"`
+	Breakdowns  []string `query:"breakdown" validate:"omitempty,max=3,dive,oneof=subtype difficulty time_of_day"`
 }
 
 func (r *GetProgressRequest) Validate() error {
+	r.normalize()
 	validate := validator.New()
 	return validate.Struct(r)
 }
@@ -22,6 +37,59 @@ func (r *GetProgressRequest) SetDefaults() {
 	if r.Days == 0 {
 		r.Days = 7
 	}
+	if r.Timezone == "" {
+		r.Timezone = "WIB"
+	}
+	if r.Granularity == "" {
+		r.Granularity = "day"
+	}
+	if r.Section != "" && !slices.Contains(r.Sections, r.Section) {
+		r.Sections = append(r.Sections, r.Section)
+	}
+}
+
+// normalize splits comma-separated list params (sections=PU,PK) into their values
+func (r *GetProgressRequest) normalize() {
+	r.Sections = splitList(r.Sections)
+	r.Breakdowns = splitList(r.Breakdowns)
+}
+
+func splitList(values []string) []string {
+	var result []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" && !slices.Contains(result, part) {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
+
+// GetPacingRequest represents query params for pacing analytics
+type GetPacingRequest struct {
+	Days     int      `query:"days" validate:"omitempty,min=1,max=366"`
+	From     string   `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To       string   `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Timezone string   `query:"timezone" validate:"omitempty,oneof=WIB WITA WIT"`
+	Sections []string `query:"sections" validate:"omitempty,max=7,dive,oneof=PU PPU PBM PK LBI LBE PM"`
+	Lang     string   `query:"lang" validate:"omitempty,oneof=id en"`
+}
+
+func (r *GetPacingRequest) Validate() error {
+	r.Sections = splitList(r.Sections)
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// SetDefaults sets default values for the request
+func (r *GetPacingRequest) SetDefaults() {
+	if r.Days == 0 {
+		r.Days = 30
+	}
+	if r.Timezone == "" {
+		r.Timezone = "WIB"
+	}
 }
 
 // === Response DTOs ===
@@ -43,15 +111,96 @@ type SectionBreakdownResponse struct {
 	AvgTimeSeconds float64 `json:"avg_time_seconds"`
 }
 
+// PeriodStatsResponse represents aggregate stats over a period
+type PeriodStatsResponse struct {
+	From           string  `json:"from"`
+	To             string  `json:"to"`
+	Attempts       int     `json:"attempts"`
+	Correct        int     `json:"correct"`
+	Accuracy       float64 `json:"accuracy"`
+	AvgTimeSeconds float64 `json:"avg_time_seconds"`
+}
+
+// PeriodComparisonResponse compares the requested period with another one.
+// Changes are current minus previous; accuracy change is in percentage points.
+type PeriodComparisonResponse struct {
+	Current        PeriodStatsResponse `json:"current"`
+	Previous       PeriodStatsResponse `json:"previous"`
+	AccuracyChange float64             `json:"accuracy_change"`
+	AttemptsChange int                 `json:"attempts_change"`
+	AvgTimeChange  float64             `json:"avg_time_change"`
+}
+
+// BreakdownResponse represents accuracy for one bucket of a breakdown
+type BreakdownResponse struct {
+	Key            string  `json:"key"`
+	Section        *string `json:"section,omitempty"`
+	Attempts       int     `json:"attempts"`
+	Correct        int     `json:"correct"`
+	Accuracy       float64 `json:"accuracy"`
+	AvgTimeSeconds float64 `json:"avg_time_seconds"`
+}
+
 // ProgressResponse represents the API response for progress analytics
 type ProgressResponse struct {
-	PeriodDays              int                        `json:"period_days"`
-	TotalQuestionsAttempted int                        `json:"total_questions_attempted"`
-	TotalCorrect            int                        `json:"total_correct"`
-	AverageAccuracy         float64                    `json:"average_accuracy"`
-	AccuracyTrend           []AccuracyTrendResponse    `json:"accuracy_trend"`
-	SectionBreakdown        []SectionBreakdownResponse `json:"section_breakdown"`
-	ImprovementThisWeek     float64                    `json:"improvement_this_week"`
+	PeriodDays              int                            `json:"period_days"`
+	From                    string                         `json:"from"`
+	To                      string                         `json:"to"`
+	Timezone                string                         `json:"timezone"`
+	Sections                []string                       `json:"sections,omitempty"`
+	Granularity             string                         `json:"granularity"`
+	TotalQuestionsAttempted int                            `json:"total_questions_attempted"`
+	TotalCorrect            int                            `json:"total_correct"`
+	AverageAccuracy         float64                        `json:"average_accuracy"`
+	AccuracyTrend           []AccuracyTrendResponse        `json:"accuracy_trend"`
+	SectionBreakdown        []SectionBreakdownResponse     `json:"section_breakdown"`
+	ImprovementThisWeek     float64                        `json:"improvement_this_week"`
+	Comparison              *PeriodComparisonResponse      `json:"comparison,omitempty"`
+	Breakdowns              map[string][]BreakdownResponse `json:"breakdowns,omitempty"`
+}
+
+// PacingResponse represents pacing analytics per section with recommendations
+type PacingResponse struct {
+	From            string                  `json:"from"`
+	To              string                  `json:"to"`
+	Timezone        string                  `json:"timezone"`
+	Sections        []SectionPacingResponse `json:"sections"`
+	Recommendations []PacingRecommendation  `json:"recommendations"`
+}
+
+// SectionPacingResponse represents timing stats for a section
+type SectionPacingResponse struct {
+	Section             string              `json:"section"`
+	SectionName         string              `json:"section_name"`
+	BudgetSeconds       int                 `json:"budget_seconds"`
+	Attempts            int                 `json:"attempts"`
+	Accuracy            float64             `json:"accuracy"`
+	AvgTimeSeconds      float64             `json:"avg_time_seconds"`
+	MedianTimeSeconds   float64             `json:"median_time_seconds"`
+	CohortMedianSeconds *float64            `json:"cohort_median_seconds,omitempty"`
+	OverBudgetShare     float64             `json:"over_budget_share"`
+	RushingShare        float64             `json:"rushing_share"`
+	OverthinkingShare   float64             `json:"overthinking_share"`
+	SpeedAccuracyCorr   *float64            `json:"speed_accuracy_correlation,omitempty"`
+	SpeedBands          []SpeedBandResponse `json:"speed_bands"`
+}
+
+// SpeedBandResponse represents accuracy within a band of answer time relative to the budget
+type SpeedBandResponse struct {
+	Band     string  `json:"band"`
+	Attempts int     `json:"attempts"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// PacingRecommendation is a structured pacing insight for a section
+type PacingRecommendation struct {
+	Section   string  `json:"section"`
+	Type      string  `json:"type"`
+	Severity  string  `json:"severity"`
+	Metric    string  `json:"metric"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	Message   string  `json:"message"`
 }
 
 // === Converters ===
@@ -77,6 +226,18 @@ func (p *ProgressAnalytics) ToResponse() ProgressResponse {
 		})
 	}
 
+	// Convert dimension breakdowns
+	if len(p.Breakdowns) > 0 {
+		resp.Breakdowns = make(map[string][]BreakdownResponse, len(p.Breakdowns))
+		for dimension, buckets := range p.Breakdowns {
+			items := make([]BreakdownResponse, 0, len(buckets))
+			for _, b := range buckets {
+				items = append(items, b.ToResponse())
+			}
+			resp.Breakdowns[dimension] = items
+		}
+	}
+
 	// Convert section breakdown
 	for _, section := range p.SectionBreakdown {
 		resp.SectionBreakdown = append(resp.SectionBreakdown, SectionBreakdownResponse{
@@ -92,6 +253,18 @@ func (p *ProgressAnalytics) ToResponse() ProgressResponse {
 	return resp
 }
 
+// ToResponse converts Breakdown to BreakdownResponse
+func (b *Breakdown) ToResponse() BreakdownResponse {
+	return BreakdownResponse{
+		Key:            b.Key,
+		Section:        b.Section,
+		Attempts:       b.Attempts,
+		Correct:        b.Correct,
+		Accuracy:       b.Accuracy,
+		AvgTimeSeconds: b.AvgTimeSeconds,
+	}
+}
+
 // ToSectionBreakdownResponse converts SectionBreakdown to SectionBreakdownResponse
 func (s *SectionBreakdown) ToResponse() SectionBreakdownResponse {
 	return SectionBreakdownResponse{
@@ -0,0 +1,59 @@
+package analytics
+
+// TimeBudgetSeconds is the per-question time budget of each section in the official
+// SNBT-UTBK format (section duration / number of questions)
+var TimeBudgetSeconds = map[string]int{
+	"PU":  60,  // 30 soal, 30 menit
+	"PPU": 45,  // 20 soal, 15 menit
+	"PBM": 75,  // 20 soal, 25 menit
+	"PK":  60,  // 20 soal, 20 menit
+	"LBI": 85,  // 30 soal, 42.5 menit
+	"LBE": 60,  // 20 soal, 20 menit
+	"PM":  128, // 20 soal, 42.5 menit
+}
+
+// The cohort median time per section is materialized periodically from the last
+// PacingCohortWindowDays of attempts, and only for sections answered by at least
+// MinCohortUsers users
+const (
+	PacingCohortWindowDays = 30
+	MinCohortUsers         = 5
+)
+
+// Pacing recommendation types
+const (
+	PacingOverBudget       = "over_budget"
+	PacingRushing          = "rushing"
+	PacingOverthinking     = "overthinking"
+	PacingSlowerThanCohort = "slower_than_cohort"
+	PacingOnPace           = "on_pace"
+)
+
+// Recommendation severities
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// SectionPacing represents a user's timing stats in a section. Speed bands are relative to
+// the section's time budget: fast under half of it, slow over it, overthinking over 1.5x.
+type SectionPacing struct {
+	Section             string   `db:"section"`
+	BudgetSeconds       int      `db:"budget_seconds"`
+	Attempts            int      `db:"attempts"`
+	Correct             int      `db:"correct"`
+	AvgTimeSeconds      float64  `db:"avg_time_seconds"`
+	MedianTimeSeconds   float64  `db:"median_time_seconds"`
+	OverBudget          int      `db:"over_budget"`
+	FastAttempts        int      `db:"fast_attempts"`
+	FastCorrect         int      `db:"fast_correct"`
+	NormalAttempts      int      `db:"normal_attempts"`
+	NormalCorrect       int      `db:"normal_correct"`
+	SlowAttempts        int      `db:"slow_attempts"`
+	SlowCorrect         int      `db:"slow_correct"`
+	FastWrong           int      `db:"fast_wrong"`
+	OverthinkingWrong   int      `db:"overthinking_wrong"`
+	SpeedAccuracyCorr   *float64 `db:"speed_accuracy_corr"`
+	CohortMedianSeconds *float64 `db:"cohort_median_seconds"`
+}
@@ -57,12 +57,88 @@ type AttemptFeedback struct {
 	IsHelpful             *bool    `json:"isHelpful" db:"is_helpful"`
 	HelpfulRating         *int     `json:"helpfulRating" db:"helpful_rating"`
 
-	ModelUsed        string  `json:"modelUsed" db:"model_used"`
-	PromptVersion    *string `json:"promptVersion" db:"prompt_version"`
-	GenerationTimeMs *int    `json:"generationTimeMs" db:"generation_time_ms"`
-	TokenCountInput  *int16  `json:"tokenCountInput" db:"token_count_input"`
-	TokenCountOutput *int16  `json:"tokenCountOutput" db:"token_count_output"`
+	ModelUsed        string   `json:"modelUsed" db:"model_used"`
+	PromptVersion    *string  `json:"promptVersion" db:"prompt_version"`
+	GenerationTimeMs *int     `json:"generationTimeMs" db:"generation_time_ms"`
+	TokenCountInput  *int     `json:"tokenCountInput" db:"token_count_input"`
+	TokenCountOutput *int     `json:"tokenCountOutput" db:"token_count_output"`
+	CostUSD          *float64 `json:"costUsd" db:"cost_usd"`
+
+	// Set when the feedback was served from the canonical feedback cache
+	FeedbackCacheID *uuid.UUID `json:"feedbackCacheId" db:"feedback_cache_id"`
+
+	// Quality evaluation (see feedback:evaluation job)
+	QualityStatus     *string    `json:"qualityStatus" db:"quality_status"`
+	QualityFlags      []string   `json:"qualityFlags" db:"quality_flags"`
+	JudgeModel        *string    `json:"judgeModel" db:"judge_model"`
+	EvaluatedAt       *time.Time `json:"evaluatedAt" db:"evaluated_at"`
+	RegenerationCount *int16     `json:"regenerationCount" db:"regeneration_count"`
 
 	model.BaseWithCreatedAt
 	model.BaseWithUpdatedAt
 }
+
+// Feedback quality statuses
+const (
+	QualityStatusOK       = "ok"
+	QualityStatusFlagged  = "flagged"
+	QualityStatusReviewed = "reviewed"
+)
+
+// FeedbackCacheEntry represents canonical feedback for a (question, answer, language, prompt version)
+// pair (from feedback_cache table)
+type FeedbackCacheEntry struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	QuestionID     uuid.UUID `json:"questionId" db:"question_id"`
+	SelectedAnswer string    `json:"selectedAnswer" db:"selected_answer"`
+	FeedbackLang   string    `json:"feedbackLang" db:"feedback_lang"`
+	PromptVersion  string    `json:"promptVersion" db:"prompt_version"`
+
+	FeedbackText string `json:"feedbackText" db:"feedback_text"`
+	ModelUsed    string `json:"modelUsed" db:"model_used"`
+
+	HitCount  int        `json:"hitCount" db:"hit_count"`
+	LastHitAt *time.Time `json:"lastHitAt" db:"last_hit_at"`
+
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+}
+
+// FlaggedFeedback is feedback awaiting human review, with the attempt facts it was checked against
+type FlaggedFeedback struct {
+	AttemptFeedback
+	UserID           uuid.UUID `db:"user_id"`
+	QuestionID       uuid.UUID `db:"question_id"`
+	SelectedAnswer   string    `db:"selected_answer"`
+	CorrectAnswer    string    `db:"correct_answer"`
+	IsCorrect        bool      `db:"is_correct"`
+	SubscriptionTier string    `db:"subscription_tier"`
+}
+
+// HistoryFilter narrows a user's attempt history; nil fields do not filter
+type HistoryFilter struct {
+	Section     *string
+	SubType     *string
+	IsCorrect   *bool
+	From        *time.Time // inclusive, UTC
+	To          *time.Time // exclusive, UTC
+	SessionID   *string
+	HasFeedback *bool
+}
+
+// HistoryItem is an attempt in the history list with the question facts shown alongside it
+type HistoryItem struct {
+	Attempt
+	Section       string  `db:"section"`
+	SubType       *string `db:"sub_type"`
+	CorrectAnswer string  `db:"correct_answer"`
+	HasFeedback   bool    `db:"has_feedback"`
+}
+
+// RetryItem is a question the user answered wrong and has not answered correctly since
+type RetryItem struct {
+	question.Question
+	WrongCount         int       `db:"wrong_count"`
+	LastWrongAt        time.Time `db:"last_wrong_at"`
+	LastSelectedAnswer string    `db:"last_selected_answer"`
+}
@@ -42,22 +42,115 @@ func (r *UpdateFeedbackRatingRequest) Validate() error {
 	return validate.Struct(r)
 }
 
+// ListFlaggedFeedbackRequest represents query params for listing low-quality feedback
+type ListFlaggedFeedbackRequest struct {
+	// Page selects legacy offset pagination; without it the list is cursor paginated
+	Page   int    `query:"page" validate:"omitempty,min=1"`
+	Limit  int    `query:"limit" validate:"min=1,max=100"`
+	Cursor string `query:"cursor" validate:"omitempty,max=500,excluded_with=Page"`
+	// IncludeTotal counts the whole list, which costs an extra query
+	IncludeTotal bool `query:"include_total"`
+}
+
+func (r *ListFlaggedFeedbackRequest) Validate() error {
+	// Set defaults
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// ReviewFeedbackRequest represents an admin decision on flagged feedback
+type ReviewFeedbackRequest struct {
+	FeedbackID string `param:"id" validate:"required,uuid"`
+	// ok keeps the feedback as is, regenerate replaces it with a fresh generation
+	Action string `json:"action" validate:"required,oneof=ok regenerate"`
+}
+
+func (r *ReviewFeedbackRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// ListAttemptsRequest represents query params for the attempt history.
+// From..To are inclusive local dates in Timezone.
+type ListAttemptsRequest struct {
+	Section     *string `query:"section" validate:"omitempty,oneof=PU PPU PBM PK LBI LBE PM"`
+	SubType     *string `query:"sub_type" validate:"omitempty,max=50"`
+	IsCorrect   *bool   `query:"is_correct"`
+	From        string  `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To          string  `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Timezone    string  `query:"timezone" validate:"omitempty,oneof=WIB WITA WIT"`
+	SessionID   *string `query:"session_id" validate:"omitempty,max=100"`
+	HasFeedback *bool   `query:"has_feedback"`
+	Cursor      string  `query:"cursor" validate:"omitempty,max=500"`
+	Limit       int     `query:"limit" validate:"min=1,max=100"`
+	// IncludeTotal counts the whole filtered history, which costs an extra query
+	IncludeTotal bool `query:"include_total"`
+}
+
+func (r *ListAttemptsRequest) Validate() error {
+	// Set defaults
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	if r.Timezone == "" {
+		r.Timezone = "WIB"
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// GetRetryQueueRequest represents query params for the "retry wrong answers" practice mode.
+// It takes the same filters as the attempt history.
+type GetRetryQueueRequest struct {
+	Section   *string `query:"section" validate:"omitempty,oneof=PU PPU PBM PK LBI LBE PM"`
+	SubType   *string `query:"sub_type" validate:"omitempty,max=50"`
+	From      string  `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To        string  `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Timezone  string  `query:"timezone" validate:"omitempty,oneof=WIB WITA WIT"`
+	SessionID *string `query:"session_id" validate:"omitempty,max=100"`
+	Limit     int     `query:"limit" validate:"min=1,max=50"`
+}
+
+func (r *GetRetryQueueRequest) Validate() error {
+	// Set defaults
+	if r.Limit == 0 {
+		r.Limit = 10
+	}
+	if r.Timezone == "" {
+		r.Timezone = "WIB"
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
 // === Response DTOs ===
 
 // AttemptResponse represents the API response for an attempt (after submission)
 type AttemptResponse struct {
-	ID                uuid.UUID    `json:"id"`
-	QuestionID        uuid.UUID    `json:"question_id"`
-	SelectedAnswer    string       `json:"selected_answer"`
-	IsCorrect         bool         `json:"is_correct"`
-	TimeSpentSeconds  int16        `json:"time_spent_seconds"`
-	UserThetaBefore   *float64     `json:"user_theta_before"`
-	UserThetaAfter    *float64     `json:"user_theta_after"`
-	ThetaChange       *float64     `json:"theta_change"`
-	FeedbackGenerated bool         `json:"feedback_generated"`
-	SessionID         *string      `json:"session_id"`
-	CreatedAt         string       `json:"created_at"`
-	Job               *JobResponse `json:"job,omitempty"`
+	ID                uuid.UUID `json:"id"`
+	QuestionID        uuid.UUID `json:"question_id"`
+	SelectedAnswer    string    `json:"selected_answer"`
+	IsCorrect         bool      `json:"is_correct"`
+	TimeSpentSeconds  int16     `json:"time_spent_seconds"`
+	UserThetaBefore   *float64  `json:"user_theta_before"`
+	UserThetaAfter    *float64  `json:"user_theta_after"`
+	ThetaChange       *float64  `json:"theta_change"`
+	FeedbackGenerated bool      `json:"feedback_generated"`
+	SessionID         *string   `json:"session_id"`
+	// AttemptNumberInSession is the 1-based position of the attempt in its session
+	AttemptNumberInSession *int16       `json:"attempt_number_in_session"`
+	CreatedAt              string       `json:"created_at"`
+	Job                    *JobResponse `json:"job,omitempty"`
+	// Feedback is set when canonical feedback was served from cache at submission time
+	Feedback *FeedbackResponse `json:"feedback,omitempty"`
+	// FeedbackSkippedReason is set when feedback was not generated because of LLM quotas
+	FeedbackSkippedReason *string `json:"feedback_skipped_reason,omitempty"`
 }
 
 // JobResponse represents job info in attempt response
@@ -97,6 +190,34 @@ type FeedbackResponse struct {
 	ModelUsed        string    `json:"model_used"`
 	GenerationTimeMs *int      `json:"generation_time_ms"`
 	IsHelpful        *bool     `json:"is_helpful,omitempty"`
+	FromCache        bool      `json:"from_cache"`
+}
+
+// FlaggedFeedbackResponse represents low-quality feedback in the admin review queue
+type FlaggedFeedbackResponse struct {
+	ID                uuid.UUID `json:"id"`
+	AttemptID         uuid.UUID `json:"attempt_id"`
+	QuestionID        uuid.UUID `json:"question_id"`
+	SelectedAnswer    string    `json:"selected_answer"`
+	CorrectAnswer     string    `json:"correct_answer"`
+	FeedbackText      string    `json:"feedback_text"`
+	FeedbackLang      *string   `json:"feedback_lang"`
+	ModelUsed         string    `json:"model_used"`
+	QualityRating     *float64  `json:"quality_rating"`
+	QualityStatus     *string   `json:"quality_status"`
+	QualityFlags      []string  `json:"quality_flags"`
+	JudgeModel        *string   `json:"judge_model,omitempty"`
+	RegenerationCount int16     `json:"regeneration_count"`
+	EvaluatedAt       *string   `json:"evaluated_at"`
+	CreatedAt         string    `json:"created_at"`
+}
+
+// ReviewFeedbackResponse represents the result of an admin feedback review
+type ReviewFeedbackResponse struct {
+	FeedbackID    uuid.UUID    `json:"feedback_id"`
+	AttemptID     uuid.UUID    `json:"attempt_id"`
+	QualityStatus string       `json:"quality_status"`
+	Job           *JobResponse `json:"job,omitempty"`
 }
 
 // FeedbackRatingResponse represents the response after rating feedback
@@ -105,22 +226,52 @@ type FeedbackRatingResponse struct {
 	IsHelpful bool      `json:"is_helpful"`
 }
 
+// AttemptHistoryItemResponse is one attempt in the history
+type AttemptHistoryItemResponse struct {
+	ID               uuid.UUID `json:"id"`
+	QuestionID       uuid.UUID `json:"question_id"`
+	Section          string    `json:"section"`
+	SubType          *string   `json:"sub_type,omitempty"`
+	SelectedAnswer   string    `json:"selected_answer"`
+	CorrectAnswer    string    `json:"correct_answer"`
+	IsCorrect        bool      `json:"is_correct"`
+	TimeSpentSeconds int16     `json:"time_spent_seconds"`
+	ThetaChange      *float64  `json:"theta_change"`
+	SessionID        *string   `json:"session_id"`
+	HasFeedback      bool      `json:"has_feedback"`
+	CreatedAt        string    `json:"created_at"`
+}
+
+// RetryQueueResponse lists questions to practice again, most often missed first
+type RetryQueueResponse struct {
+	Questions []RetryQuestionResponse `json:"questions"`
+}
+
+// RetryQuestionResponse is a previously missed question, without its answer
+type RetryQuestionResponse struct {
+	question.QuestionResponse
+	WrongCount         int    `json:"wrong_count"`
+	LastWrongAt        string `json:"last_wrong_at"`
+	LastSelectedAnswer string `json:"last_selected_answer"`
+}
+
 // === Converters ===
 
 // ToResponse converts Attempt to AttemptResponse
 func (a *Attempt) ToResponse() AttemptResponse {
 	return AttemptResponse{
-		ID:                a.ID,
-		QuestionID:        a.QuestionID,
-		SelectedAnswer:    a.SelectedAnswer,
-		IsCorrect:         a.IsCorrect,
-		TimeSpentSeconds:  a.TimeSpentSeconds,
-		UserThetaBefore:   a.UserThetaBefore,
-		UserThetaAfter:    a.UserThetaAfter,
-		ThetaChange:       a.ThetaChange,
-		FeedbackGenerated: a.FeedbackGenerated,
-		SessionID:         a.SessionID,
-		CreatedAt:         a.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		ID:                     a.ID,
+		QuestionID:             a.QuestionID,
+		SelectedAnswer:         a.SelectedAnswer,
+		IsCorrect:              a.IsCorrect,
+		TimeSpentSeconds:       a.TimeSpentSeconds,
+		UserThetaBefore:        a.UserThetaBefore,
+		UserThetaAfter:         a.UserThetaAfter,
+		ThetaChange:            a.ThetaChange,
+		FeedbackGenerated:      a.FeedbackGenerated,
+		SessionID:              a.SessionID,
+		AttemptNumberInSession: a.AttemptNumberInSession,
+		CreatedAt:              a.CreatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 }
 
@@ -169,13 +320,8 @@ func (a *Attempt) ToDetailResponse() AttemptDetailResponse {
 
 	// Include feedback if loaded
 	if a.Feedback != nil {
-		resp.Feedback = &FeedbackResponse{
-			ID:               a.Feedback.ID,
-			FeedbackText:     a.Feedback.FeedbackText,
-			ModelUsed:        a.Feedback.ModelUsed,
-			GenerationTimeMs: a.Feedback.GenerationTimeMs,
-			IsHelpful:        a.Feedback.IsHelpful,
-		}
+		feedback := a.Feedback.ToResponse()
+		resp.Feedback = &feedback
 	}
 
 	return resp
@@ -189,6 +335,7 @@ func (f *AttemptFeedback) ToResponse() FeedbackResponse {
 		ModelUsed:        f.ModelUsed,
 		GenerationTimeMs: f.GenerationTimeMs,
 		IsHelpful:        f.IsHelpful,
+		FromCache:        f.FeedbackCacheID != nil,
 	}
 }
 
@@ -210,3 +357,61 @@ func QuestionToAttemptQuestion(q *question.Question) *QuestionInAttempt {
 		Explanation: q.Explanation,
 	}
 }
+
+// ToResponse converts FlaggedFeedback to FlaggedFeedbackResponse
+func (f *FlaggedFeedback) ToResponse() FlaggedFeedbackResponse {
+	resp := FlaggedFeedbackResponse{
+		ID:             f.ID,
+		AttemptID:      f.AttemptID,
+		QuestionID:     f.QuestionID,
+		SelectedAnswer: f.SelectedAnswer,
+		CorrectAnswer:  f.CorrectAnswer,
+		FeedbackText:   f.FeedbackText,
+		FeedbackLang:   f.FeedbackLang,
+		ModelUsed:      f.ModelUsed,
+		QualityRating:  f.FeedbackQualityRating,
+		QualityStatus:  f.QualityStatus,
+		QualityFlags:   f.QualityFlags,
+		JudgeModel:     f.JudgeModel,
+		CreatedAt:      f.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if resp.QualityFlags == nil {
+		resp.QualityFlags = []string{}
+	}
+	if f.RegenerationCount != nil {
+		resp.RegenerationCount = *f.RegenerationCount
+	}
+	if f.EvaluatedAt != nil {
+		evaluatedAt := f.EvaluatedAt.Format("2006-01-02T15:04:05Z")
+		resp.EvaluatedAt = &evaluatedAt
+	}
+	return resp
+}
+
+// ToResponse converts HistoryItem to AttemptHistoryItemResponse
+func (h *HistoryItem) ToResponse() AttemptHistoryItemResponse {
+	return AttemptHistoryItemResponse{
+		ID:               h.ID,
+		QuestionID:       h.QuestionID,
+		Section:          h.Section,
+		SubType:          h.SubType,
+		SelectedAnswer:   h.SelectedAnswer,
+		CorrectAnswer:    h.CorrectAnswer,
+		IsCorrect:        h.IsCorrect,
+		TimeSpentSeconds: h.TimeSpentSeconds,
+		ThetaChange:      h.ThetaChange,
+		SessionID:        h.SessionID,
+		HasFeedback:      h.HasFeedback,
+		CreatedAt:        h.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// ToResponse converts RetryItem to RetryQuestionResponse
+func (r *RetryItem) ToResponse() RetryQuestionResponse {
+	return RetryQuestionResponse{
+		QuestionResponse:   r.Question.ToResponse(),
+		WrongCount:         r.WrongCount,
+		LastWrongAt:        r.LastWrongAt.Format("2006-01-02T15:04:05Z"),
+		LastSelectedAnswer: r.LastSelectedAnswer,
+	}
+}
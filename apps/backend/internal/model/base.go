@@ -24,12 +24,24 @@ type Base struct {
 	BaseWithUpdatedAt
 }
 
+// PaginatedResponse is a page of a list endpoint. Page and TotalPages are set for legacy
+// page/limit requests; cursor requests get cursors and links instead, and Total only
+// when include_total is requested.
 type PaginatedResponse[T any] struct {
-	Data       []T `json:"data"`
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
-	Total      int `json:"total"`
-	TotalPages int `json:"totalPages"`
+	Data       []T        `json:"data"`
+	Page       int        `json:"page,omitempty"`
+	Limit      int        `json:"limit"`
+	Total      *int       `json:"total,omitempty"`
+	TotalPages *int       `json:"totalPages,omitempty"`
+	NextCursor *string    `json:"nextCursor,omitempty"`
+	PrevCursor *string    `json:"prevCursor,omitempty"`
+	Links      *PageLinks `json:"links,omitempty"`
+}
+
+// PageLinks are ready-to-follow URLs for the neighbouring pages of a cursor-paginated list
+type PageLinks struct {
+	Next *string `json:"next,omitempty"`
+	Prev *string `json:"prev,omitempty"`
 }
 
 // Section represents UTBK subtest codes
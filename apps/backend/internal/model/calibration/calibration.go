@@ -0,0 +1,36 @@
+package calibration
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScoreCalibration is one section of a versioned theta -> UTBK score mapping
+// (score_calibrations table)
+type ScoreCalibration struct {
+	Version    string     `json:"version" db:"version"`
+	Section    string     `json:"section" db:"section"`
+	Slope      float64    `json:"slope" db:"slope"`
+	Intercept  float64    `json:"intercept" db:"intercept"`
+	ResidualSD float64    `json:"residualSd" db:"residual_sd"`
+	SampleSize int        `json:"sampleSize" db:"sample_size"`
+	IsActive   bool       `json:"isActive" db:"is_active"`
+	CreatedBy  *uuid.UUID `json:"createdBy" db:"created_by"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// TryoutResult is an official tryout score paired with the student's estimated
+// theta at the time of the tryout (tryout_results table)
+type TryoutResult struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	UserID        uuid.UUID `json:"userId" db:"user_id"`
+	Section       string    `json:"section" db:"section"`
+	TryoutName    string    `json:"tryoutName" db:"tryout_name"`
+	ScaledScore   float64   `json:"scaledScore" db:"scaled_score"`
+	TakenAt       time.Time `json:"takenAt" db:"taken_at"`
+	ThetaAtTryout float64   `json:"thetaAtTryout" db:"theta_at_tryout"`
+	ThetaSE       float64   `json:"thetaSe" db:"theta_se"`
+	AttemptsCount int       `json:"attemptsCount" db:"attempts_count"`
+	CreatedAt     time.Time `json:"createdAt" db:"created_at"`
+}
@@ -0,0 +1,119 @@
+package calibration
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// === Request DTOs ===
+
+// TryoutResultInput is a single official tryout score
+type TryoutResultInput struct {
+	UserID      string  `json:"user_id" validate:"required,uuid"`
+	Section     string  `json:"section" validate:"required,oneof=PU PPU PBM PK LBI LBE PM"`
+	TryoutName  string  `json:"tryout_name" validate:"required,max=100"`
+	ScaledScore float64 `json:"scaled_score" validate:"required,min=0,max=1000"`
+	TakenAt     string  `json:"taken_at" validate:"required,datetime=2006-01-02"`
+}
+
+// ImportTryoutResultsRequest represents a batch of tryout scores used for calibration
+type ImportTryoutResultsRequest struct {
+	Results []TryoutResultInput `json:"results" validate:"required,min=1,max=1000,dive"`
+}
+
+func (r *ImportTryoutResultsRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// FitCalibrationRequest represents a request to fit a new calibration version from tryout data
+type FitCalibrationRequest struct {
+	Version  string `json:"version" validate:"required,max=30"`
+	Activate bool   `json:"activate"`
+}
+
+func (r *FitCalibrationRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// ActivateCalibrationRequest represents path params for activating a calibration version
+type ActivateCalibrationRequest struct {
+	Version string `param:"version" validate:"required,max=30"`
+}
+
+func (r *ActivateCalibrationRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// ListCalibrationsRequest represents query params for listing calibration versions
+type ListCalibrationsRequest struct {
+	Version *string `query:"version" validate:"omitempty,max=30"`
+}
+
+func (r *ListCalibrationsRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// === Response DTOs ===
+
+// ImportTryoutResultsResponse summarizes a tryout import
+type ImportTryoutResultsResponse struct {
+	Imported int `json:"imported"`
+}
+
+// SectionCalibrationResponse represents the mapping for one section
+type SectionCalibrationResponse struct {
+	Section    string  `json:"section"`
+	Slope      float64 `json:"slope"`
+	Intercept  float64 `json:"intercept"`
+	ResidualSD float64 `json:"residual_sd"`
+	SampleSize int     `json:"sample_size"`
+	// Fitted is false when the section had too few tryout results and the
+	// previously active mapping was carried over
+	Fitted *bool `json:"fitted,omitempty"`
+}
+
+// CalibrationVersionResponse represents a calibration version across sections
+type CalibrationVersionResponse struct {
+	Version   string                       `json:"version"`
+	IsActive  bool                         `json:"is_active"`
+	CreatedAt string                       `json:"created_at"`
+	Sections  []SectionCalibrationResponse `json:"sections"`
+}
+
+// === Converters ===
+
+// ToResponse converts ScoreCalibration to SectionCalibrationResponse
+func (c *ScoreCalibration) ToResponse() SectionCalibrationResponse {
+	return SectionCalibrationResponse{
+		Section:    c.Section,
+		Slope:      c.Slope,
+		Intercept:  c.Intercept,
+		ResidualSD: c.ResidualSD,
+		SampleSize: c.SampleSize,
+	}
+}
+
+// GroupByVersion converts calibration rows (ordered by version) into version responses
+func GroupByVersion(rows []ScoreCalibration) []CalibrationVersionResponse {
+	versions := []CalibrationVersionResponse{}
+	index := map[string]int{}
+	for i := range rows {
+		row := &rows[i]
+		idx, ok := index[row.Version]
+		if !ok {
+			idx = len(versions)
+			index[row.Version] = idx
+			versions = append(versions, CalibrationVersionResponse{
+				Version:   row.Version,
+				IsActive:  row.IsActive,
+				CreatedAt: row.CreatedAt.Format("2006-01-02T15:04:05Z"),
+				Sections:  []SectionCalibrationResponse{},
+			})
+		}
+		versions[idx].Sections = append(versions[idx].Sections, row.ToResponse())
+	}
+	return versions
+}
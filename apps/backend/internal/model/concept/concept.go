@@ -0,0 +1,105 @@
+package concept
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/lib/scoring"
+	"github.com/manikandareas/genta/internal/model"
+)
+
+// Level is a concept's depth in the taxonomy
+type Level string
+
+const (
+	LevelSection Level = "section"
+	LevelSubtype Level = "subtype"
+	LevelConcept Level = "concept"
+)
+
+// ChildLevel returns the level of concepts created under this one, and false for leaves
+func (l Level) ChildLevel() (Level, bool) {
+	switch l {
+	case LevelSection:
+		return LevelSubtype, true
+	case LevelSubtype:
+		return LevelConcept, true
+	}
+	return "", false
+}
+
+// MasteryStatus summarizes a concept's mastery estimate
+type MasteryStatus string
+
+const (
+	StatusNotStarted MasteryStatus = "not_started"
+	StatusWeak       MasteryStatus = "weak"
+	StatusLearning   MasteryStatus = "learning"
+	StatusMastered   MasteryStatus = "mastered"
+)
+
+// weakMastery is the mastery below which a practised concept counts as weak
+const weakMastery = 0.4
+
+// StatusOf classifies a mastery estimate
+func StatusOf(mastery float64, attempts int) MasteryStatus {
+	switch {
+	case attempts == 0:
+		return StatusNotStarted
+	case mastery >= scoring.MasteryThreshold:
+		return StatusMastered
+	case mastery < weakMastery:
+		return StatusWeak
+	default:
+		return StatusLearning
+	}
+}
+
+// Concept is a node of the section -> subtype -> concept taxonomy (from concepts table)
+type Concept struct {
+	ID          uuid.UUID     `json:"id" db:"id"`
+	ParentID    *uuid.UUID    `json:"parentId" db:"parent_id"`
+	Level       Level         `json:"level" db:"level"`
+	Section     model.Section `json:"section" db:"section"`
+	SubType     *string       `json:"subType" db:"sub_type"`
+	Code        string        `json:"code" db:"code"`
+	Name        string        `json:"name" db:"name"`
+	NameEn      *string       `json:"nameEn" db:"name_en"`
+	Description *string       `json:"description" db:"description"`
+
+	// Bayesian Knowledge Tracing parameters
+	PInit    float64 `json:"pInit" db:"p_init"`
+	PTransit float64 `json:"pTransit" db:"p_transit"`
+	PSlip    float64 `json:"pSlip" db:"p_slip"`
+	PGuess   float64 `json:"pGuess" db:"p_guess"`
+
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+}
+
+// Node is a concept with the number of questions tagged to it and, when read for a user,
+// their mastery of it. Mastery is nil for concepts the user has not practised.
+type Node struct {
+	ID            uuid.UUID     `db:"id"`
+	ParentID      *uuid.UUID    `db:"parent_id"`
+	Level         Level         `db:"level"`
+	Section       model.Section `db:"section"`
+	SubType       *string       `db:"sub_type"`
+	Code          string        `db:"code"`
+	Name          string        `db:"name"`
+	NameEn        *string       `db:"name_en"`
+	QuestionCount int           `db:"question_count"`
+	PInit         float64       `db:"p_init"`
+	Mastery       *float64      `db:"p_mastery"`
+	AttemptsCount int           `db:"attempts_count"`
+	CorrectCount  int           `db:"correct_count"`
+	LastAttemptAt *time.Time    `db:"last_attempt_at"`
+}
+
+// EstimatedMastery is the user's mastery, or the prior for an unpractised concept
+func (n *Node) EstimatedMastery() float64 {
+	if n.Mastery != nil {
+		return *n.Mastery
+	}
+	return n.PInit
+}
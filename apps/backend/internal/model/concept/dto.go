@@ -0,0 +1,252 @@
+package concept
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/lib/llm"
+	"github.com/manikandareas/genta/internal/model"
+)
+
+// === Request DTOs ===
+
+// GetTreeRequest represents query params for the taxonomy and mastery trees
+type GetTreeRequest struct {
+	Section *string `query:"section" validate:"omitempty,oneof=PU PPU PBM PK LBI LBE PM"`
+	Lang    string  `query:"lang" validate:"omitempty,oneof=id en"`
+}
+
+func (r *GetTreeRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// GetBlockersRequest represents query params for the concepts holding back readiness
+type GetBlockersRequest struct {
+	Section *string `query:"section" validate:"omitempty,oneof=PU PPU PBM PK LBI LBE PM"`
+	Limit   int     `query:"limit" validate:"min=1,max=20"`
+	Lang    string  `query:"lang" validate:"omitempty,oneof=id en"`
+}
+
+func (r *GetBlockersRequest) Validate() error {
+	// Set defaults
+	if r.Limit == 0 {
+		r.Limit = 5
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// CreateConceptRequest adds a subtype under a section or a concept under a subtype
+type CreateConceptRequest struct {
+	ParentID    string  `json:"parent_id" validate:"required,uuid"`
+	Name        string  `json:"name" validate:"required,max=255"`
+	NameEn      *string `json:"name_en" validate:"omitempty,max=255"`
+	Description *string `json:"description" validate:"omitempty,max=2000"`
+}
+
+func (r *CreateConceptRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// SetQuestionConceptsRequest replaces the concepts a question is tagged with
+type SetQuestionConceptsRequest struct {
+	QuestionID string   `param:"id" validate:"required,uuid"`
+	ConceptIDs []string `json:"concept_ids" validate:"required,min=1,max=10,dive,uuid"`
+}
+
+func (r *SetQuestionConceptsRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// RebuildMasteryRequest selects whose mastery to recompute; empty means every user
+type RebuildMasteryRequest struct {
+	UserID *string `json:"user_id" validate:"omitempty,uuid"`
+}
+
+func (r *RebuildMasteryRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// === Response DTOs ===
+
+// ConceptResponse represents a taxonomy node
+type ConceptResponse struct {
+	ID          uuid.UUID     `json:"id"`
+	ParentID    *uuid.UUID    `json:"parent_id,omitempty"`
+	Level       Level         `json:"level"`
+	Section     model.Section `json:"section"`
+	SubType     *string       `json:"sub_type,omitempty"`
+	Code        string        `json:"code"`
+	Name        string        `json:"name"`
+	NameEn      *string       `json:"name_en,omitempty"`
+	Description *string       `json:"description,omitempty"`
+}
+
+// TreeNodeResponse is a taxonomy node with its children. Mastery fields are only set on the
+// mastery tree; a parent's mastery is the question-weighted mean over its subtree.
+type TreeNodeResponse struct {
+	ID            uuid.UUID          `json:"id"`
+	Level         Level              `json:"level"`
+	Section       model.Section      `json:"section"`
+	Code          string             `json:"code"`
+	Name          string             `json:"name"`
+	QuestionCount int                `json:"question_count"`
+	Mastery       *float64           `json:"mastery,omitempty"`
+	Status        *MasteryStatus     `json:"status,omitempty"`
+	AttemptsCount *int               `json:"attempts_count,omitempty"`
+	LastPracticed *string            `json:"last_practiced,omitempty"`
+	Children      []TreeNodeResponse `json:"children"`
+}
+
+// TreeResponse represents the taxonomy, one root per section
+type TreeResponse struct {
+	Sections []TreeNodeResponse `json:"sections"`
+}
+
+// BlockerResponse is a concept holding back a section's readiness
+type BlockerResponse struct {
+	ConceptID     uuid.UUID     `json:"concept_id"`
+	Code          string        `json:"code"`
+	Name          string        `json:"name"`
+	Section       model.Section `json:"section"`
+	SubType       *string       `json:"sub_type,omitempty"`
+	Mastery       float64       `json:"mastery"`
+	Status        MasteryStatus `json:"status"`
+	AttemptsCount int           `json:"attempts_count"`
+	// Coverage is the concept's share of its section's questions
+	Coverage float64 `json:"coverage"`
+	// SectionReadiness is the section's readiness percentage, when estimated
+	SectionReadiness *float64 `json:"section_readiness,omitempty"`
+	// Impact ranks blockers: coverage times the mastery gap times the section's readiness gap
+	Impact float64 `json:"impact"`
+}
+
+// BlockersResponse lists the concepts to work on first, highest impact first
+type BlockersResponse struct {
+	Blockers []BlockerResponse `json:"blockers"`
+}
+
+// QuestionConceptsResponse represents the concepts a question is tagged with
+type QuestionConceptsResponse struct {
+	QuestionID uuid.UUID         `json:"question_id"`
+	Concepts   []ConceptResponse `json:"concepts"`
+}
+
+// RebuildJobResponse represents a queued mastery rebuild
+type RebuildJobResponse struct {
+	JobID          string `json:"job_id"`
+	Status         string `json:"status"`
+	CheckStatusURL string `json:"check_status_url"`
+}
+
+// === Converters ===
+
+// ToResponse converts Concept to ConceptResponse
+func (c *Concept) ToResponse() ConceptResponse {
+	return ConceptResponse{
+		ID:          c.ID,
+		ParentID:    c.ParentID,
+		Level:       c.Level,
+		Section:     c.Section,
+		SubType:     c.SubType,
+		Code:        c.Code,
+		Name:        c.Name,
+		NameEn:      c.NameEn,
+		Description: c.Description,
+	}
+}
+
+// LocalizedName returns the English name when asked for and available
+func (n *Node) LocalizedName(lang llm.Language) string {
+	if lang == llm.LangEnglish && n.NameEn != nil {
+		return *n.NameEn
+	}
+	return n.Name
+}
+
+// BuildTree nests taxonomy nodes under their parents. withMastery adds the user's mastery,
+// rolled up from the nodes questions are tagged to.
+func BuildTree(nodes []Node, withMastery bool, lang llm.Language) []TreeNodeResponse {
+	children := make(map[uuid.UUID][]*Node)
+	var roots []*Node
+	for i := range nodes {
+		if nodes[i].ParentID == nil {
+			roots = append(roots, &nodes[i])
+		} else {
+			children[*nodes[i].ParentID] = append(children[*nodes[i].ParentID], &nodes[i])
+		}
+	}
+
+	// subtree accumulates the question-weighted mastery and practice of a node's subtree
+	type subtree struct {
+		weighted      float64
+		attempts      int
+		lastPracticed *time.Time
+	}
+
+	var build func(n *Node) (TreeNodeResponse, subtree)
+	build = func(n *Node) (TreeNodeResponse, subtree) {
+		resp := TreeNodeResponse{
+			ID:            n.ID,
+			Level:         n.Level,
+			Section:       n.Section,
+			Code:          n.Code,
+			Name:          n.LocalizedName(lang),
+			QuestionCount: n.QuestionCount,
+			Children:      []TreeNodeResponse{},
+		}
+
+		acc := subtree{
+			weighted:      float64(n.QuestionCount) * n.EstimatedMastery(),
+			attempts:      n.AttemptsCount,
+			lastPracticed: n.LastAttemptAt,
+		}
+		for _, child := range children[n.ID] {
+			childResp, childAcc := build(child)
+			resp.Children = append(resp.Children, childResp)
+			resp.QuestionCount += childResp.QuestionCount
+			acc.weighted += childAcc.weighted
+			acc.attempts += childAcc.attempts
+			if childAcc.lastPracticed != nil && (acc.lastPracticed == nil || childAcc.lastPracticed.After(*acc.lastPracticed)) {
+				acc.lastPracticed = childAcc.lastPracticed
+			}
+		}
+
+		if withMastery && resp.QuestionCount > 0 {
+			mastery := acc.weighted / float64(resp.QuestionCount)
+			status := StatusOf(mastery, acc.attempts)
+			attempts := acc.attempts
+			resp.Mastery = &mastery
+			resp.Status = &status
+			resp.AttemptsCount = &attempts
+			if acc.lastPracticed != nil {
+				formatted := acc.lastPracticed.Format(time.RFC3339)
+				resp.LastPracticed = &formatted
+			}
+		}
+
+		return resp, acc
+	}
+
+	tree := make([]TreeNodeResponse, 0, len(roots))
+	for _, root := range roots {
+		resp, _ := build(root)
+		tree = append(tree, resp)
+	}
+	return tree
+}
+
+// NewRebuildJobResponse builds the response for a queued mastery rebuild
+func NewRebuildJobResponse(jobID string) RebuildJobResponse {
+	return RebuildJobResponse{
+		JobID:          jobID,
+		Status:         "queued",
+		CheckStatusURL: "/api/v1/jobs/" + jobID + "/check",
+	}
+}
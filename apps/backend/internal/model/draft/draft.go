@@ -0,0 +1,103 @@
+package draft
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/model"
+	"github.com/manikandareas/genta/internal/model/question"
+)
+
+// Kind is what a draft proposes
+type Kind string
+
+const (
+	KindEnrichment Kind = "enrichment" // Fills missing fields of an existing question
+	KindVariant    Kind = "variant"    // A new question derived from a template question
+)
+
+// Status is the review state of a draft
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+)
+
+// Enrichment fields that can be drafted for an existing question
+const (
+	FieldExplanation   = "explanation"
+	FieldExplanationEn = "explanation_en"
+	FieldStrategyTip   = "strategy_tip"
+	FieldSolutionSteps = "solution_steps"
+)
+
+// EnrichableFields lists the question fields the LLM may draft
+var EnrichableFields = []string{FieldExplanation, FieldExplanationEn, FieldStrategyTip, FieldSolutionSteps}
+
+// Draft represents LLM-drafted question content awaiting editor review (from question_drafts table).
+// Drafts are never served to students; approving one applies it to the questions table.
+type Draft struct {
+	ID               uuid.UUID `json:"id" db:"id"`
+	Kind             Kind      `json:"kind" db:"kind"`
+	SourceQuestionID uuid.UUID `json:"sourceQuestionId" db:"source_question_id"`
+	Status           Status    `json:"status" db:"status"`
+
+	Content          Content  `json:"content" db:"content"`
+	TargetDifficulty *float64 `json:"targetDifficulty" db:"target_difficulty"`
+
+	// Generation metadata
+	ModelUsed     *string  `json:"modelUsed" db:"model_used"`
+	PromptVersion *string  `json:"promptVersion" db:"prompt_version"`
+	TokensInput   *int     `json:"tokensInput" db:"tokens_input"`
+	TokensOutput  *int     `json:"tokensOutput" db:"tokens_output"`
+	CostUSD       *float64 `json:"costUsd" db:"cost_usd"`
+
+	// Review
+	RequestedBy       *uuid.UUID `json:"requestedBy" db:"requested_by"`
+	ReviewedBy        *uuid.UUID `json:"reviewedBy" db:"reviewed_by"`
+	ReviewedAt        *time.Time `json:"reviewedAt" db:"reviewed_at"`
+	ReviewNotes       *string    `json:"reviewNotes" db:"review_notes"`
+	AppliedQuestionID *uuid.UUID `json:"appliedQuestionId" db:"applied_question_id"`
+
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+}
+
+// Content holds drafted question fields (stored as JSONB).
+// Enrichment drafts only set the fields they fill; variant drafts set a full question.
+type Content struct {
+	SubType       *string                  `json:"sub_type,omitempty"`
+	Text          *string                  `json:"text,omitempty"`
+	Options       []string                 `json:"options,omitempty"`
+	CorrectAnswer *string                  `json:"correct_answer,omitempty"`
+	Explanation   *string                  `json:"explanation,omitempty"`
+	ExplanationEn *string                  `json:"explanation_en,omitempty"`
+	StrategyTip   *string                  `json:"strategy_tip,omitempty"`
+	SolutionSteps *[]question.SolutionStep `json:"solution_steps,omitempty"`
+}
+
+// IsCompleteQuestion reports whether the content can be inserted as a new question
+func (c *Content) IsCompleteQuestion() bool {
+	if c.Text == nil || *c.Text == "" || len(c.Options) != 5 || c.CorrectAnswer == nil {
+		return false
+	}
+	for _, opt := range c.Options {
+		if opt == "" {
+			return false
+		}
+	}
+	switch *c.CorrectAnswer {
+	case "A", "B", "C", "D", "E":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsEmpty reports whether no field was drafted
+func (c *Content) IsEmpty() bool {
+	return c.SubType == nil && c.Text == nil && len(c.Options) == 0 && c.CorrectAnswer == nil &&
+		c.Explanation == nil && c.ExplanationEn == nil && c.StrategyTip == nil && c.SolutionSteps == nil
+}
@@ -0,0 +1,173 @@
+package draft
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// === Request DTOs ===
+
+// EnrichQuestionRequest requests LLM drafts for missing fields of a question
+type EnrichQuestionRequest struct {
+	QuestionID string `param:"id" validate:"required,uuid"`
+	// Fields to draft; empty means every enrichable field the question is missing
+	Fields []string `json:"fields" validate:"omitempty,dive,oneof=explanation explanation_en strategy_tip solution_steps"`
+}
+
+func (r *EnrichQuestionRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// EnrichBatchRequest requests enrichment drafts for questions missing fields
+type EnrichBatchRequest struct {
+	Section *string  `json:"section" validate:"omitempty,oneof=PU PPU PBM PK LBI LBE PM"`
+	Fields  []string `json:"fields" validate:"omitempty,dive,oneof=explanation explanation_en strategy_tip solution_steps"`
+	Limit   int      `json:"limit" validate:"min=1,max=500"`
+}
+
+func (r *EnrichBatchRequest) Validate() error {
+	// Set defaults
+	if r.Limit == 0 {
+		r.Limit = 50
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// GenerateVariantsRequest requests new variant questions from a template question
+type GenerateVariantsRequest struct {
+	QuestionID       string   `param:"id" validate:"required,uuid"`
+	Count            int      `json:"count" validate:"min=1,max=5"`
+	TargetDifficulty *float64 `json:"target_difficulty" validate:"omitempty,min=-3,max=3"`
+}
+
+func (r *GenerateVariantsRequest) Validate() error {
+	// Set defaults
+	if r.Count == 0 {
+		r.Count = 1
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// ListDraftsRequest represents query params for listing drafts
+type ListDraftsRequest struct {
+	Status           *string `query:"status" validate:"omitempty,oneof=pending approved rejected"`
+	Kind             *string `query:"kind" validate:"omitempty,oneof=enrichment variant"`
+	SourceQuestionID *string `query:"source_question_id" validate:"omitempty,uuid"`
+	// Page selects legacy offset pagination; without it the list is cursor paginated
+	Page   int    `query:"page" validate:"omitempty,min=1"`
+	Limit  int    `query:"limit" validate:"min=1,max=100"`
+	Cursor string `query:"cursor" validate:"omitempty,max=500,excluded_with=Page"`
+	// IncludeTotal counts the whole list, which costs an extra query
+	IncludeTotal bool `query:"include_total"`
+}
+
+func (r *ListDraftsRequest) Validate() error {
+	// Set defaults
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// GetDraftRequest represents path params for a draft
+type GetDraftRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+func (r *GetDraftRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// UpdateDraftRequest lets an editor revise drafted content before approval
+type UpdateDraftRequest struct {
+	ID      string  `param:"id" validate:"required,uuid"`
+	Content Content `json:"content"`
+}
+
+func (r *UpdateDraftRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// ReviewDraftRequest approves or rejects a draft
+type ReviewDraftRequest struct {
+	ID    string  `param:"id" validate:"required,uuid"`
+	Notes *string `json:"notes" validate:"omitempty,max=2000"`
+	// AllowDuplicate approves a variant even if it resembles an existing question
+	AllowDuplicate bool `json:"allow_duplicate"`
+}
+
+func (r *ReviewDraftRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// === Response DTOs ===
+
+// DraftResponse represents the API response for a draft
+type DraftResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	Kind              Kind       `json:"kind"`
+	SourceQuestionID  uuid.UUID  `json:"source_question_id"`
+	Status            Status     `json:"status"`
+	Content           Content    `json:"content"`
+	TargetDifficulty  *float64   `json:"target_difficulty,omitempty"`
+	ModelUsed         *string    `json:"model_used,omitempty"`
+	CostUSD           *float64   `json:"cost_usd,omitempty"`
+	ReviewedBy        *uuid.UUID `json:"reviewed_by,omitempty"`
+	ReviewedAt        *string    `json:"reviewed_at,omitempty"`
+	ReviewNotes       *string    `json:"review_notes,omitempty"`
+	AppliedQuestionID *uuid.UUID `json:"applied_question_id,omitempty"`
+	CreatedAt         string     `json:"created_at"`
+}
+
+// DraftJobResponse represents a queued drafting job
+type DraftJobResponse struct {
+	JobID          string `json:"job_id"`
+	Status         string `json:"status"`
+	CheckStatusURL string `json:"check_status_url"`
+}
+
+// === Converters ===
+
+// ToResponse converts Draft to DraftResponse
+func (d *Draft) ToResponse() DraftResponse {
+	resp := DraftResponse{
+		ID:                d.ID,
+		Kind:              d.Kind,
+		SourceQuestionID:  d.SourceQuestionID,
+		Status:            d.Status,
+		Content:           d.Content,
+		TargetDifficulty:  d.TargetDifficulty,
+		ModelUsed:         d.ModelUsed,
+		CostUSD:           d.CostUSD,
+		ReviewedBy:        d.ReviewedBy,
+		ReviewNotes:       d.ReviewNotes,
+		AppliedQuestionID: d.AppliedQuestionID,
+		CreatedAt:         d.CreatedAt.Format(time.RFC3339),
+	}
+	if d.ReviewedAt != nil {
+		reviewedAt := d.ReviewedAt.Format(time.RFC3339)
+		resp.ReviewedAt = &reviewedAt
+	}
+	return resp
+}
+
+// NewDraftJobResponse builds the response for a queued drafting job
+func NewDraftJobResponse(jobID string) DraftJobResponse {
+	return DraftJobResponse{
+		JobID:          jobID,
+		Status:         "queued",
+		CheckStatusURL: "/api/v1/jobs/" + jobID + "/check",
+	}
+}
@@ -0,0 +1,152 @@
+package duplicate
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/model"
+)
+
+// === Request DTOs ===
+
+// ScanRequest represents the body for starting a duplicate scan
+type ScanRequest struct {
+	Section   *string  `json:"section" validate:"omitempty,oneof=PU PPU PBM PK LBI LBE PM"`
+	Threshold *float64 `json:"threshold" validate:"omitempty,min=0.5,max=1"`
+}
+
+func (r *ScanRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// ListClustersRequest represents query params for listing duplicate clusters
+type ListClustersRequest struct {
+	Status  *Status `query:"status" validate:"omitempty,oneof=pending merged deactivated dismissed"`
+	Section *string `query:"section" validate:"omitempty,oneof=PU PPU PBM PK LBI LBE PM"`
+	// Page selects legacy offset pagination; without it the list is cursor paginated
+	Page   int    `query:"page" validate:"omitempty,min=1"`
+	Limit  int    `query:"limit" validate:"min=1,max=100"`
+	Cursor string `query:"cursor" validate:"omitempty,max=500,excluded_with=Page"`
+	// IncludeTotal counts the whole list, which costs an extra query
+	IncludeTotal bool `query:"include_total"`
+}
+
+func (r *ListClustersRequest) Validate() error {
+	// Set defaults
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	if r.Status == nil {
+		status := StatusPending
+		r.Status = &status
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// GetClusterRequest represents path params for getting a cluster
+type GetClusterRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+func (r *GetClusterRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// ResolveClusterRequest records an editor's decision on a pending cluster.
+// Merge keeps KeepQuestionID and removes the other members; deactivate takes QuestionIDs
+// out of practice; dismiss marks the cluster as not duplicates.
+type ResolveClusterRequest struct {
+	ID             string     `param:"id" validate:"required,uuid"`
+	Action         Action     `json:"action" validate:"required,oneof=merge deactivate dismiss"`
+	KeepQuestionID *uuid.UUID `json:"keep_question_id" validate:"required_if=Action merge"`
+	QuestionIDs    []string   `json:"question_ids" validate:"required_if=Action deactivate,omitempty,min=1,dive,uuid"`
+	Notes          *string    `json:"notes" validate:"omitempty,max=2000"`
+}
+
+func (r *ResolveClusterRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// === Response DTOs ===
+
+// ClusterResponse represents a duplicate cluster with its questions
+type ClusterResponse struct {
+	ID              uuid.UUID        `json:"id"`
+	Section         model.Section    `json:"section"`
+	Status          Status           `json:"status"`
+	MaxSimilarity   float64          `json:"max_similarity"`
+	SuggestedKeepID uuid.UUID        `json:"suggested_keep_id"`
+	KeptQuestionID  *uuid.UUID       `json:"kept_question_id,omitempty"`
+	ResolvedBy      *uuid.UUID       `json:"resolved_by,omitempty"`
+	ResolvedAt      *string          `json:"resolved_at,omitempty"`
+	ResolutionNotes *string          `json:"resolution_notes,omitempty"`
+	Members         []MemberResponse `json:"members"`
+	CreatedAt       string           `json:"created_at"`
+}
+
+// MemberResponse represents a question in a duplicate cluster
+type MemberResponse struct {
+	QuestionID   uuid.UUID `json:"question_id"`
+	Similarity   float64   `json:"similarity"`
+	Text         string    `json:"text"`
+	Options      []string  `json:"options"`
+	BankName     *string   `json:"bank_name,omitempty"`
+	IsActive     bool      `json:"is_active"`
+	AttemptCount *int      `json:"attempt_count,omitempty"`
+}
+
+// ScanJobResponse represents a queued duplicate scan
+type ScanJobResponse struct {
+	JobID          string `json:"job_id"`
+	Status         string `json:"status"`
+	CheckStatusURL string `json:"check_status_url"`
+}
+
+// === Converters ===
+
+// ToResponse converts Cluster to ClusterResponse with its members
+func (c *Cluster) ToResponse(members []Member) ClusterResponse {
+	resp := ClusterResponse{
+		ID:              c.ID,
+		Section:         c.Section,
+		Status:          c.Status,
+		MaxSimilarity:   c.MaxSimilarity,
+		SuggestedKeepID: c.SuggestedKeepID,
+		KeptQuestionID:  c.KeptQuestionID,
+		ResolvedBy:      c.ResolvedBy,
+		ResolutionNotes: c.ResolutionNotes,
+		Members:         make([]MemberResponse, len(members)),
+		CreatedAt:       c.CreatedAt.Format(time.RFC3339),
+	}
+	if c.ResolvedAt != nil {
+		resolvedAt := c.ResolvedAt.Format(time.RFC3339)
+		resp.ResolvedAt = &resolvedAt
+	}
+	for i, m := range members {
+		resp.Members[i] = MemberResponse{
+			QuestionID:   m.QuestionID,
+			Similarity:   m.Similarity,
+			Text:         m.Text,
+			Options:      m.Options,
+			BankName:     m.BankName,
+			IsActive:     m.IsActive,
+			AttemptCount: m.AttemptCount,
+		}
+	}
+	return resp
+}
+
+// NewScanJobResponse builds the response for a queued duplicate scan
+func NewScanJobResponse(jobID string) ScanJobResponse {
+	return ScanJobResponse{
+		JobID:          jobID,
+		Status:         "queued",
+		CheckStatusURL: "/api/v1/jobs/" + jobID + "/check",
+	}
+}
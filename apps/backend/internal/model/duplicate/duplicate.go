@@ -0,0 +1,66 @@
+package duplicate
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/model"
+)
+
+// DefaultThreshold is the trigram similarity of question fingerprints (text plus options,
+// lowercased and accent-folded) from which two questions are treated as likely duplicates
+const DefaultThreshold = 0.8
+
+// Status is the review state of a duplicate cluster
+type Status string
+
+const (
+	StatusPending     Status = "pending"
+	StatusMerged      Status = "merged"      // One question kept, the others removed
+	StatusDeactivated Status = "deactivated" // Chosen questions taken out of practice
+	StatusDismissed   Status = "dismissed"   // Not duplicates; the scan skips these pairs
+)
+
+// Action is an editor's decision on a pending cluster
+type Action string
+
+const (
+	ActionMerge      Action = "merge"
+	ActionDeactivate Action = "deactivate"
+	ActionDismiss    Action = "dismiss"
+)
+
+// Cluster is a group of likely duplicate questions in one section (from question_duplicate_clusters)
+type Cluster struct {
+	ID              uuid.UUID     `json:"id" db:"id"`
+	Section         model.Section `json:"section" db:"section"`
+	Status          Status        `json:"status" db:"status"`
+	MaxSimilarity   float64       `json:"maxSimilarity" db:"max_similarity"`
+	SuggestedKeepID uuid.UUID     `json:"suggestedKeepId" db:"suggested_keep_id"`
+	KeptQuestionID  *uuid.UUID    `json:"keptQuestionId" db:"kept_question_id"`
+	ResolvedBy      *uuid.UUID    `json:"resolvedBy" db:"resolved_by"`
+	ResolvedAt      *time.Time    `json:"resolvedAt" db:"resolved_at"`
+	ResolutionNotes *string       `json:"resolutionNotes" db:"resolution_notes"`
+
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+}
+
+// Member is a question in a cluster with what an editor needs to compare it
+type Member struct {
+	ClusterID    uuid.UUID `json:"clusterId" db:"cluster_id"`
+	QuestionID   uuid.UUID `json:"questionId" db:"question_id"`
+	Similarity   float64   `json:"similarity" db:"similarity"`
+	Text         string    `json:"text" db:"text"`
+	Options      []string  `json:"options" db:"options"`
+	BankName     *string   `json:"bankName" db:"bank_name"`
+	IsActive     bool      `json:"isActive" db:"is_active"`
+	AttemptCount *int      `json:"attemptCount" db:"attempt_count"`
+}
+
+// Match is an existing question similar to content about to be inserted
+type Match struct {
+	QuestionID uuid.UUID `json:"questionId" db:"question_id"`
+	Text       string    `json:"text" db:"text"`
+	Similarity float64   `json:"similarity" db:"similarity"`
+}
@@ -0,0 +1,112 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type names a domain event
+type Type string
+
+const (
+	TypeAttemptRecorded       Type = "attempt_recorded"
+	TypeSessionEnded          Type = "session_ended"
+	TypeOnboardingCompleted   Type = "onboarding_completed"
+	TypeSubscriptionActivated Type = "subscription_activated"
+)
+
+// Event is a domain event as stored in the outbox and delivered to subscribers
+type Event struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	Type        Type            `json:"type" db:"type"`
+	AggregateID string          `json:"aggregate_id" db:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at" db:"occurred_at"`
+}
+
+// Payload is the typed body of a domain event
+type Payload interface {
+	EventType() Type
+	// AggregateID identifies the entity whose state change raised the event
+	AggregateID() string
+}
+
+// New wraps a payload in an event with a fresh ID
+func New(p Payload) (Event, error) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to marshal %s event: %w", p.EventType(), err)
+	}
+
+	return Event{
+		ID:          uuid.New(),
+		Type:        p.EventType(),
+		AggregateID: p.AggregateID(),
+		Payload:     body,
+		OccurredAt:  time.Now().UTC(),
+	}, nil
+}
+
+// Decode unmarshals the event payload into p
+func (e *Event) Decode(p Payload) error {
+	if err := json.Unmarshal(e.Payload, p); err != nil {
+		return fmt.Errorf("failed to unmarshal %s event: %w", e.Type, err)
+	}
+	return nil
+}
+
+// AttemptRecorded is raised when a student answers a question
+type AttemptRecorded struct {
+	AttemptID        uuid.UUID `json:"attempt_id"`
+	UserID           uuid.UUID `json:"user_id"`
+	QuestionID       uuid.UUID `json:"question_id"`
+	SessionID        *string   `json:"session_id,omitempty"`
+	Section          string    `json:"section"`
+	IsCorrect        bool      `json:"is_correct"`
+	TimeSpentSeconds int16     `json:"time_spent_seconds"`
+
+	// GenerateFeedback is set when feedback was neither served from cache nor skipped for quota
+	GenerateFeedback bool   `json:"generate_feedback"`
+	FeedbackLanguage string `json:"feedback_language,omitempty"`
+	Personalized     bool   `json:"personalized,omitempty"`
+}
+
+func (AttemptRecorded) EventType() Type       { return TypeAttemptRecorded }
+func (p AttemptRecorded) AggregateID() string { return p.AttemptID.String() }
+
+// SessionEnded is raised when a study session is ended
+type SessionEnded struct {
+	SessionID          string    `json:"session_id"`
+	UserID             uuid.UUID `json:"user_id"`
+	Section            *string   `json:"section,omitempty"`
+	DurationMinutes    *int16    `json:"duration_minutes,omitempty"`
+	QuestionsAttempted int       `json:"questions_attempted"`
+	QuestionsCorrect   int       `json:"questions_correct"`
+}
+
+func (SessionEnded) EventType() Type       { return TypeSessionEnded }
+func (p SessionEnded) AggregateID() string { return p.SessionID }
+
+// OnboardingCompleted is raised when a user finishes onboarding
+type OnboardingCompleted struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Email    string    `json:"email"`
+	FullName *string   `json:"full_name,omitempty"`
+}
+
+func (OnboardingCompleted) EventType() Type       { return TypeOnboardingCompleted }
+func (p OnboardingCompleted) AggregateID() string { return p.UserID.String() }
+
+// SubscriptionActivated is raised when a paid subscription starts
+type SubscriptionActivated struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	Tier      string     `json:"tier"`
+	StartDate time.Time  `json:"start_date"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
+}
+
+func (SubscriptionActivated) EventType() Type       { return TypeSubscriptionActivated }
+func (p SubscriptionActivated) AggregateID() string { return p.UserID.String() }
@@ -0,0 +1,114 @@
+package leaderboard
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// pseudonymPattern allows letters, digits, spaces, dots, dashes and underscores
+var pseudonymPattern = regexp.MustCompile(`^[\p{L}\p{N} ._-]+$`)
+
+// === Request DTOs ===
+
+// GetLeaderboardRequest represents path/query params for a section leaderboard page.
+// Without an offset the page is centered on the caller's own rank.
+type GetLeaderboardRequest struct {
+	Section string `param:"section" validate:"required,oneof=PU PPU PBM PK LBI LBE PM"`
+	Season  string `query:"season" validate:"omitempty,oneof=weekly all_time"`
+	Week    string `query:"week" validate:"omitempty,max=8"`
+	Offset  *int   `query:"offset" validate:"omitempty,min=0"`
+	Limit   int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+func (r *GetLeaderboardRequest) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+	if r.Week != "" {
+		if r.Season == SeasonAllTime {
+			return errors.New("week cannot be combined with the all_time season")
+		}
+		if _, err := WeekStart(r.Week); err != nil {
+			return errors.New("week must be an ISO week like 2026-W42")
+		}
+	}
+	return nil
+}
+
+// SetDefaults fills optional params
+func (r *GetLeaderboardRequest) SetDefaults() {
+	if r.Season == "" {
+		r.Season = SeasonWeekly
+	}
+	if r.Limit == 0 {
+		r.Limit = DefaultLimit
+	}
+}
+
+// GetLeaderboardSettingsRequest represents the (empty) request for the caller's settings
+type GetLeaderboardSettingsRequest struct{}
+
+func (r *GetLeaderboardSettingsRequest) Validate() error {
+	return nil
+}
+
+// UpdateLeaderboardSettingsRequest represents the body for changing leaderboard privacy.
+// An empty pseudonym clears it.
+type UpdateLeaderboardSettingsRequest struct {
+	OptOut    *bool   `json:"opt_out,omitempty"`
+	Pseudonym *string `json:"pseudonym,omitempty" validate:"omitempty,max=30"`
+}
+
+func (r *UpdateLeaderboardSettingsRequest) Validate() error {
+	validate := validator.New()
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+	if r.Pseudonym != nil && *r.Pseudonym != "" {
+		if len([]rune(*r.Pseudonym)) < 3 || !pseudonymPattern.MatchString(*r.Pseudonym) {
+			return errors.New("pseudonym must be 3-30 letters, digits, spaces, dots, dashes or underscores")
+		}
+	}
+	return nil
+}
+
+// === Response DTOs ===
+
+// LeaderboardResponse represents one page of a section leaderboard
+type LeaderboardResponse struct {
+	Section   string                     `json:"section"`
+	Season    string                     `json:"season"`
+	SeasonKey string                     `json:"season_key"`
+	Total     int64                      `json:"total"`
+	Offset    int                        `json:"offset"`
+	Limit     int                        `json:"limit"`
+	HasMore   bool                       `json:"has_more"`
+	Entries   []LeaderboardEntryResponse `json:"entries"`
+	Me        *LeaderboardEntryResponse  `json:"me,omitempty"`
+	OptedOut  bool                       `json:"opted_out"`
+}
+
+// LeaderboardEntryResponse represents one ranked user
+type LeaderboardEntryResponse struct {
+	Rank        int64   `json:"rank"`
+	DisplayName string  `json:"display_name"`
+	Score       float64 `json:"score"`
+	ThetaGain   float64 `json:"theta_gain"`
+	Attempts    int     `json:"attempts"`
+	IsMe        bool    `json:"is_me"`
+}
+
+// LeaderboardSettingsResponse represents the caller's leaderboard privacy settings
+type LeaderboardSettingsResponse struct {
+	OptOut      bool    `json:"opt_out"`
+	Pseudonym   *string `json:"pseudonym,omitempty"`
+	DisplayName string  `json:"display_name"`
+}
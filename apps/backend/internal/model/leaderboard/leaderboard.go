@@ -0,0 +1,97 @@
+package leaderboard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SeasonWeekly  = "weekly"
+	SeasonAllTime = "all_time"
+
+	// AllTimeKey is the season key of the all-time leaderboard
+	AllTimeKey = "all_time"
+
+	// ThetaGainPoints is the score for each unit of theta gained over the season
+	ThetaGainPoints = 100.0
+	// AttemptPoints is the score for each question answered over the season
+	AttemptPoints = 1.0
+)
+
+// seasonZone is where weekly seasons turn over: Monday 00:00 WIB
+var seasonZone = time.FixedZone("WIB", 7*60*60)
+
+// Entry is a user's standing on one section leaderboard for one season
+type Entry struct {
+	UserID     uuid.UUID `db:"user_id"`
+	Section    string    `db:"section"`
+	Season     string    `db:"season"`
+	StartTheta float64   `db:"start_theta"`
+	Theta      float64   `db:"theta"`
+	Attempts   int       `db:"attempts"`
+	Score      float64   `db:"score"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// RankedEntry is an entry with the owner's chosen pseudonym, for display
+type RankedEntry struct {
+	Entry
+	Pseudonym *string `db:"pseudonym"`
+}
+
+// Settings holds a user's leaderboard privacy choices
+type Settings struct {
+	UserID    uuid.UUID `db:"user_id"`
+	OptOut    bool      `db:"opt_out"`
+	Pseudonym *string   `db:"pseudonym"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// ThetaGain is the theta gained since the season started
+func (e *Entry) ThetaGain() float64 {
+	return e.Theta - e.StartTheta
+}
+
+// Score rewards theta gained over the season plus answer volume; losses are not penalized
+// so a hard week of practice never scores below doing nothing
+func Score(thetaGain float64, attempts int) float64 {
+	return math.Max(thetaGain, 0)*ThetaGainPoints + float64(attempts)*AttemptPoints
+}
+
+// WeekKey returns the ISO week season key (e.g. 2026-W42) containing t
+func WeekKey(t time.Time) string {
+	year, week := t.In(seasonZone).ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// WeekStart returns the instant a weekly season key starts
+func WeekStart(key string) (time.Time, error) {
+	var year, week int
+	if _, err := fmt.Sscanf(key, "%d-W%d", &year, &week); err != nil || week < 1 || week > 53 {
+		return time.Time{}, fmt.Errorf("invalid week %q", key)
+	}
+
+	// January 4th is always in ISO week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, seasonZone)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	start := jan4.AddDate(0, 0, (week-1)*7-offset)
+	if WeekKey(start) != fmt.Sprintf("%d-W%02d", year, week) {
+		return time.Time{}, fmt.Errorf("invalid week %q", key)
+	}
+	return start, nil
+}
+
+// DisplayName returns the user's pseudonym, or a stable anonymous handle so real names
+// are never shown on leaderboards
+func DisplayName(userID uuid.UUID, pseudonym *string) string {
+	if pseudonym != nil && *pseudonym != "" {
+		return *pseudonym
+	}
+	sum := sha256.Sum256(userID[:])
+	return "Pejuang-" + hex.EncodeToString(sum[:3])
+}
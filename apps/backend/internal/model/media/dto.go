@@ -0,0 +1,132 @@
+package media
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/lib/content"
+	"github.com/manikandareas/genta/internal/lib/storage"
+)
+
+// === Request DTOs ===
+
+// UploadMediaRequest represents a multipart image upload through the API. The file itself
+// is read from the "file" form field.
+type UploadMediaRequest struct{}
+
+func (r *UploadMediaRequest) Validate() error {
+	return nil
+}
+
+// CreateUploadRequest asks for a presigned upload of an image straight to storage
+type CreateUploadRequest struct {
+	Filename    *string `json:"filename" validate:"omitempty,max=255"`
+	ContentType string  `json:"content_type" validate:"required,max=100"`
+	SizeBytes   int64   `json:"size_bytes" validate:"required,min=1"`
+}
+
+func (r *CreateUploadRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// CompleteUploadRequest records an image uploaded with a presigned upload
+type CompleteUploadRequest struct {
+	ID       string  `param:"id" validate:"required,uuid"`
+	Key      string  `json:"key" validate:"required,max=500"`
+	Filename *string `json:"filename" validate:"omitempty,max=255"`
+}
+
+func (r *CompleteUploadRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// GetMediaRequest represents params for serving a media asset
+type GetMediaRequest struct {
+	ID      string  `param:"id" validate:"required,uuid"`
+	Variant Variant `query:"variant" validate:"omitempty,oneof=original thumbnail"`
+}
+
+func (r *GetMediaRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// SignedDownloadRequest is a download by presigned URL from the filesystem or memory
+// storage backend
+type SignedDownloadRequest struct {
+	Key       string `param:"*" validate:"required,max=500"`
+	Expires   int64  `query:"expires" validate:"required"`
+	Signature string `query:"signature" validate:"required,hexadecimal"`
+}
+
+func (r *SignedDownloadRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// SignedUploadRequest is the form of a presigned upload to the filesystem or memory storage
+// backend. The file itself is read from the "file" form field.
+type SignedUploadRequest struct {
+	Key         string `form:"key" validate:"required,max=500"`
+	ContentType string `form:"content_type" validate:"required,max=100"`
+	MaxBytes    int64  `form:"max_bytes" validate:"required,min=1"`
+	Expires     int64  `form:"expires" validate:"required"`
+	Signature   string `form:"signature" validate:"required,hexadecimal"`
+}
+
+func (r *SignedUploadRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// === Response DTOs ===
+
+// AssetResponse describes an uploaded asset and how to reference it from content
+type AssetResponse struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL *string   `json:"thumbnail_url,omitempty"`
+	Reference    string    `json:"reference"`
+	Markdown     string    `json:"markdown"`
+	ContentType  string    `json:"content_type"`
+	SizeBytes    int64     `json:"size_bytes"`
+	Width        *int      `json:"width,omitempty"`
+	Height       *int      `json:"height,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// UploadResponse is a presigned upload; after uploading, the client completes it with
+// the upload ID and key
+type UploadResponse struct {
+	ID     uuid.UUID               `json:"id"`
+	Key    string                  `json:"key"`
+	Upload storage.PresignedUpload `json:"upload"`
+}
+
+// === Converters ===
+
+// ToResponse converts Asset to AssetResponse
+func (a *Asset) ToResponse() AssetResponse {
+	reference := content.MediaScheme + a.ID.String()
+
+	response := AssetResponse{
+		ID:          a.ID,
+		URL:         content.MediaURL(a.ID.String()),
+		Reference:   reference,
+		Markdown:    "![](" + reference + ")",
+		ContentType: a.ContentType,
+		SizeBytes:   a.SizeBytes,
+		Width:       a.Width,
+		Height:      a.Height,
+		CreatedAt:   a.CreatedAt,
+	}
+	if a.ThumbnailKey != nil {
+		thumbnail := response.URL + "?variant=" + string(VariantThumbnail)
+		response.ThumbnailURL = &thumbnail
+	}
+
+	return response
+}
@@ -0,0 +1,52 @@
+package media
+
+import (
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/lib/storage"
+	"github.com/manikandareas/genta/internal/model"
+)
+
+// UploadPolicy limits the type and size of uploaded images
+var UploadPolicy = storage.ImagePolicy
+
+// Extensions maps the image types UploadPolicy admits to their file extensions
+var Extensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type Variant string
+
+const (
+	VariantOriginal  Variant = "original"
+	VariantThumbnail Variant = "thumbnail"
+)
+
+// Asset is an uploaded media file (from media_assets table); the file itself lives in
+// object storage under StorageKey
+type Asset struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	StorageKey       string     `json:"storageKey" db:"storage_key"`
+	ThumbnailKey     *string    `json:"thumbnailKey" db:"thumbnail_key"`
+	ContentType      string     `json:"contentType" db:"content_type"`
+	SizeBytes        int64      `json:"sizeBytes" db:"size_bytes"`
+	Width            *int       `json:"width" db:"width"`
+	Height           *int       `json:"height" db:"height"`
+	Checksum         string     `json:"checksum" db:"checksum"`
+	OriginalFilename *string    `json:"originalFilename" db:"original_filename"`
+	UploadedBy       *uuid.UUID `json:"uploadedBy" db:"uploaded_by"`
+	model.BaseWithCreatedAt
+}
+
+// Download is an asset file to serve: a body to stream, or a presigned URL to redirect to
+type Download struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+	ETag        string
+	RedirectURL string
+}
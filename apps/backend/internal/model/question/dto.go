@@ -3,7 +3,9 @@ package question
 import (
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/lib/content"
 	"github.com/manikandareas/genta/internal/model"
+	"github.com/manikandareas/genta/internal/model/stimulus"
 )
 
 // === Request DTOs ===
@@ -25,13 +27,21 @@ type ListQuestionsRequest struct {
 	DifficultyMin *float64 `query:"difficulty_min" validate:"omitempty"`
 	DifficultyMax *float64 `query:"difficulty_max" validate:"omitempty"`
 	IsReviewed    *bool    `query:"is_reviewed" validate:"omitempty"`
-	Page          int      `query:"page" validate:"min=1"`
-	Limit         int      `query:"limit" validate:"min=1,max=100"`
+	// Q filters by full-text or fuzzy match on the question content
+	Q *string `query:"q" validate:"omitempty,min=2,max=200"`
+	// Page selects offset pagination, the default. Sending a cursor, or pagination=cursor
+	// for the first page, pages the list by cursor instead.
+	Page       int    `query:"page" validate:"omitempty,min=1"`
+	Limit      int    `query:"limit" validate:"min=1,max=100"`
+	Cursor     string `query:"cursor" validate:"omitempty,max=500,excluded_with=Page"`
+	Pagination string `query:"pagination" validate:"omitempty,oneof=offset cursor"`
+	// IncludeTotal counts the whole list, which costs an extra query
+	IncludeTotal bool `query:"include_total"`
 }
 
 func (r *ListQuestionsRequest) Validate() error {
 	// Set defaults
-	if r.Page == 0 {
+	if r.Page == 0 && r.Cursor == "" && r.Pagination != "cursor" {
 		r.Page = 1
 	}
 	if r.Limit == 0 {
@@ -42,6 +52,24 @@ func (r *ListQuestionsRequest) Validate() error {
 	return validate.Struct(r)
 }
 
+// SearchQuestionsRequest represents query params for searching questions by content
+type SearchQuestionsRequest struct {
+	Q       string  `query:"q" validate:"required,min=2,max=200"`
+	Section *string `query:"section" validate:"omitempty,oneof=PU PPU PBM PK LBI LBE PM"`
+	SubType *string `query:"sub_type" validate:"omitempty"`
+	Limit   int     `query:"limit" validate:"min=1,max=50"`
+}
+
+func (r *SearchQuestionsRequest) Validate() error {
+	// Set defaults
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
 // GetNextQuestionRequest represents query params for getting next question
 type GetNextQuestionRequest struct {
 	Section string `query:"section" validate:"required,oneof=PU PPU PBM PK LBI LBE PM"`
@@ -52,43 +80,112 @@ func (r *GetNextQuestionRequest) Validate() error {
 	return validate.Struct(r)
 }
 
+// UpdateQuestionContentRequest replaces a question's text and options with Markdown +
+// LaTeX source. A null stimulus_id detaches the question from its stimulus; omitted
+// explanations are left unchanged.
+type UpdateQuestionContentRequest struct {
+	ID            string   `param:"id" validate:"required,uuid"`
+	Text          string   `json:"text" validate:"required"`
+	Options       []string `json:"options" validate:"required,len=5,dive,required"`
+	StimulusID    *string  `json:"stimulus_id" validate:"omitempty,uuid"`
+	Explanation   *string  `json:"explanation" validate:"omitempty"`
+	ExplanationEn *string  `json:"explanation_en" validate:"omitempty"`
+}
+
+func (r *UpdateQuestionContentRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// PreviewContentRequest is content source to check and render without saving it
+type PreviewContentRequest struct {
+	Content string `json:"content" validate:"required"`
+}
+
+func (r *PreviewContentRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
 // === Response DTOs ===
 
+// ContentResponse is the question text and options in raw and structured form
+type ContentResponse struct {
+	Text    content.Rich   `json:"text"`
+	Options []content.Rich `json:"options"`
+}
+
+// PreviewContentResponse is sanitized content as it would be saved, with the problems
+// that would make saving it fail
+type PreviewContentResponse struct {
+	content.Rich
+	Problems []string `json:"problems"`
+}
+
 // QuestionResponse represents the API response for a question (without correct answer for practice)
 type QuestionResponse struct {
-	ID             uuid.UUID     `json:"id"`
-	Section        model.Section `json:"section"`
-	SubType        *string       `json:"sub_type"`
-	Text           string        `json:"text"`
-	Options        []string      `json:"options"`
-	DifficultyIRT  *float64      `json:"difficulty_irt,omitempty"`
-	Discrimination *float64      `json:"discrimination,omitempty"`
-	AttemptCount   *int          `json:"attempt_count,omitempty"`
-	CorrectRate    *float64      `json:"correct_rate,omitempty"`
-	AvgTimeSeconds *int16        `json:"avg_time_seconds,omitempty"`
+	ID      uuid.UUID     `json:"id"`
+	Section model.Section `json:"section"`
+	SubType *string       `json:"sub_type"`
+	Text    string        `json:"text"`
+	Options []string      `json:"options"`
+	// Content renders Text and Options for clients that display math, images and tables
+	Content        ContentResponse            `json:"content"`
+	StimulusID     *uuid.UUID                 `json:"stimulus_id,omitempty"`
+	Stimulus       *stimulus.StimulusResponse `json:"stimulus,omitempty"`
+	DifficultyIRT  *float64                   `json:"difficulty_irt,omitempty"`
+	Discrimination *float64                   `json:"discrimination,omitempty"`
+	AttemptCount   *int                       `json:"attempt_count,omitempty"`
+	CorrectRate    *float64                   `json:"correct_rate,omitempty"`
+	AvgTimeSeconds *int16                     `json:"avg_time_seconds,omitempty"`
 }
 
 // QuestionDetailResponse includes correct answer and explanation (after answering)
 type QuestionDetailResponse struct {
 	QuestionResponse
-	CorrectAnswer  string          `json:"correct_answer"`
-	Explanation    *string         `json:"explanation"`
-	ExplanationEn  *string         `json:"explanation_en,omitempty"`
-	StrategyTip    *string         `json:"strategy_tip,omitempty"`
-	SolutionSteps  *[]SolutionStep `json:"solution_steps,omitempty"`
-	RelatedConcept *string         `json:"related_concept,omitempty"`
+	CorrectAnswer string  `json:"correct_answer"`
+	Explanation   *string `json:"explanation"`
+	ExplanationEn *string `json:"explanation_en,omitempty"`
+	// Structured forms of the explanations
+	ExplanationContent   *content.Rich   `json:"explanation_content,omitempty"`
+	ExplanationEnContent *content.Rich   `json:"explanation_en_content,omitempty"`
+	StrategyTip          *string         `json:"strategy_tip,omitempty"`
+	SolutionSteps        *[]SolutionStep `json:"solution_steps,omitempty"`
+	RelatedConcept       *string         `json:"related_concept,omitempty"`
+}
+
+// SearchResultResponse is a question search hit. Snippet is escaped HTML that marks matched words with <mark>.
+type SearchResultResponse struct {
+	QuestionResponse
+	Snippet string  `json:"snippet"`
+	Score   float64 `json:"score"`
+}
+
+// SearchQuestionsResponse lists search hits, most relevant first
+type SearchQuestionsResponse struct {
+	Query   string                 `json:"query"`
+	Results []SearchResultResponse `json:"results"`
 }
 
 // === Converters ===
 
 // ToResponse converts Question to QuestionResponse (hides correct answer)
 func (q *Question) ToResponse() QuestionResponse {
+	options := []string{q.OptionA, q.OptionB, q.OptionC, q.OptionD, q.OptionE}
+
+	rendered := make([]content.Rich, len(options))
+	for i, o := range options {
+		rendered[i] = content.Render(o)
+	}
+
 	return QuestionResponse{
 		ID:             q.ID,
 		Section:        q.Section,
 		SubType:        q.SubType,
 		Text:           q.Text,
-		Options:        []string{q.OptionA, q.OptionB, q.OptionC, q.OptionD, q.OptionE},
+		Options:        options,
+		Content:        ContentResponse{Text: content.Render(q.Text), Options: rendered},
+		StimulusID:     q.StimulusID,
 		DifficultyIRT:  q.DifficultyIRT,
 		Discrimination: q.Discrimination,
 		AttemptCount:   q.AttemptCount,
@@ -100,12 +197,23 @@ func (q *Question) ToResponse() QuestionResponse {
 // ToDetailResponse converts Question to QuestionDetailResponse (includes answer)
 func (q *Question) ToDetailResponse() QuestionDetailResponse {
 	return QuestionDetailResponse{
-		QuestionResponse: q.ToResponse(),
-		CorrectAnswer:    q.CorrectAnswer,
-		Explanation:      q.Explanation,
-		ExplanationEn:    q.ExplanationEn,
-		StrategyTip:      q.StrategyTip,
-		SolutionSteps:    q.SolutionSteps,
-		RelatedConcept:   q.RelatedConcept,
+		QuestionResponse:     q.ToResponse(),
+		CorrectAnswer:        q.CorrectAnswer,
+		Explanation:          q.Explanation,
+		ExplanationEn:        q.ExplanationEn,
+		ExplanationContent:   content.RenderPtr(q.Explanation),
+		ExplanationEnContent: content.RenderPtr(q.ExplanationEn),
+		StrategyTip:          q.StrategyTip,
+		SolutionSteps:        q.SolutionSteps,
+		RelatedConcept:       q.RelatedConcept,
+	}
+}
+
+// ToResponse converts SearchResult to SearchResultResponse
+func (r *SearchResult) ToResponse() SearchResultResponse {
+	return SearchResultResponse{
+		QuestionResponse: r.Question.ToResponse(),
+		Snippet:          r.Snippet,
+		Score:            r.Rank,
 	}
 }
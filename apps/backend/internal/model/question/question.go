@@ -15,12 +15,15 @@ type Question struct {
 	Section model.Section `json:"section" db:"section"`
 	SubType *string       `json:"subType" db:"sub_type"`
 
+	// Shared passage or data the question is about
+	StimulusID *uuid.UUID `json:"stimulusId" db:"stimulus_id"`
+
 	// IRT Parameters
 	DifficultyIRT  *float64 `json:"difficultyIrt" db:"difficulty_irt"`
 	Discrimination *float64 `json:"discrimination" db:"discrimination"`
 	GuessingParam  *float64 `json:"guessingParam" db:"guessing_param"`
 
-	// Question Content
+	// Question Content (Markdown + LaTeX source, see lib/content)
 	Text          string `json:"text" db:"text"`
 	OptionA       string `json:"optionA" db:"option_a"`
 	OptionB       string `json:"optionB" db:"option_b"`
@@ -57,3 +60,11 @@ type SolutionStep struct {
 	Title   string `json:"title"`
 	Content string `json:"content"`
 }
+
+// SearchResult is a question matched by a text search, with its relevance and a highlighted
+// excerpt of the text and options
+type SearchResult struct {
+	Question
+	Snippet string  `json:"snippet" db:"snippet"`
+	Rank    float64 `json:"rank" db:"rank"`
+}
@@ -1,8 +1,11 @@
 package readiness
 
 import (
+	"math"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/lib/scoring"
 )
 
 // === Request DTOs ===
@@ -20,6 +23,7 @@ func (r *GetUserReadinessRequest) Validate() error {
 // GetSectionReadinessRequest represents path params for getting section-specific readiness
 type GetSectionReadinessRequest struct {
 	Section string `param:"section" validate:"required,oneof=PU PPU PBM PK LBI LBE PM"`
+	Lang    string `query:"lang" validate:"omitempty,oneof=id en"`
 }
 
 func (r *GetSectionReadinessRequest) Validate() error {
@@ -27,6 +31,25 @@ func (r *GetSectionReadinessRequest) Validate() error {
 	return validate.Struct(r)
 }
 
+// GetReadinessHistoryRequest represents query params for readiness time series
+type GetReadinessHistoryRequest struct {
+	Section     string `query:"section" validate:"omitempty,oneof=PU PPU PBM PK LBI LBE PM"`
+	Granularity string `query:"granularity" validate:"omitempty,oneof=day week"`
+	Days        int    `query:"days" validate:"omitempty,min=1,max=365"`
+}
+
+func (r *GetReadinessHistoryRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// GetReadinessPercentilesRequest represents the (empty) request for cohort percentile ranks
+type GetReadinessPercentilesRequest struct{}
+
+func (r *GetReadinessPercentilesRequest) Validate() error {
+	return nil
+}
+
 // CreateUserReadinessRequest represents the request body for creating readiness record
 type CreateUserReadinessRequest struct {
 	UserID              uuid.UUID `json:"userId" validate:"required"`
@@ -77,9 +100,12 @@ type ReadinessResponse struct {
 	CurrentTheta        float64 `json:"current_theta"`
 	TargetTheta         float64 `json:"target_theta"`
 
-	// Predictions
+	// Predictions (UTBK scaled score with 90% interval)
+	PredictedScore     int     `json:"predicted_score"`
 	PredictedScoreLow  int     `json:"predicted_score_low"`
 	PredictedScoreHigh int     `json:"predicted_score_high"`
+	ThetaSE            float64 `json:"theta_se"`
+	PredictionVersion  string  `json:"prediction_version"`
 	DaysToReady        *int    `json:"days_to_ready,omitempty"`
 	ReadyByDate        *string `json:"ready_by_date,omitempty"`
 
@@ -105,6 +131,19 @@ type ReadinessOverviewResponse struct {
 	WeakestSection      *string                      `json:"weakest_section,omitempty"`
 	StrongestSection    *string                      `json:"strongest_section,omitempty"`
 	RecommendedPractice *string                      `json:"recommended_practice,omitempty"`
+	PredictedComposite  *CompositePredictionResponse `json:"predicted_composite,omitempty"`
+}
+
+// CompositePredictionResponse represents the predicted overall UTBK score against the user's target
+type CompositePredictionResponse struct {
+	Score             int      `json:"score"`
+	Low               int      `json:"low"`
+	High              int      `json:"high"`
+	PredictionVersion string   `json:"prediction_version"`
+	TargetScore       *int     `json:"target_score,omitempty"`
+	GapToTarget       *int     `json:"gap_to_target,omitempty"`
+	MeetsTarget       *bool    `json:"meets_target,omitempty"`
+	TargetProbability *float64 `json:"target_probability,omitempty"`
 }
 
 // SectionDetailResponse represents detailed readiness for a specific section
@@ -143,18 +182,69 @@ type NextStepsResponse struct {
 	Message                 string  `json:"message"`
 	EstimatedCompletionDate *string `json:"estimated_completion_date,omitempty"`
 	SuggestedDailyPractice  *int    `json:"suggested_daily_practice,omitempty"`
+	ExamDate                *string `json:"exam_date,omitempty"`
+	DaysUntilExam           *int    `json:"days_until_exam,omitempty"`
+	OnTrack                 *bool   `json:"on_track,omitempty"`
+}
+
+// ReadinessHistoryResponse represents theta and readiness time series per section
+type ReadinessHistoryResponse struct {
+	Granularity string                   `json:"granularity"`
+	From        string                   `json:"from"`
+	To          string                   `json:"to"`
+	Series      []SectionHistoryResponse `json:"series"`
+}
+
+// SectionHistoryResponse represents one section's time series
+type SectionHistoryResponse struct {
+	Section string                 `json:"section"`
+	Points  []HistoryPointResponse `json:"points"`
+}
+
+// HistoryPointResponse represents a section's closing values for one period
+type HistoryPointResponse struct {
+	PeriodStart         string   `json:"period_start"`
+	Theta               *float64 `json:"theta"`
+	ThetaSE             *float64 `json:"theta_se"`
+	ReadinessPercentage *float64 `json:"readiness_percentage"`
+	PredictedScore      *int     `json:"predicted_score"`
+	TotalAttempts       int      `json:"total_attempts"`
+}
+
+// ReadinessPercentilesResponse represents the user's percentile ranks per cohort
+type ReadinessPercentilesResponse struct {
+	MinCohortSize int                        `json:"min_cohort_size"`
+	ComputedAt    *string                    `json:"computed_at,omitempty"`
+	Cohorts       []CohortPercentileResponse `json:"cohorts"`
+}
+
+// CohortPercentileResponse represents the user's ranks within one cohort. A cohort is
+// unavailable when the user has no key for it (no target PTN or exam date) or it is
+// smaller than the minimum size.
+type CohortPercentileResponse struct {
+	Cohort     string               `json:"cohort"`
+	Available  bool                 `json:"available"`
+	CohortSize int                  `json:"cohort_size,omitempty"`
+	Overall    *PercentileResponse  `json:"overall,omitempty"`
+	Sections   []PercentileResponse `json:"sections"`
+}
+
+// PercentileResponse represents the share of the cohort ranked below the user
+type PercentileResponse struct {
+	Section    string  `json:"section"`
+	Percentile float64 `json:"percentile"`
+	CohortSize int     `json:"cohort_size"`
 }
 
 // === Converters ===
 
-// ToResponse converts UserReadiness to ReadinessResponse
+// ToResponse converts UserReadiness to ReadinessResponse.
+// Predictions are left empty; callers fill them with ApplyPrediction.
 func (r *UserReadiness) ToResponse() ReadinessResponse {
 	resp := ReadinessResponse{
-		Section:            r.Section,
-		TotalAttempts:      r.TotalAttemptsCount,
-		TotalCorrect:       r.TotalCorrectCount,
-		PredictedScoreLow:  500, // default
-		PredictedScoreHigh: 550, // default
+		Section:       r.Section,
+		TotalAttempts: r.TotalAttemptsCount,
+		TotalCorrect:  r.TotalCorrectCount,
 	}
 
 	// Handle nullable fields
@@ -173,13 +263,7 @@ func (r *UserReadiness) ToResponse() ReadinessResponse {
 	if r.TargetTheta != nil {
 		resp.TargetTheta = *r.TargetTheta
 	} else {
-		resp.TargetTheta = 0.5 // default target
-	}
-	if r.PredictedScoreLow != nil {
-		resp.PredictedScoreLow = *r.PredictedScoreLow
-	}
-	if r.PredictedScoreHigh != nil {
-		resp.PredictedScoreHigh = *r.PredictedScoreHigh
+		resp.TargetTheta = DefaultTargetTheta
 	}
 	if r.DaysToReady != nil {
 		resp.DaysToReady = r.DaysToReady
@@ -195,6 +279,25 @@ func (r *UserReadiness) ToResponse() ReadinessResponse {
 	return resp
 }
 
+// Prediction returns the section's predicted score under the given calibration,
+// falling back to the ability prior when theta has not been estimated yet
+func (r *UserReadiness) Prediction(cal scoring.Calibration) scoring.Prediction {
+	theta, se := 0.0, scoring.PriorSD
+	if r.CurrentTheta != nil && r.ThetaSE != nil {
+		theta, se = *r.CurrentTheta, *r.ThetaSE
+	}
+	return scoring.Predict(cal, theta, se)
+}
+
+// ApplyPrediction fills the prediction fields of the response
+func (resp *ReadinessResponse) ApplyPrediction(p scoring.Prediction, thetaSE float64, version string) {
+	resp.PredictedScore = int(math.Round(p.Score))
+	resp.PredictedScoreLow = p.Low
+	resp.PredictedScoreHigh = p.High
+	resp.ThetaSE = thetaSE
+	resp.PredictionVersion = version
+}
+
 // ToResponseWithStats converts UserReadinessWithStats to ReadinessResponse
 func (r *UserReadinessWithStats) ToResponse() ReadinessResponse {
 	resp := r.UserReadiness.ToResponse()
@@ -210,6 +313,18 @@ func (r *UserReadinessWithStats) ToResponse() ReadinessResponse {
 	return resp
 }
 
+// ToResponse converts HistoryPoint to HistoryPointResponse
+func (p *HistoryPoint) ToResponse() HistoryPointResponse {
+	return HistoryPointResponse{
+		PeriodStart:         p.PeriodStart.Format("2006-01-02"),
+		Theta:               p.Theta,
+		ThetaSE:             p.ThetaSE,
+		ReadinessPercentage: p.ReadinessPercentage,
+		PredictedScore:      p.PredictedScore,
+		TotalAttempts:       p.TotalAttempts,
+	}
+}
+
 // ToSubtypeResponse converts SubtypeAccuracy to SubtypeAccuracyResponse
 func (s *SubtypeAccuracy) ToResponse() SubtypeAccuracyResponse {
 	return SubtypeAccuracyResponse{
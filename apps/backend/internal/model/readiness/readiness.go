@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/lib/scoring"
 )
 
 // UserReadiness represents the user_readiness table entity
@@ -23,12 +24,19 @@ type UserReadiness struct {
 
 	// IRT-based metrics
 	CurrentTheta        *float64 `json:"currentTheta" db:"current_theta"`
+	ThetaSE             *float64 `json:"thetaSe" db:"theta_se"`
 	TargetTheta         *float64 `json:"targetTheta" db:"target_theta"`
 	ReadinessPercentage *float64 `json:"readinessPercentage" db:"readiness_percentage"`
 
+	// Answers folded into the stored theta estimate, for incremental updates
+	ThetaThrough   *time.Time `json:"thetaThrough" db:"theta_through"`
+	ThetaResponses int        `json:"thetaResponses" db:"theta_responses"`
+
 	// Predictions
 	PredictedScoreLow  *int       `json:"predictedScoreLow" db:"predicted_score_low"`
 	PredictedScoreHigh *int       `json:"predictedScoreHigh" db:"predicted_score_high"`
+	PredictedScore     *int       `json:"predictedScore" db:"predicted_score"`
+	PredictionVersion  *string    `json:"predictionVersion" db:"prediction_version"`
 	ReadyByDate        *time.Time `json:"readyByDate" db:"ready_by_date"`
 
 	// Progress tracking
@@ -57,6 +65,25 @@ type UserReadinessWithStats struct {
 	LastPracticed  *time.Time `json:"lastPracticed" db:"last_practiced"`
 }
 
+// ItemResponse is a scored answer with the item parameters used for theta estimation
+type ItemResponse struct {
+	DifficultyIRT  *float64  `db:"difficulty_irt"`
+	Discrimination *float64  `db:"discrimination"`
+	IsCorrect      bool      `db:"is_correct"`
+	AnsweredAt     time.Time `db:"answered_at"`
+}
+
+// HistoryPoint is a section's closing readiness for a period (day or week)
+type HistoryPoint struct {
+	Section             string    `db:"section"`
+	PeriodStart         time.Time `db:"period_start"`
+	Theta               *float64  `db:"theta"`
+	ThetaSE             *float64  `db:"theta_se"`
+	ReadinessPercentage *float64  `db:"readiness_percentage"`
+	PredictedScore      *int      `db:"predicted_score"`
+	TotalAttempts       int       `db:"total_attempts"`
+}
+
 // SectionReadiness represents readiness data for API response
 type SectionReadiness struct {
 	ReadinessPercentage float64 `json:"readiness_percentage"`
@@ -67,17 +94,34 @@ type SectionReadiness struct {
 // InitialReadiness represents the initial readiness map for all sections
 type InitialReadiness map[string]SectionReadiness
 
+const (
+	// ReadyPercentage is the readiness percentage at which a section counts as ready
+	ReadyPercentage = 80.0
+	// DefaultTargetTheta is used for sections without a target theta
+	DefaultTargetTheta = 0.5
+)
+
+// ReadyTheta returns the theta at which a section reaches ReadyPercentage of its target
+func ReadyTheta(targetTheta float64) float64 {
+	if targetTheta <= 0 {
+		return targetTheta
+	}
+	return targetTheta * ReadyPercentage / 100
+}
+
 // DefaultSections returns all UTBK sections
 func DefaultSections() []string {
 	return []string{"PU", "PPU", "PBM", "PK", "LBI", "LBE", "PM"}
 }
 
-// NewDefaultSectionReadiness creates default readiness for a new user
+// NewDefaultSectionReadiness creates default readiness for a new user, predicted
+// from the ability prior with the default calibration
 func NewDefaultSectionReadiness() SectionReadiness {
+	prediction := scoring.Predict(scoring.DefaultCalibration(""), 0, scoring.PriorSD)
 	return SectionReadiness{
 		ReadinessPercentage: 0,
-		PredictedScoreLow:   500,
-		PredictedScoreHigh:  550,
+		PredictedScoreLow:   prediction.Low,
+		PredictedScoreHigh:  prediction.High,
 	}
 }
 
@@ -89,3 +133,30 @@ func NewDefaultInitialReadiness() InitialReadiness {
 	}
 	return readiness
 }
+
+const (
+	CohortAll       = "all"
+	CohortTargetPTN = "target_ptn"
+	CohortExamMonth = "exam_month"
+
+	// SectionOverall is the pseudo-section ranking the mean theta across sections
+	SectionOverall = "OVERALL"
+
+	// MinCohortSize is the fewest users a cohort needs before its percentiles are published
+	MinCohortSize = 20
+)
+
+// Cohorts returns the cohorts users are ranked against
+func Cohorts() []string {
+	return []string{CohortAll, CohortTargetPTN, CohortExamMonth}
+}
+
+// Percentile is a user's materialized rank within a cohort for a section
+type Percentile struct {
+	Cohort     string    `db:"cohort"`
+	CohortKey  string    `db:"cohort_key"`
+	Section    string    `db:"section"`
+	Percentile float64   `db:"percentile"`
+	CohortSize int       `db:"cohort_size"`
+	ComputedAt time.Time `db:"computed_at"`
+}
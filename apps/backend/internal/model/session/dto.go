@@ -2,6 +2,7 @@ package session
 
 import (
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 )
 
 // === Request DTOs ===
@@ -18,13 +19,19 @@ func (r *CreateSessionRequest) Validate() error {
 
 // ListSessionsRequest represents query params for listing sessions
 type ListSessionsRequest struct {
-	Page  int `query:"page" validate:"min=1"`
-	Limit int `query:"limit" validate:"min=1,max=50"`
+	// Page selects offset pagination, the default. Sending a cursor, or pagination=cursor
+	// for the first page, pages the list by cursor instead.
+	Page       int    `query:"page" validate:"omitempty,min=1"`
+	Limit      int    `query:"limit" validate:"min=1,max=50"`
+	Cursor     string `query:"cursor" validate:"omitempty,max=500,excluded_with=Page"`
+	Pagination string `query:"pagination" validate:"omitempty,oneof=offset cursor"`
+	// IncludeTotal counts the whole list, which costs an extra query
+	IncludeTotal bool `query:"include_total"`
 }
 
 func (r *ListSessionsRequest) Validate() error {
 	// Set defaults
-	if r.Page == 0 {
+	if r.Page == 0 && r.Cursor == "" && r.Pagination != "cursor" {
 		r.Page = 1
 	}
 	if r.Limit == 0 {
@@ -55,6 +62,22 @@ func (r *GetSessionRequest) Validate() error {
 	return validate.Struct(r)
 }
 
+// GetSessionSummaryRequest represents path and query params for a session summary
+type GetSessionSummaryRequest struct {
+	SessionID string `param:"session_id" validate:"required,max=100"`
+	// Lang selects the language of the PDF labels
+	Lang string `query:"lang" validate:"omitempty,oneof=id en"`
+}
+
+func (r *GetSessionSummaryRequest) Validate() error {
+	if r.Lang == "" {
+		r.Lang = "id"
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
 // === Response DTOs ===
 
 // SessionResponse represents the API response for a session
@@ -69,6 +92,47 @@ type SessionResponse struct {
 	Section            *string  `json:"section,omitempty"`
 }
 
+// SessionSummaryResponse is a session report with every attempt for review
+type SessionSummaryResponse struct {
+	Session SessionResponse `json:"session"`
+	// ThetaStart and ThetaEnd are the ability estimates before the first and after the last attempt
+	ThetaStart  *float64                   `json:"theta_start"`
+	ThetaEnd    *float64                   `json:"theta_end"`
+	ThetaChange *float64                   `json:"theta_change"`
+	Sections    []SummaryBreakdownResponse `json:"sections"`
+	SubTypes    []SummaryBreakdownResponse `json:"sub_types"`
+	Attempts    []SummaryAttemptResponse   `json:"attempts"`
+}
+
+// SummaryBreakdownResponse aggregates a session's attempts by section or subtype
+type SummaryBreakdownResponse struct {
+	Section        string  `json:"section"`
+	SectionName    string  `json:"section_name"`
+	SubType        *string `json:"sub_type,omitempty"`
+	Attempts       int     `json:"attempts"`
+	Correct        int     `json:"correct"`
+	Accuracy       float64 `json:"accuracy"`
+	AvgTimeSeconds float64 `json:"avg_time_seconds"`
+	ThetaChange    float64 `json:"theta_change"`
+}
+
+// SummaryAttemptResponse is one reviewed attempt in a session summary
+type SummaryAttemptResponse struct {
+	Number           int       `json:"number"`
+	AttemptID        uuid.UUID `json:"attempt_id"`
+	QuestionID       uuid.UUID `json:"question_id"`
+	QuestionText     string    `json:"question_text"`
+	Section          string    `json:"section"`
+	SubType          *string   `json:"sub_type,omitempty"`
+	SelectedAnswer   string    `json:"selected_answer"`
+	CorrectAnswer    string    `json:"correct_answer"`
+	IsCorrect        bool      `json:"is_correct"`
+	TimeSpentSeconds int16     `json:"time_spent_seconds"`
+	ThetaChange      *float64  `json:"theta_change"`
+	FeedbackStatus   string    `json:"feedback_status"`
+	CreatedAt        string    `json:"created_at"`
+}
+
 // === Converters ===
 
 // ToResponse converts Session to SessionResponse
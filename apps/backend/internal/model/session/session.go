@@ -7,6 +7,10 @@ import (
 	"github.com/manikandareas/genta/internal/model"
 )
 
+// IdleTimeoutMinutes is how long a session may go without an attempt before it is closed
+// automatically (see session:close_idle job)
+const IdleTimeoutMinutes = 30
+
 // Session represents a user study session entity
 type Session struct {
 	ID     string    `json:"id" db:"id"` // VARCHAR(100) in DB
@@ -29,3 +33,47 @@ type Session struct {
 	model.BaseWithCreatedAt
 	model.BaseWithUpdatedAt
 }
+
+// IsOpen reports whether the session still accepts attempts
+func (s *Session) IsOpen() bool {
+	return s.EndedAt == nil
+}
+
+// Feedback statuses of an attempt in a session summary
+const (
+	FeedbackStatusReady       = "ready"
+	FeedbackStatusUnderReview = "under_review"
+	FeedbackStatusNone        = "none"
+)
+
+// SummaryAttempt is an attempt in a session with the question facts needed to review it
+type SummaryAttempt struct {
+	AttemptID              uuid.UUID `db:"attempt_id"`
+	AttemptNumberInSession *int16    `db:"attempt_number_in_session"`
+	QuestionID             uuid.UUID `db:"question_id"`
+	QuestionText           string    `db:"question_text"`
+	Section                string    `db:"section"`
+	SubType                *string   `db:"sub_type"`
+	SelectedAnswer         string    `db:"selected_answer"`
+	CorrectAnswer          string    `db:"correct_answer"`
+	IsCorrect              bool      `db:"is_correct"`
+	TimeSpentSeconds       int16     `db:"time_spent_seconds"`
+	UserThetaBefore        *float64  `db:"user_theta_before"`
+	UserThetaAfter         *float64  `db:"user_theta_after"`
+	ThetaChange            *float64  `db:"theta_change"`
+	HasFeedback            bool      `db:"has_feedback"`
+	FeedbackQualityStatus  *string   `db:"feedback_quality_status"`
+	CreatedAt              time.Time `db:"created_at"`
+}
+
+// FeedbackStatus tells whether feedback can be read for the attempt
+func (a *SummaryAttempt) FeedbackStatus() string {
+	switch {
+	case !a.HasFeedback:
+		return FeedbackStatusNone
+	case a.FeedbackQualityStatus != nil && *a.FeedbackQualityStatus == "flagged":
+		return FeedbackStatusUnderReview
+	default:
+		return FeedbackStatusReady
+	}
+}
@@ -0,0 +1,72 @@
+package stimulus
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/lib/content"
+	"github.com/manikandareas/genta/internal/model"
+)
+
+// === Request DTOs ===
+
+// GetStimulusRequest represents path params for getting a stimulus
+type GetStimulusRequest struct {
+	ID string `param:"id" validate:"required,uuid"`
+}
+
+func (r *GetStimulusRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// CreateStimulusRequest creates a stimulus from Markdown + LaTeX source
+type CreateStimulusRequest struct {
+	Section string  `json:"section" validate:"required,oneof=PU PPU PBM PK LBI LBE PM"`
+	Title   *string `json:"title" validate:"omitempty,max=255"`
+	Content string  `json:"content" validate:"required"`
+}
+
+func (r *CreateStimulusRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// UpdateStimulusRequest revises a stimulus; omitted fields are left unchanged
+type UpdateStimulusRequest struct {
+	ID      string  `param:"id" validate:"required,uuid"`
+	Title   *string `json:"title" validate:"omitempty,max=255"`
+	Content *string `json:"content" validate:"omitempty,min=1"`
+}
+
+func (r *UpdateStimulusRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// === Response DTOs ===
+
+// StimulusResponse represents a stimulus with its content in raw and structured form
+type StimulusResponse struct {
+	ID            uuid.UUID     `json:"id"`
+	Section       model.Section `json:"section"`
+	Title         *string       `json:"title,omitempty"`
+	Content       content.Rich  `json:"content"`
+	QuestionCount int           `json:"question_count"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// === Converters ===
+
+// ToResponse converts Stimulus to StimulusResponse
+func (s *Stimulus) ToResponse() StimulusResponse {
+	return StimulusResponse{
+		ID:            s.ID,
+		Section:       s.Section,
+		Title:         s.Title,
+		Content:       content.Render(s.Content),
+		QuestionCount: s.QuestionCount,
+		UpdatedAt:     s.UpdatedAt,
+	}
+}
@@ -0,0 +1,24 @@
+package stimulus
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/model"
+)
+
+// Stimulus is shared material several questions refer to, such as an LBI/LBE reading
+// passage or a data table (from stimuli table)
+type Stimulus struct {
+	ID        uuid.UUID     `json:"id" db:"id"`
+	Section   model.Section `json:"section" db:"section"`
+	Title     *string       `json:"title" db:"title"`
+	Content   string        `json:"content" db:"content"`
+	CreatedBy *uuid.UUID    `json:"createdBy" db:"created_by"`
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+	DeletedAt *time.Time `json:"deletedAt" db:"deleted_at"`
+
+	// QuestionCount is the number of active questions linked to the stimulus
+	QuestionCount int `json:"questionCount" db:"question_count"`
+}
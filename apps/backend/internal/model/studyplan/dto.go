@@ -0,0 +1,73 @@
+package studyplan
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// === Request DTOs ===
+
+// GetStudyPlanRequest represents a request for the active study plan
+type GetStudyPlanRequest struct{}
+
+func (r *GetStudyPlanRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// GenerateStudyPlanRequest represents a request to replace the active plan with a fresh one
+type GenerateStudyPlanRequest struct {
+	// StartDate defaults to today
+	StartDate *string `json:"start_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+func (r *GenerateStudyPlanRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// === Response DTOs ===
+
+// StudyPlanResponse represents the active plan with progress
+type StudyPlanResponse struct {
+	ID            string  `json:"id"`
+	Reason        string  `json:"reason"`
+	StartDate     string  `json:"start_date"`
+	EndDate       string  `json:"end_date"`
+	ExamDate      *string `json:"exam_date,omitempty"`
+	MinutesPerDay int     `json:"minutes_per_day"`
+	// Replanned is true when the plan was regenerated while serving this request
+	Replanned bool                   `json:"replanned"`
+	Progress  StudyPlanProgress      `json:"progress"`
+	Days      []StudyPlanDayResponse `json:"days"`
+	CreatedAt string                 `json:"created_at"`
+}
+
+// StudyPlanProgress summarizes completion of the plan's past days
+type StudyPlanProgress struct {
+	PlannedQuestions   int     `json:"planned_questions"`
+	CompletedQuestions int     `json:"completed_questions"`
+	CompletionRate     float64 `json:"completion_rate"`
+	MissedDays         int     `json:"missed_days"`
+	IsBehind           bool    `json:"is_behind"`
+}
+
+// StudyPlanDayResponse represents the blocks scheduled on one day
+type StudyPlanDayResponse struct {
+	Date    string                  `json:"date"`
+	Minutes int                     `json:"minutes"`
+	IsToday bool                    `json:"is_today"`
+	Items   []StudyPlanItemResponse `json:"items"`
+}
+
+// StudyPlanItemResponse represents one practice or review block
+type StudyPlanItemResponse struct {
+	ID                 string  `json:"id"`
+	Position           int16   `json:"position"`
+	Kind               string  `json:"kind"`
+	Section            string  `json:"section"`
+	SubType            *string `json:"sub_type,omitempty"`
+	TargetQuestions    int     `json:"target_questions"`
+	CompletedQuestions int     `json:"completed_questions"`
+	Minutes            int     `json:"minutes"`
+	Completed          bool    `json:"completed"`
+}
@@ -0,0 +1,60 @@
+package studyplan
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/model"
+)
+
+const (
+	StatusActive     = "active"
+	StatusSuperseded = "superseded"
+
+	ReasonInitial        = "initial"
+	ReasonManual         = "manual"
+	ReasonBehind         = "behind"
+	ReasonExpired        = "expired"
+	ReasonProfileChanged = "profile_changed"
+)
+
+// StudyPlan represents the study_plans table entity
+type StudyPlan struct {
+	model.Base
+	UserID        uuid.UUID  `json:"userId" db:"user_id"`
+	Status        string     `json:"status" db:"status"`
+	Reason        string     `json:"reason" db:"reason"`
+	StartDate     time.Time  `json:"startDate" db:"start_date"`
+	EndDate       time.Time  `json:"endDate" db:"end_date"`
+	ExamDate      *time.Time `json:"examDate" db:"exam_date"`
+	MinutesPerDay int        `json:"minutesPerDay" db:"minutes_per_day"`
+}
+
+// Item represents the study_plan_items table entity
+type Item struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	PlanID          uuid.UUID `json:"planId" db:"plan_id"`
+	ScheduledDate   time.Time `json:"scheduledDate" db:"scheduled_date"`
+	Position        int16     `json:"position" db:"position"`
+	Kind            string    `json:"kind" db:"kind"`
+	Section         string    `json:"section" db:"section"`
+	SubType         *string   `json:"subType" db:"sub_type"`
+	TargetQuestions int       `json:"targetQuestions" db:"target_questions"`
+	Minutes         int       `json:"minutes" db:"minutes"`
+	CreatedAt       time.Time `json:"createdAt" db:"created_at"`
+}
+
+// SectionSubtype is a subtype with questions in a section and the user's record on it
+type SectionSubtype struct {
+	Section  string `db:"section"`
+	SubType  string `db:"sub_type"`
+	Attempts int    `db:"attempts"`
+	Correct  int    `db:"correct"`
+}
+
+// DailySectionCount is the number of questions a user answered in a section on a day
+type DailySectionCount struct {
+	Date     time.Time `db:"date"`
+	Section  string    `db:"section"`
+	Attempts int       `db:"attempts"`
+}
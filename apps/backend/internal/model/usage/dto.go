@@ -0,0 +1,70 @@
+package usage
+
+import (
+	"github.com/go-playground/validator/v10"
+)
+
+// === Request DTOs ===
+
+// GetUsageReportRequest represents query params for the admin spend report
+type GetUsageReportRequest struct {
+	From    string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To      string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	GroupBy string `query:"group_by" validate:"omitempty,oneof=day model user"`
+	Limit   int    `query:"limit" validate:"min=1,max=500"`
+}
+
+func (r *GetUsageReportRequest) Validate() error {
+	// Set defaults
+	if r.GroupBy == "" {
+		r.GroupBy = "day"
+	}
+	if r.Limit == 0 {
+		r.Limit = 100
+	}
+
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
+// === Response DTOs ===
+
+// UsageReportRowResponse represents usage for a single group
+type UsageReportRowResponse struct {
+	Key          string  `json:"key"`
+	RequestCount int     `json:"request_count"`
+	TokensInput  int64   `json:"tokens_input"`
+	TokensOutput int64   `json:"tokens_output"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+// BudgetStatusResponse represents global spend against configured budgets
+type BudgetStatusResponse struct {
+	DailyBudgetUSD    float64 `json:"daily_budget_usd"`
+	SpentTodayUSD     float64 `json:"spent_today_usd"`
+	MonthlyBudgetUSD  float64 `json:"monthly_budget_usd"`
+	SpentThisMonthUSD float64 `json:"spent_this_month_usd"`
+}
+
+// UsageReportResponse represents the admin spend report
+type UsageReportResponse struct {
+	From    string                   `json:"from"`
+	To      string                   `json:"to"`
+	GroupBy string                   `json:"group_by"`
+	Totals  UsageTotals              `json:"totals"`
+	Rows    []UsageReportRowResponse `json:"rows"`
+	Budget  BudgetStatusResponse     `json:"budget"`
+}
+
+// === Converters ===
+
+// ToResponse converts UsageReportRow to UsageReportRowResponse
+func (r *UsageReportRow) ToResponse() UsageReportRowResponse {
+	return UsageReportRowResponse{
+		Key:          r.Key,
+		RequestCount: r.RequestCount,
+		TokensInput:  r.TokensInput,
+		TokensOutput: r.TokensOutput,
+		CostUSD:      r.CostUSD,
+	}
+}
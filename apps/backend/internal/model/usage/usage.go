@@ -0,0 +1,80 @@
+package usage
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/manikandareas/genta/internal/model"
+)
+
+// Purposes of ledger rows. System spend such as the LLM judge has no user, so it counts
+// toward the global budget without touching any student's quota.
+const (
+	PurposeUser          = "user"
+	PurposeFeedbackJudge = "feedback_judge"
+)
+
+// DailyUsage represents a row in the llm_usage_daily ledger
+type DailyUsage struct {
+	UserID       *uuid.UUID `json:"userId" db:"user_id"`
+	Purpose      string     `json:"purpose" db:"purpose"`
+	Model        string     `json:"model" db:"model"`
+	UsageDate    time.Time  `json:"usageDate" db:"usage_date"`
+	RequestCount int        `json:"requestCount" db:"request_count"`
+	TokensInput  int64      `json:"tokensInput" db:"tokens_input"`
+	TokensOutput int64      `json:"tokensOutput" db:"tokens_output"`
+	CostUSD      float64    `json:"costUsd" db:"cost_usd"`
+
+	model.BaseWithCreatedAt
+	model.BaseWithUpdatedAt
+}
+
+// UsageTotals represents aggregated usage over a period
+type UsageTotals struct {
+	RequestCount int     `json:"request_count" db:"request_count"`
+	TokensInput  int64   `json:"tokens_input" db:"tokens_input"`
+	TokensOutput int64   `json:"tokens_output" db:"tokens_output"`
+	CostUSD      float64 `json:"cost_usd" db:"cost_usd"`
+}
+
+// TotalTokens returns input + output tokens
+func (t UsageTotals) TotalTokens() int64 {
+	return t.TokensInput + t.TokensOutput
+}
+
+// UsageReportRow represents usage grouped by day, model or user
+type UsageReportRow struct {
+	Key string `db:"key"`
+	UsageTotals
+}
+
+// Quota represents per-user token limits for a subscription tier
+type Quota struct {
+	DailyTokens   int64 `json:"daily_tokens"`
+	MonthlyTokens int64 `json:"monthly_tokens"`
+}
+
+// TierQuotas maps subscription tiers to per-user token quotas
+var TierQuotas = map[string]Quota{
+	"free":         {DailyTokens: 20_000, MonthlyTokens: 300_000},
+	"premium":      {DailyTokens: 100_000, MonthlyTokens: 2_000_000},
+	"premium_plus": {DailyTokens: 300_000, MonthlyTokens: 6_000_000},
+}
+
+// QuotaForTier returns the quota for a subscription tier (defaults to free)
+func QuotaForTier(tier string) Quota {
+	if q, ok := TierQuotas[tier]; ok {
+		return q
+	}
+	return TierQuotas["free"]
+}
+
+// ExceededReason describes which budget blocked a generation
+type ExceededReason string
+
+const (
+	ExceededUserDaily     ExceededReason = "user_daily_quota"
+	ExceededUserMonthly   ExceededReason = "user_monthly_quota"
+	ExceededGlobalDaily   ExceededReason = "global_daily_budget"
+	ExceededGlobalMonthly ExceededReason = "global_monthly_budget"
+)
@@ -23,10 +23,18 @@ func (r *CreateUserRequest) Validate() error {
 type PutUserRequest struct {
 	FullName            *string    `json:"fullName,omitempty" validate:"omitempty,max=255"`
 	TargetPtn           *string    `json:"targetPtn,omitempty" validate:"omitempty,max=100"`
+	TargetProgramID     *string    `json:"targetProgramId,omitempty" validate:"omitempty,uuid"`
 	TargetScore         *int       `json:"targetScore,omitempty" validate:"omitempty,min=0"`
 	ExamDate            *time.Time `json:"examDate,omitempty" validate:"omitempty"`
 	StudyHoursPerWeek   *int16     `json:"studyHoursPerWeek,omitempty" validate:"omitempty,min=0,max=168"`
 	OnboardingCompleted *bool      `json:"onboardingCompleted,omitempty"`
+
+	// TargetPtnID is resolved from TargetPtn against the PTN catalogue, never set by clients
+	TargetPtnID *uuid.UUID `json:"-"`
+	// ClearTargetPtnID unlinks the catalogue PTN when the target changes to an unknown one
+	ClearTargetPtnID bool `json:"-"`
+	// ClearTargetProgram unsets the target program when the target PTN changes without one
+	ClearTargetProgram bool `json:"-"`
 }
 
 func (r *PutUserRequest) Validate() error {
@@ -34,8 +42,22 @@ func (r *PutUserRequest) Validate() error {
 	return validate.Struct(r)
 }
 
+// UpdateSubscriptionRequest sets a user's subscription tier (admin). Paid tiers run until
+// EndDate, or indefinitely without one; the free tier ends the current subscription.
+type UpdateSubscriptionRequest struct {
+	UserID  string     `param:"id" validate:"required,uuid"`
+	Tier    string     `json:"tier" validate:"required,oneof=free premium premium_plus"`
+	EndDate *time.Time `json:"endDate,omitempty"`
+}
+
+func (r *UpdateSubscriptionRequest) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
+
 type CompleteOnboardingRequest struct {
 	TargetPtn         *string    `json:"targetPtn,omitempty" validate:"omitempty,max=100"`
+	TargetProgramID   *string    `json:"targetProgramId,omitempty" validate:"omitempty,uuid"`
 	TargetScore       *int       `json:"targetScore,omitempty" validate:"omitempty,min=0"`
 	ExamDate          *time.Time `json:"examDate,omitempty" validate:"omitempty"`
 	StudyHoursPerWeek *int16     `json:"studyHoursPerWeek,omitempty" validate:"omitempty,min=0,max=168"`
@@ -50,6 +72,8 @@ type CompleteOnboardingResponse struct {
 	ID                  uuid.UUID                  `json:"id"`
 	OnboardingCompleted bool                       `json:"onboarding_completed"`
 	TargetPtn           *string                    `json:"target_ptn"`
+	TargetPtnID         *uuid.UUID                 `json:"target_ptn_id"`
+	TargetProgramID     *uuid.UUID                 `json:"target_program_id"`
 	TargetScore         *int                       `json:"target_score"`
 	ExamDate            *time.Time                 `json:"exam_date"`
 	StudyHoursPerWeek   *int16                     `json:"study_hours_per_week"`
@@ -27,6 +27,8 @@ type User struct {
 
 	// Study Plan
 	TargetPtn           *string    `json:"targetPtn" db:"target_ptn"`
+	TargetPtnID         *uuid.UUID `json:"targetPtnId" db:"target_ptn_id"`
+	TargetProgramID     *uuid.UUID `json:"targetProgramId" db:"target_program_id"`
 	TargetScore         *int       `json:"targetScore" db:"target_score"`
 	ExamDate            *time.Time `json:"examDate" db:"exam_date"`
 	StudyHoursPerWeek   *int16     `json:"studyHoursPerWeek" db:"study_hours_per_week"`
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/model/achievement"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+type AchievementRepository struct {
+	server *server.Server
+}
+
+func NewAchievementRepository(server *server.Server) *AchievementRepository {
+	return &AchievementRepository{server: server}
+}
+
+// GetUnlocked retrieves the user's unlocked achievements
+func (r *AchievementRepository) GetUnlocked(ctx context.Context, userID uuid.UUID) ([]achievement.UserAchievement, error) {
+	stmt := `
+		SELECT user_id, code, value, unlocked_at, seen_at
+		FROM user_achievements
+		WHERE user_id = @user_id
+		ORDER BY unlocked_at
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	unlocked, err := pgx.CollectRows(rows, pgx.RowToStructByName[achievement.UserAchievement])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return unlocked, nil
+}
+
+// Unlock awards an achievement. It returns nil when the user already has it, so concurrent
+// evaluations of the same event award it once.
+func (r *AchievementRepository) Unlock(ctx context.Context, userID uuid.UUID, code string, value float64) (*achievement.UserAchievement, error) {
+	stmt := `
+		INSERT INTO user_achievements (user_id, code, value)
+		VALUES (@user_id, @code, @value)
+		ON CONFLICT (user_id, code) DO NOTHING
+		RETURNING user_id, code, value, unlocked_at, seen_at
+	`
+
+	args := pgx.NamedArgs{
+		"user_id": userID,
+		"code":    code,
+		"value":   value,
+	}
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	unlocked, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[achievement.UserAchievement])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &unlocked, nil
+}
+
+// MarkSeen acknowledges unlock notifications; without codes every unseen one is marked
+func (r *AchievementRepository) MarkSeen(ctx context.Context, userID uuid.UUID, codes []string) (int64, error) {
+	stmt := `
+		UPDATE user_achievements
+		SET seen_at = NOW()
+		WHERE user_id = @user_id
+			AND seen_at IS NULL
+			AND (CARDINALITY(@codes::TEXT[]) = 0 OR code = ANY(@codes::TEXT[]))
+	`
+
+	if codes == nil {
+		codes = []string{}
+	}
+
+	tag, err := r.server.DB.Pool.Exec(ctx, stmt, pgx.NamedArgs{"user_id": userID, "codes": codes})
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark achievements seen: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// CountAnswered counts the user's answers, in one section when section is set
+func (r *AchievementRepository) CountAnswered(ctx context.Context, userID uuid.UUID, section string) (int, error) {
+	stmt := `
+		SELECT COUNT(*)
+		FROM attempts a
+		JOIN questions q ON a.question_id = q.id
+		WHERE a.user_id = @user_id
+			AND a.deleted_at IS NULL
+			AND (@section::TEXT = '' OR q.section = @section::TEXT)
+	`
+
+	var count int
+	err := r.server.DB.Pool.QueryRow(ctx, stmt, pgx.NamedArgs{"user_id": userID, "section": section}).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count answers: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetStreakDays counts the consecutive local days with at least one answer ending today,
+// or zero when the user has not practiced today
+func (r *AchievementRepository) GetStreakDays(ctx context.Context, userID uuid.UUID, utcOffset int) (int, error) {
+	stmt := `
+		WITH days AS (
+			SELECT DISTINCT DATE(created_at + make_interval(hours => @utc_offset)) AS day
+			FROM attempts
+			WHERE user_id = @user_id AND deleted_at IS NULL
+		),
+		islands AS (
+			SELECT day, day - (ROW_NUMBER() OVER (ORDER BY day))::INT AS island
+			FROM days
+		)
+		SELECT COUNT(*)
+		FROM islands
+		WHERE island = (
+			SELECT island FROM islands
+			WHERE day = DATE((NOW() AT TIME ZONE 'UTC') + make_interval(hours => @utc_offset))
+		)
+	`
+
+	var days int
+	err := r.server.DB.Pool.QueryRow(ctx, stmt, pgx.NamedArgs{"user_id": userID, "utc_offset": utcOffset}).Scan(&days)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get streak: %w", err)
+	}
+
+	return days, nil
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/model/admission"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+const ptnColumns = `id, code, name, short_name, city, province, created_at, updated_at`
+
+// programSelect selects study programs with their PTN name and most recent passing-score range
+const programSelect = `
+	SELECT sp.id, sp.ptn_id, p.name AS ptn_name, sp.code, sp.name, sp.cluster,
+		ps.year, ps.score_low, ps.score_high, ps.capacity, ps.applicants
+	FROM study_programs sp
+	JOIN ptn p ON p.id = sp.ptn_id
+	LEFT JOIN LATERAL (
+		SELECT year, score_low, score_high, capacity, applicants
+		FROM program_passing_scores
+		WHERE program_id = sp.id
+		ORDER BY year DESC
+		LIMIT 1
+	) ps ON TRUE`
+
+type AdmissionRepository struct {
+	server *server.Server
+}
+
+func NewAdmissionRepository(server *server.Server) *AdmissionRepository {
+	return &AdmissionRepository{server: server}
+}
+
+// ImportCatalogue upserts PTNs, study programs and passing scores from parsed CSV rows
+// in a single transaction
+func (r *AdmissionRepository) ImportCatalogue(ctx context.Context, rows []admission.CatalogueRow) (ptns int, programs int, err error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ptnIDs := make(map[string]uuid.UUID)
+	programIDs := make(map[string]uuid.UUID)
+
+	for _, row := range rows {
+		ptnID, ok := ptnIDs[row.PtnCode]
+		if !ok {
+			err := tx.QueryRow(ctx, `
+				INSERT INTO ptn (code, name, short_name, city, province)
+				VALUES (@code, @name, NULLIF(@short_name, ''), NULLIF(@city, ''), NULLIF(@province, ''))
+				ON CONFLICT (code) DO UPDATE SET
+					name = EXCLUDED.name,
+					short_name = COALESCE(EXCLUDED.short_name, ptn.short_name),
+					city = COALESCE(EXCLUDED.city, ptn.city),
+					province = COALESCE(EXCLUDED.province, ptn.province),
+					updated_at = NOW()
+				RETURNING id
+			`, pgx.NamedArgs{
+				"code":       row.PtnCode,
+				"name":       row.PtnName,
+				"short_name": row.PtnShort,
+				"city":       row.City,
+				"province":   row.Province,
+			}).Scan(&ptnID)
+			if err != nil {
+				return 0, 0, fmt.Errorf("failed to upsert ptn %s: %w", row.PtnCode, err)
+			}
+			ptnIDs[row.PtnCode] = ptnID
+		}
+
+		programID, ok := programIDs[row.ProgramCode]
+		if !ok {
+			err := tx.QueryRow(ctx, `
+				INSERT INTO study_programs (ptn_id, code, name, cluster)
+				VALUES (@ptn_id, @code, @name, NULLIF(@cluster, ''))
+				ON CONFLICT (code) DO UPDATE SET
+					ptn_id = EXCLUDED.ptn_id,
+					name = EXCLUDED.name,
+					cluster = COALESCE(EXCLUDED.cluster, study_programs.cluster),
+					updated_at = NOW()
+				RETURNING id
+			`, pgx.NamedArgs{
+				"ptn_id":  ptnID,
+				"code":    row.ProgramCode,
+				"name":    row.ProgramName,
+				"cluster": row.Cluster,
+			}).Scan(&programID)
+			if err != nil {
+				return 0, 0, fmt.Errorf("failed to upsert study program %s: %w", row.ProgramCode, err)
+			}
+			programIDs[row.ProgramCode] = programID
+		}
+
+		_, err := tx.Exec(ctx, `
+			INSERT INTO program_passing_scores (program_id, year, score_low, score_high, capacity, applicants)
+			VALUES (@program_id, @year, @score_low, @score_high, @capacity, @applicants)
+			ON CONFLICT (program_id, year) DO UPDATE SET
+				score_low = EXCLUDED.score_low,
+				score_high = EXCLUDED.score_high,
+				capacity = EXCLUDED.capacity,
+				applicants = EXCLUDED.applicants
+		`, pgx.NamedArgs{
+			"program_id": programID,
+			"year":       row.Year,
+			"score_low":  row.ScoreLow,
+			"score_high": row.ScoreHigh,
+			"capacity":   row.Capacity,
+			"applicants": row.Applicants,
+		})
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to upsert passing score for %s: %w", row.ProgramCode, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(ptnIDs), len(programIDs), nil
+}
+
+// SearchPTN searches the catalogue by code, short name or (fuzzy) name
+func (r *AdmissionRepository) SearchPTN(ctx context.Context, search *string, limit int) ([]admission.PTN, error) {
+	stmt := `SELECT ` + ptnColumns + ` FROM ptn
+		WHERE @search::TEXT IS NULL
+			OR code ILIKE @search::TEXT
+			OR short_name ILIKE @search::TEXT
+			OR name ILIKE '%' || @search::TEXT || '%'
+			OR name % @search::TEXT
+		ORDER BY
+			CASE WHEN @search::TEXT IS NULL THEN 0 ELSE similarity(name, @search::TEXT) END DESC,
+			name
+		LIMIT @limit
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"search": search, "limit": limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	ptns, err := pgx.CollectRows(rows, pgx.RowToStructByName[admission.PTN])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return ptns, nil
+}
+
+// ResolvePTN finds a PTN by exact code, short name or name (case-insensitive).
+// Returns nil, nil when nothing matches.
+func (r *AdmissionRepository) ResolvePTN(ctx context.Context, query string) (*admission.PTN, error) {
+	stmt := `SELECT ` + ptnColumns + ` FROM ptn
+		WHERE LOWER(code) = LOWER(@query) OR LOWER(short_name) = LOWER(@query) OR LOWER(name) = LOWER(@query)
+		LIMIT 1
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"query": query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	p, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[admission.PTN])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &p, nil
+}
+
+// CountPTN returns the number of PTNs in the catalogue
+func (r *AdmissionRepository) CountPTN(ctx context.Context) (int, error) {
+	var count int
+	if err := r.server.DB.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM ptn`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count ptn: %w", err)
+	}
+	return count, nil
+}
+
+// ListPrograms retrieves a PTN's study programs with their latest passing-score range
+func (r *AdmissionRepository) ListPrograms(ctx context.Context, req *admission.ListProgramsRequest) ([]admission.StudyProgram, error) {
+	stmt := programSelect + `
+		WHERE sp.ptn_id = @ptn_id
+			AND (@cluster::TEXT IS NULL OR sp.cluster = @cluster::TEXT)
+			AND (@search::TEXT IS NULL OR sp.name ILIKE '%' || @search::TEXT || '%')
+		ORDER BY sp.name
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"ptn_id":  req.PtnID,
+		"cluster": req.Cluster,
+		"search":  req.Search,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	programs, err := pgx.CollectRows(rows, pgx.RowToStructByName[admission.StudyProgram])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return programs, nil
+}
+
+// GetProgram retrieves a study program with its latest passing-score range
+func (r *AdmissionRepository) GetProgram(ctx context.Context, programID uuid.UUID) (*admission.StudyProgram, error) {
+	rows, err := r.server.DB.Pool.Query(ctx, programSelect+` WHERE sp.id = @id`, pgx.NamedArgs{"id": programID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	p, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[admission.StudyProgram])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("study program not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &p, nil
+}
@@ -3,7 +3,6 @@ package repository
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -11,6 +10,28 @@ import (
 	"github.com/manikandareas/genta/internal/server"
 )
 
+// localCreatedAt converts an attempt's created_at (stored in UTC) to the user's local time
+const localCreatedAt = `(a.created_at + make_interval(hours => @utc_offset))`
+
+// breakdownKeys holds the bucket expression for each breakdown dimension
+var breakdownKeys = map[string]string{
+	analytics.BreakdownSubtype: `COALESCE(q.sub_type, 'unknown')`,
+	analytics.BreakdownDifficulty: `
+		CASE
+			WHEN q.difficulty_irt IS NULL THEN 'unknown'
+			WHEN q.difficulty_irt < -0.5 THEN 'easy'
+			WHEN q.difficulty_irt <= 0.5 THEN 'medium'
+			ELSE 'hard'
+		END`,
+	analytics.BreakdownTimeOfDay: `
+		CASE
+			WHEN EXTRACT(HOUR FROM ` + localCreatedAt + `) BETWEEN 5 AND 10 THEN 'morning'
+			WHEN EXTRACT(HOUR FROM ` + localCreatedAt + `) BETWEEN 11 AND 14 THEN 'afternoon'
+			WHEN EXTRACT(HOUR FROM ` + localCreatedAt + `) BETWEEN 15 AND 18 THEN 'evening'
+			ELSE 'night'
+		END`,
+}
+
 type AnalyticsRepository struct {
 	server *server.Server
 }
@@ -19,113 +40,96 @@ func NewAnalyticsRepository(server *server.Server) *AnalyticsRepository {
 	return &AnalyticsRepository{server: server}
 }
 
-// GetProgressAnalytics retrieves progress analytics for a user within a time period
-func (r *AnalyticsRepository) GetProgressAnalytics(ctx context.Context, userID uuid.UUID, days int, section string) (*analytics.ProgressAnalytics, error) {
-	startDate := time.Now().AddDate(0, 0, -days)
-
+// GetProgressAnalytics retrieves progress analytics for a user within the filter's period
+func (r *AnalyticsRepository) GetProgressAnalytics(ctx context.Context, userID uuid.UUID, filter analytics.Filter, breakdowns []string) (*analytics.ProgressAnalytics, error) {
 	// Get total stats
-	totalStats, err := r.getTotalStats(ctx, userID, startDate, section)
+	totalStats, err := r.GetPeriodStats(ctx, userID, filter)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get accuracy trend
-	accuracyTrend, err := r.getAccuracyTrend(ctx, userID, startDate, section)
+	accuracyTrend, err := r.getAccuracyTrend(ctx, userID, filter)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get section breakdown
-	sectionBreakdown, err := r.getSectionBreakdown(ctx, userID, startDate, section)
+	sectionBreakdown, err := r.getSectionBreakdown(ctx, userID, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	// Calculate improvement this week
-	improvement, err := r.calculateWeeklyImprovement(ctx, userID, section)
+	// Calculate improvement over the last week of the period
+	improvement, err := r.calculateWeeklyImprovement(ctx, userID, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	return &analytics.ProgressAnalytics{
-		PeriodDays:              days,
-		TotalQuestionsAttempted: totalStats.TotalAttempts,
-		TotalCorrect:            totalStats.TotalCorrect,
-		AverageAccuracy:         totalStats.AverageAccuracy,
+	result := &analytics.ProgressAnalytics{
+		TotalQuestionsAttempted: totalStats.Attempts,
+		TotalCorrect:            totalStats.Correct,
+		AverageAccuracy:         totalStats.Accuracy,
 		AccuracyTrend:           accuracyTrend,
 		SectionBreakdown:        sectionBreakdown,
 		ImprovementThisWeek:     improvement,
-	}, nil
-}
+	}
 
-type totalStatsResult struct {
-	TotalAttempts   int
-	TotalCorrect    int
-	AverageAccuracy float64
+	for _, dimension := range breakdowns {
+		breakdown, err := r.getBreakdown(ctx, userID, filter, dimension)
+		if err != nil {
+			return nil, err
+		}
+		if result.Breakdowns == nil {
+			result.Breakdowns = make(map[string][]analytics.Breakdown)
+		}
+		result.Breakdowns[dimension] = breakdown
+	}
+
+	return result, nil
 }
 
-func (r *AnalyticsRepository) getTotalStats(ctx context.Context, userID uuid.UUID, startDate time.Time, section string) (*totalStatsResult, error) {
+// GetPeriodStats retrieves aggregate stats for the filter's period
+func (r *AnalyticsRepository) GetPeriodStats(ctx context.Context, userID uuid.UUID, filter analytics.Filter) (*analytics.PeriodStats, error) {
+	args := pgx.NamedArgs{}
 	stmt := `
-		SELECT 
-			COUNT(*) as total_attempts,
-			COALESCE(SUM(CASE WHEN a.is_correct THEN 1 ELSE 0 END), 0) as total_correct,
-			COALESCE(AVG(CASE WHEN a.is_correct THEN 1.0 ELSE 0.0 END), 0) as average_accuracy
+		SELECT
+			COUNT(*) as attempts,
+			COALESCE(SUM(CASE WHEN a.is_correct THEN 1 ELSE 0 END), 0) as correct,
+			COALESCE(AVG(CASE WHEN a.is_correct THEN 1.0 ELSE 0.0 END), 0) as accuracy,
+			COALESCE(AVG(a.time_spent_seconds), 0) as avg_time_seconds
 		FROM attempts a
 		JOIN questions q ON a.question_id = q.id
-		WHERE a.user_id = @user_id 
-			AND a.created_at >= @start_date
-			AND a.deleted_at IS NULL
-	`
-
-	args := pgx.NamedArgs{
-		"user_id":    userID,
-		"start_date": startDate,
-	}
+		WHERE ` + filterClause(userID, filter, args)
 
-	if section != "" {
-		stmt += " AND q.section = @section"
-		args["section"] = section
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get period stats: %w", err)
 	}
 
-	var result totalStatsResult
-	err := r.server.DB.Pool.QueryRow(ctx, stmt, args).Scan(
-		&result.TotalAttempts,
-		&result.TotalCorrect,
-		&result.AverageAccuracy,
-	)
+	stats, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[analytics.PeriodStats])
 	if err != nil {
-		return nil, fmt.Errorf("failed to get total stats: %w", err)
+		return nil, fmt.Errorf("failed to collect period stats: %w", err)
 	}
 
-	return &result, nil
+	return &stats, nil
 }
 
-func (r *AnalyticsRepository) getAccuracyTrend(ctx context.Context, userID uuid.UUID, startDate time.Time, section string) ([]analytics.DailyAccuracy, error) {
+func (r *AnalyticsRepository) getAccuracyTrend(ctx context.Context, userID uuid.UUID, filter analytics.Filter) ([]analytics.DailyAccuracy, error) {
+	args := pgx.NamedArgs{}
+	where := filterClause(userID, filter, args)
+	args["granularity"] = filter.Granularity
+
 	stmt := `
-		SELECT 
-			DATE(a.created_at) as date,
+		SELECT
+			DATE_TRUNC(@granularity::TEXT, ` + localCreatedAt + `)::DATE as date,
 			COALESCE(AVG(CASE WHEN a.is_correct THEN 1.0 ELSE 0.0 END), 0) as accuracy,
 			COUNT(*) as attempts
 		FROM attempts a
 		JOIN questions q ON a.question_id = q.id
-		WHERE a.user_id = @user_id 
-			AND a.created_at >= @start_date
-			AND a.deleted_at IS NULL
-	`
-
-	args := pgx.NamedArgs{
-		"user_id":    userID,
-		"start_date": startDate,
-	}
-
-	if section != "" {
-		stmt += " AND q.section = @section"
-		args["section"] = section
-	}
-
-	stmt += `
-		GROUP BY DATE(a.created_at)
-		ORDER BY DATE(a.created_at) ASC
+		WHERE ` + where + `
+		GROUP BY 1
+		ORDER BY 1 ASC
 	`
 
 	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
@@ -150,9 +154,10 @@ func (r *AnalyticsRepository) getAccuracyTrend(ctx context.Context, userID uuid.
 	return trend, nil
 }
 
-func (r *AnalyticsRepository) getSectionBreakdown(ctx context.Context, userID uuid.UUID, startDate time.Time, filterSection string) ([]analytics.SectionBreakdown, error) {
+func (r *AnalyticsRepository) getSectionBreakdown(ctx context.Context, userID uuid.UUID, filter analytics.Filter) ([]analytics.SectionBreakdown, error) {
+	args := pgx.NamedArgs{}
 	stmt := `
-		SELECT 
+		SELECT
 			q.section,
 			COUNT(*) as attempts,
 			COALESCE(SUM(CASE WHEN a.is_correct THEN 1 ELSE 0 END), 0) as correct,
@@ -160,22 +165,7 @@ func (r *AnalyticsRepository) getSectionBreakdown(ctx context.Context, userID uu
 			COALESCE(AVG(a.time_spent_seconds), 0) as avg_time_seconds
 		FROM attempts a
 		JOIN questions q ON a.question_id = q.id
-		WHERE a.user_id = @user_id 
-			AND a.created_at >= @start_date
-			AND a.deleted_at IS NULL
-	`
-
-	args := pgx.NamedArgs{
-		"user_id":    userID,
-		"start_date": startDate,
-	}
-
-	if filterSection != "" {
-		stmt += " AND q.section = @section"
-		args["section"] = filterSection
-	}
-
-	stmt += `
+		WHERE ` + filterClause(userID, filter, args) + `
 		GROUP BY q.section
 		ORDER BY q.section ASC
 	`
@@ -203,55 +193,146 @@ func (r *AnalyticsRepository) getSectionBreakdown(ctx context.Context, userID uu
 	return breakdown, nil
 }
 
-func (r *AnalyticsRepository) calculateWeeklyImprovement(ctx context.Context, userID uuid.UUID, section string) (float64, error) {
-	// Compare this week's accuracy vs last week's accuracy
+// getBreakdown retrieves accuracy per bucket of a breakdown dimension. Subtypes are
+// grouped per section since subtype names are only unique within a section.
+func (r *AnalyticsRepository) getBreakdown(ctx context.Context, userID uuid.UUID, filter analytics.Filter, dimension string) ([]analytics.Breakdown, error) {
+	key, ok := breakdownKeys[dimension]
+	if !ok {
+		return nil, fmt.Errorf("unknown breakdown dimension: %s", dimension)
+	}
+
+	section := `NULL::VARCHAR`
+	if dimension == analytics.BreakdownSubtype {
+		section = `q.section`
+	}
+
+	args := pgx.NamedArgs{}
 	stmt := `
-		WITH this_week AS (
-			SELECT COALESCE(AVG(CASE WHEN a.is_correct THEN 1.0 ELSE 0.0 END), 0) as accuracy
-			FROM attempts a
-			JOIN questions q ON a.question_id = q.id
-			WHERE a.user_id = @user_id 
-				AND a.created_at >= NOW() - INTERVAL '7 days'
-				AND a.deleted_at IS NULL
+		SELECT
+			` + key + ` as key,
+			` + section + ` as section,
+			COUNT(*) as attempts,
+			COALESCE(SUM(CASE WHEN a.is_correct THEN 1 ELSE 0 END), 0) as correct,
+			COALESCE(AVG(CASE WHEN a.is_correct THEN 1.0 ELSE 0.0 END), 0) as accuracy,
+			COALESCE(AVG(a.time_spent_seconds), 0) as avg_time_seconds
+		FROM attempts a
+		JOIN questions q ON a.question_id = q.id
+		WHERE ` + filterClause(userID, filter, args) + `
+		GROUP BY 1, 2
+		ORDER BY 2, 1
 	`
 
-	args := pgx.NamedArgs{
-		"user_id": userID,
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s breakdown: %w", dimension, err)
 	}
 
-	if section != "" {
-		stmt += " AND q.section = @section"
-		args["section"] = section
+	breakdown, err := pgx.CollectRows(rows, pgx.RowToStructByName[analytics.Breakdown])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect %s breakdown: %w", dimension, err)
 	}
 
-	stmt += `
-		),
-		last_week AS (
-			SELECT COALESCE(AVG(CASE WHEN a.is_correct THEN 1.0 ELSE 0.0 END), 0) as accuracy
-			FROM attempts a
-			JOIN questions q ON a.question_id = q.id
-			WHERE a.user_id = @user_id 
-				AND a.created_at >= NOW() - INTERVAL '14 days'
-				AND a.created_at < NOW() - INTERVAL '7 days'
-				AND a.deleted_at IS NULL
-	`
+	return breakdown, nil
+}
+
+// calculateWeeklyImprovement compares accuracy over the last 7 days of the period with
+// the 7 days before that, in percentage points
+func (r *AnalyticsRepository) calculateWeeklyImprovement(ctx context.Context, userID uuid.UUID, filter analytics.Filter) (float64, error) {
+	thisWeek := filter
+	thisWeek.From = filter.To.AddDate(0, 0, -7)
+	lastWeek := filter
+	lastWeek.From = filter.To.AddDate(0, 0, -14)
+	lastWeek.To = thisWeek.From
+
+	current, err := r.GetPeriodStats(ctx, userID, thisWeek)
+	if err != nil {
+		return 0, fmt.Errorf("failed to calculate weekly improvement: %w", err)
+	}
+	previous, err := r.GetPeriodStats(ctx, userID, lastWeek)
+	if err != nil {
+		return 0, fmt.Errorf("failed to calculate weekly improvement: %w", err)
+	}
+
+	// Convert to percentage and round to 1 decimal
+	return float64(int((current.Accuracy-previous.Accuracy)*1000)) / 10, nil
+}
+
+// filterClause returns the WHERE conditions for the filter and sets their args
+func filterClause(userID uuid.UUID, filter analytics.Filter, args pgx.NamedArgs) string {
+	args["user_id"] = userID
+	args["from"] = filter.From
+	args["to"] = filter.To
+	args["utc_offset"] = filter.UTCOffset
+
+	clause := `a.user_id = @user_id
+			AND a.created_at >= @from
+			AND a.created_at < @to
+			AND a.deleted_at IS NULL`
+
+	if len(filter.Sections) > 0 {
+		clause += ` AND q.section = ANY(@sections)`
+		args["sections"] = filter.Sections
+	}
+
+	return clause
+}
 
-	if section != "" {
-		stmt += " AND q.section = @section"
+// GetPacingStats retrieves timing stats per section against the section time budgets,
+// with the materialized cohort median answer time where one is available
+func (r *AnalyticsRepository) GetPacingStats(ctx context.Context, userID uuid.UUID, filter analytics.Filter) ([]analytics.SectionPacing, error) {
+	sections := make([]string, 0, len(analytics.TimeBudgetSeconds))
+	budgets := make([]int, 0, len(analytics.TimeBudgetSeconds))
+	for section, seconds := range analytics.TimeBudgetSeconds {
+		sections = append(sections, section)
+		budgets = append(budgets, seconds)
 	}
 
-	stmt += `
+	args := pgx.NamedArgs{
+		"budget_sections": sections,
+		"budget_seconds":  budgets,
+	}
+	where := filterClause(userID, filter, args)
+
+	stmt := `
+		WITH budgets AS (
+			SELECT * FROM UNNEST(@budget_sections::TEXT[], @budget_seconds::INT[]) AS b(section, seconds)
 		)
-		SELECT 
-			COALESCE((SELECT accuracy FROM this_week), 0) - COALESCE((SELECT accuracy FROM last_week), 0) as improvement
+		SELECT
+			q.section,
+			b.seconds as budget_seconds,
+			COUNT(*) as attempts,
+			COUNT(*) FILTER (WHERE a.is_correct) as correct,
+			COALESCE(AVG(a.time_spent_seconds), 0) as avg_time_seconds,
+			COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY a.time_spent_seconds), 0) as median_time_seconds,
+			COUNT(*) FILTER (WHERE a.time_spent_seconds > b.seconds) as over_budget,
+			COUNT(*) FILTER (WHERE a.time_spent_seconds < b.seconds * 0.5) as fast_attempts,
+			COUNT(*) FILTER (WHERE a.time_spent_seconds < b.seconds * 0.5 AND a.is_correct) as fast_correct,
+			COUNT(*) FILTER (WHERE a.time_spent_seconds BETWEEN b.seconds * 0.5 AND b.seconds) as normal_attempts,
+			COUNT(*) FILTER (WHERE a.time_spent_seconds BETWEEN b.seconds * 0.5 AND b.seconds AND a.is_correct) as normal_correct,
+			COUNT(*) FILTER (WHERE a.time_spent_seconds > b.seconds) as slow_attempts,
+			COUNT(*) FILTER (WHERE a.time_spent_seconds > b.seconds AND a.is_correct) as slow_correct,
+			COUNT(*) FILTER (WHERE a.time_spent_seconds < b.seconds * 0.5 AND NOT a.is_correct) as fast_wrong,
+			COUNT(*) FILTER (WHERE a.time_spent_seconds > b.seconds * 1.5 AND NOT a.is_correct) as overthinking_wrong,
+			CORR(a.time_spent_seconds, CASE WHEN a.is_correct THEN 1 ELSE 0 END) as speed_accuracy_corr,
+			MAX(c.median_seconds)::FLOAT8 as cohort_median_seconds
+		FROM attempts a
+		JOIN questions q ON a.question_id = q.id
+		JOIN budgets b ON b.section = q.section
+		LEFT JOIN pacing_cohort_medians c ON c.section = q.section
+		WHERE ` + where + `
+		GROUP BY q.section, b.seconds
+		ORDER BY q.section
 	`
 
-	var improvement float64
-	err := r.server.DB.Pool.QueryRow(ctx, stmt, args).Scan(&improvement)
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pacing stats: %w", err)
+	}
+
+	pacing, err := pgx.CollectRows(rows, pgx.RowToStructByName[analytics.SectionPacing])
 	if err != nil {
-		return 0, fmt.Errorf("failed to calculate weekly improvement: %w", err)
+		return nil, fmt.Errorf("failed to collect pacing stats: %w", err)
 	}
 
-	// Convert to percentage and round to 1 decimal
-	return float64(int(improvement*1000)) / 10, nil
+	return pacing, nil
 }
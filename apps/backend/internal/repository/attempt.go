@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/manikandareas/genta/internal/errs"
 	"github.com/manikandareas/genta/internal/model/attempt"
+	"github.com/manikandareas/genta/internal/model/event"
 	"github.com/manikandareas/genta/internal/model/question"
 	"github.com/manikandareas/genta/internal/server"
 )
@@ -21,8 +23,10 @@ func NewAttemptRepository(server *server.Server) *AttemptRepository {
 	return &AttemptRepository{server: server}
 }
 
-// Create inserts a new attempt record
-func (r *AttemptRepository) Create(ctx context.Context, a *attempt.Attempt) (*attempt.Attempt, error) {
+// Create inserts a new attempt record together with its domain events in one transaction.
+// An attempt in a session is numbered within it; the session must belong to the user and be open.
+// With a cache entry, the cached feedback is attached in the same transaction and returned.
+func (r *AttemptRepository) Create(ctx context.Context, a *attempt.Attempt, cached *attempt.FeedbackCacheEntry, events ...event.Payload) (*attempt.Attempt, *attempt.AttemptFeedback, error) {
 	stmt := `
 		INSERT INTO attempts (
 			id, user_id, question_id, session_id,
@@ -57,17 +61,72 @@ func (r *AttemptRepository) Create(ctx context.Context, a *attempt.Attempt) (*at
 		"attempt_number_in_session": a.AttemptNumberInSession,
 	}
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	tx, err := r.server.DB.Pool.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create attempt: %w", err)
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Number the attempt under the session lock, so concurrent answers get distinct numbers
+	// and no attempt lands in a session that was closed in the meantime
+	if a.SessionID != nil {
+		var open bool
+		err := tx.QueryRow(ctx, `
+			SELECT ended_at IS NULL FROM user_study_sessions
+			WHERE id = @session_id AND user_id = @user_id
+			FOR UPDATE
+		`, args).Scan(&open)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, nil, errs.NewNotFoundError("session not found", false, nil)
+			}
+			return nil, nil, fmt.Errorf("failed to lock session: %w", err)
+		}
+		if !open {
+			return nil, nil, errs.NewBadRequestError("session already ended", false, nil,
+				[]errs.FieldError{{Field: "session_id", Error: "session has ended"}}, nil)
+		}
+
+		var number int16
+		err = tx.QueryRow(ctx, `
+			SELECT (COALESCE(MAX(attempt_number_in_session), 0) + 1)::SMALLINT
+			FROM attempts
+			WHERE session_id = @session_id
+		`, args).Scan(&number)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to number attempt in session: %w", err)
+		}
+		args["attempt_number_in_session"] = number
+	}
+
+	rows, err := tx.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create attempt: %w", err)
 	}
 
 	created, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[attempt.Attempt])
 	if err != nil {
-		return nil, fmt.Errorf("failed to collect created attempt: %w", err)
+		return nil, nil, fmt.Errorf("failed to collect created attempt: %w", err)
 	}
 
-	return &created, nil
+	var feedback *attempt.AttemptFeedback
+	if cached != nil {
+		if feedback, err = createFeedbackFromCache(ctx, tx, created.ID, cached); err != nil {
+			return nil, nil, err
+		}
+		created.FeedbackGenerated = true
+		created.FeedbackModelUsed = &cached.ModelUsed
+	}
+
+	if err := appendEvents(ctx, tx, events...); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit attempt: %w", err)
+	}
+
+	return &created, feedback, nil
 }
 
 // GetByID retrieves an attempt by ID
@@ -154,7 +213,9 @@ func (r *AttemptRepository) GetByIDWithDetails(ctx context.Context, attemptID st
 		SELECT id, attempt_id, feedback_text, feedback_lang,
 			feedback_quality_rating, is_helpful, helpful_rating,
 			model_used, prompt_version, generation_time_ms,
-			token_count_input, token_count_output, created_at, updated_at
+			token_count_input, token_count_output, cost_usd, feedback_cache_id,
+			quality_status, quality_flags, judge_model, evaluated_at, regeneration_count,
+			created_at, updated_at
 		FROM attempt_feedback 
 		WHERE attempt_id = @attempt_id
 	`
@@ -240,16 +301,18 @@ func (r *AttemptRepository) CreateFeedback(ctx context.Context, f *attempt.Attem
 		INSERT INTO attempt_feedback (
 			id, attempt_id, feedback_text, feedback_lang,
 			model_used, prompt_version, generation_time_ms,
-			token_count_input, token_count_output, created_at, updated_at
+			token_count_input, token_count_output, cost_usd, feedback_cache_id, created_at, updated_at
 		) VALUES (
 			@id, @attempt_id, @feedback_text, @feedback_lang,
 			@model_used, @prompt_version, @generation_time_ms,
-			@token_count_input, @token_count_output, NOW(), NOW()
+			@token_count_input, @token_count_output, @cost_usd, @feedback_cache_id, NOW(), NOW()
 		)
 		RETURNING id, attempt_id, feedback_text, feedback_lang,
 			feedback_quality_rating, is_helpful, helpful_rating,
 			model_used, prompt_version, generation_time_ms,
-			token_count_input, token_count_output, created_at, updated_at
+			token_count_input, token_count_output, cost_usd, feedback_cache_id,
+			quality_status, quality_flags, judge_model, evaluated_at, regeneration_count,
+			created_at, updated_at
 	`
 
 	args := pgx.NamedArgs{
@@ -262,6 +325,8 @@ func (r *AttemptRepository) CreateFeedback(ctx context.Context, f *attempt.Attem
 		"generation_time_ms": f.GenerationTimeMs,
 		"token_count_input":  f.TokenCountInput,
 		"token_count_output": f.TokenCountOutput,
+		"cost_usd":           f.CostUSD,
+		"feedback_cache_id":  f.FeedbackCacheID,
 	}
 
 	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
@@ -297,35 +362,309 @@ func (r *AttemptRepository) MarkFeedbackGenerated(ctx context.Context, attemptID
 	return nil
 }
 
-// UpdateQuestionStats updates question statistics after an attempt
-func (r *AttemptRepository) UpdateQuestionStats(ctx context.Context, questionID uuid.UUID, isCorrect bool, timeSpent int16) error {
+// GetCachedFeedback looks up canonical feedback for a question/answer pair.
+// Returns nil without error on a cache miss.
+func (r *AttemptRepository) GetCachedFeedback(ctx context.Context, questionID uuid.UUID, selectedAnswer, lang, promptVersion string) (*attempt.FeedbackCacheEntry, error) {
 	stmt := `
-		UPDATE questions 
-		SET 
-			attempt_count = COALESCE(attempt_count, 0) + 1,
-			correct_rate = (
-				(COALESCE(correct_rate, 0) * COALESCE(attempt_count, 0)) + @is_correct_int
-			) / (COALESCE(attempt_count, 0) + 1),
-			avg_time_seconds = (
-				(COALESCE(avg_time_seconds, 0) * COALESCE(attempt_count, 0)) + @time_spent
-			) / (COALESCE(attempt_count, 0) + 1),
-			updated_at = NOW()
-		WHERE id = @question_id
+		SELECT id, question_id, selected_answer, feedback_lang, prompt_version,
+			feedback_text, model_used, hit_count, last_hit_at, created_at, updated_at
+		FROM feedback_cache
+		WHERE question_id = @question_id
+			AND selected_answer = @selected_answer
+			AND feedback_lang = @feedback_lang
+			AND prompt_version = @prompt_version
 	`
 
-	isCorrectInt := 0
-	if isCorrect {
-		isCorrectInt = 1
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"question_id":     questionID,
+		"selected_answer": selectedAnswer,
+		"feedback_lang":   lang,
+		"prompt_version":  promptVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feedback cache: %w", err)
 	}
 
+	entry, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[attempt.FeedbackCacheEntry])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect feedback cache entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// CreateFeedbackFromCache attaches cached feedback to an attempt, marks the attempt's
+// feedback as generated and records the cache hit in a single transaction. Returns nil
+// without error when the attempt already has feedback, so a retried job is harmless.
+func (r *AttemptRepository) CreateFeedbackFromCache(ctx context.Context, attemptID uuid.UUID, entry *attempt.FeedbackCacheEntry) (*attempt.AttemptFeedback, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	created, err := createFeedbackFromCache(ctx, tx, attemptID, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return created, nil
+}
+
+// createFeedbackFromCache writes cached feedback for an attempt inside tx; see
+// CreateFeedbackFromCache
+func createFeedbackFromCache(ctx context.Context, tx pgx.Tx, attemptID uuid.UUID, entry *attempt.FeedbackCacheEntry) (*attempt.AttemptFeedback, error) {
+	feedbackStmt := `
+		INSERT INTO attempt_feedback (
+			id, attempt_id, feedback_text, feedback_lang,
+			model_used, prompt_version, generation_time_ms,
+			token_count_input, token_count_output, cost_usd, feedback_cache_id, created_at, updated_at
+		) VALUES (
+			@id, @attempt_id, @feedback_text, @feedback_lang,
+			@model_used, @prompt_version, 0,
+			0, 0, 0, @feedback_cache_id, NOW(), NOW()
+		)
+		ON CONFLICT (attempt_id) DO NOTHING
+		RETURNING id, attempt_id, feedback_text, feedback_lang,
+			feedback_quality_rating, is_helpful, helpful_rating,
+			model_used, prompt_version, generation_time_ms,
+			token_count_input, token_count_output, cost_usd, feedback_cache_id,
+			quality_status, quality_flags, judge_model, evaluated_at, regeneration_count,
+			created_at, updated_at
+	`
+
+	rows, err := tx.Query(ctx, feedbackStmt, pgx.NamedArgs{
+		"id":                uuid.New(),
+		"attempt_id":        attemptID,
+		"feedback_text":     entry.FeedbackText,
+		"feedback_lang":     entry.FeedbackLang,
+		"model_used":        entry.ModelUsed,
+		"prompt_version":    entry.PromptVersion,
+		"feedback_cache_id": entry.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create feedback from cache: %w", err)
+	}
+
+	created, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[attempt.AttemptFeedback])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect created feedback: %w", err)
+	}
+
+	attemptStmt := `
+		UPDATE attempts
+		SET feedback_generated = true,
+			feedback_model_used = @model_used,
+			feedback_generation_ms = 0
+		WHERE id = @id
+	`
+	_, err = tx.Exec(ctx, attemptStmt, pgx.NamedArgs{
+		"id":         attemptID,
+		"model_used": entry.ModelUsed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark feedback generated: %w", err)
+	}
+
+	hitStmt := `
+		UPDATE feedback_cache
+		SET hit_count = hit_count + 1, last_hit_at = NOW()
+		WHERE id = @id
+	`
+	_, err = tx.Exec(ctx, hitStmt, pgx.NamedArgs{"id": entry.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record feedback cache hit: %w", err)
+	}
+
+	return &created, nil
+}
+
+const flaggedFeedbackColumns = `
+	f.id, f.attempt_id, f.feedback_text, f.feedback_lang,
+	f.feedback_quality_rating, f.is_helpful, f.helpful_rating,
+	f.model_used, f.prompt_version, f.generation_time_ms,
+	f.token_count_input, f.token_count_output, f.cost_usd, f.feedback_cache_id,
+	f.quality_status, f.quality_flags, f.judge_model, f.evaluated_at, f.regeneration_count,
+	f.created_at, f.updated_at,
+	a.user_id, a.question_id, a.selected_answer, a.is_correct,
+	q.correct_answer, u.subscription_tier`
+
+const flaggedFeedbackJoins = `
+	FROM attempt_feedback f
+	JOIN attempts a ON a.id = f.attempt_id
+	JOIN questions q ON q.id = a.question_id
+	JOIN users u ON u.id = a.user_id`
+
+// ListFlaggedFeedback retrieves feedback flagged by quality evaluation, newest first
+func (r *AttemptRepository) ListFlaggedFeedback(ctx context.Context, params PageParams) (*Page[attempt.FlaggedFeedback], error) {
+	return queryPage(ctx, r.server, pageQuery{
+		columns:    flaggedFeedbackColumns,
+		from:       flaggedFeedbackJoins,
+		conditions: []string{"f.quality_status = @status"},
+		args:       pgx.NamedArgs{"status": attempt.QualityStatusFlagged},
+		keyset:     keyset{name: "flagged_feedback", timeColumn: "f.created_at", idColumn: "f.id", descending: true},
+	}, params, func(f *attempt.FlaggedFeedback) (time.Time, string) {
+		return f.CreatedAt, f.ID.String()
+	})
+}
+
+// GetFeedbackForReview retrieves a feedback row with the attempt facts needed to review it
+func (r *AttemptRepository) GetFeedbackForReview(ctx context.Context, feedbackID string) (*attempt.FlaggedFeedback, error) {
+	stmt := `SELECT ` + flaggedFeedbackColumns + flaggedFeedbackJoins + ` WHERE f.id = @id`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"id": feedbackID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	f, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[attempt.FlaggedFeedback])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("feedback not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &f, nil
+}
+
+// MarkFeedbackReviewed records that a human checked the feedback and kept it
+func (r *AttemptRepository) MarkFeedbackReviewed(ctx context.Context, feedbackID string) error {
+	stmt := `
+		UPDATE attempt_feedback
+		SET quality_status = @status, updated_at = NOW()
+		WHERE id = @id
+	`
+
 	_, err := r.server.DB.Pool.Exec(ctx, stmt, pgx.NamedArgs{
-		"question_id":    questionID,
-		"is_correct_int": isCorrectInt,
-		"time_spent":     timeSpent,
+		"id":     feedbackID,
+		"status": attempt.QualityStatusReviewed,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to update question stats: %w", err)
+		return fmt.Errorf("failed to mark feedback reviewed: %w", err)
 	}
 
 	return nil
 }
+
+// historyConditions builds the WHERE conditions of a history filter over attempts a joined
+// with questions q
+func historyConditions(userID uuid.UUID, filter attempt.HistoryFilter, args pgx.NamedArgs) []string {
+	conditions := []string{"a.user_id = @user_id", "a.deleted_at IS NULL"}
+	args["user_id"] = userID
+
+	if filter.Section != nil {
+		conditions = append(conditions, "q.section = @section")
+		args["section"] = *filter.Section
+	}
+	if filter.SubType != nil {
+		conditions = append(conditions, "q.sub_type = @sub_type")
+		args["sub_type"] = *filter.SubType
+	}
+	if filter.IsCorrect != nil {
+		conditions = append(conditions, "a.is_correct = @is_correct")
+		args["is_correct"] = *filter.IsCorrect
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "a.created_at >= @from")
+		args["from"] = *filter.From
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "a.created_at < @to")
+		args["to"] = *filter.To
+	}
+	if filter.SessionID != nil {
+		conditions = append(conditions, "a.session_id = @session_id")
+		args["session_id"] = *filter.SessionID
+	}
+	if filter.HasFeedback != nil {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM attempt_feedback af WHERE af.attempt_id = a.id) = @has_feedback")
+		args["has_feedback"] = *filter.HasFeedback
+	}
+
+	return conditions
+}
+
+// ListHistory retrieves a user's attempts newest first, one page at a time
+func (r *AttemptRepository) ListHistory(ctx context.Context, userID uuid.UUID, filter attempt.HistoryFilter, params PageParams) (*Page[attempt.HistoryItem], error) {
+	args := pgx.NamedArgs{}
+	conditions := historyConditions(userID, filter, args)
+
+	return queryPage(ctx, r.server, pageQuery{
+		columns: `a.id, a.user_id, a.question_id, a.session_id,
+			a.selected_answer, a.is_correct, a.time_spent_seconds,
+			a.user_theta_before, a.user_theta_after, a.theta_change,
+			a.feedback_generated, a.feedback_model_used, a.feedback_generation_ms,
+			a.feedback_helpful, a.attempt_number_in_session, a.created_at, a.deleted_at,
+			q.section, q.sub_type, q.correct_answer,
+			EXISTS (SELECT 1 FROM attempt_feedback af WHERE af.attempt_id = a.id) AS has_feedback`,
+		from:       "FROM attempts a JOIN questions q ON q.id = a.question_id",
+		conditions: conditions,
+		args:       args,
+		keyset:     keyset{name: "attempts", timeColumn: "a.created_at", idColumn: "a.id", descending: true},
+	}, params, func(h *attempt.HistoryItem) (time.Time, string) {
+		return h.CreatedAt, h.ID.String()
+	})
+}
+
+// GetRetryQueue retrieves questions the user answered wrong within the filter and has not
+// answered correctly since, most often missed first
+func (r *AttemptRepository) GetRetryQueue(ctx context.Context, userID uuid.UUID, filter attempt.HistoryFilter, limit int) ([]attempt.RetryItem, error) {
+	args := pgx.NamedArgs{"limit": limit}
+	filter.IsCorrect = nil
+	filter.HasFeedback = nil
+	conditions := historyConditions(userID, filter, args)
+
+	stmt := `
+		WITH wrong AS (
+			SELECT a.question_id,
+				COUNT(*) AS wrong_count,
+				MAX(a.created_at) AS last_wrong_at,
+				(ARRAY_AGG(a.selected_answer ORDER BY a.created_at DESC))[1] AS last_selected_answer
+			FROM attempts a
+			JOIN questions q ON q.id = a.question_id
+			WHERE ` + joinConditions(conditions) + ` AND a.is_correct = false
+			GROUP BY a.question_id
+		)
+		SELECT q.id, q.question_bank_id, q.section, q.sub_type,
+			q.difficulty_irt, q.discrimination, q.guessing_param,
+			q.text, q.option_a, q.option_b, q.option_c, q.option_d, q.option_e, q.correct_answer,
+			q.explanation, q.explanation_en, q.strategy_tip, q.related_concept, q.solution_steps,
+			q.is_active, q.attempt_count, q.correct_rate, q.avg_time_seconds,
+			q.created_at, q.updated_at, q.deleted_at,
+			w.wrong_count, w.last_wrong_at, w.last_selected_answer
+		FROM wrong w
+		JOIN questions q ON q.id = w.question_id
+		WHERE q.deleted_at IS NULL AND q.is_active = true
+			AND NOT EXISTS (
+				SELECT 1 FROM attempts c
+				WHERE c.user_id = @user_id AND c.question_id = w.question_id
+					AND c.is_correct = true AND c.deleted_at IS NULL
+					AND c.created_at > w.last_wrong_at
+			)
+		ORDER BY w.wrong_count DESC, w.last_wrong_at DESC
+		LIMIT @limit
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retry queue: %w", err)
+	}
+
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[attempt.RetryItem])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect retry queue: %w", err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/model/calibration"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+const calibrationColumns = `version, section, slope, intercept, residual_sd, sample_size, is_active, created_by, created_at`
+
+type CalibrationRepository struct {
+	server *server.Server
+}
+
+func NewCalibrationRepository(server *server.Server) *CalibrationRepository {
+	return &CalibrationRepository{server: server}
+}
+
+// GetActive retrieves the active calibration for every section
+func (r *CalibrationRepository) GetActive(ctx context.Context) ([]calibration.ScoreCalibration, error) {
+	stmt := `SELECT ` + calibrationColumns + ` FROM score_calibrations WHERE is_active ORDER BY section`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	calibrations, err := pgx.CollectRows(rows, pgx.RowToStructByName[calibration.ScoreCalibration])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return calibrations, nil
+}
+
+// List retrieves calibration rows, optionally for a single version, newest version first
+func (r *CalibrationRepository) List(ctx context.Context, version *string) ([]calibration.ScoreCalibration, error) {
+	stmt := `SELECT ` + calibrationColumns + ` FROM score_calibrations
+		WHERE (@version::TEXT IS NULL OR version = @version::TEXT)
+		ORDER BY created_at DESC, version, section
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"version": version})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	calibrations, err := pgx.CollectRows(rows, pgx.RowToStructByName[calibration.ScoreCalibration])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return calibrations, nil
+}
+
+// VersionExists reports whether any calibration rows exist for a version
+func (r *CalibrationRepository) VersionExists(ctx context.Context, version string) (bool, error) {
+	var exists bool
+	err := r.server.DB.Pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM score_calibrations WHERE version = @version)`,
+		pgx.NamedArgs{"version": version},
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check calibration version: %w", err)
+	}
+	return exists, nil
+}
+
+// CreateVersion inserts all sections of a new calibration version in one transaction
+func (r *CalibrationRepository) CreateVersion(ctx context.Context, calibrations []calibration.ScoreCalibration) error {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	stmt := `
+		INSERT INTO score_calibrations (version, section, slope, intercept, residual_sd, sample_size, is_active, created_by)
+		VALUES (@version, @section, @slope, @intercept, @residual_sd, @sample_size, FALSE, @created_by)
+	`
+
+	for _, c := range calibrations {
+		_, err := tx.Exec(ctx, stmt, pgx.NamedArgs{
+			"version":     c.Version,
+			"section":     c.Section,
+			"slope":       c.Slope,
+			"intercept":   c.Intercept,
+			"residual_sd": c.ResidualSD,
+			"sample_size": c.SampleSize,
+			"created_by":  c.CreatedBy,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to insert calibration: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Activate makes a calibration version the active one
+func (r *CalibrationRepository) Activate(ctx context.Context, version string) error {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE score_calibrations SET is_active = (version = @version)`, pgx.NamedArgs{"version": version})
+	if err != nil {
+		return fmt.Errorf("failed to activate calibration: %w", err)
+	}
+
+	var activated int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM score_calibrations WHERE is_active`).Scan(&activated); err != nil {
+		return fmt.Errorf("failed to verify activation: %w", err)
+	}
+	if activated == 0 {
+		return errs.NewNotFoundError("calibration version not found", false, nil)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpsertTryoutResult stores a tryout score with the theta estimated at the time of the tryout
+func (r *CalibrationRepository) UpsertTryoutResult(ctx context.Context, t *calibration.TryoutResult) error {
+	stmt := `
+		INSERT INTO tryout_results (
+			user_id, section, tryout_name, scaled_score, taken_at,
+			theta_at_tryout, theta_se, attempts_count
+		) VALUES (
+			@user_id, @section, @tryout_name, @scaled_score, @taken_at,
+			@theta_at_tryout, @theta_se, @attempts_count
+		)
+		ON CONFLICT (user_id, section, tryout_name) DO UPDATE SET
+			scaled_score = EXCLUDED.scaled_score,
+			taken_at = EXCLUDED.taken_at,
+			theta_at_tryout = EXCLUDED.theta_at_tryout,
+			theta_se = EXCLUDED.theta_se,
+			attempts_count = EXCLUDED.attempts_count
+	`
+
+	_, err := r.server.DB.Pool.Exec(ctx, stmt, pgx.NamedArgs{
+		"user_id":         t.UserID,
+		"section":         t.Section,
+		"tryout_name":     t.TryoutName,
+		"scaled_score":    t.ScaledScore,
+		"taken_at":        t.TakenAt,
+		"theta_at_tryout": t.ThetaAtTryout,
+		"theta_se":        t.ThetaSE,
+		"attempts_count":  t.AttemptsCount,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert tryout result: %w", err)
+	}
+
+	return nil
+}
+
+// GetTryoutResults retrieves tryout results for a section from students with at least
+// minAttempts practice answers before the tryout
+func (r *CalibrationRepository) GetTryoutResults(ctx context.Context, section string, minAttempts int) ([]calibration.TryoutResult, error) {
+	stmt := `
+		SELECT id, user_id, section, tryout_name, scaled_score, taken_at,
+			theta_at_tryout, theta_se, attempts_count, created_at
+		FROM tryout_results
+		WHERE section = @section AND attempts_count >= @min_attempts
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"section":      section,
+		"min_attempts": minAttempts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	results, err := pgx.CollectRows(rows, pgx.RowToStructByName[calibration.TryoutResult])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// UserExists reports whether a user ID refers to an existing user
+func (r *CalibrationRepository) UserExists(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.server.DB.Pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM users WHERE id = @id AND deleted_at IS NULL)`,
+		pgx.NamedArgs{"id": userID},
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check user: %w", err)
+	}
+	return exists, nil
+}
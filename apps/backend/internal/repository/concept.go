@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/model/concept"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+const conceptColumns = `
+	id, parent_id, level, section, sub_type, code, name, name_en, description,
+	p_init::FLOAT8 AS p_init, p_transit::FLOAT8 AS p_transit,
+	p_slip::FLOAT8 AS p_slip, p_guess::FLOAT8 AS p_guess,
+	created_at, updated_at
+`
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+type ConceptRepository struct {
+	server *server.Server
+}
+
+func NewConceptRepository(server *server.Server) *ConceptRepository {
+	return &ConceptRepository{server: server}
+}
+
+// ListNodes retrieves the taxonomy in section order with active question counts, and the
+// user's mastery when userID is set
+func (r *ConceptRepository) ListNodes(ctx context.Context, userID *uuid.UUID, section *string) ([]concept.Node, error) {
+	stmt := `
+		SELECT c.id, c.parent_id, c.level, c.section, c.sub_type, c.code, c.name, c.name_en,
+			(
+				SELECT COUNT(*) FROM question_concepts qc
+				JOIN questions q ON q.id = qc.question_id
+				WHERE qc.concept_id = c.id AND q.deleted_at IS NULL AND q.is_active = true
+			)::INT AS question_count,
+			c.p_init::FLOAT8 AS p_init,
+			m.p_mastery::FLOAT8 AS p_mastery,
+			COALESCE(m.attempts_count, 0) AS attempts_count,
+			COALESCE(m.correct_count, 0) AS correct_count,
+			m.last_attempt_at
+		FROM concepts c
+		LEFT JOIN user_concept_mastery m ON m.concept_id = c.id AND m.user_id = @user_id
+		WHERE (@section::TEXT IS NULL OR c.section = @section)
+		ORDER BY array_position(ARRAY['PU', 'PPU', 'PBM', 'PK', 'LBI', 'LBE', 'PM'], c.section::TEXT), c.name
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"section": section,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list concepts: %w", err)
+	}
+
+	nodes, err := pgx.CollectRows(rows, pgx.RowToStructByName[concept.Node])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect concepts: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// GetByID retrieves a concept by its ID
+func (r *ConceptRepository) GetByID(ctx context.Context, conceptID string) (*concept.Concept, error) {
+	rows, err := r.server.DB.Pool.Query(ctx, `SELECT `+conceptColumns+` FROM concepts WHERE id = @id`,
+		pgx.NamedArgs{"id": conceptID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	c, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[concept.Concept])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("concept not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &c, nil
+}
+
+// Create adds a child concept one level below parent. Its code extends the parent's with a
+// slug of the name; a subtype's slug is also its sub_type value.
+func (r *ConceptRepository) Create(ctx context.Context, parent *concept.Concept, req *concept.CreateConceptRequest) (*concept.Concept, error) {
+	level, ok := parent.Level.ChildLevel()
+	if !ok {
+		return nil, errs.NewBadRequestError("concepts cannot be nested below the concept level", false, nil,
+			[]errs.FieldError{{Field: "parent_id", Error: "must be a section or subtype"}}, nil)
+	}
+
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(req.Name), "-"), "-")
+	if slug == "" {
+		return nil, errs.NewBadRequestError("concept name has no letters or digits", false, nil,
+			[]errs.FieldError{{Field: "name", Error: "invalid"}}, nil)
+	}
+
+	subType := parent.SubType
+	if level == concept.LevelSubtype {
+		subType = &slug
+	}
+
+	stmt := `
+		INSERT INTO concepts (parent_id, level, section, sub_type, code, name, name_en, description)
+		VALUES (@parent_id, @level, @section, @sub_type, @code, @name, @name_en, @description)
+		RETURNING ` + conceptColumns
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"parent_id":   parent.ID,
+		"level":       level,
+		"section":     parent.Section,
+		"sub_type":    subType,
+		"code":        parent.Code + "." + slug,
+		"name":        req.Name,
+		"name_en":     req.NameEn,
+		"description": req.Description,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create concept: %w", err)
+	}
+
+	c, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[concept.Concept])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect created concept: %w", err)
+	}
+
+	return &c, nil
+}
+
+// GetQuestionConcepts retrieves the concepts a question is tagged with
+func (r *ConceptRepository) GetQuestionConcepts(ctx context.Context, questionID string) ([]concept.Concept, error) {
+	stmt := `
+		SELECT ` + conceptColumns + ` FROM concepts
+		WHERE id IN (SELECT concept_id FROM question_concepts WHERE question_id = @question_id)
+		ORDER BY code
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"question_id": questionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question concepts: %w", err)
+	}
+
+	concepts, err := pgx.CollectRows(rows, pgx.RowToStructByName[concept.Concept])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect question concepts: %w", err)
+	}
+
+	return concepts, nil
+}
+
+// SetQuestionConcepts replaces a question's tags. Every concept must belong to the
+// question's section.
+func (r *ConceptRepository) SetQuestionConcepts(ctx context.Context, questionID string, conceptIDs []string) error {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var section string
+	err = tx.QueryRow(ctx, `SELECT section FROM questions WHERE id = @id AND deleted_at IS NULL FOR UPDATE`,
+		pgx.NamedArgs{"id": questionID}).Scan(&section)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errs.NewNotFoundError("question not found", false, nil)
+		}
+		return fmt.Errorf("failed to lock question: %w", err)
+	}
+
+	var matching int
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM concepts WHERE id = ANY(@ids::UUID[]) AND section = @section`,
+		pgx.NamedArgs{"ids": conceptIDs, "section": section}).Scan(&matching)
+	if err != nil {
+		return fmt.Errorf("failed to check concepts: %w", err)
+	}
+	if matching != len(uniqueStrings(conceptIDs)) {
+		return errs.NewBadRequestError("unknown concept or concept from another section", false, nil,
+			[]errs.FieldError{{Field: "concept_ids", Error: "must be concepts of section " + section}}, nil)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM question_concepts WHERE question_id = @id`, pgx.NamedArgs{"id": questionID}); err != nil {
+		return fmt.Errorf("failed to clear question concepts: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO question_concepts (question_id, concept_id)
+		SELECT @question_id, UNNEST(@ids::UUID[])
+		ON CONFLICT DO NOTHING
+	`, pgx.NamedArgs{"question_id": questionID, "ids": conceptIDs})
+	if err != nil {
+		return fmt.Errorf("failed to tag question: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func uniqueStrings(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
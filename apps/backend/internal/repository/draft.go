@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/model/draft"
+	"github.com/manikandareas/genta/internal/model/duplicate"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+const draftColumns = `
+	id, kind, source_question_id, status, content, target_difficulty,
+	model_used, prompt_version, tokens_input, tokens_output, cost_usd,
+	requested_by, reviewed_by, reviewed_at, review_notes, applied_question_id,
+	created_at, updated_at
+`
+
+type DraftRepository struct {
+	server *server.Server
+}
+
+func NewDraftRepository(server *server.Server) *DraftRepository {
+	return &DraftRepository{server: server}
+}
+
+// GetByID retrieves a draft by its ID
+func (r *DraftRepository) GetByID(ctx context.Context, draftID string) (*draft.Draft, error) {
+	stmt := `SELECT ` + draftColumns + ` FROM question_drafts WHERE id = @id`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"id": draftID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	d, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[draft.Draft])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("draft not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &d, nil
+}
+
+// List retrieves drafts with optional filtering, oldest first, one page at a time
+func (r *DraftRepository) List(ctx context.Context, req *draft.ListDraftsRequest, params PageParams) (*Page[draft.Draft], error) {
+	args := pgx.NamedArgs{}
+
+	var conditions []string
+
+	if req.Status != nil {
+		conditions = append(conditions, "status = @status")
+		args["status"] = *req.Status
+	}
+
+	if req.Kind != nil {
+		conditions = append(conditions, "kind = @kind")
+		args["kind"] = *req.Kind
+	}
+
+	if req.SourceQuestionID != nil {
+		conditions = append(conditions, "source_question_id = @source_question_id")
+		args["source_question_id"] = *req.SourceQuestionID
+	}
+
+	return queryPage(ctx, r.server, pageQuery{
+		columns:    draftColumns,
+		from:       "FROM question_drafts",
+		conditions: conditions,
+		args:       args,
+		keyset:     keyset{name: "drafts", timeColumn: "created_at", idColumn: "id"},
+	}, params, func(d *draft.Draft) (time.Time, string) {
+		return d.CreatedAt, d.ID.String()
+	})
+}
+
+// UpdateContent replaces the drafted content of a pending draft
+// AverageCost returns the mean LLM cost of recent drafts of a kind, or zero without any
+func (r *DraftRepository) AverageCost(ctx context.Context, kind draft.Kind) (float64, error) {
+	var avg float64
+	err := r.server.DB.Pool.QueryRow(ctx, `
+		SELECT COALESCE(AVG(cost_usd), 0)::FLOAT8
+		FROM question_drafts
+		WHERE kind = $1 AND cost_usd > 0 AND created_at >= NOW() - INTERVAL '30 days'
+	`, kind).Scan(&avg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get average draft cost: %w", err)
+	}
+	return avg, nil
+}
+
+func (r *DraftRepository) UpdateContent(ctx context.Context, draftID string, content draft.Content) (*draft.Draft, error) {
+	stmt := `
+		UPDATE question_drafts
+		SET content = @content
+		WHERE id = @id AND status = 'pending'
+		RETURNING ` + draftColumns
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"id":      draftID,
+		"content": content,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update draft: %w", err)
+	}
+
+	d, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[draft.Draft])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("pending draft not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to collect updated draft: %w", err)
+	}
+
+	return &d, nil
+}
+
+// Approve applies a pending draft to the questions table and marks it approved.
+// Enrichment drafts fill fields of the source question; variant drafts insert a new active
+// question in the source question's bank and section, unless it likely duplicates an
+// existing question and allowDuplicate is not set.
+func (r *DraftRepository) Approve(ctx context.Context, draftID string, reviewerID uuid.UUID, notes *string, allowDuplicate bool) (*draft.Draft, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT `+draftColumns+` FROM question_drafts WHERE id = @id FOR UPDATE`, pgx.NamedArgs{"id": draftID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock draft: %w", err)
+	}
+
+	d, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[draft.Draft])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("draft not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to collect draft: %w", err)
+	}
+
+	if d.Status != draft.StatusPending {
+		return nil, errs.NewBadRequestError("draft has already been reviewed", false, nil, nil, nil)
+	}
+
+	var appliedQuestionID uuid.UUID
+	switch d.Kind {
+	case draft.KindEnrichment:
+		_, err = tx.Exec(ctx, `
+			UPDATE questions
+			SET explanation = COALESCE(@explanation, explanation),
+				explanation_en = COALESCE(@explanation_en, explanation_en),
+				strategy_tip = COALESCE(@strategy_tip, strategy_tip),
+				solution_steps = COALESCE(@solution_steps, solution_steps),
+				updated_at = NOW()
+			WHERE id = @id
+		`, pgx.NamedArgs{
+			"id":             d.SourceQuestionID,
+			"explanation":    d.Content.Explanation,
+			"explanation_en": d.Content.ExplanationEn,
+			"strategy_tip":   d.Content.StrategyTip,
+			"solution_steps": d.Content.SolutionSteps,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply enrichment draft: %w", err)
+		}
+		appliedQuestionID = d.SourceQuestionID
+
+	case draft.KindVariant:
+		if !d.Content.IsCompleteQuestion() {
+			return nil, errs.NewBadRequestError("variant draft is not a complete question", false, nil, nil, nil)
+		}
+
+		if !allowDuplicate {
+			if err := r.checkDuplicates(ctx, tx, &d); err != nil {
+				return nil, err
+			}
+		}
+
+		// Without a target the variant inherits the source's difficulty; adaptive selection
+		// and theta updates need one, so a variant of an uncalibrated question is refused
+		err = tx.QueryRow(ctx, `
+			INSERT INTO questions (
+				question_bank_id, section, sub_type, difficulty_irt,
+				text, option_a, option_b, option_c, option_d, option_e, correct_answer,
+				explanation, explanation_en, strategy_tip, solution_steps, is_active
+			)
+			SELECT question_bank_id, section, COALESCE(@sub_type, sub_type),
+				COALESCE(@difficulty_irt::DECIMAL(4, 2), difficulty_irt),
+				@text, @option_a, @option_b, @option_c, @option_d, @option_e, @correct_answer,
+				@explanation, @explanation_en, @strategy_tip, @solution_steps, true
+			FROM questions
+			WHERE id = @source_question_id
+				AND COALESCE(@difficulty_irt::DECIMAL(4, 2), difficulty_irt) IS NOT NULL
+			RETURNING id
+		`, pgx.NamedArgs{
+			"source_question_id": d.SourceQuestionID,
+			"sub_type":           d.Content.SubType,
+			"difficulty_irt":     d.TargetDifficulty,
+			"text":               d.Content.Text,
+			"option_a":           d.Content.Options[0],
+			"option_b":           d.Content.Options[1],
+			"option_c":           d.Content.Options[2],
+			"option_d":           d.Content.Options[3],
+			"option_e":           d.Content.Options[4],
+			"correct_answer":     d.Content.CorrectAnswer,
+			"explanation":        d.Content.Explanation,
+			"explanation_en":     d.Content.ExplanationEn,
+			"strategy_tip":       d.Content.StrategyTip,
+			"solution_steps":     d.Content.SolutionSteps,
+		}).Scan(&appliedQuestionID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, errs.NewBadRequestError("variant has no difficulty", false, nil,
+					[]errs.FieldError{{Field: "target_difficulty", Error: "source question has no difficulty; generate the variant with a target difficulty"}}, nil)
+			}
+			return nil, fmt.Errorf("failed to insert variant question: %w", err)
+		}
+	}
+
+	approved, err := r.setReviewed(ctx, tx, draftID, draft.StatusApproved, reviewerID, notes, &appliedQuestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return approved, nil
+}
+
+// Reject marks a pending draft as rejected
+func (r *DraftRepository) Reject(ctx context.Context, draftID string, reviewerID uuid.UUID, notes *string) (*draft.Draft, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rejected, err := r.setReviewed(ctx, tx, draftID, draft.StatusRejected, reviewerID, notes, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return rejected, nil
+}
+
+// checkDuplicates rejects a variant that likely duplicates an active question in its section
+func (r *DraftRepository) checkDuplicates(ctx context.Context, tx pgx.Tx, d *draft.Draft) error {
+	var section string
+	err := tx.QueryRow(ctx, `SELECT section FROM questions WHERE id = @id`, pgx.NamedArgs{"id": d.SourceQuestionID}).Scan(&section)
+	if err != nil {
+		return fmt.Errorf("failed to get source question section: %w", err)
+	}
+
+	matches, err := findSimilarQuestions(ctx, tx, section, *d.Content.Text, d.Content.Options, duplicate.DefaultThreshold, 5)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	fieldErrors := make([]errs.FieldError, len(matches))
+	for i, m := range matches {
+		fieldErrors[i] = errs.FieldError{
+			Field: m.QuestionID.String(),
+			Error: fmt.Sprintf("%.0f%% similar to an existing question", m.Similarity*100),
+		}
+	}
+	code := "DUPLICATE_QUESTION"
+	return errs.NewBadRequestError("variant likely duplicates existing questions; approve with allow_duplicate to insert anyway",
+		false, &code, fieldErrors, nil)
+}
+
+func (r *DraftRepository) setReviewed(ctx context.Context, tx pgx.Tx, draftID string, status draft.Status, reviewerID uuid.UUID, notes *string, appliedQuestionID *uuid.UUID) (*draft.Draft, error) {
+	stmt := `
+		UPDATE question_drafts
+		SET status = @status,
+			reviewed_by = @reviewed_by,
+			reviewed_at = NOW(),
+			review_notes = @review_notes,
+			applied_question_id = @applied_question_id
+		WHERE id = @id AND status = 'pending'
+		RETURNING ` + draftColumns
+
+	rows, err := tx.Query(ctx, stmt, pgx.NamedArgs{
+		"id":                  draftID,
+		"status":              status,
+		"reviewed_by":         reviewerID,
+		"review_notes":        notes,
+		"applied_question_id": appliedQuestionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update draft status: %w", err)
+	}
+
+	d, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[draft.Draft])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("pending draft not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to collect reviewed draft: %w", err)
+	}
+
+	return &d, nil
+}
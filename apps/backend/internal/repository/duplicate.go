@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/model/duplicate"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+const clusterColumns = `
+	id, section, status, max_similarity, suggested_keep_id, kept_question_id,
+	resolved_by, resolved_at, resolution_notes, created_at, updated_at
+`
+
+type DuplicateRepository struct {
+	server *server.Server
+}
+
+func NewDuplicateRepository(server *server.Server) *DuplicateRepository {
+	return &DuplicateRepository{server: server}
+}
+
+// queryer is satisfied by both the pool and a transaction
+type queryer interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// findSimilarQuestions returns active questions in a section whose fingerprint is at least
+// threshold similar to the given content. The fingerprint is built exactly like the
+// questions.dedup_text column so both sides compare alike.
+func findSimilarQuestions(ctx context.Context, q queryer, section, text string, options []string, threshold float64, limit int) ([]duplicate.Match, error) {
+	stmt := `
+		WITH candidate AS (
+			SELECT immutable_unaccent(regexp_replace(lower(
+				@text || ' ' || array_to_string(@options::TEXT[], ' ')
+			), '\s+', ' ', 'g')) AS dedup_text
+		)
+		SELECT q.id AS question_id, q.text, similarity(q.dedup_text, c.dedup_text)::FLOAT8 AS similarity
+		FROM questions q, candidate c
+		WHERE q.section = @section
+			AND q.deleted_at IS NULL
+			AND q.is_active = true
+			AND q.dedup_text % c.dedup_text
+			AND similarity(q.dedup_text, c.dedup_text) >= @threshold
+		ORDER BY similarity DESC, q.id
+		LIMIT @limit
+	`
+
+	rows, err := q.Query(ctx, stmt, pgx.NamedArgs{
+		"section":   section,
+		"text":      text,
+		"options":   options,
+		"threshold": threshold,
+		"limit":     limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find similar questions: %w", err)
+	}
+
+	matches, err := pgx.CollectRows(rows, pgx.RowToStructByName[duplicate.Match])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect similar questions: %w", err)
+	}
+
+	return matches, nil
+}
+
+// FindSimilar checks content against the bank before it is inserted
+func (r *DuplicateRepository) FindSimilar(ctx context.Context, section, text string, options []string, threshold float64) ([]duplicate.Match, error) {
+	return findSimilarQuestions(ctx, r.server.DB.Pool, section, text, options, threshold, 5)
+}
+
+// ListClusters retrieves duplicate clusters, oldest first, one page at a time
+func (r *DuplicateRepository) ListClusters(ctx context.Context, req *duplicate.ListClustersRequest, params PageParams) (*Page[duplicate.Cluster], error) {
+	args := pgx.NamedArgs{"status": *req.Status}
+	conditions := []string{"status = @status"}
+
+	if req.Section != nil {
+		conditions = append(conditions, "section = @section")
+		args["section"] = *req.Section
+	}
+
+	return queryPage(ctx, r.server, pageQuery{
+		columns:    clusterColumns,
+		from:       "FROM question_duplicate_clusters",
+		conditions: conditions,
+		args:       args,
+		keyset:     keyset{name: "duplicate_clusters", timeColumn: "created_at", idColumn: "id"},
+	}, params, func(c *duplicate.Cluster) (time.Time, string) {
+		return c.CreatedAt, c.ID.String()
+	})
+}
+
+// GetCluster retrieves a duplicate cluster by its ID
+func (r *DuplicateRepository) GetCluster(ctx context.Context, clusterID string) (*duplicate.Cluster, error) {
+	rows, err := r.server.DB.Pool.Query(ctx, `SELECT `+clusterColumns+` FROM question_duplicate_clusters WHERE id = @id`,
+		pgx.NamedArgs{"id": clusterID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	c, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[duplicate.Cluster])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("duplicate cluster not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &c, nil
+}
+
+// GetMembers retrieves the questions of the given clusters, most similar first
+func (r *DuplicateRepository) GetMembers(ctx context.Context, clusterIDs []uuid.UUID) ([]duplicate.Member, error) {
+	stmt := `
+		SELECT m.cluster_id, m.question_id, m.similarity::FLOAT8 AS similarity,
+			q.text, ARRAY[q.option_a, q.option_b, q.option_c, q.option_d, q.option_e] AS options,
+			b.name AS bank_name, q.is_active, q.attempt_count
+		FROM question_duplicate_members m
+		JOIN questions q ON q.id = m.question_id
+		LEFT JOIN question_banks b ON b.id = q.question_bank_id
+		WHERE m.cluster_id = ANY(@cluster_ids)
+		ORDER BY m.cluster_id, m.similarity DESC, q.created_at
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"cluster_ids": clusterIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster members: %w", err)
+	}
+
+	members, err := pgx.CollectRows(rows, pgx.RowToStructByName[duplicate.Member])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect cluster members: %w", err)
+	}
+
+	return members, nil
+}
+
+// Resolve applies an editor's decision to a pending cluster. Merging soft-deletes every
+// member but the kept one; deactivating takes the chosen members out of practice.
+// Attempts keep pointing at their original questions either way.
+func (r *DuplicateRepository) Resolve(ctx context.Context, req *duplicate.ResolveClusterRequest, resolverID uuid.UUID) (*duplicate.Cluster, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status duplicate.Status
+	err = tx.QueryRow(ctx, `SELECT status FROM question_duplicate_clusters WHERE id = @id FOR UPDATE`,
+		pgx.NamedArgs{"id": req.ID}).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("duplicate cluster not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to lock duplicate cluster: %w", err)
+	}
+	if status != duplicate.StatusPending {
+		return nil, errs.NewBadRequestError("duplicate cluster has already been resolved", false, nil, nil, nil)
+	}
+
+	rows, err := tx.Query(ctx, `SELECT question_id::TEXT FROM question_duplicate_members WHERE cluster_id = @id`,
+		pgx.NamedArgs{"id": req.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster members: %w", err)
+	}
+	memberIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect cluster members: %w", err)
+	}
+
+	var (
+		newStatus duplicate.Status
+		keptID    *uuid.UUID
+	)
+	switch req.Action {
+	case duplicate.ActionMerge:
+		if !slices.Contains(memberIDs, req.KeepQuestionID.String()) {
+			return nil, errs.NewBadRequestError("question to keep is not in the cluster", false, nil,
+				[]errs.FieldError{{Field: "keep_question_id", Error: "not a cluster member"}}, nil)
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE questions
+			SET is_active = false, deleted_at = NOW(), updated_at = NOW()
+			WHERE id = ANY(@ids::UUID[]) AND id <> @keep_id AND deleted_at IS NULL
+		`, pgx.NamedArgs{"ids": memberIDs, "keep_id": req.KeepQuestionID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge duplicate questions: %w", err)
+		}
+		newStatus = duplicate.StatusMerged
+		keptID = req.KeepQuestionID
+
+	case duplicate.ActionDeactivate:
+		for _, id := range req.QuestionIDs {
+			if !slices.Contains(memberIDs, id) {
+				return nil, errs.NewBadRequestError("question to deactivate is not in the cluster", false, nil,
+					[]errs.FieldError{{Field: "question_ids", Error: id + " is not a cluster member"}}, nil)
+			}
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE questions SET is_active = false, updated_at = NOW()
+			WHERE id = ANY(@ids::UUID[])
+		`, pgx.NamedArgs{"ids": req.QuestionIDs})
+		if err != nil {
+			return nil, fmt.Errorf("failed to deactivate duplicate questions: %w", err)
+		}
+		newStatus = duplicate.StatusDeactivated
+
+	case duplicate.ActionDismiss:
+		newStatus = duplicate.StatusDismissed
+	}
+
+	rows, err = tx.Query(ctx, `
+		UPDATE question_duplicate_clusters
+		SET status = @status,
+			kept_question_id = @kept_question_id,
+			resolved_by = @resolved_by,
+			resolved_at = NOW(),
+			resolution_notes = @notes,
+			updated_at = NOW()
+		WHERE id = @id
+		RETURNING `+clusterColumns, pgx.NamedArgs{
+		"id":               req.ID,
+		"status":           newStatus,
+		"kept_question_id": keptID,
+		"resolved_by":      resolverID,
+		"notes":            req.Notes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve duplicate cluster: %w", err)
+	}
+
+	resolved, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[duplicate.Cluster])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect resolved cluster: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &resolved, nil
+}
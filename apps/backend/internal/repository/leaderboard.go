@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/model/leaderboard"
+	"github.com/manikandareas/genta/internal/server"
+	"github.com/redis/go-redis/v9"
+)
+
+// LeaderboardRepository keeps leaderboard entries in Postgres, the source of truth, and
+// mirrors them into Redis sorted sets for ranking
+type LeaderboardRepository struct {
+	server *server.Server
+}
+
+func NewLeaderboardRepository(server *server.Server) *LeaderboardRepository {
+	return &LeaderboardRepository{server: server}
+}
+
+// RecordAttempt counts an answer toward the user's section entry for a season and refreshes
+// its theta from user_readiness. A new entry starts from the section's closing theta before
+// seasonStart (the prior mean for the all-time season, where seasonStart is nil).
+func (r *LeaderboardRepository) RecordAttempt(ctx context.Context, userID uuid.UUID, section, season string, seasonStart *time.Time) (*leaderboard.Entry, error) {
+	stmt := `
+		INSERT INTO leaderboard_entries AS e (user_id, section, season, start_theta, theta, attempts, score)
+		SELECT
+			ur.user_id,
+			ur.section,
+			@season,
+			start.theta,
+			COALESCE(ur.current_theta, 0),
+			1,
+			GREATEST(COALESCE(ur.current_theta, 0) - start.theta, 0) * @gain_points + @attempt_points
+		FROM user_readiness ur
+		CROSS JOIN LATERAL (
+			SELECT COALESCE(
+				(
+					SELECT theta FROM readiness_snapshots
+					WHERE user_id = ur.user_id AND section = ur.section AND recorded_at < @season_start::TIMESTAMP
+					ORDER BY recorded_at DESC LIMIT 1
+				),
+				(
+					SELECT theta FROM readiness_daily
+					WHERE user_id = ur.user_id AND section = ur.section AND day < @season_start::DATE
+					ORDER BY day DESC LIMIT 1
+				),
+				0
+			) AS theta
+		) start
+		WHERE ur.user_id = @user_id AND ur.section = @section
+		ON CONFLICT (user_id, section, season) DO UPDATE SET
+			theta = EXCLUDED.theta,
+			attempts = e.attempts + 1,
+			score = GREATEST(EXCLUDED.theta - e.start_theta, 0) * @gain_points + (e.attempts + 1) * @attempt_points,
+			updated_at = NOW()
+		RETURNING e.user_id, e.section, e.season, e.start_theta, e.theta, e.attempts, e.score, e.updated_at
+	`
+
+	var start *time.Time
+	if seasonStart != nil {
+		utc := seasonStart.UTC()
+		start = &utc
+	}
+
+	args := pgx.NamedArgs{
+		"user_id":        userID,
+		"section":        section,
+		"season":         season,
+		"season_start":   start,
+		"gain_points":    leaderboard.ThetaGainPoints,
+		"attempt_points": leaderboard.AttemptPoints,
+	}
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	entry, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[leaderboard.Entry])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// GetSeasonEntries retrieves every entry of a section leaderboard, leaving out users who opted out
+func (r *LeaderboardRepository) GetSeasonEntries(ctx context.Context, section, season string) ([]leaderboard.Entry, error) {
+	stmt := `
+		SELECT e.user_id, e.section, e.season, e.start_theta, e.theta, e.attempts, e.score, e.updated_at
+		FROM leaderboard_entries e
+		LEFT JOIN leaderboard_settings s ON s.user_id = e.user_id
+		WHERE e.section = @section AND e.season = @season AND COALESCE(s.opt_out, FALSE) = FALSE
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"section": section, "season": season})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[leaderboard.Entry])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetRankedEntries retrieves the given users' entries with their pseudonyms
+func (r *LeaderboardRepository) GetRankedEntries(ctx context.Context, section, season string, userIDs []uuid.UUID) ([]leaderboard.RankedEntry, error) {
+	stmt := `
+		SELECT e.user_id, e.section, e.season, e.start_theta, e.theta, e.attempts, e.score, e.updated_at, s.pseudonym
+		FROM leaderboard_entries e
+		LEFT JOIN leaderboard_settings s ON s.user_id = e.user_id
+		WHERE e.section = @section AND e.season = @season AND e.user_id = ANY(@user_ids)
+	`
+
+	args := pgx.NamedArgs{
+		"section":  section,
+		"season":   season,
+		"user_ids": userIDs,
+	}
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[leaderboard.RankedEntry])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetUserEntries retrieves all of a user's entries across sections and seasons
+func (r *LeaderboardRepository) GetUserEntries(ctx context.Context, userID uuid.UUID) ([]leaderboard.Entry, error) {
+	stmt := `
+		SELECT user_id, section, season, start_theta, theta, attempts, score, updated_at
+		FROM leaderboard_entries
+		WHERE user_id = @user_id
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	entries, err := pgx.CollectRows(rows, pgx.RowToStructByName[leaderboard.Entry])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetSettings retrieves the user's leaderboard settings, defaulting to visible without a pseudonym
+func (r *LeaderboardRepository) GetSettings(ctx context.Context, userID uuid.UUID) (*leaderboard.Settings, error) {
+	stmt := `
+		SELECT user_id, opt_out, pseudonym, updated_at
+		FROM leaderboard_settings
+		WHERE user_id = @user_id
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	settings, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[leaderboard.Settings])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &leaderboard.Settings{UserID: userID}, nil
+		}
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// SaveSettings stores the user's leaderboard settings
+func (r *LeaderboardRepository) SaveSettings(ctx context.Context, settings *leaderboard.Settings) (*leaderboard.Settings, error) {
+	stmt := `
+		INSERT INTO leaderboard_settings (user_id, opt_out, pseudonym)
+		VALUES (@user_id, @opt_out, @pseudonym)
+		ON CONFLICT (user_id) DO UPDATE SET
+			opt_out = EXCLUDED.opt_out,
+			pseudonym = EXCLUDED.pseudonym,
+			updated_at = NOW()
+		RETURNING user_id, opt_out, pseudonym, updated_at
+	`
+
+	args := pgx.NamedArgs{
+		"user_id":   settings.UserID,
+		"opt_out":   settings.OptOut,
+		"pseudonym": settings.Pseudonym,
+	}
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	saved, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[leaderboard.Settings])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &saved, nil
+}
+
+// === Redis sorted sets ===
+
+// RankingKey returns the Redis sorted set key of a section leaderboard for a season
+func RankingKey(section, season string) string {
+	return "leaderboard:" + season + ":" + section
+}
+
+// HasRanking reports whether the sorted set for a leaderboard has been built
+func (r *LeaderboardRepository) HasRanking(ctx context.Context, key string) (bool, error) {
+	n, err := r.server.Redis.Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check leaderboard ranking: %w", err)
+	}
+	return n > 0, nil
+}
+
+// BuildRanking loads entries into a sorted set; ttl of zero keeps the set indefinitely.
+// The set is built under a temporary key and renamed into place only if no other build
+// got there first, so scores written since the entries were read are never overwritten.
+func (r *LeaderboardRepository) BuildRanking(ctx context.Context, key string, entries []leaderboard.Entry, ttl time.Duration) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	members := make([]redis.Z, 0, len(entries))
+	for _, e := range entries {
+		members = append(members, redis.Z{Score: e.Score, Member: e.UserID.String()})
+	}
+
+	tmpKey := key + ":build:" + uuid.NewString()
+
+	pipe := r.server.Redis.TxPipeline()
+	pipe.ZAdd(ctx, tmpKey, members...)
+	if ttl > 0 {
+		pipe.Expire(ctx, tmpKey, ttl)
+	}
+	pipe.RenameNX(ctx, tmpKey, key)
+	pipe.Del(ctx, tmpKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to build leaderboard ranking: %w", err)
+	}
+
+	return nil
+}
+
+// SetRankingScore sets a user's score in a sorted set
+func (r *LeaderboardRepository) SetRankingScore(ctx context.Context, key string, userID uuid.UUID, score float64) error {
+	if err := r.server.Redis.ZAdd(ctx, key, redis.Z{Score: score, Member: userID.String()}).Err(); err != nil {
+		return fmt.Errorf("failed to set leaderboard score: %w", err)
+	}
+	return nil
+}
+
+// RemoveFromRanking removes a user from a sorted set
+func (r *LeaderboardRepository) RemoveFromRanking(ctx context.Context, key string, userID uuid.UUID) error {
+	if err := r.server.Redis.ZRem(ctx, key, userID.String()).Err(); err != nil {
+		return fmt.Errorf("failed to remove from leaderboard ranking: %w", err)
+	}
+	return nil
+}
+
+// GetRank returns the user's zero-based rank, highest score first, and whether they are ranked
+func (r *LeaderboardRepository) GetRank(ctx context.Context, key string, userID uuid.UUID) (int64, bool, error) {
+	rank, err := r.server.Redis.ZRevRank(ctx, key, userID.String()).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("failed to get leaderboard rank: %w", err)
+	}
+	return rank, true, nil
+}
+
+// GetRankingPage returns limit members from the zero-based offset, highest score first,
+// along with the total number of ranked users
+func (r *LeaderboardRepository) GetRankingPage(ctx context.Context, key string, offset, limit int) ([]redis.Z, int64, error) {
+	pipe := r.server.Redis.Pipeline()
+	total := pipe.ZCard(ctx, key)
+	page := pipe.ZRevRangeWithScores(ctx, key, int64(offset), int64(offset+limit-1))
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, 0, fmt.Errorf("failed to get leaderboard page: %w", err)
+	}
+	return page.Val(), total.Val(), nil
+}
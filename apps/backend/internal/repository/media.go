@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/model/media"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+const mediaColumns = `
+	id, storage_key, thumbnail_key, content_type, size_bytes, width, height, checksum,
+	original_filename, uploaded_by, created_at
+`
+
+type MediaRepository struct {
+	server *server.Server
+}
+
+func NewMediaRepository(server *server.Server) *MediaRepository {
+	return &MediaRepository{server: server}
+}
+
+// Create records an asset whose file has been stored
+func (r *MediaRepository) Create(ctx context.Context, asset *media.Asset) (*media.Asset, error) {
+	rows, err := r.server.DB.Pool.Query(ctx, `
+		INSERT INTO media_assets (
+			id, storage_key, thumbnail_key, content_type, size_bytes, width, height, checksum,
+			original_filename, uploaded_by
+		)
+		VALUES (
+			@id, @storage_key, @thumbnail_key, @content_type, @size_bytes, @width, @height, @checksum,
+			@original_filename, @uploaded_by
+		)
+		RETURNING `+mediaColumns, pgx.NamedArgs{
+		"id":                asset.ID,
+		"storage_key":       asset.StorageKey,
+		"thumbnail_key":     asset.ThumbnailKey,
+		"content_type":      asset.ContentType,
+		"size_bytes":        asset.SizeBytes,
+		"width":             asset.Width,
+		"height":            asset.Height,
+		"checksum":          asset.Checksum,
+		"original_filename": asset.OriginalFilename,
+		"uploaded_by":       asset.UploadedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert media asset: %w", err)
+	}
+
+	created, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[media.Asset])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect media asset: %w", err)
+	}
+
+	return &created, nil
+}
+
+// GetByID retrieves an asset by its ID
+func (r *MediaRepository) GetByID(ctx context.Context, assetID string) (*media.Asset, error) {
+	rows, err := r.server.DB.Pool.Query(ctx, `SELECT `+mediaColumns+` FROM media_assets WHERE id = @id`,
+		pgx.NamedArgs{"id": assetID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	asset, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[media.Asset])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("media not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &asset, nil
+}
+
+// GetByChecksum finds an earlier upload of the same file, or returns nil
+func (r *MediaRepository) GetByChecksum(ctx context.Context, checksum string) (*media.Asset, error) {
+	rows, err := r.server.DB.Pool.Query(ctx, `
+		SELECT `+mediaColumns+` FROM media_assets
+		WHERE checksum = @checksum
+		ORDER BY created_at
+		LIMIT 1
+	`, pgx.NamedArgs{"checksum": checksum})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	asset, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[media.Asset])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &asset, nil
+}
+
+// MissingIDs returns the asset IDs among ids that do not exist
+func (r *MediaRepository) MissingIDs(ctx context.Context, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.server.DB.Pool.Query(ctx, `
+		SELECT wanted::TEXT
+		FROM unnest(@ids::UUID[]) AS wanted
+		WHERE NOT EXISTS (SELECT 1 FROM media_assets WHERE id = wanted)
+	`, pgx.NamedArgs{"ids": ids})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check media assets: %w", err)
+	}
+
+	missing, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect missing media assets: %w", err)
+	}
+
+	return missing, nil
+}
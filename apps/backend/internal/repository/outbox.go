@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/model/event"
+)
+
+// appendEvents writes domain events to the outbox inside the caller's transaction, so they
+// are only published if the state change they describe commits
+func appendEvents(ctx context.Context, tx pgx.Tx, payloads ...event.Payload) error {
+	for _, p := range payloads {
+		evt, err := event.New(p)
+		if err != nil {
+			return err
+		}
+
+		stmt := `
+			INSERT INTO outbox_events (id, type, aggregate_id, payload, occurred_at)
+			VALUES (@id, @type, @aggregate_id, @payload, NOW())
+		`
+
+		_, err = tx.Exec(ctx, stmt, pgx.NamedArgs{
+			"id":           evt.ID,
+			"type":         evt.Type,
+			"aggregate_id": evt.AggregateID,
+			"payload":      evt.Payload,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to append %s event: %w", evt.Type, err)
+		}
+	}
+
+	return nil
+}
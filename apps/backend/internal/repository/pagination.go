@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+// PageParams selects a page of a list. A non-zero Page uses legacy offset pagination and
+// always counts the total; otherwise the list is paged by Cursor (empty for the first page).
+type PageParams struct {
+	Page         int
+	Limit        int
+	Cursor       string
+	IncludeTotal bool
+}
+
+// Page is one page of a list. Cursors are opaque, signed tokens; a nil cursor means there
+// is no page in that direction.
+type Page[T any] struct {
+	Items      []T
+	NextCursor *string
+	PrevCursor *string
+	Total      *int
+}
+
+// keyset orders a list by a timestamp column with a unique tie-breaker, both in the same
+// direction, so a page can resume strictly after the last row of the previous one
+type keyset struct {
+	// name binds cursors to the list that issued them
+	name       string
+	timeColumn string
+	idColumn   string
+	descending bool
+}
+
+// pageQuery is a list query split up so the helper can add keyset conditions and ordering
+type pageQuery struct {
+	columns    string
+	from       string
+	conditions []string
+	args       pgx.NamedArgs
+	keyset     keyset
+}
+
+type cursorDirection string
+
+const (
+	cursorNext cursorDirection = "next"
+	cursorPrev cursorDirection = "prev"
+)
+
+// pageCursor is the decoded content of a cursor token
+type pageCursor struct {
+	List      string          `json:"l"`
+	Time      time.Time       `json:"t"`
+	ID        string          `json:"id"`
+	Direction cursorDirection `json:"d"`
+}
+
+// queryPage runs a list query one page at a time. keyOf returns a row's keyset values.
+func queryPage[T any](ctx context.Context, srv *server.Server, q pageQuery, params PageParams, keyOf func(*T) (time.Time, string)) (*Page[T], error) {
+	secret := []byte(srv.Config.Auth.CursorSigningKey)
+	ks := q.keyset
+
+	page := &Page[T]{}
+	if params.Page > 0 || params.IncludeTotal {
+		var total int
+		countStmt := "SELECT COUNT(*) " + q.from + whereClause(q.conditions)
+		if err := srv.DB.Pool.QueryRow(ctx, countStmt, q.args).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", ks.name, err)
+		}
+		page.Total = &total
+	}
+
+	var cursor *pageCursor
+	if params.Page == 0 && params.Cursor != "" {
+		c, err := decodeCursor(secret, params.Cursor)
+		if err != nil || c.List != ks.name {
+			return nil, errs.NewBadRequestError("invalid cursor", false, nil,
+				[]errs.FieldError{{Field: "cursor", Error: "malformed or issued by another list"}}, nil)
+		}
+		cursor = c
+	}
+
+	// A prev cursor walks the order backwards from the first row of the current page
+	backward := cursor != nil && cursor.Direction == cursorPrev
+	descending := ks.descending != backward
+
+	conditions := slices.Clone(q.conditions)
+	if cursor != nil {
+		op := ">"
+		if descending {
+			op = "<"
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s, %s) %s (@cursor_time, @cursor_id)", ks.timeColumn, ks.idColumn, op))
+		q.args["cursor_time"] = cursor.Time
+		q.args["cursor_id"] = cursor.ID
+	}
+
+	order := "ASC"
+	if descending {
+		order = "DESC"
+	}
+	stmt := "SELECT " + q.columns + " " + q.from + whereClause(conditions) +
+		fmt.Sprintf(" ORDER BY %s %s, %s %s LIMIT @page_limit", ks.timeColumn, order, ks.idColumn, order)
+
+	// One extra row tells whether another page exists in the walking direction
+	q.args["page_limit"] = params.Limit + 1
+	if params.Page > 0 {
+		stmt += " OFFSET @page_offset"
+		q.args["page_offset"] = (params.Page - 1) * params.Limit
+	}
+
+	rows, err := srv.DB.Pool.Query(ctx, stmt, q.args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", ks.name, err)
+	}
+
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[T])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect %s: %w", ks.name, err)
+	}
+
+	hasMore := len(items) > params.Limit
+	if hasMore {
+		items = items[:params.Limit]
+	}
+	if backward {
+		slices.Reverse(items)
+	}
+	page.Items = items
+
+	if params.Page > 0 || len(items) == 0 {
+		return page, nil
+	}
+
+	cursorAt := func(item *T, dir cursorDirection) *string {
+		t, id := keyOf(item)
+		token := encodeCursor(secret, pageCursor{List: ks.name, Time: t, ID: id, Direction: dir})
+		return &token
+	}
+
+	// Walking forward, there is a later page if rows were left over and an earlier one if we
+	// came from a cursor; walking backward it is the other way round
+	if backward || hasMore {
+		page.NextCursor = cursorAt(&items[len(items)-1], cursorNext)
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		page.PrevCursor = cursorAt(&items[0], cursorPrev)
+	}
+
+	return page, nil
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + joinConditions(conditions)
+}
+
+// encodeCursor serializes and signs a cursor as "<payload>.<signature>", both base64url
+func encodeCursor(secret []byte, c pageCursor) string {
+	payload, _ := json.Marshal(c)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// decodeCursor verifies a cursor's signature and parses it
+func decodeCursor(secret []byte, token string) (*pageCursor, error) {
+	encodedPayload, encodedSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, fmt.Errorf("cursor has no signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor signature encoding: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, fmt.Errorf("cursor signature mismatch")
+	}
+
+	var c pageCursor
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if c.Direction != cursorNext && c.Direction != cursorPrev {
+		return nil, fmt.Errorf("invalid cursor direction")
+	}
+
+	return &c, nil
+}
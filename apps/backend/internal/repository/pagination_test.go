@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var cursorSecret = []byte("test-cursor-signing-key-0123456789")
+
+// signedCursor signs an arbitrary payload the way encodeCursor does
+func signedCursor(payload string) string {
+	mac := hmac.New(sha256.New, cursorSecret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor pageCursor
+	}{
+		{
+			name:   "next cursor",
+			cursor: pageCursor{List: "attempts", Time: time.Date(2026, 3, 2, 10, 4, 5, 123456000, time.UTC), ID: "9b2f6c1e-0d55-4a3b-9f0e-2f1d7c3a4b5c", Direction: cursorNext},
+		},
+		{
+			name:   "prev cursor",
+			cursor: pageCursor{List: "sessions", Time: time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC), ID: "sess_01", Direction: cursorPrev},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := encodeCursor(cursorSecret, tt.cursor)
+			assert.NotContains(t, token, "=", "tokens are unpadded base64url")
+
+			got, err := decodeCursor(cursorSecret, token)
+			require.NoError(t, err)
+			assert.Equal(t, tt.cursor.List, got.List)
+			assert.True(t, tt.cursor.Time.Equal(got.Time))
+			assert.Equal(t, tt.cursor.ID, got.ID)
+			assert.Equal(t, tt.cursor.Direction, got.Direction)
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	valid := encodeCursor(cursorSecret, pageCursor{List: "attempts", Time: time.Now().UTC(), ID: "a", Direction: cursorNext})
+	payload, sig, _ := strings.Cut(valid, ".")
+
+	// Swap the payload for another list while keeping the original signature
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"l":"sessions","t":"2026-03-02T00:00:00Z","id":"a","d":"next"}`))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "no signature", token: payload},
+		{name: "payload not base64", token: "!!!." + sig},
+		{name: "signature not base64", token: payload + ".!!!"},
+		{name: "tampered payload", token: forged + "." + sig},
+		{name: "truncated signature", token: payload + "." + sig[:len(sig)-4]},
+		{name: "signed with another key", token: encodeCursor([]byte("another-key"), pageCursor{List: "attempts", ID: "a", Direction: cursorNext})},
+		{name: "signed garbage", token: signedCursor("not json")},
+		{name: "unknown direction", token: signedCursor(`{"l":"attempts","t":"2026-03-02T00:00:00Z","id":"a","d":"sideways"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := decodeCursor(cursorSecret, tt.token)
+			assert.Error(t, err)
+			assert.Nil(t, c)
+		})
+	}
+}
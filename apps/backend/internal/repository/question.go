@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/manikandareas/genta/internal/errs"
@@ -11,6 +12,23 @@ import (
 	"github.com/manikandareas/genta/internal/server"
 )
 
+const questionColumns = `
+	id, question_bank_id, section, sub_type, stimulus_id,
+	difficulty_irt, discrimination, guessing_param,
+	text, option_a, option_b, option_c, option_d, option_e, correct_answer,
+	explanation, explanation_en, strategy_tip, related_concept, solution_steps,
+	is_active, attempt_count, correct_rate, avg_time_seconds,
+	created_at, updated_at, deleted_at
+`
+
+// questionSearchMatch matches @q against the stemmed, accent-folded search vector, or
+// fuzzily (trigram word similarity) against the question text and related concept
+const questionSearchMatch = `(
+	search_vector @@ websearch_to_tsquery('genta_id', @q)
+	OR immutable_unaccent(lower(@q)) <% immutable_unaccent(lower(text))
+	OR immutable_unaccent(lower(@q)) <% immutable_unaccent(lower(related_concept))
+)`
+
 type QuestionRepository struct {
 	server *server.Server
 }
@@ -22,7 +40,7 @@ func NewQuestionRepository(server *server.Server) *QuestionRepository {
 // GetByID retrieves a question by its ID
 func (r *QuestionRepository) GetByID(ctx context.Context, questionID string) (*question.Question, error) {
 	stmt := `
-		SELECT id, question_bank_id, section, sub_type, 
+		SELECT id, question_bank_id, section, sub_type, stimulus_id,
 			difficulty_irt, discrimination, guessing_param,
 			text, option_a, option_b, option_c, option_d, option_e, correct_answer,
 			explanation, explanation_en, strategy_tip, related_concept, solution_steps,
@@ -48,13 +66,9 @@ func (r *QuestionRepository) GetByID(ctx context.Context, questionID string) (*q
 	return &q, nil
 }
 
-// List retrieves questions with optional filtering and pagination
-func (r *QuestionRepository) List(ctx context.Context, req *question.ListQuestionsRequest) ([]question.Question, int, error) {
-	offset := (req.Page - 1) * req.Limit
-	args := pgx.NamedArgs{
-		"limit":  req.Limit,
-		"offset": offset,
-	}
+// List retrieves questions with optional filtering, newest first, one page at a time
+func (r *QuestionRepository) List(ctx context.Context, req *question.ListQuestionsRequest, params PageParams) (*Page[question.Question], error) {
+	args := pgx.NamedArgs{}
 
 	// Build WHERE conditions
 	conditions := []string{"deleted_at IS NULL", "is_active = true"}
@@ -84,41 +98,76 @@ func (r *QuestionRepository) List(ctx context.Context, req *question.ListQuestio
 		args["is_reviewed"] = *req.IsReviewed
 	}
 
-	whereClause := "WHERE " + joinConditions(conditions)
+	if req.Q != nil {
+		conditions = append(conditions, questionSearchMatch)
+		args["q"] = *req.Q
+	}
 
-	// Count total
-	var total int
-	countStmt := "SELECT COUNT(*) FROM questions " + whereClause
-	err := r.server.DB.Pool.QueryRow(ctx, countStmt, args).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count questions: %w", err)
+	return queryPage(ctx, r.server, pageQuery{
+		columns:    questionColumns,
+		from:       "FROM questions",
+		conditions: conditions,
+		args:       args,
+		keyset:     keyset{name: "questions", timeColumn: "created_at", idColumn: "id", descending: true},
+	}, params, func(q *question.Question) (time.Time, string) {
+		return q.CreatedAt, q.ID.String()
+	})
+}
+
+// Search ranks questions matching a text query by relevance. Full-text matches weigh the
+// question text above options and concept, and those above explanations; trigram word
+// similarity lets typos and half-remembered phrases still match. Snippets are HTML: the
+// source is escaped, so <mark> is the only markup in them.
+func (r *QuestionRepository) Search(ctx context.Context, req *question.SearchQuestionsRequest) ([]question.SearchResult, error) {
+	args := pgx.NamedArgs{
+		"q":     req.Q,
+		"limit": req.Limit,
+	}
+
+	conditions := []string{"deleted_at IS NULL", "is_active = true", questionSearchMatch}
+
+	if req.Section != nil {
+		conditions = append(conditions, "section = @section")
+		args["section"] = *req.Section
+	}
+
+	if req.SubType != nil {
+		conditions = append(conditions, "sub_type = @sub_type")
+		args["sub_type"] = *req.SubType
 	}
 
-	// Get questions
 	stmt := `
-		SELECT id, question_bank_id, section, sub_type, 
-			difficulty_irt, discrimination, guessing_param,
-			text, option_a, option_b, option_c, option_d, option_e, correct_answer,
-			explanation, explanation_en, strategy_tip, related_concept, solution_steps,
-			is_active, attempt_count, correct_rate, avg_time_seconds,
-			created_at, updated_at, deleted_at
-		FROM questions 
-		` + whereClause + `
-		ORDER BY created_at DESC
-		LIMIT @limit OFFSET @offset
+		SELECT ` + questionColumns + `,
+			ts_headline('genta_id',
+				replace(replace(replace(
+					text || ' / ' || option_a || ' / ' || option_b || ' / ' || option_c || ' / ' ||
+						option_d || ' / ' || option_e || COALESCE(' / ' || related_concept, ''),
+				'&', '&amp;'), '<', '&lt;'), '>', '&gt;'),
+				websearch_to_tsquery('genta_id', @q),
+				'StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=" … "'
+			) AS snippet,
+			ts_rank_cd(search_vector, websearch_to_tsquery('genta_id', @q))
+				+ GREATEST(
+					word_similarity(immutable_unaccent(lower(@q)), immutable_unaccent(lower(text))),
+					COALESCE(word_similarity(immutable_unaccent(lower(@q)), immutable_unaccent(lower(related_concept))), 0)
+				) AS rank
+		FROM questions
+		WHERE ` + joinConditions(conditions) + `
+		ORDER BY rank DESC, id
+		LIMIT @limit
 	`
 
 	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to execute query: %w", err)
+		return nil, fmt.Errorf("failed to search questions: %w", err)
 	}
 
-	questions, err := pgx.CollectRows(rows, pgx.RowToStructByName[question.Question])
+	results, err := pgx.CollectRows(rows, pgx.RowToStructByName[question.SearchResult])
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to collect rows: %w", err)
+		return nil, fmt.Errorf("failed to collect search results: %w", err)
 	}
 
-	return questions, total, nil
+	return results, nil
 }
 
 // joinConditions joins conditions with AND
@@ -140,7 +189,7 @@ func (r *QuestionRepository) GetNextForUser(ctx context.Context, userID string,
 	// For now, use a simpler approach: get a random question from the section
 	// that the user hasn't attempted in the last 24 hours
 	stmt := `
-		SELECT q.id, q.question_bank_id, q.section, q.sub_type, 
+		SELECT q.id, q.question_bank_id, q.section, q.sub_type, q.stimulus_id,
 			q.difficulty_irt, q.discrimination, q.guessing_param,
 			q.text, q.option_a, q.option_b, q.option_c, q.option_d, q.option_e, q.correct_answer,
 			q.explanation, q.explanation_en, q.strategy_tip, q.related_concept, q.solution_steps,
@@ -185,7 +234,7 @@ func (r *QuestionRepository) GetNextForUser(ctx context.Context, userID string,
 // getRandomFromSection gets a random question from a section (fallback)
 func (r *QuestionRepository) getRandomFromSection(ctx context.Context, section string) (*question.Question, error) {
 	stmt := `
-		SELECT id, question_bank_id, section, sub_type, 
+		SELECT id, question_bank_id, section, sub_type, stimulus_id,
 			difficulty_irt, discrimination, guessing_param,
 			text, option_a, option_b, option_c, option_d, option_e, correct_answer,
 			explanation, explanation_en, strategy_tip, related_concept, solution_steps,
@@ -212,3 +261,76 @@ func (r *QuestionRepository) getRandomFromSection(ctx context.Context, section s
 
 	return &q, nil
 }
+
+// UpdateContent replaces a question's text and options and links or unlinks its stimulus.
+// The request holds sanitized content; a linked stimulus must be from the question's section.
+func (r *QuestionRepository) UpdateContent(ctx context.Context, req *question.UpdateQuestionContentRequest) (*question.Question, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var section string
+	err = tx.QueryRow(ctx, `SELECT section FROM questions WHERE id = @id AND deleted_at IS NULL FOR UPDATE`,
+		pgx.NamedArgs{"id": req.ID}).Scan(&section)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("question not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to lock question: %w", err)
+	}
+
+	if req.StimulusID != nil {
+		var stimulusSection string
+		err = tx.QueryRow(ctx, `SELECT section FROM stimuli WHERE id = @id AND deleted_at IS NULL`,
+			pgx.NamedArgs{"id": *req.StimulusID}).Scan(&stimulusSection)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get stimulus: %w", err)
+		}
+		if err != nil || stimulusSection != section {
+			return nil, errs.NewBadRequestError("unknown stimulus or stimulus from another section", false, nil,
+				[]errs.FieldError{{Field: "stimulus_id", Error: "must be a stimulus of section " + section}}, nil)
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+		UPDATE questions
+		SET text = @text,
+			option_a = @option_a,
+			option_b = @option_b,
+			option_c = @option_c,
+			option_d = @option_d,
+			option_e = @option_e,
+			stimulus_id = @stimulus_id,
+			explanation = COALESCE(@explanation, explanation),
+			explanation_en = COALESCE(@explanation_en, explanation_en),
+			updated_at = NOW()
+		WHERE id = @id
+		RETURNING `+questionColumns, pgx.NamedArgs{
+		"id":             req.ID,
+		"text":           req.Text,
+		"option_a":       req.Options[0],
+		"option_b":       req.Options[1],
+		"option_c":       req.Options[2],
+		"option_d":       req.Options[3],
+		"option_e":       req.Options[4],
+		"stimulus_id":    req.StimulusID,
+		"explanation":    req.Explanation,
+		"explanation_en": req.ExplanationEn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update question content: %w", err)
+	}
+
+	q, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[question.Question])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect updated question: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &q, nil
+}
@@ -436,3 +436,207 @@ func (r *ReadinessRepository) GetOverallStats(ctx context.Context, userID uuid.U
 
 	return totalAttempts, totalCorrect, nil
 }
+
+// GetSectionResponses retrieves the user's scored answers in a section with item parameters,
+// oldest first, optionally only those answered before a point in time
+func (r *ReadinessRepository) GetSectionResponses(ctx context.Context, userID uuid.UUID, section string, before *time.Time) ([]readiness.ItemResponse, error) {
+	stmt := `
+		SELECT q.difficulty_irt, q.discrimination, a.is_correct, a.created_at AS answered_at
+		FROM attempts a
+		JOIN questions q ON a.question_id = q.id
+		WHERE a.user_id = @user_id AND q.section = @section AND a.deleted_at IS NULL
+			AND (@before::TIMESTAMP IS NULL OR a.created_at < @before::TIMESTAMP)
+		ORDER BY a.created_at
+	`
+
+	args := pgx.NamedArgs{
+		"user_id": userID,
+		"section": section,
+		"before":  before,
+	}
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	responses, err := pgx.CollectRows(rows, pgx.RowToStructByName[readiness.ItemResponse])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return responses, nil
+}
+
+// GetSectionResponsesAfter retrieves the user's scored answers in a section given after a
+// point in time, oldest first
+func (r *ReadinessRepository) GetSectionResponsesAfter(ctx context.Context, userID uuid.UUID, section string, after time.Time) ([]readiness.ItemResponse, error) {
+	stmt := `
+		SELECT q.difficulty_irt, q.discrimination, a.is_correct, a.created_at AS answered_at
+		FROM attempts a
+		JOIN questions q ON a.question_id = q.id
+		WHERE a.user_id = @user_id AND q.section = @section AND a.deleted_at IS NULL
+			AND a.created_at > @after
+		ORDER BY a.created_at
+	`
+
+	args := pgx.NamedArgs{
+		"user_id": userID,
+		"section": section,
+		"after":   after,
+	}
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	responses, err := pgx.CollectRows(rows, pgx.RowToStructByName[readiness.ItemResponse])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return responses, nil
+}
+
+// UpdatePrediction stores the section theta estimate with the answers it covers and the
+// predicted score, and recalculates readiness percentage against the target theta
+func (r *ReadinessRepository) UpdatePrediction(ctx context.Context, userID uuid.UUID, section string, theta, thetaSE float64, thetaThrough *time.Time, thetaResponses int, score, low, high int, version string) error {
+	stmt := `
+		UPDATE user_readiness
+		SET 
+			current_theta = @current_theta,
+			theta_se = @theta_se,
+			theta_through = @theta_through,
+			theta_responses = @theta_responses,
+			predicted_score = @predicted_score,
+			predicted_score_low = @predicted_score_low,
+			predicted_score_high = @predicted_score_high,
+			prediction_version = @prediction_version,
+			readiness_percentage = CASE 
+				WHEN target_theta IS NOT NULL AND target_theta != 0 
+				THEN GREATEST(LEAST((@current_theta / target_theta) * 100, 100), 0)
+				ELSE 0 
+			END,
+			last_updated = NOW()
+		WHERE user_id = @user_id AND section = @section
+	`
+
+	args := pgx.NamedArgs{
+		"user_id":              userID,
+		"section":              section,
+		"current_theta":        theta,
+		"theta_se":             thetaSE,
+		"theta_through":        thetaThrough,
+		"theta_responses":      thetaResponses,
+		"predicted_score":      score,
+		"predicted_score_low":  low,
+		"predicted_score_high": high,
+		"prediction_version":   version,
+	}
+
+	_, err := r.server.DB.Pool.Exec(ctx, stmt, args)
+	if err != nil {
+		return fmt.Errorf("failed to update prediction: %w", err)
+	}
+
+	return nil
+}
+
+// InsertSnapshot records the section's current readiness in its history
+func (r *ReadinessRepository) InsertSnapshot(ctx context.Context, userID uuid.UUID, section string) error {
+	stmt := `
+		INSERT INTO readiness_snapshots (user_id, section, theta, theta_se, readiness_percentage, predicted_score, total_attempts)
+		SELECT user_id, section, current_theta, theta_se, readiness_percentage, predicted_score, COALESCE(total_attempts_count, 0)
+		FROM user_readiness
+		WHERE user_id = @user_id AND section = @section
+	`
+
+	args := pgx.NamedArgs{
+		"user_id": userID,
+		"section": section,
+	}
+
+	_, err := r.server.DB.Pool.Exec(ctx, stmt, args)
+	if err != nil {
+		return fmt.Errorf("failed to insert readiness snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// GetHistory retrieves each section's closing readiness per day or week between two dates.
+// Days already rolled up come from readiness_daily; later days from the raw snapshots.
+func (r *ReadinessRepository) GetHistory(ctx context.Context, userID uuid.UUID, section, granularity string, from, to time.Time) ([]readiness.HistoryPoint, error) {
+	stmt := `
+		WITH daily AS (
+			SELECT section, day, theta, theta_se, readiness_percentage, predicted_score, total_attempts
+			FROM readiness_daily
+			WHERE user_id = @user_id 
+				AND day BETWEEN @from::DATE AND @to::DATE
+				AND (@section::TEXT = '' OR section = @section::TEXT)
+			UNION ALL
+			(
+				SELECT DISTINCT ON (s.section, DATE(s.recorded_at))
+					s.section, DATE(s.recorded_at), s.theta, s.theta_se, s.readiness_percentage, s.predicted_score, s.total_attempts
+				FROM readiness_snapshots s
+				WHERE s.user_id = @user_id 
+					AND DATE(s.recorded_at) BETWEEN @from::DATE AND @to::DATE
+					AND (@section::TEXT = '' OR s.section = @section::TEXT)
+					AND NOT EXISTS (
+						SELECT 1 FROM readiness_daily d 
+						WHERE d.user_id = s.user_id AND d.section = s.section AND d.day = DATE(s.recorded_at)
+					)
+				ORDER BY s.section, DATE(s.recorded_at), s.recorded_at DESC
+			)
+		)
+		SELECT DISTINCT ON (section, period_start)
+			section, period_start, theta, theta_se, readiness_percentage, predicted_score, total_attempts
+		FROM (
+			SELECT *, DATE_TRUNC(@granularity::TEXT, day::TIMESTAMP)::DATE AS period_start FROM daily
+		) periods
+		ORDER BY section, period_start, day DESC
+	`
+
+	args := pgx.NamedArgs{
+		"user_id":     userID,
+		"section":     section,
+		"granularity": granularity,
+		"from":        from,
+		"to":          to,
+	}
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	points, err := pgx.CollectRows(rows, pgx.RowToStructByName[readiness.HistoryPoint])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return points, nil
+}
+
+// GetPercentiles retrieves the user's materialized percentile ranks, ordered by cohort and section
+func (r *ReadinessRepository) GetPercentiles(ctx context.Context, userID uuid.UUID) ([]readiness.Percentile, error) {
+	stmt := `
+		SELECT cohort, cohort_key, section, percentile, cohort_size, computed_at
+		FROM readiness_percentiles
+		WHERE user_id = @user_id
+		ORDER BY cohort, section
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	percentiles, err := pgx.CollectRows(rows, pgx.RowToStructByName[readiness.Percentile])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return percentiles, nil
+}
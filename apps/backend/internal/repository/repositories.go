@@ -3,21 +3,43 @@ package repository
 import "github.com/manikandareas/genta/internal/server"
 
 type Repositories struct {
-	User      *UserRepository
-	Readiness *ReadinessRepository
-	Question  *QuestionRepository
-	Attempt   *AttemptRepository
-	Session   *SessionRepository
-	Analytics *AnalyticsRepository
+	User        *UserRepository
+	Readiness   *ReadinessRepository
+	Question    *QuestionRepository
+	Attempt     *AttemptRepository
+	Session     *SessionRepository
+	Analytics   *AnalyticsRepository
+	Usage       *UsageRepository
+	Draft       *DraftRepository
+	Calibration *CalibrationRepository
+	Admission   *AdmissionRepository
+	StudyPlan   *StudyPlanRepository
+	Leaderboard *LeaderboardRepository
+	Achievement *AchievementRepository
+	Duplicate   *DuplicateRepository
+	Concept     *ConceptRepository
+	Media       *MediaRepository
+	Stimulus    *StimulusRepository
 }
 
 func NewRepositories(s *server.Server) *Repositories {
 	return &Repositories{
-		User:      NewUserRepository(s),
-		Readiness: NewReadinessRepository(s),
-		Question:  NewQuestionRepository(s),
-		Attempt:   NewAttemptRepository(s),
-		Session:   NewSessionRepository(s),
-		Analytics: NewAnalyticsRepository(s),
+		User:        NewUserRepository(s),
+		Readiness:   NewReadinessRepository(s),
+		Question:    NewQuestionRepository(s),
+		Attempt:     NewAttemptRepository(s),
+		Session:     NewSessionRepository(s),
+		Analytics:   NewAnalyticsRepository(s),
+		Usage:       NewUsageRepository(s),
+		Draft:       NewDraftRepository(s),
+		Calibration: NewCalibrationRepository(s),
+		Admission:   NewAdmissionRepository(s),
+		StudyPlan:   NewStudyPlanRepository(s),
+		Leaderboard: NewLeaderboardRepository(s),
+		Achievement: NewAchievementRepository(s),
+		Duplicate:   NewDuplicateRepository(s),
+		Concept:     NewConceptRepository(s),
+		Media:       NewMediaRepository(s),
+		Stimulus:    NewStimulusRepository(s),
 	}
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/model/event"
 	"github.com/manikandareas/genta/internal/model/session"
 	"github.com/manikandareas/genta/internal/server"
 )
@@ -107,45 +108,19 @@ func (r *SessionRepository) GetByIDAndUserID(ctx context.Context, sessionID stri
 	return &sess, nil
 }
 
-// List retrieves sessions for a user with pagination
-func (r *SessionRepository) List(ctx context.Context, userID uuid.UUID, req *session.ListSessionsRequest) ([]session.Session, int, error) {
-	offset := (req.Page - 1) * req.Limit
-	args := pgx.NamedArgs{
-		"user_id": userID,
-		"limit":   req.Limit,
-		"offset":  offset,
-	}
-
-	// Count total
-	countStmt := `SELECT COUNT(*) FROM user_study_sessions WHERE user_id = @user_id`
-	var total int
-	err := r.server.DB.Pool.QueryRow(ctx, countStmt, args).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count sessions: %w", err)
-	}
-
-	// Get sessions
-	stmt := `
-		SELECT id, user_id, started_at, ended_at, duration_minutes,
+// List retrieves sessions for a user, most recently started first, one page at a time
+func (r *SessionRepository) List(ctx context.Context, userID uuid.UUID, params PageParams) (*Page[session.Session], error) {
+	return queryPage(ctx, r.server, pageQuery{
+		columns: `id, user_id, started_at, ended_at, duration_minutes,
 			questions_attempted, questions_correct, accuracy_in_session,
-			section, created_at, updated_at
-		FROM user_study_sessions
-		WHERE user_id = @user_id
-		ORDER BY started_at DESC
-		LIMIT @limit OFFSET @offset
-	`
-
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to execute query: %w", err)
-	}
-
-	sessions, err := pgx.CollectRows(rows, pgx.RowToStructByName[session.Session])
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to collect rows: %w", err)
-	}
-
-	return sessions, total, nil
+			section, created_at, updated_at`,
+		from:       "FROM user_study_sessions",
+		conditions: []string{"user_id = @user_id"},
+		args:       pgx.NamedArgs{"user_id": userID},
+		keyset:     keyset{name: "sessions", timeColumn: "started_at", idColumn: "id", descending: true},
+	}, params, func(s *session.Session) (time.Time, string) {
+		return s.StartedAt, s.ID
+	})
 }
 
 // End ends a session by setting ended_at and calculating duration/accuracy
@@ -194,7 +169,13 @@ func (r *SessionRepository) End(ctx context.Context, sessionID string, userID uu
 		"updated_at":          now,
 	}
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, stmt, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to end session: %w", err)
 	}
@@ -204,34 +185,61 @@ func (r *SessionRepository) End(ctx context.Context, sessionID string, userID uu
 		return nil, fmt.Errorf("failed to collect row: %w", err)
 	}
 
-	return &updatedSess, nil
-}
+	// The event carries the final session stats, so it is built from the updated row
+	ended := event.SessionEnded{
+		SessionID:          updatedSess.ID,
+		UserID:             updatedSess.UserID,
+		Section:            updatedSess.Section,
+		DurationMinutes:    updatedSess.DurationMinutes,
+		QuestionsAttempted: updatedSess.QuestionsAttempted,
+		QuestionsCorrect:   updatedSess.QuestionsCorrect,
+	}
+	if err := appendEvents(ctx, tx, ended); err != nil {
+		return nil, err
+	}
 
-// UpdateStats updates session statistics (called after each attempt)
-func (r *SessionRepository) UpdateStats(ctx context.Context, sessionID string, isCorrect bool) error {
-	correctIncrement := 0
-	if isCorrect {
-		correctIncrement = 1
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit session end: %w", err)
 	}
 
+	return &updatedSess, nil
+}
+
+// GetSummaryAttempts retrieves a session's attempts in answer order, with the question
+// facts and feedback state needed to review them
+func (r *SessionRepository) GetSummaryAttempts(ctx context.Context, sessionID string, userID uuid.UUID) ([]session.SummaryAttempt, error) {
 	stmt := `
-		UPDATE user_study_sessions
-		SET questions_attempted = questions_attempted + 1,
-			questions_correct = questions_correct + @correct_increment,
-			updated_at = @updated_at
-		WHERE id = @id
+		SELECT a.id AS attempt_id, a.attempt_number_in_session, a.question_id,
+			q.text AS question_text, q.section, q.sub_type,
+			a.selected_answer, q.correct_answer, a.is_correct, a.time_spent_seconds,
+			a.user_theta_before, a.user_theta_after, a.theta_change,
+			f.id IS NOT NULL AS has_feedback, f.quality_status AS feedback_quality_status,
+			a.created_at
+		FROM attempts a
+		JOIN questions q ON q.id = a.question_id
+		LEFT JOIN LATERAL (
+			SELECT af.id, af.quality_status
+			FROM attempt_feedback af
+			WHERE af.attempt_id = a.id
+			ORDER BY af.created_at DESC
+			LIMIT 1
+		) f ON true
+		WHERE a.session_id = @session_id AND a.user_id = @user_id AND a.deleted_at IS NULL
+		ORDER BY a.attempt_number_in_session NULLS LAST, a.created_at
 	`
 
-	args := pgx.NamedArgs{
-		"id":                sessionID,
-		"correct_increment": correctIncrement,
-		"updated_at":        time.Now(),
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"session_id": sessionID,
+		"user_id":    userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session attempts: %w", err)
 	}
 
-	_, err := r.server.DB.Pool.Exec(ctx, stmt, args)
+	attempts, err := pgx.CollectRows(rows, pgx.RowToStructByName[session.SummaryAttempt])
 	if err != nil {
-		return fmt.Errorf("failed to update session stats: %w", err)
+		return nil, fmt.Errorf("failed to collect session attempts: %w", err)
 	}
 
-	return nil
+	return attempts, nil
 }
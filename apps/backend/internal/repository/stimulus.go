@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/model/stimulus"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+type StimulusRepository struct {
+	server *server.Server
+}
+
+func NewStimulusRepository(server *server.Server) *StimulusRepository {
+	return &StimulusRepository{server: server}
+}
+
+// GetByID retrieves a stimulus with the number of active questions linked to it
+func (r *StimulusRepository) GetByID(ctx context.Context, stimulusID string) (*stimulus.Stimulus, error) {
+	return getStimulus(ctx, r.server.DB.Pool, stimulusID)
+}
+
+// Create stores a stimulus; content must already be sanitized
+func (r *StimulusRepository) Create(ctx context.Context, req *stimulus.CreateStimulusRequest, createdBy uuid.UUID) (*stimulus.Stimulus, error) {
+	var id uuid.UUID
+	err := r.server.DB.Pool.QueryRow(ctx, `
+		INSERT INTO stimuli (section, title, content, created_by)
+		VALUES (@section, @title, @content, @created_by)
+		RETURNING id
+	`, pgx.NamedArgs{
+		"section":    req.Section,
+		"title":      req.Title,
+		"content":    req.Content,
+		"created_by": createdBy,
+	}).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert stimulus: %w", err)
+	}
+
+	return getStimulus(ctx, r.server.DB.Pool, id.String())
+}
+
+// Update revises a stimulus; nil fields are left unchanged and content must already be
+// sanitized
+func (r *StimulusRepository) Update(ctx context.Context, req *stimulus.UpdateStimulusRequest) (*stimulus.Stimulus, error) {
+	tag, err := r.server.DB.Pool.Exec(ctx, `
+		UPDATE stimuli
+		SET title = COALESCE(@title, title),
+			content = COALESCE(@content, content),
+			updated_at = NOW()
+		WHERE id = @id AND deleted_at IS NULL
+	`, pgx.NamedArgs{
+		"id":      req.ID,
+		"title":   req.Title,
+		"content": req.Content,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update stimulus: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, errs.NewNotFoundError("stimulus not found", false, nil)
+	}
+
+	return getStimulus(ctx, r.server.DB.Pool, req.ID)
+}
+
+func getStimulus(ctx context.Context, q queryer, stimulusID string) (*stimulus.Stimulus, error) {
+	rows, err := q.Query(ctx, `
+		SELECT s.id, s.section, s.title, s.content, s.created_by,
+			s.created_at, s.updated_at, s.deleted_at,
+			(
+				SELECT COUNT(*) FROM questions q
+				WHERE q.stimulus_id = s.id AND q.deleted_at IS NULL AND q.is_active = true
+			) AS question_count
+		FROM stimuli s
+		WHERE s.id = @id AND s.deleted_at IS NULL
+	`, pgx.NamedArgs{"id": stimulusID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	s, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[stimulus.Stimulus])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("stimulus not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &s, nil
+}
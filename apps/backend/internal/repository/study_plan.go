@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/planner"
+	"github.com/manikandareas/genta/internal/model/studyplan"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+type StudyPlanRepository struct {
+	server *server.Server
+}
+
+func NewStudyPlanRepository(server *server.Server) *StudyPlanRepository {
+	return &StudyPlanRepository{server: server}
+}
+
+// GetActive retrieves the user's active plan, or nil when the user has none
+func (r *StudyPlanRepository) GetActive(ctx context.Context, userID uuid.UUID) (*studyplan.StudyPlan, error) {
+	stmt := `SELECT * FROM study_plans WHERE user_id = @user_id AND status = 'active'`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	plan, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[studyplan.StudyPlan])
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	return &plan, nil
+}
+
+// GetItems retrieves a plan's blocks in schedule order
+func (r *StudyPlanRepository) GetItems(ctx context.Context, planID uuid.UUID) ([]studyplan.Item, error) {
+	stmt := `
+		SELECT * FROM study_plan_items
+		WHERE plan_id = @plan_id
+		ORDER BY scheduled_date, position
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"plan_id": planID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	items, err := pgx.CollectRows(rows, pgx.RowToStructByName[studyplan.Item])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return items, nil
+}
+
+// Replace supersedes the user's active plan and stores a new one with its blocks
+func (r *StudyPlanRepository) Replace(ctx context.Context, plan *studyplan.StudyPlan, blocks []planner.Block) (*studyplan.StudyPlan, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the user so concurrent replacements take turns instead of both inserting an active plan
+	var userID uuid.UUID
+	err = tx.QueryRow(ctx, `SELECT id FROM users WHERE id = @user_id AND deleted_at IS NULL FOR UPDATE`,
+		pgx.NamedArgs{"user_id": plan.UserID}).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("user not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE study_plans SET status = 'superseded', updated_at = NOW()
+		WHERE user_id = @user_id AND status = 'active'
+	`, pgx.NamedArgs{"user_id": plan.UserID}); err != nil {
+		return nil, fmt.Errorf("failed to supersede active plan: %w", err)
+	}
+
+	stmt := `
+		INSERT INTO study_plans (user_id, reason, start_date, end_date, exam_date, minutes_per_day)
+		VALUES (@user_id, @reason, @start_date, @end_date, @exam_date, @minutes_per_day)
+		RETURNING *
+	`
+
+	args := pgx.NamedArgs{
+		"user_id":         plan.UserID,
+		"reason":          plan.Reason,
+		"start_date":      plan.StartDate,
+		"end_date":        plan.EndDate,
+		"exam_date":       plan.ExamDate,
+		"minutes_per_day": plan.MinutesPerDay,
+	}
+
+	rows, err := tx.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute insert: %w", err)
+	}
+
+	created, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[studyplan.StudyPlan])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	batch := &pgx.Batch{}
+	for _, b := range blocks {
+		batch.Queue(`
+			INSERT INTO study_plan_items (plan_id, scheduled_date, position, kind, section, sub_type, target_questions, minutes)
+			VALUES (@plan_id, @scheduled_date, @position, @kind, @section, @sub_type, @target_questions, @minutes)
+		`, pgx.NamedArgs{
+			"plan_id":          created.ID,
+			"scheduled_date":   b.Date,
+			"position":         b.Position,
+			"kind":             b.Kind,
+			"section":          b.Section,
+			"sub_type":         b.SubType,
+			"target_questions": b.TargetQuestions,
+			"minutes":          b.Minutes,
+		})
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, fmt.Errorf("failed to insert plan items: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &created, nil
+}
+
+// GetSectionSubtypes retrieves every subtype with active questions, with the user's
+// attempts and correct answers on it
+func (r *StudyPlanRepository) GetSectionSubtypes(ctx context.Context, userID uuid.UUID) ([]studyplan.SectionSubtype, error) {
+	stmt := `
+		SELECT 
+			q.section,
+			q.sub_type,
+			COUNT(a.id) as attempts,
+			COUNT(a.id) FILTER (WHERE a.is_correct) as correct
+		FROM questions q
+		LEFT JOIN attempts a ON a.question_id = q.id 
+			AND a.user_id = @user_id 
+			AND a.deleted_at IS NULL
+		WHERE q.sub_type IS NOT NULL
+			AND q.is_active = true
+			AND q.deleted_at IS NULL
+		GROUP BY q.section, q.sub_type
+		ORDER BY q.section, q.sub_type
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{"user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	subtypes, err := pgx.CollectRows(rows, pgx.RowToStructByName[studyplan.SectionSubtype])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return subtypes, nil
+}
+
+// GetDailySectionCounts retrieves how many questions the user answered per day and
+// section between two dates (inclusive)
+func (r *StudyPlanRepository) GetDailySectionCounts(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]studyplan.DailySectionCount, error) {
+	stmt := `
+		SELECT 
+			DATE(a.created_at) as date,
+			q.section,
+			COUNT(a.id) as attempts
+		FROM attempts a
+		JOIN questions q ON a.question_id = q.id
+		WHERE a.user_id = @user_id 
+			AND DATE(a.created_at) BETWEEN @from::DATE AND @to::DATE
+			AND a.deleted_at IS NULL
+		GROUP BY DATE(a.created_at), q.section
+	`
+
+	args := pgx.NamedArgs{
+		"user_id": userID,
+		"from":    from,
+		"to":      to,
+	}
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	counts, err := pgx.CollectRows(rows, pgx.RowToStructByName[studyplan.DailySectionCount])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect rows: %w", err)
+	}
+
+	return counts, nil
+}
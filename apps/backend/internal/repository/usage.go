@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/manikandareas/genta/internal/model/usage"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+type UsageRepository struct {
+	server *server.Server
+}
+
+func NewUsageRepository(server *server.Server) *UsageRepository {
+	return &UsageRepository{server: server}
+}
+
+// GetUserTotals returns a user's LLM usage since the given date (inclusive)
+func (r *UsageRepository) GetUserTotals(ctx context.Context, userID uuid.UUID, since time.Time) (*usage.UsageTotals, error) {
+	stmt := `
+		SELECT
+			COALESCE(SUM(request_count), 0)::INTEGER AS request_count,
+			COALESCE(SUM(tokens_input), 0)::BIGINT AS tokens_input,
+			COALESCE(SUM(tokens_output), 0)::BIGINT AS tokens_output,
+			COALESCE(SUM(cost_usd), 0)::FLOAT8 AS cost_usd
+		FROM llm_usage_daily
+		WHERE user_id = @user_id AND usage_date >= @since
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"user_id": userID,
+		"since":   since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user llm usage: %w", err)
+	}
+
+	totals, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[usage.UsageTotals])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect user llm usage: %w", err)
+	}
+
+	return &totals, nil
+}
+
+// GetGlobalTotals returns LLM usage across all users since the given date (inclusive)
+func (r *UsageRepository) GetGlobalTotals(ctx context.Context, since time.Time) (*usage.UsageTotals, error) {
+	stmt := `
+		SELECT
+			COALESCE(SUM(request_count), 0)::INTEGER AS request_count,
+			COALESCE(SUM(tokens_input), 0)::BIGINT AS tokens_input,
+			COALESCE(SUM(tokens_output), 0)::BIGINT AS tokens_output,
+			COALESCE(SUM(cost_usd), 0)::FLOAT8 AS cost_usd
+		FROM llm_usage_daily
+		WHERE usage_date >= @since
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"since": since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query global llm usage: %w", err)
+	}
+
+	totals, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[usage.UsageTotals])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect global llm usage: %w", err)
+	}
+
+	return &totals, nil
+}
+
+// GetTotalsBetween returns LLM usage across all users between from and to (inclusive)
+func (r *UsageRepository) GetTotalsBetween(ctx context.Context, from, to time.Time) (*usage.UsageTotals, error) {
+	stmt := `
+		SELECT
+			COALESCE(SUM(request_count), 0)::INTEGER AS request_count,
+			COALESCE(SUM(tokens_input), 0)::BIGINT AS tokens_input,
+			COALESCE(SUM(tokens_output), 0)::BIGINT AS tokens_output,
+			COALESCE(SUM(cost_usd), 0)::FLOAT8 AS cost_usd
+		FROM llm_usage_daily
+		WHERE usage_date BETWEEN @from AND @to
+	`
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"from": from,
+		"to":   to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query llm usage totals: %w", err)
+	}
+
+	totals, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[usage.UsageTotals])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect llm usage totals: %w", err)
+	}
+
+	return &totals, nil
+}
+
+// GetReport returns LLM usage between from and to (inclusive), grouped by day, model or user
+func (r *UsageRepository) GetReport(ctx context.Context, from, to time.Time, groupBy string, limit int) ([]usage.UsageReportRow, error) {
+	var keyExpr string
+	switch groupBy {
+	case "model":
+		keyExpr = "u.model"
+	case "user":
+		// System spend has no user and is grouped by its purpose instead
+		keyExpr = "COALESCE(u.user_id::TEXT, u.purpose)"
+	default:
+		keyExpr = "TO_CHAR(u.usage_date, 'YYYY-MM-DD')"
+	}
+
+	orderBy := "cost_usd DESC"
+	if groupBy == "day" {
+		orderBy = "key ASC"
+	}
+
+	stmt := fmt.Sprintf(`
+		SELECT
+			%s AS key,
+			COALESCE(SUM(u.request_count), 0)::INTEGER AS request_count,
+			COALESCE(SUM(u.tokens_input), 0)::BIGINT AS tokens_input,
+			COALESCE(SUM(u.tokens_output), 0)::BIGINT AS tokens_output,
+			COALESCE(SUM(u.cost_usd), 0)::FLOAT8 AS cost_usd
+		FROM llm_usage_daily u
+		WHERE u.usage_date BETWEEN @from AND @to
+		GROUP BY 1
+		ORDER BY %s
+		LIMIT @limit
+	`, keyExpr, orderBy)
+
+	rows, err := r.server.DB.Pool.Query(ctx, stmt, pgx.NamedArgs{
+		"from":  from,
+		"to":    to,
+		"limit": limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query llm usage report: %w", err)
+	}
+
+	report, err := pgx.CollectRows(rows, pgx.RowToStructByName[usage.UsageReportRow])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect llm usage report: %w", err)
+	}
+
+	return report, nil
+}
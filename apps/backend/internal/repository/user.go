@@ -8,6 +8,7 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/model/event"
 	"github.com/manikandareas/genta/internal/model/user"
 	"github.com/manikandareas/genta/internal/server"
 )
@@ -37,6 +38,20 @@ func (r *UserRepository) PutUser(ctx context.Context, userID string, request *us
 		args["target_ptn"] = *request.TargetPtn
 	}
 
+	if request.TargetPtnID != nil {
+		setClauses = append(setClauses, "target_ptn_id = @target_ptn_id")
+		args["target_ptn_id"] = *request.TargetPtnID
+	} else if request.ClearTargetPtnID {
+		setClauses = append(setClauses, "target_ptn_id = NULL")
+	}
+
+	if request.TargetProgramID != nil {
+		setClauses = append(setClauses, "target_program_id = @target_program_id")
+		args["target_program_id"] = *request.TargetProgramID
+	} else if request.ClearTargetProgram {
+		setClauses = append(setClauses, "target_program_id = NULL")
+	}
+
 	if request.TargetScore != nil {
 		setClauses = append(setClauses, "target_score = @target_score")
 		args["target_score"] = *request.TargetScore
@@ -57,9 +72,25 @@ func (r *UserRepository) PutUser(ctx context.Context, userID string, request *us
 		args["onboarding_completed"] = *request.OnboardingCompleted
 	}
 
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the row so only the update that completes onboarding raises the event
+	var wasOnboarded bool
+	err = tx.QueryRow(ctx, "SELECT onboarding_completed FROM users WHERE id = @id AND deleted_at IS NULL FOR UPDATE", args).Scan(&wasOnboarded)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("user not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to lock user: %w", err)
+	}
+
 	stmt := "UPDATE users SET " + strings.Join(setClauses, ", ") + " WHERE id = @id AND deleted_at IS NULL RETURNING *"
 
-	rows, err := r.server.DB.Pool.Query(ctx, stmt, args)
+	rows, err := tx.Query(ctx, stmt, args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -72,6 +103,97 @@ func (r *UserRepository) PutUser(ctx context.Context, userID string, request *us
 		return nil, fmt.Errorf("failed to collect row: %w", err)
 	}
 
+	if !wasOnboarded && updatedUser.OnboardingCompleted {
+		completed := event.OnboardingCompleted{
+			UserID:   updatedUser.ID,
+			Email:    updatedUser.Email,
+			FullName: updatedUser.FullName,
+		}
+		if err := appendEvents(ctx, tx, completed); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit user update: %w", err)
+	}
+
+	return &updatedUser, nil
+}
+
+// UpdateSubscription sets a user's subscription tier. Moving onto a paid tier, or renewing
+// an inactive one, starts a new subscription and raises SubscriptionActivated.
+func (r *UserRepository) UpdateSubscription(ctx context.Context, req *user.UpdateSubscriptionRequest) (*user.User, error) {
+	tx, err := r.server.DB.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	args := pgx.NamedArgs{
+		"id":       req.UserID,
+		"tier":     req.Tier,
+		"end_date": req.EndDate,
+	}
+
+	// Lock the row so only the update that starts the subscription raises the event
+	var tier string
+	var wasActive bool
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(subscription_tier, 'free'), COALESCE(is_subscription_active, false)
+		FROM users WHERE id = @id AND deleted_at IS NULL
+		FOR UPDATE
+	`, args).Scan(&tier, &wasActive)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.NewNotFoundError("user not found", false, nil)
+		}
+		return nil, fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	paid := req.Tier != "free"
+	args["paid"] = paid
+	args["activated"] = paid && (!wasActive || tier != req.Tier)
+
+	rows, err := tx.Query(ctx, `
+		UPDATE users
+		SET subscription_tier = @tier,
+			subscription_start_date = CASE WHEN @activated THEN NOW() ELSE subscription_start_date END,
+			subscription_end_date = CASE
+				WHEN @paid THEN @end_date
+				WHEN is_subscription_active THEN NOW()
+				ELSE subscription_end_date
+			END,
+			is_subscription_active = @paid,
+			updated_at = NOW()
+		WHERE id = @id
+		RETURNING *
+	`, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update subscription: %w", err)
+	}
+
+	updatedUser, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[user.User])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect row: %w", err)
+	}
+
+	if args["activated"] == true {
+		activated := event.SubscriptionActivated{
+			UserID:    updatedUser.ID,
+			Tier:      updatedUser.SubscriptionTier,
+			StartDate: *updatedUser.SubscriptionStartDate,
+			EndDate:   updatedUser.SubscriptionEndDate,
+		}
+		if err := appendEvents(ctx, tx, activated); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit subscription update: %w", err)
+	}
+
 	return &updatedUser, nil
 }
 
@@ -0,0 +1,18 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/handler"
+	"github.com/manikandareas/genta/internal/middleware"
+)
+
+func registerAchievementRoutes(r *echo.Group, h *handler.AchievementHandler, auth *middleware.AuthMiddleware) {
+	achievements := r.Group("/achievements")
+	achievements.Use(auth.RequireAuth)
+
+	// Get the achievements catalogue with the caller's unlocks
+	achievements.GET("", h.GetAchievements)
+
+	// Acknowledge unlock notifications
+	achievements.POST("/seen", h.MarkAchievementsSeen)
+}
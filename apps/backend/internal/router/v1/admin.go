@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/handler"
+	"github.com/manikandareas/genta/internal/middleware"
+)
+
+func registerAdminRoutes(r *echo.Group, usageHandler *handler.UsageHandler, feedbackQualityHandler *handler.FeedbackQualityHandler, calibrationHandler *handler.CalibrationHandler, admissionHandler *handler.AdmissionHandler, conceptHandler *handler.ConceptHandler, userHandler *handler.UserHandler, auth *middleware.AuthMiddleware) {
+	admin := r.Group("/admin")
+	admin.Use(auth.RequireAuth)
+	admin.Use(auth.RequireRole(middleware.RoleAdmin))
+
+	// Subscription changes
+	admin.PUT("/users/:id/subscription", userHandler.UpdateSubscription)
+
+	// LLM usage and spend report
+	admin.GET("/llm-usage", usageHandler.GetReport)
+
+	// Generated feedback flagged by quality evaluation
+	feedback := admin.Group("/feedback")
+	feedback.GET("/flagged", feedbackQualityHandler.ListFlagged)
+	feedback.POST("/:id/review", feedbackQualityHandler.Review)
+
+	// Score prediction calibration from tryout data
+	admin.POST("/tryout-results", calibrationHandler.ImportTryoutResults)
+	calibrations := admin.Group("/score-calibrations")
+	calibrations.GET("", calibrationHandler.ListCalibrations)
+	calibrations.POST("", calibrationHandler.FitCalibration)
+	calibrations.POST("/:version/activate", calibrationHandler.ActivateCalibration)
+
+	// PTN and study program catalogue import
+	admin.POST("/ptn-catalogue/import", admissionHandler.ImportCatalogue)
+
+	// Replay attempt history into concept mastery
+	admin.POST("/concept-mastery/rebuild", conceptHandler.RebuildConceptMastery)
+}
@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/handler"
+	"github.com/manikandareas/genta/internal/middleware"
+)
+
+func registerAdmissionRoutes(r *echo.Group, h *handler.AdmissionHandler, auth *middleware.AuthMiddleware) {
+	// PTN and study program catalogue
+	ptn := r.Group("/ptn")
+	ptn.Use(auth.RequireAuth)
+	ptn.GET("", h.SearchPTN)
+	ptn.GET("/:id/programs", h.ListPrograms)
+
+	// Admission chance estimate
+	admission := r.Group("/admission")
+	admission.Use(auth.RequireAuth)
+	admission.GET("/chance", h.GetChance)
+}
@@ -12,4 +12,7 @@ func registerAnalyticsRoutes(r *echo.Group, h *handler.AnalyticsHandler, auth *m
 
 	// Get progress analytics
 	analytics.GET("/progress", h.GetProgress)
+
+	// Get pacing analytics against UTBK time budgets
+	analytics.GET("/pacing", h.GetPacing)
 }
@@ -13,6 +13,12 @@ func registerAttemptRoutes(r *echo.Group, h *handler.AttemptHandler, auth *middl
 	// Create new attempt (record answer)
 	attempts.POST("", h.CreateAttempt)
 
+	// List attempt history (cursor paginated)
+	attempts.GET("", h.ListAttempts)
+
+	// Questions answered wrong, for "retry wrong answers" practice
+	attempts.GET("/retry", h.GetRetryQueue)
+
 	// Get attempt by ID with details
 	attempts.GET("/:attempt_id", h.GetAttempt)
 
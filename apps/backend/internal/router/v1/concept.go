@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/handler"
+	"github.com/manikandareas/genta/internal/middleware"
+)
+
+func registerConceptRoutes(r *echo.Group, h *handler.ConceptHandler, auth *middleware.AuthMiddleware) {
+	concepts := r.Group("/concepts")
+	concepts.Use(auth.RequireAuth)
+
+	// Get the concept taxonomy
+	concepts.GET("", h.GetConcepts)
+
+	// Get the caller's mastery per concept
+	concepts.GET("/mastery", h.GetConceptMastery)
+
+	// Get the concepts holding back the caller's readiness
+	concepts.GET("/blockers", h.GetReadinessBlockers)
+}
@@ -0,0 +1,18 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/handler"
+	"github.com/manikandareas/genta/internal/middleware"
+)
+
+func registerContentRoutes(r *echo.Group, h *handler.ContentHandler, auth *middleware.AuthMiddleware) {
+	// Media is public so content images can be loaded without an auth header
+	r.GET("/media/:id", h.GetMedia)
+
+	stimuli := r.Group("/stimuli")
+	stimuli.Use(auth.RequireAuth)
+
+	// Get a shared passage, table or figure
+	stimuli.GET("/:id", h.GetStimulus)
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/handler"
+	"github.com/manikandareas/genta/internal/middleware"
+)
+
+func registerEditorRoutes(r *echo.Group, h *handler.AuthoringHandler, duplicateHandler *handler.DuplicateHandler, conceptHandler *handler.ConceptHandler, contentHandler *handler.ContentHandler, auth *middleware.AuthMiddleware) {
+	editor := r.Group("/editor")
+	editor.Use(auth.RequireAuth)
+	editor.Use(auth.RequireRole(middleware.RoleEditor, middleware.RoleAdmin))
+
+	// LLM-assisted authoring (results land in the draft review queue)
+	editor.POST("/questions/enrich-batch", h.EnrichBatch)
+	editor.POST("/questions/:id/enrich", h.EnrichQuestion)
+	editor.POST("/questions/:id/variants", h.GenerateVariants)
+
+	// Rich content: images, shared stimuli and question text
+	editor.POST("/media", contentHandler.UploadMedia)
+	editor.POST("/media/uploads", contentHandler.CreateMediaUpload)
+	editor.POST("/media/uploads/:id/complete", contentHandler.CompleteMediaUpload)
+	editor.POST("/stimuli", contentHandler.CreateStimulus)
+	editor.PATCH("/stimuli/:id", contentHandler.UpdateStimulus)
+	editor.PUT("/questions/:id/content", contentHandler.UpdateQuestionContent)
+	editor.POST("/content/preview", contentHandler.PreviewContent)
+
+	// Concept taxonomy and question tagging
+	editor.POST("/concepts", conceptHandler.CreateConcept)
+	editor.PUT("/questions/:id/concepts", conceptHandler.SetQuestionConcepts)
+
+	// Draft review workflow
+	editor.GET("/drafts", h.ListDrafts)
+	editor.GET("/drafts/:id", h.GetDraft)
+	editor.PATCH("/drafts/:id", h.UpdateDraft)
+	editor.POST("/drafts/:id/approve", h.ApproveDraft)
+	editor.POST("/drafts/:id/reject", h.RejectDraft)
+
+	// Duplicate question review
+	editor.POST("/duplicates/scan", duplicateHandler.ScanDuplicates)
+	editor.GET("/duplicates", duplicateHandler.ListDuplicateClusters)
+	editor.GET("/duplicates/:id", duplicateHandler.GetDuplicateCluster)
+	editor.POST("/duplicates/:id/resolve", duplicateHandler.ResolveDuplicateCluster)
+}
@@ -0,0 +1,19 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/handler"
+	"github.com/manikandareas/genta/internal/middleware"
+)
+
+func registerLeaderboardRoutes(r *echo.Group, h *handler.LeaderboardHandler, auth *middleware.AuthMiddleware) {
+	leaderboards := r.Group("/leaderboards")
+	leaderboards.Use(auth.RequireAuth)
+
+	// Get or update the caller's leaderboard privacy settings
+	leaderboards.GET("/settings", h.GetLeaderboardSettings)
+	leaderboards.PATCH("/settings", h.UpdateLeaderboardSettings)
+
+	// Get a page of a section leaderboard
+	leaderboards.GET("/:section", h.GetLeaderboard)
+}
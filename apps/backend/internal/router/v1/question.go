@@ -13,6 +13,9 @@ func registerQuestionRoutes(r *echo.Group, h *handler.QuestionHandler, auth *mid
 	// List questions with optional section filter
 	questions.GET("", h.ListQuestions)
 
+	// Search questions by content
+	questions.GET("/search", h.SearchQuestions)
+
 	// Get next question for practice (adaptive)
 	questions.GET("/next", h.GetNextQuestion)
 
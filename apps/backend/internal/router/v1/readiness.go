@@ -13,6 +13,12 @@ func registerReadinessRoutes(r *echo.Group, h *handler.ReadinessHandler, auth *m
 	// Get overall readiness overview
 	readiness.GET("", h.GetReadinessOverview)
 
+	// Get theta and readiness time series
+	readiness.GET("/history", h.GetReadinessHistory)
+
+	// Get cohort percentile ranks
+	readiness.GET("/percentiles", h.GetReadinessPercentiles)
+
 	// Get section-specific readiness detail
 	readiness.GET("/:section", h.GetSectionReadiness)
 
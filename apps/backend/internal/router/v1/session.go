@@ -19,6 +19,10 @@ func registerSessionRoutes(r *echo.Group, h *handler.SessionHandler, auth *middl
 	// Get session by ID
 	sessions.GET("/:session_id", h.GetSession)
 
+	// Session summary with per-question review, as JSON or PDF
+	sessions.GET("/:session_id/summary", h.GetSessionSummary)
+	sessions.GET("/:session_id/summary/pdf", h.DownloadSessionSummary)
+
 	// End a session
 	sessions.PUT("/:session_id/end", h.EndSession)
 }
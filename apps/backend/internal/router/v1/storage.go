@@ -0,0 +1,14 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/handler"
+)
+
+func registerStorageRoutes(r *echo.Group, h *handler.StorageHandler) {
+	// Public: presigned URLs carry their own signature in place of an auth header
+	storage := r.Group("/storage")
+
+	storage.GET("/objects/*", h.GetObject)
+	storage.POST("/uploads", h.Upload)
+}
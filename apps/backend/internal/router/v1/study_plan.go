@@ -0,0 +1,15 @@
+package v1
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/handler"
+	"github.com/manikandareas/genta/internal/middleware"
+)
+
+func registerStudyPlanRoutes(r *echo.Group, h *handler.StudyPlanHandler, auth *middleware.AuthMiddleware) {
+	studyPlan := r.Group("/study-plan")
+	studyPlan.Use(auth.RequireAuth)
+
+	studyPlan.GET("", h.GetStudyPlan)
+	studyPlan.POST("", h.GenerateStudyPlan)
+}
@@ -25,6 +25,33 @@ func RegisterV1Routes(router *echo.Group, handlers *handler.Handlers, middleware
 	// analytics routes
 	registerAnalyticsRoutes(router, handlers.Analytics, middleware.Auth)
 
+	// admission routes
+	registerAdmissionRoutes(router, handlers.Admission, middleware.Auth)
+
+	// study plan routes
+	registerStudyPlanRoutes(router, handlers.StudyPlan, middleware.Auth)
+
+	// leaderboard routes
+	registerLeaderboardRoutes(router, handlers.Leaderboard, middleware.Auth)
+
+	// achievement routes
+	registerAchievementRoutes(router, handlers.Achievement, middleware.Auth)
+
+	// concept routes
+	registerConceptRoutes(router, handlers.Concept, middleware.Auth)
+
+	// media and stimulus routes
+	registerContentRoutes(router, handlers.Content, middleware.Auth)
+
+	// presigned storage routes
+	registerStorageRoutes(router, handlers.Storage)
+
 	// job routes
 	registerJobRoutes(router, handlers.Job, middleware.Auth)
+
+	// admin routes
+	registerAdminRoutes(router, handlers.Usage, handlers.FeedbackQuality, handlers.Calibration, handlers.Admission, handlers.Concept, handlers.User, middleware.Auth)
+
+	// editor routes
+	registerEditorRoutes(router, handlers.Authoring, handlers.Duplicate, handlers.Concept, handlers.Content, middleware.Auth)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/manikandareas/genta/internal/config"
 	"github.com/manikandareas/genta/internal/database"
 	"github.com/manikandareas/genta/internal/lib/job"
+	"github.com/manikandareas/genta/internal/lib/storage"
 	loggerPkg "github.com/manikandareas/genta/internal/logger"
 	"github.com/newrelic/go-agent/v3/integrations/nrredis-v9"
 	"github.com/redis/go-redis/v9"
@@ -24,6 +25,7 @@ type Server struct {
 	Redis         *redis.Client
 	httpServer    *http.Server
 	Job           *job.JobService
+	Storage       storage.Storage
 }
 
 func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.LoggerService) (*Server, error) {
@@ -51,6 +53,11 @@ func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.Lo
 		// Don't fail startup if Redis is unavailable
 	}
 
+	mediaStorage, err := storage.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
 	// job service
 	jobService := job.NewJobService(logger, cfg)
 	jobService.InitHandlers(cfg, logger)
@@ -68,6 +75,7 @@ func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.Lo
 		DB:            db,
 		Redis:         redisClient,
 		Job:           jobService,
+		Storage:       mediaStorage,
 	}
 
 	// Start metrics collection
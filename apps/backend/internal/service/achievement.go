@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/llm"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/achievement"
+	"github.com/manikandareas/genta/internal/repository"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+type AchievementService struct {
+	server          *server.Server
+	achievementRepo *repository.AchievementRepository
+	readinessRepo   *repository.ReadinessRepository
+	sessionRepo     *repository.SessionRepository
+	userRepo        *repository.UserRepository
+}
+
+func NewAchievementService(
+	server *server.Server,
+	achievementRepo *repository.AchievementRepository,
+	readinessRepo *repository.ReadinessRepository,
+	sessionRepo *repository.SessionRepository,
+	userRepo *repository.UserRepository,
+) *AchievementService {
+	return &AchievementService{
+		server:          server,
+		achievementRepo: achievementRepo,
+		readinessRepo:   readinessRepo,
+		sessionRepo:     sessionRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// Evaluate checks the rules triggered by the user's events and awards every achievement
+// whose threshold is met. It returns the newly unlocked achievements for notification.
+func (s *AchievementService) Evaluate(ctx context.Context, userID uuid.UUID, events ...achievement.Event) ([]achievement.UnlockedResponse, error) {
+	unlocked, err := s.achievementRepo.GetUnlocked(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	has := make(map[string]bool, len(unlocked))
+	for _, u := range unlocked {
+		has[u.Code] = true
+	}
+
+	// Metrics are shared by several rules, so each is measured at most once per evaluation
+	measured := make(map[string]float64)
+	measure := func(def achievement.Definition, event achievement.Event) (float64, error) {
+		key := string(def.Metric) + "|" + def.Section + "|" + event.SessionID
+		if v, ok := measured[key]; ok {
+			return v, nil
+		}
+		v, err := s.measure(ctx, userID, def, event)
+		if err != nil {
+			return 0, err
+		}
+		measured[key] = v
+		return v, nil
+	}
+
+	var awarded []achievement.UnlockedResponse
+	for _, def := range achievement.Catalogue() {
+		if has[def.Code] {
+			continue
+		}
+		for _, event := range events {
+			if !def.TriggeredBy(event) {
+				continue
+			}
+
+			value, err := measure(def, event)
+			if err != nil {
+				return awarded, err
+			}
+			if value < def.Threshold {
+				continue
+			}
+
+			u, err := s.achievementRepo.Unlock(ctx, userID, def.Code, value)
+			if err != nil {
+				return awarded, err
+			}
+			has[def.Code] = true
+			if u != nil {
+				awarded = append(awarded, def.ToUnlockedResponse(llm.LangIndonesian, u))
+				s.server.Logger.Info().
+					Str("event", "achievement_unlocked").
+					Str("user_id", userID.String()).
+					Str("code", def.Code).
+					Float64("value", value).
+					Msg("Achievement unlocked")
+			}
+			break
+		}
+	}
+
+	return awarded, nil
+}
+
+// measure returns the current value of a rule's metric for the user
+func (s *AchievementService) measure(ctx context.Context, userID uuid.UUID, def achievement.Definition, event achievement.Event) (float64, error) {
+	switch def.Metric {
+	case achievement.MetricQuestionsAnswered:
+		count, err := s.achievementRepo.CountAnswered(ctx, userID, def.Section)
+		return float64(count), err
+
+	case achievement.MetricStreakDays:
+		days, err := s.achievementRepo.GetStreakDays(ctx, userID, achievement.StreakUTCOffset)
+		return float64(days), err
+
+	case achievement.MetricReadinessPercentage:
+		ur, err := s.readinessRepo.GetBySection(ctx, userID, def.Section)
+		if err != nil {
+			var httpErr *errs.HTTPError
+			if errors.As(err, &httpErr) && httpErr.Status == 404 {
+				return 0, nil
+			}
+			return 0, err
+		}
+		if ur.ReadinessPercentage == nil {
+			return 0, nil
+		}
+		return *ur.ReadinessPercentage, nil
+
+	case achievement.MetricPerfectSession:
+		if event.SessionID == "" {
+			return 0, nil
+		}
+		sess, err := s.sessionRepo.GetByIDAndUserID(ctx, event.SessionID, userID)
+		if err != nil {
+			return 0, err
+		}
+		if sess.QuestionsCorrect != sess.QuestionsAttempted {
+			return 0, nil
+		}
+		return float64(sess.QuestionsAttempted), nil
+	}
+
+	return 0, nil
+}
+
+// GetCatalogue returns every achievement with the caller's unlocks
+func (s *AchievementService) GetCatalogue(ctx echo.Context, clerkID string, req *achievement.GetCatalogueRequest) (*achievement.CatalogueResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	user, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	unlocked, err := s.achievementRepo.GetUnlocked(ctx.Request().Context(), user.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get unlocked achievements")
+		return nil, err
+	}
+	byCode := make(map[string]*achievement.UserAchievement, len(unlocked))
+	for i := range unlocked {
+		byCode[unlocked[i].Code] = &unlocked[i]
+	}
+
+	lang := llm.Language(req.Lang)
+	catalogue := achievement.Catalogue()
+	response := &achievement.CatalogueResponse{
+		Total:        len(catalogue),
+		Achievements: make([]achievement.AchievementResponse, 0, len(catalogue)),
+	}
+	for _, def := range catalogue {
+		u := byCode[def.Code]
+		if u != nil {
+			response.Unlocked++
+			if u.SeenAt == nil {
+				response.Unseen++
+			}
+		}
+		response.Achievements = append(response.Achievements, def.ToResponse(lang, u))
+	}
+
+	return response, nil
+}
+
+// MarkSeen acknowledges the caller's unlock notifications
+func (s *AchievementService) MarkSeen(ctx echo.Context, clerkID string, req *achievement.MarkSeenRequest) (*achievement.MarkSeenResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	user, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	marked, err := s.achievementRepo.MarkSeen(ctx.Request().Context(), user.ID, req.Codes)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to mark achievements seen")
+		return nil, err
+	}
+
+	return &achievement.MarkSeenResponse{Marked: marked}, nil
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/scoring"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/admission"
+	"github.com/manikandareas/genta/internal/repository"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+// catalogueRequiredColumns are the CSV columns every catalogue row needs. Optional columns:
+// ptn_short_name, city, province, cluster, capacity, applicants.
+var catalogueRequiredColumns = []string{"ptn_code", "ptn_name", "program_code", "program_name", "year", "score_low", "score_high"}
+
+type AdmissionService struct {
+	server           *server.Server
+	admissionRepo    *repository.AdmissionRepository
+	userRepo         *repository.UserRepository
+	readinessService *ReadinessService
+}
+
+func NewAdmissionService(
+	server *server.Server,
+	admissionRepo *repository.AdmissionRepository,
+	userRepo *repository.UserRepository,
+	readinessService *ReadinessService,
+) *AdmissionService {
+	return &AdmissionService{
+		server:           server,
+		admissionRepo:    admissionRepo,
+		userRepo:         userRepo,
+		readinessService: readinessService,
+	}
+}
+
+// ResolvedTarget is a user's target PTN/program validated against the catalogue
+type ResolvedTarget struct {
+	PtnName   *string
+	PtnID     *uuid.UUID
+	ProgramID *uuid.UUID
+}
+
+// ResolveTarget validates a target PTN (code, short name or name) and optional program
+// against the catalogue. While the catalogue is empty the free-text PTN is accepted as is.
+func (s *AdmissionService) ResolveTarget(ctx context.Context, targetPtn *string, targetProgramID *string) (*ResolvedTarget, error) {
+	resolved := &ResolvedTarget{PtnName: targetPtn}
+
+	if targetProgramID != nil {
+		programID, _ := uuid.Parse(*targetProgramID)
+		program, err := s.admissionRepo.GetProgram(ctx, programID)
+		if err != nil {
+			var httpErr *errs.HTTPError
+			if errors.As(err, &httpErr) && httpErr.Status == 404 {
+				return nil, errs.NewBadRequestError("unknown target program", false, nil,
+					[]errs.FieldError{{Field: "targetProgramId", Error: "not found in catalogue"}}, nil)
+			}
+			return nil, err
+		}
+
+		if targetPtn != nil {
+			ptn, err := s.admissionRepo.ResolvePTN(ctx, strings.TrimSpace(*targetPtn))
+			if err != nil {
+				return nil, err
+			}
+			if ptn == nil || ptn.ID != program.PtnID {
+				return nil, errs.NewBadRequestError("target program does not belong to target PTN", false, nil,
+					[]errs.FieldError{{Field: "targetProgramId", Error: "does not belong to targetPtn"}}, nil)
+			}
+		}
+
+		resolved.PtnName = &program.PtnName
+		resolved.PtnID = &program.PtnID
+		resolved.ProgramID = &program.ID
+		return resolved, nil
+	}
+
+	if targetPtn == nil {
+		return resolved, nil
+	}
+
+	ptn, err := s.admissionRepo.ResolvePTN(ctx, strings.TrimSpace(*targetPtn))
+	if err != nil {
+		return nil, err
+	}
+	if ptn == nil {
+		count, err := s.admissionRepo.CountPTN(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return resolved, nil
+		}
+		return nil, errs.NewBadRequestError("unknown target PTN", false, nil,
+			[]errs.FieldError{{Field: "targetPtn", Error: "not found in catalogue, see GET /api/v1/ptn?search="}}, nil)
+	}
+
+	resolved.PtnName = &ptn.Name
+	resolved.PtnID = &ptn.ID
+	return resolved, nil
+}
+
+// SearchPTN searches the PTN catalogue
+func (s *AdmissionService) SearchPTN(ctx echo.Context, req *admission.ListPTNRequest) ([]admission.PTNResponse, error) {
+	ptns, err := s.admissionRepo.SearchPTN(ctx.Request().Context(), req.Search, req.Limit)
+	if err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Msg("failed to search ptn")
+		return nil, err
+	}
+
+	responses := make([]admission.PTNResponse, len(ptns))
+	for i := range ptns {
+		responses[i] = ptns[i].ToResponse()
+	}
+	return responses, nil
+}
+
+// ListPrograms lists a PTN's study programs with their latest passing-score range
+func (s *AdmissionService) ListPrograms(ctx echo.Context, req *admission.ListProgramsRequest) ([]admission.StudyProgramResponse, error) {
+	programs, err := s.admissionRepo.ListPrograms(ctx.Request().Context(), req)
+	if err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Str("ptn_id", req.PtnID).Msg("failed to list study programs")
+		return nil, err
+	}
+
+	responses := make([]admission.StudyProgramResponse, len(programs))
+	for i := range programs {
+		responses[i] = programs[i].ToResponse()
+	}
+	return responses, nil
+}
+
+// GetChance estimates the admission probability for a program from the user's predicted
+// composite score
+func (s *AdmissionService) GetChance(ctx echo.Context, clerkID string, req *admission.GetAdmissionChanceRequest) (*admission.AdmissionChanceResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	u, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	var programID uuid.UUID
+	switch {
+	case req.ProgramID != nil:
+		programID, _ = uuid.Parse(*req.ProgramID)
+	case u.TargetProgramID != nil:
+		programID = *u.TargetProgramID
+	default:
+		return nil, errs.NewBadRequestError("program_id is required when no target program is set", false, nil, nil, nil)
+	}
+
+	program, err := s.admissionRepo.GetProgram(ctx.Request().Context(), programID)
+	if err != nil {
+		return nil, err
+	}
+	if program.ScoreLow == nil || program.ScoreHigh == nil {
+		return nil, errs.NewBadRequestError("no passing-score data for this program", false, nil, nil, nil)
+	}
+
+	composite, version, err := s.readinessService.PredictComposite(ctx.Request().Context(), u.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to predict composite score")
+		return nil, err
+	}
+
+	passing := (*program.ScoreLow + *program.ScoreHigh) / 2
+	probability := scoring.AdmissionProbability(composite, *program.ScoreLow, *program.ScoreHigh)
+	probability = math.Round(probability*1000) / 1000
+
+	response := &admission.AdmissionChanceResponse{
+		Program:            program.ToResponse(),
+		PredictedComposite: int(math.Round(composite.Score)),
+		PredictedLow:       composite.Low,
+		PredictedHigh:      composite.High,
+		PredictionVersion:  version,
+		PassingScore:       math.Round(passing*100) / 100,
+		GapToPassing:       int(math.Round(passing - composite.Score)),
+		Probability:        probability,
+		Category:           admission.ChanceCategory(probability),
+	}
+	if program.Capacity != nil && program.Applicants != nil && *program.Capacity > 0 {
+		selectivity := math.Round(float64(*program.Applicants)/float64(*program.Capacity)*100) / 100
+		response.Selectivity = &selectivity
+	}
+
+	logger.Info().
+		Str("event", "admission_chance_estimated").
+		Str("user_id", u.ID.String()).
+		Str("program_id", program.ID.String()).
+		Float64("probability", probability).
+		Msg("Admission chance estimated")
+
+	return response, nil
+}
+
+// ImportCatalogue parses a catalogue CSV and upserts its PTNs, programs and passing scores
+func (s *AdmissionService) ImportCatalogue(ctx echo.Context, r io.Reader) (*admission.ImportCatalogueResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	rows, err := parseCatalogueCSV(r)
+	if err != nil {
+		return nil, errs.NewBadRequestError(err.Error(), true, nil, nil, nil)
+	}
+	if len(rows) == 0 {
+		return nil, errs.NewBadRequestError("catalogue CSV has no rows", false, nil, nil, nil)
+	}
+
+	ptns, programs, err := s.admissionRepo.ImportCatalogue(ctx.Request().Context(), rows)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to import ptn catalogue")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "ptn_catalogue_imported").
+		Int("rows", len(rows)).
+		Int("ptns", ptns).
+		Int("programs", programs).
+		Msg("PTN catalogue imported")
+
+	return &admission.ImportCatalogueResponse{Rows: len(rows), PTNs: ptns, Programs: programs}, nil
+}
+
+// parseCatalogueCSV reads catalogue rows keyed by header name
+func parseCatalogueCSV(r io.Reader) ([]admission.CatalogueRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range catalogueRequiredColumns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("missing required column %q", name)
+		}
+	}
+
+	var rows []admission.CatalogueRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		get := func(name string) string {
+			i, ok := index[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := admission.CatalogueRow{
+			PtnCode:     get("ptn_code"),
+			PtnName:     get("ptn_name"),
+			PtnShort:    get("ptn_short_name"),
+			City:        get("city"),
+			Province:    get("province"),
+			ProgramCode: get("program_code"),
+			ProgramName: get("program_name"),
+			Cluster:     strings.ToLower(get("cluster")),
+		}
+		for _, name := range catalogueRequiredColumns {
+			if get(name) == "" {
+				return nil, fmt.Errorf("line %d: %s is required", line, name)
+			}
+		}
+		if row.Cluster != "" && row.Cluster != "saintek" && row.Cluster != "soshum" {
+			return nil, fmt.Errorf("line %d: cluster must be saintek or soshum", line)
+		}
+
+		year, err := strconv.ParseInt(get("year"), 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid year", line)
+		}
+		row.Year = int16(year)
+
+		if row.ScoreLow, err = strconv.ParseFloat(get("score_low"), 64); err != nil {
+			return nil, fmt.Errorf("line %d: invalid score_low", line)
+		}
+		if row.ScoreHigh, err = strconv.ParseFloat(get("score_high"), 64); err != nil {
+			return nil, fmt.Errorf("line %d: invalid score_high", line)
+		}
+		if row.ScoreHigh < row.ScoreLow {
+			return nil, fmt.Errorf("line %d: score_high must not be below score_low", line)
+		}
+
+		if row.Capacity, err = optionalInt(get("capacity")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid capacity", line)
+		}
+		if row.Applicants, err = optionalInt(get("applicants")); err != nil {
+			return nil, fmt.Errorf("line %d: invalid applicants", line)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func optionalInt(s string) (*int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
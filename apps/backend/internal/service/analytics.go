@@ -1,6 +1,12 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/manikandareas/genta/internal/errs"
 	"github.com/manikandareas/genta/internal/middleware"
@@ -9,6 +15,9 @@ import (
 	"github.com/manikandareas/genta/internal/server"
 )
 
+// maxAnalyticsPeriodDays caps the length of an analytics period
+const maxAnalyticsPeriodDays = 366
+
 type AnalyticsService struct {
 	server        *server.Server
 	analyticsRepo *repository.AnalyticsRepository
@@ -41,25 +50,151 @@ func (s *AnalyticsService) GetProgress(ctx echo.Context, clerkID string, req *an
 	// Set defaults
 	req.SetDefaults()
 
+	period, err := resolvePeriod(req.From, req.To, req.Days, req.Timezone, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	filter := period.filter(req)
+
 	// Get progress analytics from repository
-	progress, err := s.analyticsRepo.GetProgressAnalytics(ctx.Request().Context(), user.ID, req.Days, req.Section)
+	progress, err := s.analyticsRepo.GetProgressAnalytics(ctx.Request().Context(), user.ID, filter, req.Breakdowns)
 	if err != nil {
 		logger.Error().Err(err).
-			Int("days", req.Days).
-			Str("section", req.Section).
+			Str("from", period.from.Format("2006-01-02")).
+			Str("to", period.to.Format("2006-01-02")).
+			Strs("sections", req.Sections).
 			Msg("failed to get progress analytics")
 		return nil, err
 	}
+	progress.PeriodDays = period.days()
 
 	// Convert to response
 	response := progress.ToResponse()
+	response.From = period.from.Format("2006-01-02")
+	response.To = period.to.Format("2006-01-02")
+	response.Timezone = req.Timezone
+	response.Sections = req.Sections
+	response.Granularity = req.Granularity
+
+	if comparePeriod, ok, err := resolveComparePeriod(req, period); err != nil {
+		return nil, err
+	} else if ok {
+		response.Comparison, err = s.comparePeriods(ctx.Request().Context(), user.ID, req, period, comparePeriod)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to compare analytics periods")
+			return nil, err
+		}
+	}
 
 	logger.Debug().
 		Str("user_id", user.ID.String()).
-		Int("period_days", req.Days).
+		Int("period_days", response.PeriodDays).
 		Int("total_attempts", response.TotalQuestionsAttempted).
 		Float64("average_accuracy", response.AverageAccuracy).
 		Msg("Progress analytics retrieved")
 
 	return &response, nil
 }
+
+// comparePeriods compares the requested period's stats with another period
+func (s *AnalyticsService) comparePeriods(
+	ctx context.Context,
+	userID uuid.UUID,
+	req *analytics.GetProgressRequest,
+	current, previous analyticsPeriod,
+) (*analytics.PeriodComparisonResponse, error) {
+	currentStats, err := s.analyticsRepo.GetPeriodStats(ctx, userID, current.filter(req))
+	if err != nil {
+		return nil, err
+	}
+	previousStats, err := s.analyticsRepo.GetPeriodStats(ctx, userID, previous.filter(req))
+	if err != nil {
+		return nil, err
+	}
+
+	return &analytics.PeriodComparisonResponse{
+		Current:        current.statsResponse(currentStats),
+		Previous:       previous.statsResponse(previousStats),
+		AccuracyChange: math.Round((currentStats.Accuracy-previousStats.Accuracy)*1000) / 10,
+		AttemptsChange: currentStats.Attempts - previousStats.Attempts,
+		AvgTimeChange:  math.Round((currentStats.AvgTimeSeconds-previousStats.AvgTimeSeconds)*10) / 10,
+	}, nil
+}
+
+// analyticsPeriod is an inclusive range of local dates in a UTC offset
+type analyticsPeriod struct {
+	from      time.Time
+	to        time.Time
+	utcOffset int
+}
+
+// resolvePeriod builds the period from explicit dates, or the last `days` days up to today
+// in the user's time zone
+func resolvePeriod(fromStr, toStr string, days int, timezone string, now time.Time) (analyticsPeriod, error) {
+	offset := analytics.TimezoneOffsets[timezone]
+	loc := time.FixedZone(timezone, offset*3600)
+	localNow := now.In(loc)
+	today := time.Date(localNow.Year(), localNow.Month(), localNow.Day(), 0, 0, 0, 0, loc)
+
+	period := analyticsPeriod{to: today, utcOffset: offset}
+	if toStr != "" {
+		period.to, _ = time.ParseInLocation("2006-01-02", toStr, loc)
+	}
+	if fromStr != "" {
+		period.from, _ = time.ParseInLocation("2006-01-02", fromStr, loc)
+	} else {
+		period.from = period.to.AddDate(0, 0, -days+1)
+	}
+
+	if period.from.After(period.to) {
+		return period, errs.NewBadRequestError("from must not be after to", false, nil, nil, nil)
+	}
+	if period.days() > maxAnalyticsPeriodDays {
+		return period, errs.NewBadRequestError(fmt.Sprintf("period cannot exceed %d days", maxAnalyticsPeriodDays), false, nil, nil, nil)
+	}
+
+	return period, nil
+}
+
+// resolveComparePeriod returns the period to compare against, if one was requested
+func resolveComparePeriod(req *analytics.GetProgressRequest, period analyticsPeriod) (analyticsPeriod, bool, error) {
+	if req.CompareFrom != "" {
+		compare, err := resolvePeriod(req.CompareFrom, req.CompareTo, 0, req.Timezone, time.Now())
+		return compare, err == nil, err
+	}
+	if req.Compare == "previous" {
+		days := period.days()
+		return analyticsPeriod{
+			from:      period.from.AddDate(0, 0, -days),
+			to:        period.from.AddDate(0, 0, -1),
+			utcOffset: period.utcOffset,
+		}, true, nil
+	}
+	return analyticsPeriod{}, false, nil
+}
+
+func (p analyticsPeriod) days() int {
+	return int(p.to.Sub(p.from).Hours()/24) + 1
+}
+
+// filter converts the period to UTC bounds for querying
+func (p analyticsPeriod) filter(req *analytics.GetProgressRequest) analytics.Filter {
+	return analytics.Filter{
+		From:        p.from.UTC(),
+		To:          p.to.AddDate(0, 0, 1).UTC(),
+		UTCOffset:   p.utcOffset,
+		Sections:    req.Sections,
+		Granularity: req.Granularity,
+	}
+}
+
+func (p analyticsPeriod) statsResponse(stats *analytics.PeriodStats) analytics.PeriodStatsResponse {
+	return analytics.PeriodStatsResponse{
+		From:           p.from.Format("2006-01-02"),
+		To:             p.to.Format("2006-01-02"),
+		Attempts:       stats.Attempts,
+		Correct:        stats.Correct,
+		Accuracy:       stats.Accuracy,
+		AvgTimeSeconds: stats.AvgTimeSeconds,
+	}
+}
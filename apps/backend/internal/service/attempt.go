@@ -1,22 +1,39 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/manikandareas/genta/internal/errs"
 	"github.com/manikandareas/genta/internal/lib/job"
+	"github.com/manikandareas/genta/internal/lib/llm"
 	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/achievement"
 	"github.com/manikandareas/genta/internal/model/attempt"
+	"github.com/manikandareas/genta/internal/model/event"
+	"github.com/manikandareas/genta/internal/model/usage"
 	"github.com/manikandareas/genta/internal/repository"
 	"github.com/manikandareas/genta/internal/server"
 )
 
+// personalizedFeedbackTier is the subscription tier that gets feedback tailored to the
+// student's profile instead of canonical cached feedback
+const personalizedFeedbackTier = "premium_plus"
+
 type AttemptService struct {
-	server       *server.Server
-	attemptRepo  *repository.AttemptRepository
-	questionRepo *repository.QuestionRepository
-	userRepo     *repository.UserRepository
-	jobService   *job.JobService
+	server             *server.Server
+	attemptRepo        *repository.AttemptRepository
+	questionRepo       *repository.QuestionRepository
+	userRepo           *repository.UserRepository
+	sessionRepo        *repository.SessionRepository
+	jobService         *job.JobService
+	usageService       *UsageService
+	readinessService   *ReadinessService
+	leaderboardService *LeaderboardService
+	achievementService *AchievementService
 }
 
 func NewAttemptService(
@@ -24,14 +41,24 @@ func NewAttemptService(
 	attemptRepo *repository.AttemptRepository,
 	questionRepo *repository.QuestionRepository,
 	userRepo *repository.UserRepository,
+	sessionRepo *repository.SessionRepository,
 	jobService *job.JobService,
+	usageService *UsageService,
+	readinessService *ReadinessService,
+	leaderboardService *LeaderboardService,
+	achievementService *AchievementService,
 ) *AttemptService {
 	return &AttemptService{
-		server:       server,
-		attemptRepo:  attemptRepo,
-		questionRepo: questionRepo,
-		userRepo:     userRepo,
-		jobService:   jobService,
+		server:             server,
+		attemptRepo:        attemptRepo,
+		questionRepo:       questionRepo,
+		userRepo:           userRepo,
+		sessionRepo:        sessionRepo,
+		jobService:         jobService,
+		usageService:       usageService,
+		readinessService:   readinessService,
+		leaderboardService: leaderboardService,
+		achievementService: achievementService,
 	}
 }
 
@@ -53,6 +80,26 @@ func (s *AttemptService) Create(ctx echo.Context, clerkID string, req *attempt.C
 		return nil, err
 	}
 
+	// The session must be the user's own, still open, and cover the question's section
+	sess, err := s.sessionRepo.GetByIDAndUserID(ctx.Request().Context(), req.SessionID, user.ID)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) && httpErr.Status == 404 {
+			return nil, errs.NewBadRequestError("unknown session", false, nil,
+				[]errs.FieldError{{Field: "session_id", Error: "not found"}}, nil)
+		}
+		logger.Error().Err(err).Str("session_id", req.SessionID).Msg("failed to get session")
+		return nil, err
+	}
+	if !sess.IsOpen() {
+		return nil, errs.NewBadRequestError("session already ended", false, nil,
+			[]errs.FieldError{{Field: "session_id", Error: "session has ended"}}, nil)
+	}
+	if sess.Section != nil && *sess.Section != string(question.Section) {
+		return nil, errs.NewBadRequestError("question is outside the session's section", false, nil,
+			[]errs.FieldError{{Field: "question_id", Error: fmt.Sprintf("section %s does not match session section %s", question.Section, *sess.Section)}}, nil)
+	}
+
 	// Check if answer is correct
 	isCorrect := req.SelectedAnswer == question.CorrectAnswer
 
@@ -84,40 +131,69 @@ func (s *AttemptService) Create(ctx echo.Context, clerkID string, req *attempt.C
 		FeedbackGenerated: false,
 	}
 
-	created, err := s.attemptRepo.Create(ctx.Request().Context(), newAttempt)
+	// Serve canonical feedback from cache when possible; personalized feedback always goes to the LLM
+	feedbackLang := string(llm.LangIndonesian)
+	personalized := user.SubscriptionTier == personalizedFeedbackTier
+	var cacheEntry *attempt.FeedbackCacheEntry
+	if !personalized {
+		cacheEntry = s.lookupCachedFeedback(ctx, newAttempt, feedbackLang)
+	}
+
+	// Skip feedback generation when the user's quota or the global budget is exhausted
+	var skippedReason *usage.ExceededReason
+	if s.usageService != nil && cacheEntry == nil {
+		skippedReason, err = s.usageService.CheckQuota(ctx.Request().Context(), user)
+		if err != nil {
+			// Fail open: a ledger read error should not block feedback
+			logger.Warn().Err(err).Msg("failed to check llm quota")
+			skippedReason = nil
+		} else if skippedReason != nil {
+			logger.Info().
+				Str("event", "feedback_skipped").
+				Str("attempt_id", newAttempt.ID.String()).
+				Str("reason", string(*skippedReason)).
+				Msg("Feedback generation skipped")
+		}
+	}
+
+	// Stats, feedback generation, readiness, leaderboards and achievements are driven by the
+	// AttemptRecorded event, which commits with the attempt so none is lost when Redis is
+	// unavailable
+	generateFeedback := s.jobService != nil && skippedReason == nil && cacheEntry == nil
+	recorded := event.AttemptRecorded{
+		AttemptID:        newAttempt.ID,
+		UserID:           user.ID,
+		QuestionID:       questionUUID,
+		SessionID:        newAttempt.SessionID,
+		Section:          string(question.Section),
+		IsCorrect:        isCorrect,
+		TimeSpentSeconds: req.TimeSpentSeconds,
+		GenerateFeedback: generateFeedback,
+		FeedbackLanguage: feedbackLang,
+		Personalized:     personalized,
+	}
+
+	// Cached feedback commits with the attempt, so a cache hit never leaves it without feedback
+	created, cachedFeedback, err := s.attemptRepo.Create(ctx.Request().Context(), newAttempt, cacheEntry, recorded)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to create attempt")
 		return nil, err
 	}
 
-	// Update question statistics (async-safe, non-blocking)
-	go func() {
-		_ = s.attemptRepo.UpdateQuestionStats(ctx.Request().Context(), questionUUID, isCorrect, req.TimeSpentSeconds)
-	}()
-
-	// Enqueue feedback generation task
 	var jobID string
 	if s.jobService != nil {
-		task, err := job.NewFeedbackGenerationTask(
-			created.ID.String(),
-			user.ID.String(),
-			req.QuestionID,
-			isCorrect,
-		)
-		if err != nil {
-			logger.Warn().Err(err).Msg("failed to create feedback generation task")
-		} else {
-			info, err := s.jobService.Client.Enqueue(task)
-			if err != nil {
-				logger.Warn().Err(err).Msg("failed to enqueue feedback generation task")
-			} else {
-				jobID = info.ID
-				logger.Info().
-					Str("job_id", jobID).
-					Str("attempt_id", created.ID.String()).
-					Msg("Feedback generation task enqueued")
-			}
-		}
+		s.jobService.WakeOutboxRelay()
+	}
+	if generateFeedback {
+		jobID = job.FeedbackTaskID(created.ID.String())
+	}
+
+	if cacheEntry != nil {
+		logger.Info().
+			Str("event", "feedback_cache_hit").
+			Str("attempt_id", created.ID.String()).
+			Str("feedback_cache_id", cacheEntry.ID.String()).
+			Msg("Feedback served from cache")
 	}
 
 	logger.Info().
@@ -129,9 +205,67 @@ func (s *AttemptService) Create(ctx echo.Context, clerkID string, req *attempt.C
 		Msg("Attempt recorded")
 
 	response := created.ToResponseWithJob(jobID)
+	if cachedFeedback != nil {
+		feedback := cachedFeedback.ToResponse()
+		response.FeedbackGenerated = true
+		response.Feedback = &feedback
+	}
+	if skippedReason != nil {
+		reason := string(*skippedReason)
+		response.FeedbackSkippedReason = &reason
+	}
 	return &response, nil
 }
 
+// applyAttemptRecorded updates the student's progress after an answer: section readiness,
+// then the leaderboards and achievements that read it. It runs as an AttemptRecorded
+// subscriber and is retried until it succeeds.
+func (s *AttemptService) applyAttemptRecorded(ctx context.Context, evt *event.Event) error {
+	var p event.AttemptRecorded
+	if err := evt.Decode(&p); err != nil {
+		return err
+	}
+
+	if err := s.readinessService.Recompute(ctx, p.UserID, p.Section); err != nil {
+		return fmt.Errorf("failed to recompute readiness: %w", err)
+	}
+
+	// Counting the answer is not idempotent, so a retried delivery counts it only once
+	err := s.jobService.Once(ctx, evt, "attempt_progress:leaderboard", func() error {
+		return s.leaderboardService.RecordAttempt(ctx, p.UserID, p.Section)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record leaderboard attempt: %w", err)
+	}
+
+	var sessionID string
+	if p.SessionID != nil {
+		sessionID = *p.SessionID
+	}
+	_, err = s.achievementService.Evaluate(ctx, p.UserID,
+		achievement.Event{Type: achievement.EventAttemptRecorded, UserID: p.UserID, Section: p.Section, SessionID: sessionID},
+		achievement.Event{Type: achievement.EventReadinessUpdated, UserID: p.UserID, Section: p.Section},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate achievements: %w", err)
+	}
+
+	return nil
+}
+
+// lookupCachedFeedback finds canonical cached feedback for the attempt's answer.
+// Read errors are logged and treated as a cache miss.
+func (s *AttemptService) lookupCachedFeedback(ctx echo.Context, a *attempt.Attempt, lang string) *attempt.FeedbackCacheEntry {
+	logger := middleware.GetLogger(ctx)
+
+	entry, err := s.attemptRepo.GetCachedFeedback(ctx.Request().Context(), a.QuestionID, a.SelectedAnswer, lang, llm.PromptVersion())
+	if err != nil {
+		logger.Warn().Err(err).Str("attempt_id", a.ID.String()).Msg("failed to read feedback cache")
+		return nil
+	}
+	return entry
+}
+
 // GetByID retrieves an attempt with question and feedback details
 func (s *AttemptService) GetByID(ctx echo.Context, clerkID string, attemptID string) (*attempt.AttemptDetailResponse, error) {
 	logger := middleware.GetLogger(ctx)
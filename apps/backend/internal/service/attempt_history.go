@@ -0,0 +1,110 @@
+package service
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model"
+	"github.com/manikandareas/genta/internal/model/analytics"
+	"github.com/manikandareas/genta/internal/model/attempt"
+	"github.com/manikandareas/genta/internal/repository"
+)
+
+// List retrieves the user's attempt history, newest first, one cursor page at a time
+func (s *AttemptService) List(ctx echo.Context, clerkID string, req *attempt.ListAttemptsRequest) (*model.PaginatedResponse[attempt.AttemptHistoryItemResponse], error) {
+	logger := middleware.GetLogger(ctx)
+
+	user, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	filter, err := historyFilter(req.From, req.To, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	filter.Section = req.Section
+	filter.SubType = req.SubType
+	filter.IsCorrect = req.IsCorrect
+	filter.SessionID = req.SessionID
+	filter.HasFeedback = req.HasFeedback
+
+	params := repository.PageParams{Limit: req.Limit, Cursor: req.Cursor, IncludeTotal: req.IncludeTotal}
+
+	page, err := s.attemptRepo.ListHistory(ctx.Request().Context(), user.ID, filter, params)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to list attempt history")
+		return nil, err
+	}
+
+	return paginatedResponse(ctx, page, params, func(item *attempt.HistoryItem) attempt.AttemptHistoryItemResponse {
+		return item.ToResponse()
+	}), nil
+}
+
+// GetRetryQueue builds a "retry wrong answers" practice set from the history filter
+func (s *AttemptService) GetRetryQueue(ctx echo.Context, clerkID string, req *attempt.GetRetryQueueRequest) (*attempt.RetryQueueResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	user, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	filter, err := historyFilter(req.From, req.To, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	filter.Section = req.Section
+	filter.SubType = req.SubType
+	filter.SessionID = req.SessionID
+
+	items, err := s.attemptRepo.GetRetryQueue(ctx.Request().Context(), user.ID, filter, req.Limit)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get retry queue")
+		return nil, err
+	}
+
+	response := &attempt.RetryQueueResponse{
+		Questions: make([]attempt.RetryQuestionResponse, 0, len(items)),
+	}
+	for i := range items {
+		response.Questions = append(response.Questions, items[i].ToResponse())
+	}
+
+	logger.Debug().
+		Str("user_id", user.ID.String()).
+		Int("questions", len(response.Questions)).
+		Msg("Retry queue built")
+
+	return response, nil
+}
+
+// historyFilter converts an optional inclusive local date range into UTC bounds
+func historyFilter(from, to, timezone string) (attempt.HistoryFilter, error) {
+	var filter attempt.HistoryFilter
+
+	offset := analytics.TimezoneOffsets[timezone]
+	loc := time.FixedZone(timezone, offset*3600)
+
+	if from != "" {
+		start, _ := time.ParseInLocation("2006-01-02", from, loc)
+		start = start.UTC()
+		filter.From = &start
+	}
+	if to != "" {
+		end, _ := time.ParseInLocation("2006-01-02", to, loc)
+		end = end.AddDate(0, 0, 1).UTC()
+		filter.To = &end
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, errs.NewBadRequestError("from must not be after to", false, nil, nil, nil)
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/job"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model"
+	"github.com/manikandareas/genta/internal/model/draft"
+	"github.com/manikandareas/genta/internal/repository"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+type AuthoringService struct {
+	server       *server.Server
+	draftRepo    *repository.DraftRepository
+	questionRepo *repository.QuestionRepository
+	userRepo     *repository.UserRepository
+	usageService *UsageService
+	jobService   *job.JobService
+}
+
+func NewAuthoringService(
+	server *server.Server,
+	draftRepo *repository.DraftRepository,
+	questionRepo *repository.QuestionRepository,
+	userRepo *repository.UserRepository,
+	usageService *UsageService,
+	jobService *job.JobService,
+) *AuthoringService {
+	return &AuthoringService{
+		server:       server,
+		draftRepo:    draftRepo,
+		questionRepo: questionRepo,
+		userRepo:     userRepo,
+		usageService: usageService,
+		jobService:   jobService,
+	}
+}
+
+// EnrichQuestion enqueues drafting of missing explanation fields for a question
+func (s *AuthoringService) EnrichQuestion(ctx echo.Context, clerkID string, req *draft.EnrichQuestionRequest) (*draft.DraftJobResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	editor, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	if _, err := s.questionRepo.GetByID(ctx.Request().Context(), req.QuestionID); err != nil {
+		return nil, err
+	}
+
+	task, err := job.NewQuestionEnrichmentTask(req.QuestionID, req.Fields, false, editor.ID.String())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create question enrichment task")
+		return nil, err
+	}
+
+	return s.enqueue(ctx, task, "question_enrichment_requested")
+}
+
+// EnrichBatch enqueues enrichment drafts for questions missing fields. A batch the
+// remaining global LLM budget cannot cover is refused up front.
+func (s *AuthoringService) EnrichBatch(ctx echo.Context, clerkID string, req *draft.EnrichBatchRequest) (*draft.DraftJobResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	editor, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	if err := s.checkBatchBudget(ctx, req.Limit); err != nil {
+		return nil, err
+	}
+
+	section := ""
+	if req.Section != nil {
+		section = *req.Section
+	}
+
+	task, err := job.NewQuestionEnrichmentBatchTask(section, req.Fields, req.Limit, editor.ID.String())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create question enrichment batch task")
+		return nil, err
+	}
+
+	return s.enqueue(ctx, task, "question_enrichment_batch_requested")
+}
+
+// GenerateVariants enqueues generation of variant question drafts from a template question
+func (s *AuthoringService) GenerateVariants(ctx echo.Context, clerkID string, req *draft.GenerateVariantsRequest) (*draft.DraftJobResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	editor, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	if _, err := s.questionRepo.GetByID(ctx.Request().Context(), req.QuestionID); err != nil {
+		return nil, err
+	}
+
+	task, err := job.NewQuestionVariantsTask(req.QuestionID, req.Count, req.TargetDifficulty, editor.ID.String())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create question variants task")
+		return nil, err
+	}
+
+	return s.enqueue(ctx, task, "question_variants_requested")
+}
+
+// ListDrafts lists drafts for review
+func (s *AuthoringService) ListDrafts(ctx echo.Context, req *draft.ListDraftsRequest) (*model.PaginatedResponse[draft.DraftResponse], error) {
+	logger := middleware.GetLogger(ctx)
+
+	params := repository.PageParams{Page: req.Page, Limit: req.Limit, Cursor: req.Cursor, IncludeTotal: req.IncludeTotal}
+
+	page, err := s.draftRepo.List(ctx.Request().Context(), req, params)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to list drafts")
+		return nil, err
+	}
+
+	return paginatedResponse(ctx, page, params, func(d *draft.Draft) draft.DraftResponse {
+		return d.ToResponse()
+	}), nil
+}
+
+// GetDraft retrieves a single draft
+func (s *AuthoringService) GetDraft(ctx echo.Context, draftID string) (*draft.DraftResponse, error) {
+	d, err := s.draftRepo.GetByID(ctx.Request().Context(), draftID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := d.ToResponse()
+	return &response, nil
+}
+
+// UpdateDraft lets an editor revise drafted content before approving it
+func (s *AuthoringService) UpdateDraft(ctx echo.Context, req *draft.UpdateDraftRequest) (*draft.DraftResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if req.Content.IsEmpty() {
+		return nil, errs.NewBadRequestError("draft content must not be empty", false, nil, nil, nil)
+	}
+
+	d, err := s.draftRepo.UpdateContent(ctx.Request().Context(), req.ID, req.Content)
+	if err != nil {
+		logger.Error().Err(err).Str("draft_id", req.ID).Msg("failed to update draft")
+		return nil, err
+	}
+
+	response := d.ToResponse()
+	return &response, nil
+}
+
+// ApproveDraft applies a draft to the question bank
+func (s *AuthoringService) ApproveDraft(ctx echo.Context, clerkID string, req *draft.ReviewDraftRequest) (*draft.DraftResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	reviewer, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	d, err := s.draftRepo.Approve(ctx.Request().Context(), req.ID, reviewer.ID, req.Notes, req.AllowDuplicate)
+	if err != nil {
+		logger.Error().Err(err).Str("draft_id", req.ID).Msg("failed to approve draft")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "question_draft_approved").
+		Str("draft_id", req.ID).
+		Str("kind", string(d.Kind)).
+		Str("reviewer_id", reviewer.ID.String()).
+		Msg("Question draft approved")
+
+	response := d.ToResponse()
+	return &response, nil
+}
+
+// RejectDraft rejects a draft without applying it
+func (s *AuthoringService) RejectDraft(ctx echo.Context, clerkID string, req *draft.ReviewDraftRequest) (*draft.DraftResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	reviewer, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	d, err := s.draftRepo.Reject(ctx.Request().Context(), req.ID, reviewer.ID, req.Notes)
+	if err != nil {
+		logger.Error().Err(err).Str("draft_id", req.ID).Msg("failed to reject draft")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "question_draft_rejected").
+		Str("draft_id", req.ID).
+		Str("kind", string(d.Kind)).
+		Str("reviewer_id", reviewer.ID.String()).
+		Msg("Question draft rejected")
+
+	response := d.ToResponse()
+	return &response, nil
+}
+
+// checkBatchBudget refuses an enrichment batch whose estimated cost, from the average cost
+// of recent enrichment drafts, exceeds the remaining global budget
+func (s *AuthoringService) checkBatchBudget(ctx echo.Context, limit int) error {
+	logger := middleware.GetLogger(ctx)
+
+	remaining, limited, err := s.usageService.RemainingGlobalBudget(ctx.Request().Context())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get remaining llm budget")
+		return err
+	}
+	if !limited {
+		return nil
+	}
+
+	code := "LLM_BUDGET_EXCEEDED"
+	if remaining <= 0 {
+		return errs.NewBadRequestError("the LLM spend budget is used up", false, &code, nil, nil)
+	}
+
+	avgCost, err := s.draftRepo.AverageCost(ctx.Request().Context(), draft.KindEnrichment)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to estimate enrichment cost")
+		return err
+	}
+	if avgCost <= 0 {
+		return nil
+	}
+
+	if affordable := int(remaining / avgCost); affordable < limit {
+		return errs.NewBadRequestError(
+			fmt.Sprintf("the remaining LLM budget covers about %d enrichments", affordable),
+			false, &code,
+			[]errs.FieldError{{Field: "limit", Error: fmt.Sprintf("must be at most %d", affordable)}},
+			nil,
+		)
+	}
+
+	return nil
+}
+
+func (s *AuthoringService) enqueue(ctx echo.Context, task *asynq.Task, event string) (*draft.DraftJobResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if s.jobService == nil {
+		logger.Error().Str("task_type", task.Type()).Msg("job service not available")
+		return nil, errs.NewInternalServerError()
+	}
+
+	info, err := s.jobService.Client.Enqueue(task)
+	if err != nil {
+		logger.Error().Err(err).Str("task_type", task.Type()).Msg("failed to enqueue authoring task")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", event).
+		Str("job_id", info.ID).
+		Msg("Authoring task enqueued")
+
+	response := draft.NewDraftJobResponse(info.ID)
+	return &response, nil
+}
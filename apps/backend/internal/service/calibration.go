@@ -0,0 +1,227 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/scoring"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/calibration"
+	"github.com/manikandareas/genta/internal/model/readiness"
+	"github.com/manikandareas/genta/internal/repository"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+// minTryoutPracticeAttempts is how many practice answers a student needs before a tryout
+// for the tryout to be used in calibration
+const minTryoutPracticeAttempts = 10
+
+type CalibrationService struct {
+	server          *server.Server
+	calibrationRepo *repository.CalibrationRepository
+	readinessRepo   *repository.ReadinessRepository
+	userRepo        *repository.UserRepository
+}
+
+func NewCalibrationService(
+	server *server.Server,
+	calibrationRepo *repository.CalibrationRepository,
+	readinessRepo *repository.ReadinessRepository,
+	userRepo *repository.UserRepository,
+) *CalibrationService {
+	return &CalibrationService{
+		server:          server,
+		calibrationRepo: calibrationRepo,
+		readinessRepo:   readinessRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// ImportTryoutResults stores official tryout scores, pairing each with the student's
+// section theta estimated from the answers given before the tryout
+func (s *CalibrationService) ImportTryoutResults(ctx echo.Context, req *calibration.ImportTryoutResultsRequest) (*calibration.ImportTryoutResultsResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	imported := 0
+	for i, input := range req.Results {
+		userID, _ := uuid.Parse(input.UserID)
+		takenAt, _ := time.Parse("2006-01-02", input.TakenAt)
+
+		exists, err := s.calibrationRepo.UserExists(ctx.Request().Context(), userID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to check tryout user")
+			return nil, err
+		}
+		if !exists {
+			return nil, errs.NewBadRequestError(fmt.Sprintf("unknown user_id in results[%d]", i), false, nil, nil, nil)
+		}
+
+		responses, err := s.readinessRepo.GetSectionResponses(ctx.Request().Context(), userID, input.Section, &takenAt)
+		if err != nil {
+			logger.Error().Err(err).Str("user_id", input.UserID).Msg("failed to get responses before tryout")
+			return nil, err
+		}
+
+		theta, se := scoring.EstimateTheta(toScoringResponses(responses))
+		err = s.calibrationRepo.UpsertTryoutResult(ctx.Request().Context(), &calibration.TryoutResult{
+			UserID:        userID,
+			Section:       input.Section,
+			TryoutName:    input.TryoutName,
+			ScaledScore:   input.ScaledScore,
+			TakenAt:       takenAt,
+			ThetaAtTryout: theta,
+			ThetaSE:       se,
+			AttemptsCount: len(responses),
+		})
+		if err != nil {
+			logger.Error().Err(err).Str("user_id", input.UserID).Msg("failed to store tryout result")
+			return nil, err
+		}
+		imported++
+	}
+
+	logger.Info().
+		Str("event", "tryout_results_imported").
+		Int("count", imported).
+		Msg("Tryout results imported")
+
+	return &calibration.ImportTryoutResultsResponse{Imported: imported}, nil
+}
+
+// Fit creates a new calibration version by regressing tryout scores on theta per section.
+// Sections without enough tryout data keep the currently active mapping.
+func (s *CalibrationService) Fit(ctx echo.Context, clerkID string, req *calibration.FitCalibrationRequest) (*calibration.CalibrationVersionResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	admin, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	exists, err := s.calibrationRepo.VersionExists(ctx.Request().Context(), req.Version)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to check calibration version")
+		return nil, err
+	}
+	if exists {
+		return nil, errs.NewBadRequestError("calibration version already exists", false, nil, nil, nil)
+	}
+
+	active, err := s.calibrationRepo.GetActive(ctx.Request().Context())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get active calibrations")
+		return nil, err
+	}
+	activeBySection := make(map[string]calibration.ScoreCalibration, len(active))
+	for _, c := range active {
+		activeBySection[c.Section] = c
+	}
+
+	rows := make([]calibration.ScoreCalibration, 0, len(readiness.DefaultSections()))
+	fitted := make(map[string]bool)
+	for _, section := range readiness.DefaultSections() {
+		results, err := s.calibrationRepo.GetTryoutResults(ctx.Request().Context(), section, minTryoutPracticeAttempts)
+		if err != nil {
+			logger.Error().Err(err).Str("section", section).Msg("failed to get tryout results")
+			return nil, err
+		}
+
+		points := make([]scoring.CalibrationPoint, len(results))
+		for i, r := range results {
+			points[i] = scoring.CalibrationPoint{Theta: r.ThetaAtTryout, Score: r.ScaledScore}
+		}
+
+		row := calibration.ScoreCalibration{Version: req.Version, Section: section, CreatedBy: &admin.ID}
+		cal, err := scoring.Fit(req.Version, section, points)
+		if err == nil {
+			row.Slope, row.Intercept, row.ResidualSD = cal.Slope, cal.Intercept, cal.ResidualSD
+			row.SampleSize = len(points)
+			fitted[section] = true
+		} else {
+			// Too few or degenerate samples: carry over the active mapping (or the built-in one)
+			previous, ok := activeBySection[section]
+			if !ok {
+				def := scoring.DefaultCalibration(section)
+				previous = calibration.ScoreCalibration{Slope: def.Slope, Intercept: def.Intercept, ResidualSD: def.ResidualSD}
+			}
+			row.Slope, row.Intercept, row.ResidualSD = previous.Slope, previous.Intercept, previous.ResidualSD
+			row.SampleSize = previous.SampleSize
+			logger.Warn().Err(err).Str("section", section).Int("samples", len(points)).Msg("section not fitted, carrying over active calibration")
+		}
+		rows = append(rows, row)
+	}
+
+	if err := s.calibrationRepo.CreateVersion(ctx.Request().Context(), rows); err != nil {
+		logger.Error().Err(err).Str("version", req.Version).Msg("failed to create calibration version")
+		return nil, err
+	}
+
+	if req.Activate {
+		if err := s.calibrationRepo.Activate(ctx.Request().Context(), req.Version); err != nil {
+			logger.Error().Err(err).Str("version", req.Version).Msg("failed to activate calibration version")
+			return nil, err
+		}
+	}
+
+	logger.Info().
+		Str("event", "score_calibration_fitted").
+		Str("version", req.Version).
+		Int("sections_fitted", len(fitted)).
+		Bool("activated", req.Activate).
+		Msg("Score calibration fitted")
+
+	created, err := s.calibrationRepo.List(ctx.Request().Context(), &req.Version)
+	if err != nil {
+		return nil, err
+	}
+	versions := calibration.GroupByVersion(created)
+	if len(versions) == 0 {
+		return nil, errs.NewInternalServerError()
+	}
+
+	response := versions[0]
+	for i := range response.Sections {
+		isFitted := fitted[response.Sections[i].Section]
+		response.Sections[i].Fitted = &isFitted
+	}
+	return &response, nil
+}
+
+// Activate switches predictions to a calibration version
+func (s *CalibrationService) Activate(ctx echo.Context, req *calibration.ActivateCalibrationRequest) (*calibration.CalibrationVersionResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.calibrationRepo.Activate(ctx.Request().Context(), req.Version); err != nil {
+		logger.Error().Err(err).Str("version", req.Version).Msg("failed to activate calibration version")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "score_calibration_activated").
+		Str("version", req.Version).
+		Msg("Score calibration activated")
+
+	rows, err := s.calibrationRepo.List(ctx.Request().Context(), &req.Version)
+	if err != nil {
+		return nil, err
+	}
+	versions := calibration.GroupByVersion(rows)
+	if len(versions) == 0 {
+		return nil, errs.NewNotFoundError("calibration version not found", false, nil)
+	}
+	return &versions[0], nil
+}
+
+// List returns calibration versions, newest first
+func (s *CalibrationService) List(ctx echo.Context, req *calibration.ListCalibrationsRequest) ([]calibration.CalibrationVersionResponse, error) {
+	rows, err := s.calibrationRepo.List(ctx.Request().Context(), req.Version)
+	if err != nil {
+		middleware.GetLogger(ctx).Error().Err(err).Msg("failed to list calibrations")
+		return nil, err
+	}
+	return calibration.GroupByVersion(rows), nil
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/job"
+	"github.com/manikandareas/genta/internal/lib/llm"
+	"github.com/manikandareas/genta/internal/lib/scoring"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/concept"
+	"github.com/manikandareas/genta/internal/model/readiness"
+	"github.com/manikandareas/genta/internal/repository"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+type ConceptService struct {
+	server        *server.Server
+	conceptRepo   *repository.ConceptRepository
+	readinessRepo *repository.ReadinessRepository
+	userRepo      *repository.UserRepository
+	jobService    *job.JobService
+}
+
+func NewConceptService(server *server.Server, conceptRepo *repository.ConceptRepository, readinessRepo *repository.ReadinessRepository, userRepo *repository.UserRepository, jobService *job.JobService) *ConceptService {
+	return &ConceptService{
+		server:        server,
+		conceptRepo:   conceptRepo,
+		readinessRepo: readinessRepo,
+		userRepo:      userRepo,
+		jobService:    jobService,
+	}
+}
+
+// GetTaxonomy returns the concept taxonomy with question counts
+func (s *ConceptService) GetTaxonomy(ctx echo.Context, req *concept.GetTreeRequest) (*concept.TreeResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	nodes, err := s.conceptRepo.ListNodes(ctx.Request().Context(), nil, req.Section)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to list concepts")
+		return nil, err
+	}
+
+	return &concept.TreeResponse{Sections: concept.BuildTree(nodes, false, llm.Language(req.Lang))}, nil
+}
+
+// GetMastery returns the taxonomy with the user's estimated mastery of every node
+func (s *ConceptService) GetMastery(ctx echo.Context, clerkID string, req *concept.GetTreeRequest) (*concept.TreeResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	user, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	nodes, err := s.conceptRepo.ListNodes(ctx.Request().Context(), &user.ID, req.Section)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to list concept mastery")
+		return nil, err
+	}
+
+	return &concept.TreeResponse{Sections: concept.BuildTree(nodes, true, llm.Language(req.Lang))}, nil
+}
+
+// GetBlockers ranks the concepts holding back the user's readiness. A concept weighs more
+// the larger its share of the section's questions, the further it is from mastery, and the
+// further its section is from ready; sections already ready are left out.
+func (s *ConceptService) GetBlockers(ctx echo.Context, clerkID string, req *concept.GetBlockersRequest) (*concept.BlockersResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	user, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	nodes, err := s.conceptRepo.ListNodes(ctx.Request().Context(), &user.ID, req.Section)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to list concept mastery")
+		return nil, err
+	}
+
+	readinessRows, err := s.readinessRepo.GetUserReadiness(ctx.Request().Context(), user.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get user readiness")
+		return nil, err
+	}
+	sectionReadiness := make(map[string]*float64, len(readinessRows))
+	for i := range readinessRows {
+		sectionReadiness[readinessRows[i].Section] = readinessRows[i].ReadinessPercentage
+	}
+
+	sectionQuestions := make(map[string]int)
+	for _, n := range nodes {
+		sectionQuestions[string(n.Section)] += n.QuestionCount
+	}
+
+	lang := llm.Language(req.Lang)
+	blockers := []concept.BlockerResponse{}
+	for i := range nodes {
+		n := &nodes[i]
+		section := string(n.Section)
+		if n.QuestionCount == 0 {
+			continue
+		}
+
+		readinessGap := 1.0
+		if pct := sectionReadiness[section]; pct != nil {
+			if *pct >= readiness.ReadyPercentage {
+				continue
+			}
+			readinessGap = 1 - *pct/100
+		}
+
+		mastery := n.EstimatedMastery()
+		masteryGap := scoring.MasteryThreshold - mastery
+		if masteryGap <= 0 {
+			continue
+		}
+
+		coverage := share(n.QuestionCount, sectionQuestions[section])
+		blockers = append(blockers, concept.BlockerResponse{
+			ConceptID:        n.ID,
+			Code:             n.Code,
+			Name:             n.LocalizedName(lang),
+			Section:          n.Section,
+			SubType:          n.SubType,
+			Mastery:          roundTo(mastery, 3),
+			Status:           concept.StatusOf(mastery, n.AttemptsCount),
+			AttemptsCount:    n.AttemptsCount,
+			Coverage:         roundTo(coverage, 3),
+			SectionReadiness: sectionReadiness[section],
+			Impact:           roundTo(coverage*masteryGap*readinessGap, 4),
+		})
+	}
+
+	sort.SliceStable(blockers, func(i, j int) bool { return blockers[i].Impact > blockers[j].Impact })
+	if len(blockers) > req.Limit {
+		blockers = blockers[:req.Limit]
+	}
+
+	return &concept.BlockersResponse{Blockers: blockers}, nil
+}
+
+// CreateConcept adds a subtype or concept to the taxonomy
+func (s *ConceptService) CreateConcept(ctx echo.Context, req *concept.CreateConceptRequest) (*concept.ConceptResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	parent, err := s.conceptRepo.GetByID(ctx.Request().Context(), req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.conceptRepo.Create(ctx.Request().Context(), parent, req)
+	if err != nil {
+		logger.Error().Err(err).Str("parent_id", req.ParentID).Msg("failed to create concept")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "concept_created").
+		Str("concept_id", c.ID.String()).
+		Str("code", c.Code).
+		Msg("Concept created")
+
+	response := c.ToResponse()
+	return &response, nil
+}
+
+// SetQuestionConcepts replaces the concepts a question is tagged with
+func (s *ConceptService) SetQuestionConcepts(ctx echo.Context, req *concept.SetQuestionConceptsRequest) (*concept.QuestionConceptsResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if err := s.conceptRepo.SetQuestionConcepts(ctx.Request().Context(), req.QuestionID, req.ConceptIDs); err != nil {
+		logger.Error().Err(err).Str("question_id", req.QuestionID).Msg("failed to tag question")
+		return nil, err
+	}
+
+	concepts, err := s.conceptRepo.GetQuestionConcepts(ctx.Request().Context(), req.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &concept.QuestionConceptsResponse{
+		QuestionID: uuid.MustParse(req.QuestionID),
+		Concepts:   make([]concept.ConceptResponse, len(concepts)),
+	}
+	for i := range concepts {
+		response.Concepts[i] = concepts[i].ToResponse()
+	}
+
+	return response, nil
+}
+
+// RebuildMastery enqueues a replay of attempt history into concept mastery
+func (s *ConceptService) RebuildMastery(ctx echo.Context, req *concept.RebuildMasteryRequest) (*concept.RebuildJobResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if s.jobService == nil {
+		logger.Error().Msg("job service not available")
+		return nil, errs.NewInternalServerError()
+	}
+
+	var userID string
+	if req.UserID != nil {
+		userID = *req.UserID
+	}
+
+	task, err := job.NewConceptMasteryRebuildTask(userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create concept mastery rebuild task")
+		return nil, err
+	}
+
+	info, err := s.jobService.Client.Enqueue(task)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to enqueue concept mastery rebuild task")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "concept_mastery_rebuild_requested").
+		Str("job_id", info.ID).
+		Str("user_id", userID).
+		Msg("Concept mastery rebuild enqueued")
+
+	response := concept.NewRebuildJobResponse(info.ID)
+	return &response, nil
+}
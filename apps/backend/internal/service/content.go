@@ -0,0 +1,552 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/content"
+	"github.com/manikandareas/genta/internal/lib/storage"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/media"
+	"github.com/manikandareas/genta/internal/model/question"
+	"github.com/manikandareas/genta/internal/model/stimulus"
+	"github.com/manikandareas/genta/internal/repository"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+const (
+	mediaUploadExpiry   = 15 * time.Minute
+	mediaDownloadExpiry = 15 * time.Minute
+)
+
+// mediaKeyPattern matches the keys mediaKey generates
+var mediaKeyPattern = regexp.MustCompile(`^media/\d{4}/\d{2}/[0-9a-f-]{36}\.(png|jpg|gif|webp)$`)
+
+type ContentService struct {
+	server       *server.Server
+	mediaRepo    *repository.MediaRepository
+	stimulusRepo *repository.StimulusRepository
+	questionRepo *repository.QuestionRepository
+	userRepo     *repository.UserRepository
+}
+
+func NewContentService(
+	server *server.Server,
+	mediaRepo *repository.MediaRepository,
+	stimulusRepo *repository.StimulusRepository,
+	questionRepo *repository.QuestionRepository,
+	userRepo *repository.UserRepository,
+) *ContentService {
+	return &ContentService{
+		server:       server,
+		mediaRepo:    mediaRepo,
+		stimulusRepo: stimulusRepo,
+		questionRepo: questionRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// UploadMedia stores an uploaded image. The type is sniffed from the file rather than
+// trusted from the request, and re-uploading the same file returns the existing asset.
+func (s *ContentService) UploadMedia(ctx echo.Context, clerkID string, file io.Reader, filename string) (*media.AssetResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	uploader, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, media.UploadPolicy.MaxBytes+1))
+	if err != nil {
+		return nil, errs.NewBadRequestError("failed to read file", false, nil, nil, nil)
+	}
+
+	return s.ingestMedia(ctx, uploader.ID, uuid.New(), data, filename, "")
+}
+
+// CreateMediaUpload presigns an upload of an image straight to storage, so large files
+// don't pass through the API. The client uploads the file with the returned form, then
+// calls CompleteMediaUpload with the upload ID and key.
+func (s *ContentService) CreateMediaUpload(ctx echo.Context, clerkID string, req *media.CreateUploadRequest) (*media.UploadResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if _, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID); err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	if err := media.UploadPolicy.Check(req.ContentType, req.SizeBytes); err != nil {
+		field := "size_bytes"
+		if !media.UploadPolicy.Allows(req.ContentType) {
+			field = "content_type"
+		}
+		return nil, invalidMedia(field, err)
+	}
+
+	id := uuid.New()
+	key := mediaKey(id, media.Extensions[req.ContentType])
+
+	upload, err := s.server.Storage.PresignPost(ctx.Request().Context(), key, req.ContentType, req.SizeBytes, mediaUploadExpiry)
+	if err != nil {
+		logger.Error().Err(err).Str("key", key).Msg("failed to presign media upload")
+		return nil, err
+	}
+
+	return &media.UploadResponse{ID: id, Key: key, Upload: *upload}, nil
+}
+
+// CompleteMediaUpload records an image uploaded with CreateMediaUpload. The stored file
+// is checked like a direct upload and removed when it is refused. Completing an upload
+// again returns the asset it already made.
+func (s *ContentService) CompleteMediaUpload(ctx echo.Context, clerkID string, req *media.CompleteUploadRequest) (*media.AssetResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	uploader, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	id, err := uuid.Parse(req.ID)
+	if err != nil || !mediaKeyPattern.MatchString(req.Key) || !strings.Contains(req.Key, "/"+id.String()+".") {
+		return nil, errs.NewBadRequestError("key does not belong to this upload", false, nil,
+			[]errs.FieldError{{Field: "key", Error: "must be the key returned with the upload"}}, nil)
+	}
+
+	if asset, err := s.completedUpload(ctx, id, req.Key); err != nil || asset != nil {
+		return asset, err
+	}
+
+	body, _, err := s.server.Storage.Open(ctx.Request().Context(), req.Key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, errs.NewNotFoundError("upload not found", false, nil)
+		}
+		logger.Error().Err(err).Str("key", req.Key).Msg("failed to open uploaded media")
+		return nil, err
+	}
+	data, err := io.ReadAll(io.LimitReader(body, media.UploadPolicy.MaxBytes+1))
+	body.Close()
+	if err != nil {
+		logger.Error().Err(err).Str("key", req.Key).Msg("failed to read uploaded media")
+		return nil, err
+	}
+
+	var filename string
+	if req.Filename != nil {
+		filename = *req.Filename
+	}
+
+	response, err := s.ingestMedia(ctx, uploader.ID, id, data, filename, req.Key)
+	if err != nil {
+		s.removeObjects(ctx, req.Key)
+		return nil, err
+	}
+	return response, nil
+}
+
+// ingestMedia checks an image and records it as an asset along with its thumbnail. The
+// file is stored under a new key unless it already sits in storage at uploadedKey.
+func (s *ContentService) ingestMedia(
+	ctx echo.Context,
+	uploaderID uuid.UUID,
+	id uuid.UUID,
+	data []byte,
+	filename string,
+	uploadedKey string,
+) (*media.AssetResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	contentType, _, err := storage.Sniff(bytes.NewReader(data))
+	if err != nil {
+		return nil, errs.NewBadRequestError("failed to read file", false, nil, nil, nil)
+	}
+	if err := media.UploadPolicy.Check(contentType, int64(len(data))); err != nil {
+		return nil, invalidMedia("file", err)
+	}
+	ext := media.Extensions[contentType]
+	if uploadedKey != "" && path.Ext(uploadedKey) != ext {
+		return nil, invalidMedia("file", &storage.ValidationError{Message: "does not match the content type of the upload"})
+	}
+
+	img, err := storage.DecodeImage(data)
+	if err != nil {
+		return nil, invalidMedia("file", err)
+	}
+
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	existing, err := s.mediaRepo.GetByChecksum(ctx.Request().Context(), checksum)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to look up media by checksum")
+		return nil, err
+	}
+	if existing != nil {
+		// The uploaded file is a duplicate, unless it is the existing asset's own file
+		if uploadedKey != "" && existing.StorageKey != uploadedKey {
+			s.removeObjects(ctx, uploadedKey)
+		}
+		response := existing.ToResponse()
+		return &response, nil
+	}
+
+	key := uploadedKey
+	if key == "" {
+		key = mediaKey(id, ext)
+		if _, err := s.server.Storage.Put(ctx.Request().Context(), key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+			logger.Error().Err(err).Str("key", key).Msg("failed to store media")
+			return nil, err
+		}
+	}
+	stored := []string{key}
+
+	// A missing thumbnail only costs bandwidth, so failing to make one doesn't fail the upload
+	var thumbnailKey *string
+	if thumb, ok := storage.Thumbnail(img, storage.ThumbnailSize); ok {
+		encoded, thumbType, thumbExt, err := storage.EncodeThumbnail(thumb)
+		if err == nil {
+			tk := strings.TrimSuffix(key, path.Ext(key)) + "_thumb" + thumbExt
+			_, err = s.server.Storage.Put(ctx.Request().Context(), tk, bytes.NewReader(encoded), int64(len(encoded)), thumbType)
+			if err == nil {
+				thumbnailKey = &tk
+				stored = append(stored, tk)
+			}
+		}
+		if err != nil {
+			logger.Warn().Err(err).Str("key", key).Msg("failed to store media thumbnail")
+		}
+	}
+
+	var original *string
+	if filename != "" {
+		original = &filename
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	asset, err := s.mediaRepo.Create(ctx.Request().Context(), &media.Asset{
+		ID:               id,
+		StorageKey:       key,
+		ThumbnailKey:     thumbnailKey,
+		ContentType:      contentType,
+		SizeBytes:        int64(len(data)),
+		Width:            &width,
+		Height:           &height,
+		Checksum:         checksum,
+		OriginalFilename: original,
+		UploadedBy:       &uploaderID,
+	})
+	if err != nil {
+		// A concurrent completion of the same upload recorded it first and owns the files
+		if uploadedKey != "" {
+			if done, doneErr := s.completedUpload(ctx, id, uploadedKey); doneErr == nil && done != nil {
+				return done, nil
+			}
+		}
+		logger.Error().Err(err).Str("key", key).Msg("failed to record media asset")
+		s.removeObjects(ctx, stored...)
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "media_uploaded").
+		Str("asset_id", asset.ID.String()).
+		Str("content_type", contentType).
+		Int64("size_bytes", asset.SizeBytes).
+		Bool("thumbnail", thumbnailKey != nil).
+		Str("uploader_id", uploaderID.String()).
+		Msg("Media uploaded")
+
+	response := asset.ToResponse()
+	return &response, nil
+}
+
+// OpenMedia returns an asset's file, or its thumbnail, for serving. With redirected
+// downloads the file is not opened and the download carries a presigned URL instead.
+func (s *ContentService) OpenMedia(ctx echo.Context, assetID string, variant media.Variant) (*media.Download, error) {
+	logger := middleware.GetLogger(ctx)
+
+	asset, err := s.mediaRepo.GetByID(ctx.Request().Context(), assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Images small enough to need no thumbnail serve the original for both variants
+	key, etag := asset.StorageKey, asset.Checksum
+	if variant == media.VariantThumbnail && asset.ThumbnailKey != nil {
+		key, etag = *asset.ThumbnailKey, asset.Checksum+"-thumb"
+	}
+
+	if s.server.Config.Storage.RedirectDownloads {
+		url, err := s.server.Storage.PresignGet(ctx.Request().Context(), key, mediaDownloadExpiry)
+		if err != nil {
+			logger.Error().Err(err).Str("asset_id", assetID).Msg("failed to presign media download")
+			return nil, err
+		}
+		return &media.Download{RedirectURL: url}, nil
+	}
+
+	body, obj, err := s.server.Storage.Open(ctx.Request().Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			logger.Error().Str("asset_id", assetID).Str("key", key).Msg("media file missing from storage")
+			return nil, errs.NewNotFoundError("media not found", false, nil)
+		}
+		logger.Error().Err(err).Str("asset_id", assetID).Msg("failed to open media")
+		return nil, err
+	}
+
+	contentType := obj.ContentType
+	if contentType == "" {
+		contentType = asset.ContentType
+	}
+
+	return &media.Download{
+		Body:        body,
+		ContentType: contentType,
+		Size:        obj.Size,
+		ETag:        etag,
+	}, nil
+}
+
+// completedUpload returns the asset an upload already made, or nil if it has none yet
+func (s *ContentService) completedUpload(ctx echo.Context, id uuid.UUID, key string) (*media.AssetResponse, error) {
+	asset, err := s.mediaRepo.GetByID(ctx.Request().Context(), id.String())
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) && httpErr.Status == 404 {
+			return nil, nil
+		}
+		middleware.GetLogger(ctx).Error().Err(err).Str("asset_id", id.String()).Msg("failed to get media")
+		return nil, err
+	}
+	if asset.StorageKey != key {
+		return nil, errs.NewBadRequestError("key does not belong to this upload", false, nil,
+			[]errs.FieldError{{Field: "key", Error: "must be the key returned with the upload"}}, nil)
+	}
+
+	response := asset.ToResponse()
+	return &response, nil
+}
+
+// removeObjects deletes stored files that no asset refers to
+func (s *ContentService) removeObjects(ctx echo.Context, keys ...string) {
+	logger := middleware.GetLogger(ctx)
+
+	for _, key := range keys {
+		if err := s.server.Storage.Delete(ctx.Request().Context(), key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			logger.Warn().Err(err).Str("key", key).Msg("failed to remove orphaned media")
+		}
+	}
+}
+
+// mediaKey is the storage key of an asset's original file
+func mediaKey(id uuid.UUID, ext string) string {
+	return fmt.Sprintf("media/%s/%s%s", time.Now().UTC().Format("2006/01"), id, ext)
+}
+
+// invalidMedia turns a refusal from the upload policy into a bad request on field
+func invalidMedia(field string, err error) error {
+	var validationErr *storage.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+	return errs.NewBadRequestError("invalid file", false, nil,
+		[]errs.FieldError{{Field: field, Error: validationErr.Message}}, nil)
+}
+
+// GetStimulus retrieves a stimulus
+func (s *ContentService) GetStimulus(ctx echo.Context, stimulusID string) (*stimulus.StimulusResponse, error) {
+	st, err := s.stimulusRepo.GetByID(ctx.Request().Context(), stimulusID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := st.ToResponse()
+	return &response, nil
+}
+
+// CreateStimulus stores a shared passage, table or figure for questions to link to
+func (s *ContentService) CreateStimulus(ctx echo.Context, clerkID string, req *stimulus.CreateStimulusRequest) (*stimulus.StimulusResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	editor, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	check := &contentCheck{}
+	check.sanitize("content", &req.Content, content.MaxStimulusLength)
+	if err := s.verify(ctx, check); err != nil {
+		return nil, err
+	}
+
+	st, err := s.stimulusRepo.Create(ctx.Request().Context(), req, editor.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create stimulus")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "stimulus_created").
+		Str("stimulus_id", st.ID.String()).
+		Str("section", string(st.Section)).
+		Str("editor_id", editor.ID.String()).
+		Msg("Stimulus created")
+
+	response := st.ToResponse()
+	return &response, nil
+}
+
+// UpdateStimulus revises a stimulus; linked questions show the new content right away
+func (s *ContentService) UpdateStimulus(ctx echo.Context, req *stimulus.UpdateStimulusRequest) (*stimulus.StimulusResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	check := &contentCheck{}
+	if req.Content != nil {
+		check.sanitize("content", req.Content, content.MaxStimulusLength)
+	}
+	if err := s.verify(ctx, check); err != nil {
+		return nil, err
+	}
+
+	st, err := s.stimulusRepo.Update(ctx.Request().Context(), req)
+	if err != nil {
+		logger.Error().Err(err).Str("stimulus_id", req.ID).Msg("failed to update stimulus")
+		return nil, err
+	}
+
+	response := st.ToResponse()
+	return &response, nil
+}
+
+// UpdateQuestionContent replaces a question's text and options with sanitized rich content
+func (s *ContentService) UpdateQuestionContent(ctx echo.Context, clerkID string, req *question.UpdateQuestionContentRequest) (*question.QuestionDetailResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	editor, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	check := &contentCheck{}
+	check.sanitize("text", &req.Text, content.MaxQuestionLength)
+	for i := range req.Options {
+		check.sanitize(fmt.Sprintf("options[%d]", i), &req.Options[i], content.MaxOptionLength)
+	}
+	if req.Explanation != nil {
+		check.sanitize("explanation", req.Explanation, content.MaxExplanationLength)
+	}
+	if req.ExplanationEn != nil {
+		check.sanitize("explanation_en", req.ExplanationEn, content.MaxExplanationLength)
+	}
+	if err := s.verify(ctx, check); err != nil {
+		return nil, err
+	}
+
+	q, err := s.questionRepo.UpdateContent(ctx.Request().Context(), req)
+	if err != nil {
+		logger.Error().Err(err).Str("question_id", req.ID).Msg("failed to update question content")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "question_content_updated").
+		Str("question_id", req.ID).
+		Str("editor_id", editor.ID.String()).
+		Msg("Question content updated")
+
+	response := q.ToDetailResponse()
+	if q.StimulusID != nil {
+		st, err := s.stimulusRepo.GetByID(ctx.Request().Context(), q.StimulusID.String())
+		if err != nil {
+			return nil, err
+		}
+		stimulusResponse := st.ToResponse()
+		response.Stimulus = &stimulusResponse
+	}
+
+	return &response, nil
+}
+
+// PreviewContent sanitizes and renders content source without saving it
+func (s *ContentService) PreviewContent(ctx echo.Context, req *question.PreviewContentRequest) (*question.PreviewContentResponse, error) {
+	clean, problems := content.Sanitize(req.Content, content.MaxStimulusLength)
+	doc := content.Parse(clean)
+
+	missing, err := s.mediaRepo.MissingIDs(ctx.Request().Context(), content.AssetIDs(doc))
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range missing {
+		problems = append(problems, "references unknown media "+id)
+	}
+
+	return &question.PreviewContentResponse{
+		Rich:     content.Rich{Raw: clean, Doc: doc},
+		Problems: append([]string{}, problems...),
+	}, nil
+}
+
+// verify rejects content with sanitizing problems or references to unknown media
+func (s *ContentService) verify(ctx echo.Context, check *contentCheck) error {
+	missing, err := s.mediaRepo.MissingIDs(ctx.Request().Context(), check.assetIDs)
+	if err != nil {
+		return err
+	}
+	for _, id := range missing {
+		field := check.assetFields[id]
+		check.fieldErrors = append(check.fieldErrors, errs.FieldError{Field: field, Error: "references unknown media " + id})
+	}
+
+	if len(check.fieldErrors) == 0 {
+		return nil
+	}
+
+	code := "INVALID_CONTENT"
+	return errs.NewBadRequestError("content is invalid", false, &code, check.fieldErrors, nil)
+}
+
+// contentCheck sanitizes the content fields of a request in place, collecting their
+// problems and the media they reference
+type contentCheck struct {
+	fieldErrors []errs.FieldError
+	assetIDs    []string
+	// assetFields is the first field referencing each asset
+	assetFields map[string]string
+}
+
+func (c *contentCheck) sanitize(field string, raw *string, maxLength int) {
+	clean, problems := content.Sanitize(*raw, maxLength)
+	*raw = clean
+
+	for _, p := range problems {
+		c.fieldErrors = append(c.fieldErrors, errs.FieldError{Field: field, Error: p})
+	}
+
+	if c.assetFields == nil {
+		c.assetFields = map[string]string{}
+	}
+	for _, id := range content.AssetIDs(content.Parse(clean)) {
+		if _, seen := c.assetFields[id]; !seen {
+			c.assetFields[id] = field
+			c.assetIDs = append(c.assetIDs, id)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/job"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model"
+	"github.com/manikandareas/genta/internal/model/duplicate"
+	"github.com/manikandareas/genta/internal/repository"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+type DuplicateService struct {
+	server        *server.Server
+	duplicateRepo *repository.DuplicateRepository
+	userRepo      *repository.UserRepository
+	jobService    *job.JobService
+}
+
+func NewDuplicateService(server *server.Server, duplicateRepo *repository.DuplicateRepository, userRepo *repository.UserRepository, jobService *job.JobService) *DuplicateService {
+	return &DuplicateService{
+		server:        server,
+		duplicateRepo: duplicateRepo,
+		userRepo:      userRepo,
+		jobService:    jobService,
+	}
+}
+
+// Scan enqueues a rescan of the bank for likely duplicate questions
+func (s *DuplicateService) Scan(ctx echo.Context, clerkID string, req *duplicate.ScanRequest) (*duplicate.ScanJobResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	editor, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	if s.jobService == nil {
+		logger.Error().Msg("job service not available")
+		return nil, errs.NewInternalServerError()
+	}
+
+	var section string
+	if req.Section != nil {
+		section = *req.Section
+	}
+	var threshold float64
+	if req.Threshold != nil {
+		threshold = *req.Threshold
+	}
+
+	task, err := job.NewQuestionDuplicateScanTask(section, threshold, editor.ID.String())
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create question duplicate scan task")
+		return nil, err
+	}
+
+	info, err := s.jobService.Client.Enqueue(task)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to enqueue question duplicate scan task")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "question_duplicate_scan_requested").
+		Str("job_id", info.ID).
+		Str("section", section).
+		Msg("Question duplicate scan enqueued")
+
+	response := duplicate.NewScanJobResponse(info.ID)
+	return &response, nil
+}
+
+// ListClusters lists duplicate clusters with their questions
+func (s *DuplicateService) ListClusters(ctx echo.Context, req *duplicate.ListClustersRequest) (*model.PaginatedResponse[duplicate.ClusterResponse], error) {
+	logger := middleware.GetLogger(ctx)
+
+	params := repository.PageParams{Page: req.Page, Limit: req.Limit, Cursor: req.Cursor, IncludeTotal: req.IncludeTotal}
+
+	page, err := s.duplicateRepo.ListClusters(ctx.Request().Context(), req, params)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to list duplicate clusters")
+		return nil, err
+	}
+
+	clusterIDs := make([]uuid.UUID, len(page.Items))
+	for i := range page.Items {
+		clusterIDs[i] = page.Items[i].ID
+	}
+
+	members, err := s.duplicateRepo.GetMembers(ctx.Request().Context(), clusterIDs)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get duplicate cluster members")
+		return nil, err
+	}
+
+	membersByCluster := make(map[uuid.UUID][]duplicate.Member)
+	for _, m := range members {
+		membersByCluster[m.ClusterID] = append(membersByCluster[m.ClusterID], m)
+	}
+
+	return paginatedResponse(ctx, page, params, func(c *duplicate.Cluster) duplicate.ClusterResponse {
+		return c.ToResponse(membersByCluster[c.ID])
+	}), nil
+}
+
+// GetCluster retrieves a duplicate cluster with its questions
+func (s *DuplicateService) GetCluster(ctx echo.Context, clusterID string) (*duplicate.ClusterResponse, error) {
+	c, err := s.duplicateRepo.GetCluster(ctx.Request().Context(), clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.clusterResponse(ctx, c)
+}
+
+// ResolveCluster merges, deactivates or dismisses a pending duplicate cluster
+func (s *DuplicateService) ResolveCluster(ctx echo.Context, clerkID string, req *duplicate.ResolveClusterRequest) (*duplicate.ClusterResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	editor, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	c, err := s.duplicateRepo.Resolve(ctx.Request().Context(), req, editor.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("cluster_id", req.ID).Msg("failed to resolve duplicate cluster")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "question_duplicates_resolved").
+		Str("cluster_id", req.ID).
+		Str("action", string(req.Action)).
+		Str("editor_id", editor.ID.String()).
+		Msg("Duplicate cluster resolved")
+
+	return s.clusterResponse(ctx, c)
+}
+
+func (s *DuplicateService) clusterResponse(ctx echo.Context, c *duplicate.Cluster) (*duplicate.ClusterResponse, error) {
+	members, err := s.duplicateRepo.GetMembers(ctx.Request().Context(), []uuid.UUID{c.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	response := c.ToResponse(members)
+	return &response, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/job"
+	"github.com/manikandareas/genta/internal/lib/llm"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model"
+	"github.com/manikandareas/genta/internal/model/attempt"
+	"github.com/manikandareas/genta/internal/repository"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+type FeedbackQualityService struct {
+	server      *server.Server
+	attemptRepo *repository.AttemptRepository
+	jobService  *job.JobService
+}
+
+func NewFeedbackQualityService(server *server.Server, attemptRepo *repository.AttemptRepository, jobService *job.JobService) *FeedbackQualityService {
+	return &FeedbackQualityService{
+		server:      server,
+		attemptRepo: attemptRepo,
+		jobService:  jobService,
+	}
+}
+
+// ListFlagged returns feedback flagged by quality evaluation for human review
+func (s *FeedbackQualityService) ListFlagged(ctx echo.Context, req *attempt.ListFlaggedFeedbackRequest) (*model.PaginatedResponse[attempt.FlaggedFeedbackResponse], error) {
+	logger := middleware.GetLogger(ctx)
+
+	params := repository.PageParams{Page: req.Page, Limit: req.Limit, Cursor: req.Cursor, IncludeTotal: req.IncludeTotal}
+
+	page, err := s.attemptRepo.ListFlaggedFeedback(ctx.Request().Context(), params)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to list flagged feedback")
+		return nil, err
+	}
+
+	return paginatedResponse(ctx, page, params, func(f *attempt.FlaggedFeedback) attempt.FlaggedFeedbackResponse {
+		return f.ToResponse()
+	}), nil
+}
+
+// Review either accepts flagged feedback as is or queues a regeneration that replaces it
+func (s *FeedbackQualityService) Review(ctx echo.Context, req *attempt.ReviewFeedbackRequest) (*attempt.ReviewFeedbackResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	f, err := s.attemptRepo.GetFeedbackForReview(ctx.Request().Context(), req.FeedbackID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Action == "ok" {
+		if err := s.attemptRepo.MarkFeedbackReviewed(ctx.Request().Context(), req.FeedbackID); err != nil {
+			logger.Error().Err(err).Str("feedback_id", req.FeedbackID).Msg("failed to mark feedback reviewed")
+			return nil, err
+		}
+
+		logger.Info().
+			Str("event", "feedback_reviewed").
+			Str("feedback_id", req.FeedbackID).
+			Msg("Flagged feedback accepted")
+
+		return &attempt.ReviewFeedbackResponse{
+			FeedbackID:    f.ID,
+			AttemptID:     f.AttemptID,
+			QualityStatus: attempt.QualityStatusReviewed,
+		}, nil
+	}
+
+	if s.jobService == nil || s.jobService.Client == nil {
+		logger.Error().Str("feedback_id", req.FeedbackID).Msg("job service unavailable for feedback regeneration")
+		return nil, errs.NewInternalServerError()
+	}
+
+	lang := string(llm.LangIndonesian)
+	if f.FeedbackLang != nil {
+		lang = *f.FeedbackLang
+	}
+
+	task, err := job.NewFeedbackRegenerationTask(
+		f.AttemptID.String(),
+		f.UserID.String(),
+		f.QuestionID.String(),
+		f.IsCorrect,
+		lang,
+		f.SubscriptionTier == personalizedFeedbackTier,
+	)
+	if err != nil {
+		logger.Error().Err(err).Str("feedback_id", req.FeedbackID).Msg("failed to create feedback regeneration task")
+		return nil, errs.NewInternalServerError()
+	}
+
+	info, err := s.jobService.Client.Enqueue(task)
+	if err != nil {
+		logger.Error().Err(err).Str("feedback_id", req.FeedbackID).Msg("failed to enqueue feedback regeneration task")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "feedback_regeneration_requested").
+		Str("feedback_id", req.FeedbackID).
+		Str("job_id", info.ID).
+		Msg("Feedback regeneration queued")
+
+	status := attempt.QualityStatusFlagged
+	if f.QualityStatus != nil {
+		status = *f.QualityStatus
+	}
+
+	return &attempt.ReviewFeedbackResponse{
+		FeedbackID:    f.ID,
+		AttemptID:     f.AttemptID,
+		QualityStatus: status,
+		Job: &attempt.JobResponse{
+			JobID:                      info.ID,
+			Status:                     "queued",
+			EstimatedCompletionSeconds: 8,
+			CheckStatusURL:             "/api/v1/jobs/" + info.ID + "/check",
+		},
+	}, nil
+}
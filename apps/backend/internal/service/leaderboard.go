@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/leaderboard"
+	"github.com/manikandareas/genta/internal/repository"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+// weeklyRankingTTL keeps past weekly rankings in Redis for a few weeks; older seasons are
+// rebuilt from Postgres on demand
+const weeklyRankingTTL = 5 * 7 * 24 * time.Hour
+
+type LeaderboardService struct {
+	server          *server.Server
+	leaderboardRepo *repository.LeaderboardRepository
+	userRepo        *repository.UserRepository
+}
+
+func NewLeaderboardService(
+	server *server.Server,
+	leaderboardRepo *repository.LeaderboardRepository,
+	userRepo *repository.UserRepository,
+) *LeaderboardService {
+	return &LeaderboardService{
+		server:          server,
+		leaderboardRepo: leaderboardRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// RecordAttempt counts an answer toward the user's weekly and all-time section leaderboards.
+// It runs after readiness is recomputed so the entry picks up the new theta.
+func (s *LeaderboardService) RecordAttempt(ctx context.Context, userID uuid.UUID, section string) error {
+	now := time.Now()
+	week := leaderboard.WeekKey(now)
+	weekStart, err := leaderboard.WeekStart(week)
+	if err != nil {
+		return err
+	}
+
+	settings, err := s.leaderboardRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	seasons := []struct {
+		key   string
+		start *time.Time
+		ttl   time.Duration
+	}{
+		{key: week, start: &weekStart, ttl: weeklyRankingTTL},
+		{key: leaderboard.AllTimeKey},
+	}
+
+	for _, season := range seasons {
+		entry, err := s.leaderboardRepo.RecordAttempt(ctx, userID, section, season.key, season.start)
+		if err != nil {
+			return err
+		}
+		if entry == nil || settings.OptOut {
+			continue
+		}
+		if err := s.index(ctx, entry, season.ttl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// index mirrors an entry into its sorted set, building the set from Postgres when it is
+// missing so a partial set is never served. The score is set after a build as well, since
+// a concurrent build from an older snapshot may have won the rename.
+func (s *LeaderboardService) index(ctx context.Context, entry *leaderboard.Entry, ttl time.Duration) error {
+	key := repository.RankingKey(entry.Section, entry.Season)
+	built, err := s.leaderboardRepo.HasRanking(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !built {
+		if err := s.buildRanking(ctx, entry.Section, entry.Season, ttl); err != nil {
+			return err
+		}
+	}
+	return s.leaderboardRepo.SetRankingScore(ctx, key, entry.UserID, entry.Score)
+}
+
+func (s *LeaderboardService) buildRanking(ctx context.Context, section, season string, ttl time.Duration) error {
+	entries, err := s.leaderboardRepo.GetSeasonEntries(ctx, section, season)
+	if err != nil {
+		return err
+	}
+	return s.leaderboardRepo.BuildRanking(ctx, repository.RankingKey(section, season), entries, ttl)
+}
+
+// rankingPage is one page of a ranking, highest score first
+type rankingPage struct {
+	userIDs []uuid.UUID
+	offset  int
+	total   int64
+	myRank  int64
+	ranked  bool
+}
+
+// pageOffset returns the requested offset, or centers the page on the caller's rank
+func pageOffset(req *leaderboard.GetLeaderboardRequest, myRank int64, ranked bool) int {
+	switch {
+	case req.Offset != nil:
+		return *req.Offset
+	case ranked:
+		return max(int(myRank)-req.Limit/2, 0)
+	}
+	return 0
+}
+
+// redisRanking reads a page from the sorted set, building it first when it is missing
+func (s *LeaderboardService) redisRanking(ctx context.Context, req *leaderboard.GetLeaderboardRequest, seasonKey string, ttl time.Duration, userID uuid.UUID) (*rankingPage, error) {
+	key := repository.RankingKey(req.Section, seasonKey)
+	built, err := s.leaderboardRepo.HasRanking(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !built {
+		if err := s.buildRanking(ctx, req.Section, seasonKey, ttl); err != nil {
+			return nil, err
+		}
+	}
+
+	myRank, ranked, err := s.leaderboardRepo.GetRank(ctx, key, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := pageOffset(req, myRank, ranked)
+	page, total, err := s.leaderboardRepo.GetRankingPage(ctx, key, offset, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(page))
+	for _, z := range page {
+		if id, err := uuid.Parse(z.Member.(string)); err == nil {
+			userIDs = append(userIDs, id)
+		}
+	}
+
+	return &rankingPage{userIDs: userIDs, offset: offset, total: total, myRank: myRank, ranked: ranked}, nil
+}
+
+// postgresRanking ranks the season entries in memory, for when Redis is unavailable. Ties
+// are ordered as the sorted set orders them, by member descending.
+func (s *LeaderboardService) postgresRanking(ctx context.Context, req *leaderboard.GetLeaderboardRequest, seasonKey string, userID uuid.UUID) (*rankingPage, error) {
+	entries, err := s.leaderboardRepo.GetSeasonEntries(ctx, req.Section, seasonKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].UserID.String() > entries[j].UserID.String()
+	})
+
+	myRank, ranked := int64(0), false
+	for i, e := range entries {
+		if e.UserID == userID {
+			myRank, ranked = int64(i), true
+			break
+		}
+	}
+
+	offset := pageOffset(req, myRank, ranked)
+	start := min(offset, len(entries))
+	end := min(start+req.Limit, len(entries))
+
+	userIDs := make([]uuid.UUID, 0, end-start)
+	for _, e := range entries[start:end] {
+		userIDs = append(userIDs, e.UserID)
+	}
+
+	return &rankingPage{userIDs: userIDs, offset: offset, total: int64(len(entries)), myRank: myRank, ranked: ranked}, nil
+}
+
+// Get returns a page of a section leaderboard. Without an offset the page is centered on
+// the caller's rank, or starts at the top when the caller is not ranked. Rankings are read
+// from Redis, falling back to Postgres when Redis is unavailable.
+func (s *LeaderboardService) Get(ctx echo.Context, clerkID string, req *leaderboard.GetLeaderboardRequest) (*leaderboard.LeaderboardResponse, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	u, err := s.userRepo.GetUserByClerkID(reqCtx, clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	req.SetDefaults()
+
+	seasonKey, ttl := leaderboard.AllTimeKey, time.Duration(0)
+	if req.Season == leaderboard.SeasonWeekly {
+		seasonKey, ttl = req.Week, weeklyRankingTTL
+		if seasonKey == "" {
+			seasonKey = leaderboard.WeekKey(time.Now())
+		}
+	}
+
+	settings, err := s.leaderboardRepo.GetSettings(reqCtx, u.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get leaderboard settings")
+		return nil, err
+	}
+
+	page, err := s.redisRanking(reqCtx, req, seasonKey, ttl, u.ID)
+	if err != nil {
+		logger.Warn().Err(err).Str("key", repository.RankingKey(req.Section, seasonKey)).
+			Msg("leaderboard ranking unavailable in Redis, falling back to Postgres")
+		page, err = s.postgresRanking(reqCtx, req, seasonKey, u.ID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to rank leaderboard entries")
+			return nil, err
+		}
+	}
+
+	// Hydrate ranked users, plus the caller when they fall outside the page
+	userIDs := make([]uuid.UUID, 0, len(page.userIDs)+1)
+	userIDs = append(userIDs, page.userIDs...)
+	if page.ranked {
+		userIDs = append(userIDs, u.ID)
+	}
+
+	entries, err := s.leaderboardRepo.GetRankedEntries(reqCtx, req.Section, seasonKey, userIDs)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get leaderboard entries")
+		return nil, err
+	}
+	byUser := make(map[uuid.UUID]leaderboard.RankedEntry, len(entries))
+	for _, e := range entries {
+		byUser[e.UserID] = e
+	}
+
+	response := &leaderboard.LeaderboardResponse{
+		Section:   req.Section,
+		Season:    req.Season,
+		SeasonKey: seasonKey,
+		Total:     page.total,
+		Offset:    page.offset,
+		Limit:     req.Limit,
+		HasMore:   int64(page.offset+len(page.userIDs)) < page.total,
+		Entries:   make([]leaderboard.LeaderboardEntryResponse, 0, len(page.userIDs)),
+		OptedOut:  settings.OptOut,
+	}
+
+	for i, id := range page.userIDs {
+		entry, ok := byUser[id]
+		if !ok {
+			continue
+		}
+		response.Entries = append(response.Entries, toLeaderboardEntryResponse(entry, int64(page.offset+i+1), u.ID))
+	}
+
+	if entry, ok := byUser[u.ID]; ok && page.ranked {
+		me := toLeaderboardEntryResponse(entry, page.myRank+1, u.ID)
+		response.Me = &me
+	}
+
+	return response, nil
+}
+
+// GetSettings returns the caller's leaderboard privacy settings
+func (s *LeaderboardService) GetSettings(ctx echo.Context, clerkID string) (*leaderboard.LeaderboardSettingsResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	u, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	settings, err := s.leaderboardRepo.GetSettings(ctx.Request().Context(), u.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get leaderboard settings")
+		return nil, err
+	}
+
+	return toLeaderboardSettingsResponse(settings), nil
+}
+
+// UpdateSettings changes the caller's opt-out and pseudonym. Opting out removes the user
+// from every built ranking right away; opting back in restores them.
+func (s *LeaderboardService) UpdateSettings(ctx echo.Context, clerkID string, req *leaderboard.UpdateLeaderboardSettingsRequest) (*leaderboard.LeaderboardSettingsResponse, error) {
+	logger := middleware.GetLogger(ctx)
+	reqCtx := ctx.Request().Context()
+
+	u, err := s.userRepo.GetUserByClerkID(reqCtx, clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	settings, err := s.leaderboardRepo.GetSettings(reqCtx, u.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get leaderboard settings")
+		return nil, err
+	}
+
+	wasOptedOut := settings.OptOut
+	if req.OptOut != nil {
+		settings.OptOut = *req.OptOut
+	}
+	if req.Pseudonym != nil {
+		settings.Pseudonym = req.Pseudonym
+		if *req.Pseudonym == "" {
+			settings.Pseudonym = nil
+		}
+	}
+
+	settings, err = s.leaderboardRepo.SaveSettings(reqCtx, settings)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to save leaderboard settings")
+		return nil, err
+	}
+
+	if settings.OptOut != wasOptedOut {
+		if err := s.syncRankings(reqCtx, u.ID, settings.OptOut); err != nil {
+			logger.Error().Err(err).Msg("failed to sync leaderboard rankings")
+			return nil, err
+		}
+	}
+
+	logger.Info().
+		Str("event", "leaderboard_settings_updated").
+		Str("user_id", u.ID.String()).
+		Bool("opt_out", settings.OptOut).
+		Msg("Leaderboard settings updated")
+
+	return toLeaderboardSettingsResponse(settings), nil
+}
+
+// syncRankings removes or restores the user's entries in every ranking already built
+func (s *LeaderboardService) syncRankings(ctx context.Context, userID uuid.UUID, optOut bool) error {
+	entries, err := s.leaderboardRepo.GetUserEntries(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		key := repository.RankingKey(entry.Section, entry.Season)
+		built, err := s.leaderboardRepo.HasRanking(ctx, key)
+		if err != nil {
+			return err
+		}
+		switch {
+		case !built:
+			continue
+		case optOut:
+			err = s.leaderboardRepo.RemoveFromRanking(ctx, key, userID)
+		default:
+			err = s.leaderboardRepo.SetRankingScore(ctx, key, userID, entry.Score)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func toLeaderboardEntryResponse(entry leaderboard.RankedEntry, rank int64, callerID uuid.UUID) leaderboard.LeaderboardEntryResponse {
+	return leaderboard.LeaderboardEntryResponse{
+		Rank:        rank,
+		DisplayName: leaderboard.DisplayName(entry.UserID, entry.Pseudonym),
+		Score:       entry.Score,
+		ThetaGain:   math.Round(entry.ThetaGain()*1000) / 1000,
+		Attempts:    entry.Attempts,
+		IsMe:        entry.UserID == callerID,
+	}
+}
+
+func toLeaderboardSettingsResponse(settings *leaderboard.Settings) *leaderboard.LeaderboardSettingsResponse {
+	return &leaderboard.LeaderboardSettingsResponse{
+		OptOut:      settings.OptOut,
+		Pseudonym:   settings.Pseudonym,
+		DisplayName: leaderboard.DisplayName(settings.UserID, settings.Pseudonym),
+	}
+}
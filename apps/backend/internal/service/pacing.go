@@ -0,0 +1,191 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/llm"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/analytics"
+)
+
+const (
+	// minPacingAttempts is how many timed answers a section needs before it gets recommendations
+	minPacingAttempts = 10
+
+	overBudgetWarningShare  = 0.3
+	overBudgetCriticalShare = 0.5
+	// rushingShare is the share of fast wrong answers that signals rushing
+	rushingShare = 0.2
+	// overthinkingShare is the share of very slow wrong answers that signals overthinking
+	overthinkingShare = 0.15
+	// slowerThanCohortRatio is how much slower than the cohort median counts as slow
+	slowerThanCohortRatio = 1.25
+)
+
+// pacingMessages holds the recommendation message formats per language
+var pacingMessages = map[llm.Language]map[string]string{
+	llm.LangIndonesian: {
+		analytics.PacingOverBudget:       "%.0f%% soal %s kamu kerjakan melebihi batas waktu %d detik per soal. Latih strategi lewati dulu soal sulit agar waktu tidak habis.",
+		analytics.PacingRushing:          "%.0f%% jawaban %s kamu salah padahal dikerjakan kurang dari setengah batas waktu. Baca soal lebih teliti sebelum menjawab.",
+		analytics.PacingOverthinking:     "%.0f%% jawaban %s kamu salah setelah lebih dari 1,5x batas waktu. Jika buntu, tandai soal dan lanjutkan ke soal berikutnya.",
+		analytics.PacingSlowerThanCohort: "Median waktumu di %s %.0f detik, lebih lambat dari median pengguna lain (%.0f detik). Latih soal serupa dengan timer.",
+		analytics.PacingOnPace:           "Kecepatanmu di %s sudah sesuai batas waktu UTBK. Pertahankan!",
+	},
+	llm.LangEnglish: {
+		analytics.PacingOverBudget:       "%.0f%% of your %s answers exceeded the %d-second budget per question. Practice skipping hard questions first so you don't run out of time.",
+		analytics.PacingRushing:          "%.0f%% of your %s answers were wrong after less than half the time budget. Read questions more carefully before answering.",
+		analytics.PacingOverthinking:     "%.0f%% of your %s answers were wrong after more than 1.5x the time budget. When stuck, flag the question and move on.",
+		analytics.PacingSlowerThanCohort: "Your median time in %s is %.0f seconds, slower than other students' median (%.0f seconds). Practice similar questions with a timer.",
+		analytics.PacingOnPace:           "Your pace in %s is within the UTBK time budget. Keep it up!",
+	},
+}
+
+// GetPacing retrieves time-management analytics per section against UTBK time budgets
+func (s *AnalyticsService) GetPacing(ctx echo.Context, clerkID string, req *analytics.GetPacingRequest) (*analytics.PacingResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	user, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	req.SetDefaults()
+
+	period, err := resolvePeriod(req.From, req.To, req.Days, req.Timezone, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	filter := analytics.Filter{
+		From:      period.from.UTC(),
+		To:        period.to.AddDate(0, 0, 1).UTC(),
+		UTCOffset: period.utcOffset,
+		Sections:  req.Sections,
+	}
+
+	stats, err := s.analyticsRepo.GetPacingStats(ctx.Request().Context(), user.ID, filter)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get pacing stats")
+		return nil, err
+	}
+
+	messages, ok := pacingMessages[llm.Language(req.Lang)]
+	if !ok {
+		messages = pacingMessages[llm.LangIndonesian]
+	}
+
+	response := &analytics.PacingResponse{
+		From:            period.from.Format("2006-01-02"),
+		To:              period.to.Format("2006-01-02"),
+		Timezone:        req.Timezone,
+		Sections:        make([]analytics.SectionPacingResponse, 0, len(stats)),
+		Recommendations: []analytics.PacingRecommendation{},
+	}
+
+	for _, st := range stats {
+		response.Sections = append(response.Sections, toSectionPacingResponse(st))
+		response.Recommendations = append(response.Recommendations, pacingRecommendations(st, messages)...)
+	}
+
+	logger.Debug().
+		Str("user_id", user.ID.String()).
+		Int("sections", len(response.Sections)).
+		Int("recommendations", len(response.Recommendations)).
+		Msg("Pacing analytics retrieved")
+
+	return response, nil
+}
+
+// pacingRecommendations turns a section's timing stats into recommendations, most
+// pressing first; a section without issues gets an on-pace note
+func pacingRecommendations(st analytics.SectionPacing, messages map[string]string) []analytics.PacingRecommendation {
+	if st.Attempts < minPacingAttempts {
+		return nil
+	}
+
+	var recommendations []analytics.PacingRecommendation
+	add := func(kind, severity, metric string, value, threshold float64, message string) {
+		recommendations = append(recommendations, analytics.PacingRecommendation{
+			Section:   st.Section,
+			Type:      kind,
+			Severity:  severity,
+			Metric:    metric,
+			Value:     roundTo(value, 3),
+			Threshold: threshold,
+			Message:   message,
+		})
+	}
+
+	overBudget := share(st.OverBudget, st.Attempts)
+	if overBudget >= overBudgetWarningShare {
+		severity := analytics.SeverityWarning
+		if overBudget >= overBudgetCriticalShare {
+			severity = analytics.SeverityCritical
+		}
+		add(analytics.PacingOverBudget, severity, "over_budget_share", overBudget, overBudgetWarningShare,
+			fmt.Sprintf(messages[analytics.PacingOverBudget], overBudget*100, st.Section, st.BudgetSeconds))
+	}
+
+	if rushing := share(st.FastWrong, st.Attempts); rushing >= rushingShare {
+		add(analytics.PacingRushing, analytics.SeverityWarning, "rushing_share", rushing, rushingShare,
+			fmt.Sprintf(messages[analytics.PacingRushing], rushing*100, st.Section))
+	}
+
+	if overthinking := share(st.OverthinkingWrong, st.Attempts); overthinking >= overthinkingShare {
+		add(analytics.PacingOverthinking, analytics.SeverityWarning, "overthinking_share", overthinking, overthinkingShare,
+			fmt.Sprintf(messages[analytics.PacingOverthinking], overthinking*100, st.Section))
+	}
+
+	if st.CohortMedianSeconds != nil && *st.CohortMedianSeconds > 0 {
+		ratio := st.MedianTimeSeconds / *st.CohortMedianSeconds
+		if ratio >= slowerThanCohortRatio {
+			add(analytics.PacingSlowerThanCohort, analytics.SeverityInfo, "cohort_time_ratio", ratio, slowerThanCohortRatio,
+				fmt.Sprintf(messages[analytics.PacingSlowerThanCohort], st.Section, st.MedianTimeSeconds, *st.CohortMedianSeconds))
+		}
+	}
+
+	if len(recommendations) == 0 {
+		add(analytics.PacingOnPace, analytics.SeverityInfo, "over_budget_share", overBudget, overBudgetWarningShare,
+			fmt.Sprintf(messages[analytics.PacingOnPace], st.Section))
+	}
+
+	return recommendations
+}
+
+func toSectionPacingResponse(st analytics.SectionPacing) analytics.SectionPacingResponse {
+	return analytics.SectionPacingResponse{
+		Section:             st.Section,
+		SectionName:         analytics.GetSectionName(st.Section),
+		BudgetSeconds:       st.BudgetSeconds,
+		Attempts:            st.Attempts,
+		Accuracy:            roundTo(share(st.Correct, st.Attempts), 3),
+		AvgTimeSeconds:      roundTo(st.AvgTimeSeconds, 1),
+		MedianTimeSeconds:   roundTo(st.MedianTimeSeconds, 1),
+		CohortMedianSeconds: st.CohortMedianSeconds,
+		OverBudgetShare:     roundTo(share(st.OverBudget, st.Attempts), 3),
+		RushingShare:        roundTo(share(st.FastWrong, st.Attempts), 3),
+		OverthinkingShare:   roundTo(share(st.OverthinkingWrong, st.Attempts), 3),
+		SpeedAccuracyCorr:   st.SpeedAccuracyCorr,
+		SpeedBands: []analytics.SpeedBandResponse{
+			{Band: "fast", Attempts: st.FastAttempts, Accuracy: roundTo(share(st.FastCorrect, st.FastAttempts), 3)},
+			{Band: "normal", Attempts: st.NormalAttempts, Accuracy: roundTo(share(st.NormalCorrect, st.NormalAttempts), 3)},
+			{Band: "slow", Attempts: st.SlowAttempts, Accuracy: roundTo(share(st.SlowCorrect, st.SlowAttempts), 3)},
+		},
+	}
+}
+
+func share(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
+
+func roundTo(v float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/model"
+	"github.com/manikandareas/genta/internal/repository"
+)
+
+// paginatedResponse converts a repository page into the list response. Legacy page/limit
+// requests get page counts; cursor requests get cursors and links to the neighbouring pages.
+func paginatedResponse[T any, R any](ctx echo.Context, page *repository.Page[T], params repository.PageParams, convert func(*T) R) *model.PaginatedResponse[R] {
+	response := &model.PaginatedResponse[R]{
+		Data:  make([]R, len(page.Items)),
+		Limit: params.Limit,
+		Total: page.Total,
+	}
+	for i := range page.Items {
+		response.Data[i] = convert(&page.Items[i])
+	}
+
+	if params.Page > 0 {
+		response.Page = params.Page
+		if page.Total != nil {
+			totalPages := *page.Total / params.Limit
+			if *page.Total%params.Limit > 0 {
+				totalPages++
+			}
+			response.TotalPages = &totalPages
+		}
+		return response
+	}
+
+	response.NextCursor = page.NextCursor
+	response.PrevCursor = page.PrevCursor
+	if page.NextCursor != nil || page.PrevCursor != nil {
+		response.Links = &model.PageLinks{
+			Next: pageLink(ctx, page.NextCursor),
+			Prev: pageLink(ctx, page.PrevCursor),
+		}
+	}
+
+	return response
+}
+
+// pageLink is the current request URL with its cursor replaced
+func pageLink(ctx echo.Context, cursor *string) *string {
+	if cursor == nil {
+		return nil
+	}
+
+	u := *ctx.Request().URL
+	query := u.Query()
+	query.Del("page")
+	query.Set("cursor", *cursor)
+	u.RawQuery = query.Encode()
+
+	link := u.RequestURI()
+	return &link
+}
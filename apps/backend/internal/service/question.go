@@ -13,13 +13,15 @@ import (
 type QuestionService struct {
 	server       *server.Server
 	questionRepo *repository.QuestionRepository
+	stimulusRepo *repository.StimulusRepository
 	userRepo     *repository.UserRepository
 }
 
-func NewQuestionService(server *server.Server, questionRepo *repository.QuestionRepository, userRepo *repository.UserRepository) *QuestionService {
+func NewQuestionService(server *server.Server, questionRepo *repository.QuestionRepository, stimulusRepo *repository.StimulusRepository, userRepo *repository.UserRepository) *QuestionService {
 	return &QuestionService{
 		server:       server,
 		questionRepo: questionRepo,
+		stimulusRepo: stimulusRepo,
 		userRepo:     userRepo,
 	}
 }
@@ -35,6 +37,8 @@ func (s *QuestionService) GetByID(ctx echo.Context, questionID string) (*questio
 	}
 
 	response := q.ToDetailResponse()
+	s.attachStimulus(ctx, &response.QuestionResponse)
+
 	return &response, nil
 }
 
@@ -42,30 +46,39 @@ func (s *QuestionService) GetByID(ctx echo.Context, questionID string) (*questio
 func (s *QuestionService) List(ctx echo.Context, req *question.ListQuestionsRequest) (*model.PaginatedResponse[question.QuestionResponse], error) {
 	logger := middleware.GetLogger(ctx)
 
-	questions, total, err := s.questionRepo.List(ctx.Request().Context(), req)
+	params := repository.PageParams{Page: req.Page, Limit: req.Limit, Cursor: req.Cursor, IncludeTotal: req.IncludeTotal}
+
+	page, err := s.questionRepo.List(ctx.Request().Context(), req, params)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to list questions")
 		return nil, err
 	}
 
 	// Convert to response (hide correct answers)
-	responses := make([]question.QuestionResponse, len(questions))
-	for i, q := range questions {
-		responses[i] = q.ToResponse()
+	return paginatedResponse(ctx, page, params, func(q *question.Question) question.QuestionResponse {
+		return q.ToResponse()
+	}), nil
+}
+
+// Search finds questions by content, most relevant first
+func (s *QuestionService) Search(ctx echo.Context, req *question.SearchQuestionsRequest) (*question.SearchQuestionsResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	results, err := s.questionRepo.Search(ctx.Request().Context(), req)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to search questions")
+		return nil, err
 	}
 
-	totalPages := total / req.Limit
-	if total%req.Limit > 0 {
-		totalPages++
+	response := &question.SearchQuestionsResponse{
+		Query:   req.Q,
+		Results: make([]question.SearchResultResponse, len(results)),
+	}
+	for i := range results {
+		response.Results[i] = results[i].ToResponse()
 	}
 
-	return &model.PaginatedResponse[question.QuestionResponse]{
-		Data:       responses,
-		Page:       req.Page,
-		Limit:      req.Limit,
-		Total:      total,
-		TotalPages: totalPages,
-	}, nil
+	return response, nil
 }
 
 // GetNext retrieves the next question for a user based on section
@@ -91,6 +104,7 @@ func (s *QuestionService) GetNext(ctx echo.Context, clerkID string, section stri
 	}
 
 	response := q.ToResponse()
+	s.attachStimulus(ctx, &response)
 
 	logger.Info().
 		Str("event", "question_served").
@@ -101,3 +115,23 @@ func (s *QuestionService) GetNext(ctx echo.Context, clerkID string, section stri
 
 	return &response, nil
 }
+
+// attachStimulus adds the shared passage a question refers to. Lists leave it out and
+// carry only stimulus_id, since many questions there share the same stimulus.
+func (s *QuestionService) attachStimulus(ctx echo.Context, response *question.QuestionResponse) {
+	if response.StimulusID == nil {
+		return
+	}
+
+	st, err := s.stimulusRepo.GetByID(ctx.Request().Context(), response.StimulusID.String())
+	if err != nil {
+		middleware.GetLogger(ctx).Warn().Err(err).
+			Str("question_id", response.ID.String()).
+			Str("stimulus_id", response.StimulusID.String()).
+			Msg("failed to get question stimulus")
+		return
+	}
+
+	stimulusResponse := st.ToResponse()
+	response.Stimulus = &stimulusResponse
+}
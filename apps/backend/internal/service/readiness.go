@@ -1,29 +1,52 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/llm"
+	"github.com/manikandareas/genta/internal/lib/scoring"
 	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/calibration"
 	"github.com/manikandareas/genta/internal/model/readiness"
+	"github.com/manikandareas/genta/internal/model/user"
 	"github.com/manikandareas/genta/internal/repository"
 	"github.com/manikandareas/genta/internal/server"
 )
 
+const (
+	minDailyPractice     = 5
+	maxDailyPractice     = 20
+	maxDailyPracticeLate = 40
+	defaultDailyPractice = 10
+
+	defaultHistoryGranularity = "day"
+	defaultHistoryDays        = 90
+)
+
 type ReadinessService struct {
-	server        *server.Server
-	readinessRepo *repository.ReadinessRepository
-	userRepo      *repository.UserRepository
+	server          *server.Server
+	readinessRepo   *repository.ReadinessRepository
+	userRepo        *repository.UserRepository
+	calibrationRepo *repository.CalibrationRepository
 }
 
 func NewReadinessService(
 	server *server.Server,
 	readinessRepo *repository.ReadinessRepository,
 	userRepo *repository.UserRepository,
+	calibrationRepo *repository.CalibrationRepository,
 ) *ReadinessService {
 	return &ReadinessService{
-		server:        server,
-		readinessRepo: readinessRepo,
-		userRepo:      userRepo,
+		server:          server,
+		readinessRepo:   readinessRepo,
+		userRepo:        userRepo,
+		calibrationRepo: calibrationRepo,
 	}
 }
 
@@ -58,7 +81,8 @@ func (s *ReadinessService) GetOverview(ctx echo.Context, clerkID string) (*readi
 	}
 
 	// Build response
-	response := s.buildOverviewResponse(allReadiness)
+	calibrations := s.activeCalibrations(ctx.Request().Context())
+	response := s.buildOverviewResponse(allReadiness, calibrations, user)
 
 	logger.Debug().
 		Str("user_id", user.ID.String()).
@@ -69,7 +93,8 @@ func (s *ReadinessService) GetOverview(ctx echo.Context, clerkID string) (*readi
 }
 
 // GetBySection retrieves detailed readiness for a specific section
-func (s *ReadinessService) GetBySection(ctx echo.Context, clerkID string, section string) (*readiness.SectionDetailResponse, error) {
+func (s *ReadinessService) GetBySection(ctx echo.Context, clerkID string, req *readiness.GetSectionReadinessRequest) (*readiness.SectionDetailResponse, error) {
+	section := req.Section
 	logger := middleware.GetLogger(ctx)
 
 	user, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
@@ -114,7 +139,8 @@ func (s *ReadinessService) GetBySection(ctx echo.Context, clerkID string, sectio
 	}
 
 	// Build response
-	response := s.buildSectionDetailResponse(ur, subtypes, trend)
+	calibrations := s.activeCalibrations(ctx.Request().Context())
+	response := s.buildSectionDetailResponse(ur, subtypes, trend, calibrations[section], user.ExamDate, llm.Language(req.Lang))
 
 	logger.Debug().
 		Str("user_id", user.ID.String()).
@@ -149,6 +175,7 @@ func (s *ReadinessService) UpdateTargetTheta(ctx echo.Context, clerkID string, s
 	}
 
 	response := ur.ToResponse()
+	applySectionPrediction(&response, ur, s.activeCalibrations(ctx.Request().Context())[section])
 
 	logger.Info().
 		Str("event", "target_theta_updated").
@@ -169,7 +196,7 @@ func (s *ReadinessService) RefreshReadiness(ctx echo.Context, clerkID string, se
 		return errs.NewNotFoundError("user not found", false, nil)
 	}
 
-	if err := s.readinessRepo.UpdateReadiness(ctx.Request().Context(), user.ID, section); err != nil {
+	if err := s.Recompute(ctx.Request().Context(), user.ID, section); err != nil {
 		logger.Error().Err(err).Str("section", section).Msg("failed to refresh readiness")
 		return err
 	}
@@ -182,10 +209,274 @@ func (s *ReadinessService) RefreshReadiness(ctx echo.Context, clerkID string, se
 	return nil
 }
 
+// Recompute refreshes a section's stats, folds the answers given since the stored theta
+// estimate into it and stores the predicted score under the active calibration. Theta is
+// estimated from every answer when there is no stored estimate, or when the answers it
+// covers no longer add up to the section total.
+func (s *ReadinessService) Recompute(ctx context.Context, userID uuid.UUID, section string) error {
+	if err := s.readinessRepo.UpdateReadiness(ctx, userID, section); err != nil {
+		return err
+	}
+
+	ur, err := s.readinessRepo.GetBySection(ctx, userID, section)
+	if err != nil {
+		return err
+	}
+
+	theta, se, through, folded, err := s.estimateTheta(ctx, ur)
+	if err != nil {
+		return err
+	}
+
+	cal := s.activeCalibrations(ctx)[section]
+	prediction := scoring.Predict(cal, theta, se)
+
+	if err := s.readinessRepo.UpdatePrediction(ctx, userID, section,
+		theta, se, through, folded, int(math.Round(prediction.Score)), prediction.Low, prediction.High, cal.Version); err != nil {
+		return err
+	}
+
+	return s.readinessRepo.InsertSnapshot(ctx, userID, section)
+}
+
+// estimateTheta updates the stored section estimate with the answers given since it was
+// made, returning the new estimate, the time of the last answer it covers and how many
+// answers it covers
+func (s *ReadinessService) estimateTheta(ctx context.Context, ur *readiness.UserReadiness) (float64, float64, *time.Time, int, error) {
+	if ur.ThetaThrough != nil && ur.CurrentTheta != nil && ur.ThetaSE != nil && *ur.ThetaSE > 0 {
+		responses, err := s.readinessRepo.GetSectionResponsesAfter(ctx, ur.UserID, ur.Section, *ur.ThetaThrough)
+		if err != nil {
+			return 0, 0, nil, 0, err
+		}
+
+		// Answers committed out of order would be skipped by the watermark; the count catches them
+		if folded := ur.ThetaResponses + len(responses); folded == ur.TotalAttemptsCount {
+			theta, se := scoring.UpdateTheta(*ur.CurrentTheta, *ur.ThetaSE, toScoringResponses(responses))
+			return theta, se, lastAnsweredAt(responses, ur.ThetaThrough), folded, nil
+		}
+	}
+
+	responses, err := s.readinessRepo.GetSectionResponses(ctx, ur.UserID, ur.Section, nil)
+	if err != nil {
+		return 0, 0, nil, 0, err
+	}
+
+	theta, se := scoring.EstimateTheta(toScoringResponses(responses))
+	return theta, se, lastAnsweredAt(responses, nil), len(responses), nil
+}
+
+// lastAnsweredAt returns the time of the last of responses ordered oldest first, or
+// fallback when there are none
+func lastAnsweredAt(responses []readiness.ItemResponse, fallback *time.Time) *time.Time {
+	if len(responses) == 0 {
+		return fallback
+	}
+	last := responses[len(responses)-1].AnsweredAt
+	return &last
+}
+
+// GetHistory retrieves theta and readiness time series per section
+func (s *ReadinessService) GetHistory(ctx echo.Context, clerkID string, req *readiness.GetReadinessHistoryRequest) (*readiness.ReadinessHistoryResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	user, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	granularity := req.Granularity
+	if granularity == "" {
+		granularity = defaultHistoryGranularity
+	}
+	days := req.Days
+	if days == 0 {
+		days = defaultHistoryDays
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -days+1)
+
+	points, err := s.readinessRepo.GetHistory(ctx.Request().Context(), user.ID, req.Section, granularity, from, to)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get readiness history")
+		return nil, err
+	}
+
+	response := &readiness.ReadinessHistoryResponse{
+		Granularity: granularity,
+		From:        from.Format("2006-01-02"),
+		To:          to.Format("2006-01-02"),
+		Series:      []readiness.SectionHistoryResponse{},
+	}
+
+	// Points arrive ordered by section, then period
+	for _, p := range points {
+		if n := len(response.Series); n == 0 || response.Series[n-1].Section != p.Section {
+			response.Series = append(response.Series, readiness.SectionHistoryResponse{Section: p.Section})
+		}
+		series := &response.Series[len(response.Series)-1]
+		series.Points = append(series.Points, p.ToResponse())
+	}
+
+	return response, nil
+}
+
+// GetPercentiles returns the user's percentile ranks against all users, users with the same
+// target PTN and users taking the exam in the same month, as last materialized by the nightly job
+func (s *ReadinessService) GetPercentiles(ctx echo.Context, clerkID string) (*readiness.ReadinessPercentilesResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	user, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	percentiles, err := s.readinessRepo.GetPercentiles(ctx.Request().Context(), user.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get readiness percentiles")
+		return nil, err
+	}
+
+	byCohort := make(map[string][]readiness.Percentile)
+	var computedAt *time.Time
+	for _, p := range percentiles {
+		byCohort[p.Cohort] = append(byCohort[p.Cohort], p)
+		if computedAt == nil || p.ComputedAt.After(*computedAt) {
+			computedAt = &p.ComputedAt
+		}
+	}
+
+	response := &readiness.ReadinessPercentilesResponse{
+		MinCohortSize: readiness.MinCohortSize,
+		Cohorts:       make([]readiness.CohortPercentileResponse, 0, len(readiness.Cohorts())),
+	}
+	if computedAt != nil {
+		ts := computedAt.Format("2006-01-02T15:04:05Z")
+		response.ComputedAt = &ts
+	}
+
+	for _, cohort := range readiness.Cohorts() {
+		cohortResponse := readiness.CohortPercentileResponse{
+			Cohort:   cohort,
+			Sections: []readiness.PercentileResponse{},
+		}
+		for _, p := range byCohort[cohort] {
+			pr := readiness.PercentileResponse{
+				Section:    p.Section,
+				Percentile: p.Percentile,
+				CohortSize: p.CohortSize,
+			}
+			if p.Section == readiness.SectionOverall {
+				cohortResponse.Overall = &pr
+				cohortResponse.CohortSize = p.CohortSize
+				continue
+			}
+			cohortResponse.Sections = append(cohortResponse.Sections, pr)
+		}
+		cohortResponse.Available = len(byCohort[cohort]) > 0
+		response.Cohorts = append(response.Cohorts, cohortResponse)
+	}
+
+	return response, nil
+}
+
+// PredictComposite returns the user's predicted overall UTBK score under the active calibration
+func (s *ReadinessService) PredictComposite(ctx context.Context, userID uuid.UUID) (scoring.Prediction, string, error) {
+	rows, err := s.readinessRepo.GetUserReadiness(ctx, userID)
+	if err != nil {
+		return scoring.Prediction{}, "", err
+	}
+
+	calibrations := s.activeCalibrations(ctx)
+	bySection := make(map[string]readiness.UserReadiness, len(rows))
+	for _, row := range rows {
+		bySection[row.Section] = row
+	}
+
+	// Sections without readiness yet are predicted from the ability prior
+	predictions := make([]scoring.Prediction, 0, len(calibrations))
+	version := scoring.DefaultVersion
+	for _, section := range readiness.DefaultSections() {
+		ur := bySection[section]
+		cal := calibrations[section]
+		predictions = append(predictions, ur.Prediction(cal))
+		version = cal.Version
+	}
+
+	return scoring.Composite(predictions), version, nil
+}
+
 // === Helper methods ===
 
+// activeCalibrations returns the active calibration per section, falling back to the
+// built-in calibration for sections without one
+func (s *ReadinessService) activeCalibrations(ctx context.Context) map[string]scoring.Calibration {
+	calibrations := make(map[string]scoring.Calibration)
+	for _, section := range readiness.DefaultSections() {
+		calibrations[section] = scoring.DefaultCalibration(section)
+	}
+
+	rows, err := s.calibrationRepo.GetActive(ctx)
+	if err != nil {
+		s.server.Logger.Warn().Err(err).Msg("failed to get active score calibrations, using defaults")
+		return calibrations
+	}
+
+	for _, row := range rows {
+		calibrations[row.Section] = toScoringCalibration(row)
+	}
+
+	return calibrations
+}
+
+// applySectionPrediction fills the response's predicted score from the stored theta estimate
+func applySectionPrediction(resp *readiness.ReadinessResponse, ur *readiness.UserReadiness, cal scoring.Calibration) scoring.Prediction {
+	prediction := ur.Prediction(cal)
+	thetaSE := scoring.PriorSD
+	if ur.ThetaSE != nil {
+		thetaSE = *ur.ThetaSE
+	}
+	resp.ApplyPrediction(prediction, thetaSE, cal.Version)
+	return prediction
+}
+
+// buildCompositePrediction averages section predictions into the overall score and compares it
+// with the user's target score
+func buildCompositePrediction(predictions []scoring.Prediction, version string, u *user.User) *readiness.CompositePredictionResponse {
+	if len(predictions) == 0 {
+		return nil
+	}
+
+	composite := scoring.Composite(predictions)
+	response := &readiness.CompositePredictionResponse{
+		Score:             int(math.Round(composite.Score)),
+		Low:               composite.Low,
+		High:              composite.High,
+		PredictionVersion: version,
+	}
+
+	if u != nil && u.TargetScore != nil {
+		target := *u.TargetScore
+		gap := target - response.Score
+		meets := response.Score >= target
+		probability := math.Round(composite.ProbabilityAtLeast(float64(target))*1000) / 1000
+		response.TargetScore = &target
+		response.GapToTarget = &gap
+		response.MeetsTarget = &meets
+		response.TargetProbability = &probability
+	}
+
+	return response
+}
+
 // buildOverviewResponse builds the overview response from readiness data
-func (s *ReadinessService) buildOverviewResponse(allReadiness []readiness.UserReadinessWithStats) *readiness.ReadinessOverviewResponse {
+func (s *ReadinessService) buildOverviewResponse(
+	allReadiness []readiness.UserReadinessWithStats,
+	calibrations map[string]scoring.Calibration,
+	u *user.User,
+) *readiness.ReadinessOverviewResponse {
 	response := &readiness.ReadinessOverviewResponse{
 		SectionReadiness: make(map[string]readiness.ReadinessResponse),
 	}
@@ -202,8 +493,14 @@ func (s *ReadinessService) buildOverviewResponse(allReadiness []readiness.UserRe
 
 	tpsSections := map[string]bool{"PU": true, "PPU": true, "PBM": true, "PK": true}
 
+	var predictions []scoring.Prediction
+	version := scoring.DefaultVersion
+
 	for _, ur := range allReadiness {
 		resp := ur.ToResponse()
+		cal := calibrations[ur.Section]
+		predictions = append(predictions, applySectionPrediction(&resp, &ur.UserReadiness, cal))
+		version = cal.Version
 		response.SectionReadiness[ur.Section] = resp
 
 		response.TotalAttempts += ur.TotalAttemptsCount
@@ -259,6 +556,8 @@ func (s *ReadinessService) buildOverviewResponse(allReadiness []readiness.UserRe
 		response.RecommendedPractice = weakestSection
 	}
 
+	response.PredictedComposite = buildCompositePrediction(predictions, version, u)
+
 	return response
 }
 
@@ -267,8 +566,12 @@ func (s *ReadinessService) buildSectionDetailResponse(
 	ur *readiness.UserReadinessWithStats,
 	subtypes []readiness.SubtypeAccuracy,
 	trend []readiness.AccuracyTrendPoint,
+	cal scoring.Calibration,
+	examDate *time.Time,
+	lang llm.Language,
 ) *readiness.SectionDetailResponse {
 	baseResponse := ur.ToResponse()
+	applySectionPrediction(&baseResponse, &ur.UserReadiness, cal)
 
 	response := &readiness.SectionDetailResponse{
 		ReadinessResponse: baseResponse,
@@ -281,13 +584,46 @@ func (s *ReadinessService) buildSectionDetailResponse(
 	}
 
 	// Build next steps
-	response.NextSteps = s.buildNextSteps(ur)
+	response.NextSteps = s.buildNextSteps(ur, examDate, lang)
 
 	return response
 }
 
-// buildNextSteps generates recommendations based on readiness
-func (s *ReadinessService) buildNextSteps(ur *readiness.UserReadinessWithStats) *readiness.NextStepsResponse {
+// nextStepsMessages holds the next-steps message formats per language
+var nextStepsMessages = map[llm.Language]struct {
+	Ready        string
+	OnTrack      string
+	LateForExam  string
+	Forecast     string
+	NotImproving string
+	NoHistory    string
+}{
+	llm.LangIndonesian: {
+		Ready:        "Selamat! Kamu sudah siap untuk section ini. Tetap latihan untuk mempertahankan kemampuan.",
+		OnTrack:      "Terus berlatih! Dengan kenaikan %.2f poin theta per minggu, kamu diperkirakan siap dalam %d hari (%s), %d hari sebelum ujian.",
+		LateForExam:  "Dengan kenaikan %.2f poin theta per minggu, kamu diperkirakan baru siap dalam %d hari (%s), setelah tanggal ujian %s. Tambah latihan menjadi %d soal per hari agar siap tepat waktu.",
+		Forecast:     "Terus berlatih! Dengan kenaikan %.2f poin theta per minggu, kamu diperkirakan siap dalam %d hari (%s).",
+		NotImproving: "Kemampuanmu di section ini belum meningkat akhir-akhir ini. Kerjakan %d soal per hari dan pelajari pembahasan soal yang salah.",
+		NoHistory:    "Mulai berlatih secara rutin, minimal %d soal per hari, agar kami bisa memperkirakan kapan kamu siap.",
+	},
+	llm.LangEnglish: {
+		Ready:        "Congratulations! You are ready for this section. Keep practicing to maintain your skills.",
+		OnTrack:      "Keep practicing! Improving %.2f theta points per week, you are projected to be ready in %d days (%s), %d days before the exam.",
+		LateForExam:  "Improving %.2f theta points per week, you are projected to be ready in %d days (%s), after your exam date %s. Increase to %d questions per day to be ready in time.",
+		Forecast:     "Keep practicing! Improving %.2f theta points per week, you are projected to be ready in %d days (%s).",
+		NotImproving: "Your ability in this section has not improved recently. Practice %d questions per day and review the explanations of questions you missed.",
+		NoHistory:    "Practice regularly, at least %d questions per day, so we can forecast when you will be ready.",
+	},
+}
+
+// buildNextSteps generates recommendations from the readiness forecast, comparing the
+// projected ready date with the user's exam date
+func (s *ReadinessService) buildNextSteps(ur *readiness.UserReadinessWithStats, examDate *time.Time, lang llm.Language) *readiness.NextStepsResponse {
+	messages, ok := nextStepsMessages[lang]
+	if !ok {
+		messages = nextStepsMessages[llm.LangIndonesian]
+	}
+
 	nextSteps := &readiness.NextStepsResponse{}
 
 	readinessPercentage := float64(0)
@@ -295,37 +631,102 @@ func (s *ReadinessService) buildNextSteps(ur *readiness.UserReadinessWithStats)
 		readinessPercentage = *ur.ReadinessPercentage
 	}
 
-	if readinessPercentage >= 80 {
+	if examDate != nil {
+		date := examDate.Format("2006-01-02")
+		daysUntilExam := daysUntil(*examDate, time.Now())
+		nextSteps.ExamDate = &date
+		nextSteps.DaysUntilExam = &daysUntilExam
+	}
+
+	if readinessPercentage >= readiness.ReadyPercentage {
 		nextSteps.IsReady = true
-		nextSteps.Message = "Selamat! Kamu sudah siap untuk section ini. Tetap latihan untuk mempertahankan kemampuan."
-	} else {
-		nextSteps.IsReady = false
-
-		if ur.DaysToReady != nil && *ur.DaysToReady > 0 {
-			days := *ur.DaysToReady
-			nextSteps.Message = "Terus berlatih! Estimasi kamu akan siap dalam " + string(rune(days)) + " hari."
-
-			// Suggest daily practice based on gap
-			gap := 80 - readinessPercentage
-			suggestedDaily := int(gap / 5) // rough estimate
-			if suggestedDaily < 5 {
-				suggestedDaily = 5
-			}
-			if suggestedDaily > 20 {
-				suggestedDaily = 20
-			}
-			nextSteps.SuggestedDailyPractice = &suggestedDaily
+		nextSteps.Message = messages.Ready
+		return nextSteps
+	}
+
+	// Suggest daily practice based on the readiness gap
+	suggestedDaily := clampInt(int((readiness.ReadyPercentage-readinessPercentage)/5), minDailyPractice, maxDailyPractice)
+
+	switch {
+	case ur.DaysToReady != nil && ur.ReadyByDate != nil && ur.ImprovementRatePerWeek != nil:
+		days := *ur.DaysToReady
+		rate := *ur.ImprovementRatePerWeek
+		readyBy := ur.ReadyByDate.Format("2006-01-02")
+		nextSteps.EstimatedCompletionDate = &readyBy
+
+		if nextSteps.DaysUntilExam == nil {
+			nextSteps.Message = fmt.Sprintf(messages.Forecast, rate, days, readyBy)
+			break
+		}
+
+		daysUntilExam := *nextSteps.DaysUntilExam
+		onTrack := days <= daysUntilExam
+		nextSteps.OnTrack = &onTrack
+		if onTrack {
+			nextSteps.Message = fmt.Sprintf(messages.OnTrack, rate, days, readyBy, daysUntilExam-days)
+			break
+		}
+
+		// Scale practice by how far the projection overshoots the exam
+		if daysUntilExam > 0 {
+			suggestedDaily = clampInt(int(math.Ceil(float64(suggestedDaily)*float64(days)/float64(daysUntilExam))), minDailyPractice, maxDailyPracticeLate)
 		} else {
-			nextSteps.Message = "Mulai berlatih untuk meningkatkan readiness kamu di section ini."
-			defaultDaily := 10
-			nextSteps.SuggestedDailyPractice = &defaultDaily
+			suggestedDaily = maxDailyPracticeLate
 		}
+		nextSteps.Message = fmt.Sprintf(messages.LateForExam, rate, days, readyBy, *nextSteps.ExamDate, suggestedDaily)
 
-		if ur.ReadyByDate != nil {
-			date := ur.ReadyByDate.Format("2006-01-02")
-			nextSteps.EstimatedCompletionDate = &date
+	case ur.ImprovementRatePerWeek != nil:
+		// A trend was fitted but the target is out of reach at the current rate
+		if nextSteps.DaysUntilExam != nil {
+			onTrack := false
+			nextSteps.OnTrack = &onTrack
 		}
+		nextSteps.Message = fmt.Sprintf(messages.NotImproving, suggestedDaily)
+
+	default:
+		suggestedDaily = defaultDailyPractice
+		nextSteps.Message = fmt.Sprintf(messages.NoHistory, suggestedDaily)
 	}
 
+	nextSteps.SuggestedDailyPractice = &suggestedDaily
 	return nextSteps
 }
+
+// daysUntil returns the whole days from now until date, never negative
+func daysUntil(date, now time.Time) int {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	target := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(target.Sub(today).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+func clampInt(v, low, high int) int {
+	return max(low, min(v, high))
+}
+
+func toScoringResponses(responses []readiness.ItemResponse) []scoring.Response {
+	result := make([]scoring.Response, len(responses))
+	for i, r := range responses {
+		result[i] = scoring.Response{Correct: r.IsCorrect}
+		if r.DifficultyIRT != nil {
+			result[i].Difficulty = *r.DifficultyIRT
+		}
+		if r.Discrimination != nil {
+			result[i].Discrimination = *r.Discrimination
+		}
+	}
+	return result
+}
+
+func toScoringCalibration(c calibration.ScoreCalibration) scoring.Calibration {
+	return scoring.Calibration{
+		Version:    c.Version,
+		Section:    c.Section,
+		Slope:      c.Slope,
+		Intercept:  c.Intercept,
+		ResidualSD: c.ResidualSD,
+	}
+}
@@ -5,19 +5,32 @@ import (
 
 	"github.com/manikandareas/genta/internal/lib/clerk"
 	"github.com/manikandareas/genta/internal/lib/job"
+	"github.com/manikandareas/genta/internal/model/event"
 	"github.com/manikandareas/genta/internal/repository"
 	"github.com/manikandareas/genta/internal/server"
 )
 
 type Services struct {
-	Auth      *AuthService
-	Job       *job.JobService
-	User      *UserService
-	Question  *QuestionService
-	Attempt   *AttemptService
-	Session   *SessionService
-	Readiness *ReadinessService
-	Analytics *AnalyticsService
+	Auth            *AuthService
+	Job             *job.JobService
+	User            *UserService
+	Question        *QuestionService
+	Attempt         *AttemptService
+	Session         *SessionService
+	Readiness       *ReadinessService
+	Analytics       *AnalyticsService
+	Usage           *UsageService
+	Authoring       *AuthoringService
+	FeedbackQuality *FeedbackQualityService
+	Calibration     *CalibrationService
+	Admission       *AdmissionService
+	StudyPlan       *StudyPlanService
+	Leaderboard     *LeaderboardService
+	Achievement     *AchievementService
+	Duplicate       *DuplicateService
+	Concept         *ConceptService
+	Content         *ContentService
+	Storage         *StorageService
 }
 
 func NewServices(s *server.Server, repos *repository.Repositories) (*Services, error) {
@@ -28,21 +41,60 @@ func NewServices(s *server.Server, repos *repository.Repositories) (*Services, e
 		return nil, fmt.Errorf("failed to create Clerk client: %w", err)
 	}
 
-	userService := NewUserService(s, repos.User, repos.Readiness, clerkClient)
-	questionService := NewQuestionService(s, repos.Question, repos.User)
-	attemptService := NewAttemptService(s, repos.Attempt, repos.Question, repos.User, s.Job)
-	sessionService := NewSessionService(s, repos.Session, repos.User)
-	readinessService := NewReadinessService(s, repos.Readiness, repos.User)
+	// Feedback jobs serve cached feedback through the attempt repository
+	if s.Job != nil {
+		s.Job.SetFeedbackCache(repos.Attempt)
+	}
+
+	questionService := NewQuestionService(s, repos.Question, repos.Stimulus, repos.User)
+	usageService := NewUsageService(s, repos.Usage)
+
+	// Every LLM call made by jobs (feedback, judge, authoring) is held to the global budget
+	if s.Job != nil {
+		s.Job.SetBudgetCheck(usageService.CheckGlobalBudget)
+	}
+	readinessService := NewReadinessService(s, repos.Readiness, repos.User, repos.Calibration)
+	admissionService := NewAdmissionService(s, repos.Admission, repos.User, readinessService)
+	userService := NewUserService(s, repos.User, repos.Readiness, clerkClient, admissionService)
+	leaderboardService := NewLeaderboardService(s, repos.Leaderboard, repos.User)
+	achievementService := NewAchievementService(s, repos.Achievement, repos.Readiness, repos.Session, repos.User)
+	attemptService := NewAttemptService(s, repos.Attempt, repos.Question, repos.User, repos.Session, s.Job, usageService, readinessService, leaderboardService, achievementService)
+	sessionService := NewSessionService(s, repos.Session, repos.User, achievementService)
 	analyticsService := NewAnalyticsService(s, repos.Analytics, repos.User)
+	authoringService := NewAuthoringService(s, repos.Draft, repos.Question, repos.User, usageService, s.Job)
+	feedbackQualityService := NewFeedbackQualityService(s, repos.Attempt, s.Job)
+	calibrationService := NewCalibrationService(s, repos.Calibration, repos.Readiness, repos.User)
+	duplicateService := NewDuplicateService(s, repos.Duplicate, repos.User, s.Job)
+	conceptService := NewConceptService(s, repos.Concept, repos.Readiness, repos.User, s.Job)
+	contentService := NewContentService(s, repos.Media, repos.Stimulus, repos.Question, repos.User)
+	studyPlanService := NewStudyPlanService(s, repos.StudyPlan, repos.Readiness, repos.User)
+
+	// Progress updates follow domain events, so a failed update is retried instead of lost
+	if s.Job != nil {
+		s.Job.Subscribe(event.TypeAttemptRecorded, "attempt_progress", attemptService.applyAttemptRecorded)
+		s.Job.Subscribe(event.TypeSessionEnded, "session_achievements", sessionService.applySessionEnded)
+	}
 
 	return &Services{
-		Job:       s.Job,
-		Auth:      authService,
-		User:      userService,
-		Question:  questionService,
-		Attempt:   attemptService,
-		Session:   sessionService,
-		Readiness: readinessService,
-		Analytics: analyticsService,
+		Job:             s.Job,
+		Auth:            authService,
+		User:            userService,
+		Question:        questionService,
+		Attempt:         attemptService,
+		Session:         sessionService,
+		Readiness:       readinessService,
+		Analytics:       analyticsService,
+		Usage:           usageService,
+		Authoring:       authoringService,
+		FeedbackQuality: feedbackQualityService,
+		Calibration:     calibrationService,
+		Admission:       admissionService,
+		StudyPlan:       studyPlanService,
+		Leaderboard:     leaderboardService,
+		Achievement:     achievementService,
+		Duplicate:       duplicateService,
+		Concept:         conceptService,
+		Content:         contentService,
+		Storage:         NewStorageService(s),
 	}, nil
 }
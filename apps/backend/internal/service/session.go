@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,22 +10,26 @@ import (
 	"github.com/manikandareas/genta/internal/errs"
 	"github.com/manikandareas/genta/internal/middleware"
 	"github.com/manikandareas/genta/internal/model"
+	"github.com/manikandareas/genta/internal/model/achievement"
+	"github.com/manikandareas/genta/internal/model/event"
 	"github.com/manikandareas/genta/internal/model/session"
 	"github.com/manikandareas/genta/internal/repository"
 	"github.com/manikandareas/genta/internal/server"
 )
 
 type SessionService struct {
-	server      *server.Server
-	sessionRepo *repository.SessionRepository
-	userRepo    *repository.UserRepository
+	server             *server.Server
+	sessionRepo        *repository.SessionRepository
+	userRepo           *repository.UserRepository
+	achievementService *AchievementService
 }
 
-func NewSessionService(server *server.Server, sessionRepo *repository.SessionRepository, userRepo *repository.UserRepository) *SessionService {
+func NewSessionService(server *server.Server, sessionRepo *repository.SessionRepository, userRepo *repository.UserRepository, achievementService *AchievementService) *SessionService {
 	return &SessionService{
-		server:      server,
-		sessionRepo: sessionRepo,
-		userRepo:    userRepo,
+		server:             server,
+		sessionRepo:        sessionRepo,
+		userRepo:           userRepo,
+		achievementService: achievementService,
 	}
 }
 
@@ -73,30 +79,17 @@ func (s *SessionService) List(ctx echo.Context, clerkID string, req *session.Lis
 		return nil, errs.NewNotFoundError("user not found", false, nil)
 	}
 
-	sessions, total, err := s.sessionRepo.List(ctx.Request().Context(), user.ID, req)
+	params := repository.PageParams{Page: req.Page, Limit: req.Limit, Cursor: req.Cursor, IncludeTotal: req.IncludeTotal}
+
+	page, err := s.sessionRepo.List(ctx.Request().Context(), user.ID, params)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to list sessions")
 		return nil, err
 	}
 
-	// Convert to response
-	responses := make([]session.SessionResponse, len(sessions))
-	for i, sess := range sessions {
-		responses[i] = sess.ToResponse()
-	}
-
-	totalPages := total / req.Limit
-	if total%req.Limit > 0 {
-		totalPages++
-	}
-
-	return &model.PaginatedResponse[session.SessionResponse]{
-		Data:       responses,
-		Page:       req.Page,
-		Limit:      req.Limit,
-		Total:      total,
-		TotalPages: totalPages,
-	}, nil
+	return paginatedResponse(ctx, page, params, func(sess *session.Session) session.SessionResponse {
+		return sess.ToResponse()
+	}), nil
 }
 
 // End ends a study session
@@ -129,6 +122,24 @@ func (s *SessionService) End(ctx echo.Context, clerkID string, sessionID string)
 	return &response, nil
 }
 
+// applySessionEnded awards achievements unlocked by a finished session, whether the
+// student ended it or it was closed for being idle. It runs as a SessionEnded subscriber
+// and is retried until it succeeds.
+func (s *SessionService) applySessionEnded(ctx context.Context, evt *event.Event) error {
+	var p event.SessionEnded
+	if err := evt.Decode(&p); err != nil {
+		return err
+	}
+
+	_, err := s.achievementService.Evaluate(ctx, p.UserID,
+		achievement.Event{Type: achievement.EventSessionEnded, UserID: p.UserID, SessionID: p.SessionID},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate achievements: %w", err)
+	}
+	return nil
+}
+
 // GetByID retrieves a session by ID (with ownership check)
 func (s *SessionService) GetByID(ctx echo.Context, clerkID string, sessionID string) (*session.SessionResponse, error) {
 	logger := middleware.GetLogger(ctx)
@@ -0,0 +1,411 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/llm"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/analytics"
+	"github.com/manikandareas/genta/internal/model/session"
+)
+
+// summaryQuestionSnippetRunes bounds the question text printed under each attempt in the PDF
+const summaryQuestionSnippetRunes = 160
+
+// summaryLabels holds the PDF labels per language
+var summaryLabels = map[llm.Language]map[string]string{
+	llm.LangIndonesian: {
+		"title":                 "Ringkasan Sesi Belajar",
+		"session":               "Sesi",
+		"started":               "Mulai",
+		"ended":                 "Selesai",
+		"in_progress":           "Sedang berlangsung",
+		"duration":              "Durasi",
+		"minutes":               "menit",
+		"questions":             "Soal dikerjakan",
+		"accuracy":              "Akurasi",
+		"theta":                 "Theta (awal -> akhir)",
+		"by_section":            "Per Subtes",
+		"by_subtype":            "Per Tipe Soal",
+		"attempts":              "Jawaban",
+		"section":               "Subtes",
+		"subtype":               "Tipe",
+		"count":                 "Soal",
+		"correct":               "Benar",
+		"avg_time":              "Rata-rata (dtk)",
+		"theta_change":          "Perubahan theta",
+		"answer":                "Jawab",
+		"key":                   "Kunci",
+		"result":                "Hasil",
+		"time":                  "Waktu",
+		"feedback":              "Feedback",
+		"right":                 "Benar",
+		"wrong":                 "Salah",
+		"no_attempts":           "Belum ada jawaban di sesi ini.",
+		"feedback_ready":        "tersedia",
+		"feedback_under_review": "ditinjau",
+		"feedback_none":         "-",
+	},
+	llm.LangEnglish: {
+		"title":                 "Study Session Summary",
+		"session":               "Session",
+		"started":               "Started",
+		"ended":                 "Ended",
+		"in_progress":           "In progress",
+		"duration":              "Duration",
+		"minutes":               "minutes",
+		"questions":             "Questions answered",
+		"accuracy":              "Accuracy",
+		"theta":                 "Theta (start -> end)",
+		"by_section":            "By Section",
+		"by_subtype":            "By Question Type",
+		"attempts":              "Answers",
+		"section":               "Section",
+		"subtype":               "Type",
+		"count":                 "Questions",
+		"correct":               "Correct",
+		"avg_time":              "Avg time (s)",
+		"theta_change":          "Theta change",
+		"answer":                "Answer",
+		"key":                   "Key",
+		"result":                "Result",
+		"time":                  "Time",
+		"feedback":              "Feedback",
+		"right":                 "Correct",
+		"wrong":                 "Wrong",
+		"no_attempts":           "No answers in this session yet.",
+		"feedback_ready":        "available",
+		"feedback_under_review": "in review",
+		"feedback_none":         "-",
+	},
+}
+
+// GetSummary retrieves a session report listing every attempt in answer order
+func (s *SessionService) GetSummary(ctx echo.Context, clerkID string, req *session.GetSessionSummaryRequest) (*session.SessionSummaryResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	user, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	sess, err := s.sessionRepo.GetByIDAndUserID(ctx.Request().Context(), req.SessionID, user.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("session_id", req.SessionID).Msg("failed to get session")
+		return nil, err
+	}
+
+	attempts, err := s.sessionRepo.GetSummaryAttempts(ctx.Request().Context(), sess.ID, user.ID)
+	if err != nil {
+		logger.Error().Err(err).Str("session_id", sess.ID).Msg("failed to get session attempts")
+		return nil, err
+	}
+
+	response := buildSessionSummary(sess, attempts)
+
+	logger.Debug().
+		Str("user_id", user.ID.String()).
+		Str("session_id", sess.ID).
+		Int("attempts", len(response.Attempts)).
+		Msg("Session summary retrieved")
+
+	return response, nil
+}
+
+// GetSummaryPDF renders the session summary as a PDF document
+func (s *SessionService) GetSummaryPDF(ctx echo.Context, clerkID string, req *session.GetSessionSummaryRequest) ([]byte, error) {
+	logger := middleware.GetLogger(ctx)
+
+	summary, err := s.GetSummary(ctx, clerkID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, ok := summaryLabels[llm.Language(req.Lang)]
+	if !ok {
+		labels = summaryLabels[llm.LangIndonesian]
+	}
+
+	data, err := renderSessionSummaryPDF(summary, labels)
+	if err != nil {
+		logger.Error().Err(err).Str("session_id", req.SessionID).Msg("failed to render session summary pdf")
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func buildSessionSummary(sess *session.Session, attempts []session.SummaryAttempt) *session.SessionSummaryResponse {
+	response := &session.SessionSummaryResponse{
+		Session:  sess.ToResponse(),
+		Sections: []session.SummaryBreakdownResponse{},
+		SubTypes: []session.SummaryBreakdownResponse{},
+		Attempts: make([]session.SummaryAttemptResponse, 0, len(attempts)),
+	}
+
+	if len(attempts) > 0 {
+		response.ThetaStart = attempts[0].UserThetaBefore
+		response.ThetaEnd = attempts[len(attempts)-1].UserThetaAfter
+		if response.ThetaStart != nil && response.ThetaEnd != nil {
+			change := roundTo(*response.ThetaEnd-*response.ThetaStart, 3)
+			response.ThetaChange = &change
+		}
+	}
+
+	// Breakdowns keep the order in which sections and subtypes first appear in the session
+	type tally struct {
+		section     string
+		subType     *string
+		attempts    int
+		correct     int
+		totalTime   int
+		thetaChange float64
+	}
+	var sectionOrder, subTypeOrder []string
+	sections := map[string]*tally{}
+	subTypes := map[string]*tally{}
+	add := func(order *[]string, m map[string]*tally, key string, t tally, a *session.SummaryAttempt) {
+		entry, ok := m[key]
+		if !ok {
+			entry = &t
+			m[key] = entry
+			*order = append(*order, key)
+		}
+		entry.attempts++
+		if a.IsCorrect {
+			entry.correct++
+		}
+		entry.totalTime += int(a.TimeSpentSeconds)
+		if a.ThetaChange != nil {
+			entry.thetaChange += *a.ThetaChange
+		}
+	}
+
+	for i := range attempts {
+		a := &attempts[i]
+
+		number := i + 1
+		if a.AttemptNumberInSession != nil {
+			number = int(*a.AttemptNumberInSession)
+		}
+
+		response.Attempts = append(response.Attempts, session.SummaryAttemptResponse{
+			Number:           number,
+			AttemptID:        a.AttemptID,
+			QuestionID:       a.QuestionID,
+			QuestionText:     a.QuestionText,
+			Section:          a.Section,
+			SubType:          a.SubType,
+			SelectedAnswer:   a.SelectedAnswer,
+			CorrectAnswer:    a.CorrectAnswer,
+			IsCorrect:        a.IsCorrect,
+			TimeSpentSeconds: a.TimeSpentSeconds,
+			ThetaChange:      a.ThetaChange,
+			FeedbackStatus:   a.FeedbackStatus(),
+			CreatedAt:        a.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		})
+
+		add(&sectionOrder, sections, a.Section, tally{section: a.Section}, a)
+		if a.SubType != nil {
+			add(&subTypeOrder, subTypes, a.Section+"/"+*a.SubType, tally{section: a.Section, subType: a.SubType}, a)
+		}
+	}
+
+	toBreakdown := func(t *tally) session.SummaryBreakdownResponse {
+		return session.SummaryBreakdownResponse{
+			Section:        t.section,
+			SectionName:    analytics.GetSectionName(t.section),
+			SubType:        t.subType,
+			Attempts:       t.attempts,
+			Correct:        t.correct,
+			Accuracy:       roundTo(share(t.correct, t.attempts), 3),
+			AvgTimeSeconds: roundTo(float64(t.totalTime)/float64(t.attempts), 1),
+			ThetaChange:    roundTo(t.thetaChange, 3),
+		}
+	}
+	for _, key := range sectionOrder {
+		response.Sections = append(response.Sections, toBreakdown(sections[key]))
+	}
+	for _, key := range subTypeOrder {
+		response.SubTypes = append(response.SubTypes, toBreakdown(subTypes[key]))
+	}
+
+	return response
+}
+
+func renderSessionSummaryPDF(summary *session.SessionSummaryResponse, labels map[string]string) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-10)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("%d/{nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	// Core fonts are cp1252; question text may contain other UTF-8 characters
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, tr(labels["title"]), "", 1, "L", false, 0, "")
+
+	sess := summary.Session
+	info := [][2]string{
+		{labels["session"], sess.ID},
+		{labels["started"], sess.StartedAt},
+		{labels["ended"], labels["in_progress"]},
+		{labels["questions"], fmt.Sprintf("%d", len(summary.Attempts))},
+	}
+	if sess.EndedAt != nil {
+		info[2][1] = *sess.EndedAt
+	}
+	if sess.DurationMinutes != nil {
+		info = append(info, [2]string{labels["duration"], fmt.Sprintf("%d %s", *sess.DurationMinutes, labels["minutes"])})
+	}
+	if sess.Section != nil {
+		info = append(info, [2]string{labels["section"], analytics.GetSectionName(*sess.Section)})
+	}
+	correct := 0
+	for _, a := range summary.Attempts {
+		if a.IsCorrect {
+			correct++
+		}
+	}
+	if len(summary.Attempts) > 0 {
+		info = append(info, [2]string{labels["accuracy"], fmt.Sprintf("%.0f%% (%d/%d)", share(correct, len(summary.Attempts))*100, correct, len(summary.Attempts))})
+	}
+	if summary.ThetaStart != nil && summary.ThetaEnd != nil && summary.ThetaChange != nil {
+		info = append(info, [2]string{labels["theta"], fmt.Sprintf("%.3f -> %.3f (%+.3f)", *summary.ThetaStart, *summary.ThetaEnd, *summary.ThetaChange)})
+	}
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, row := range info {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(50, 6, tr(row[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, tr(row[1]), "", 1, "L", false, 0, "")
+	}
+
+	if len(summary.Attempts) == 0 {
+		pdf.Ln(4)
+		pdf.CellFormat(0, 6, tr(labels["no_attempts"]), "", 1, "L", false, 0, "")
+		return outputPDF(pdf)
+	}
+
+	heading := func(text string) {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.CellFormat(0, 8, tr(text), "", 1, "L", false, 0, "")
+	}
+	tableHeader := func(widths []float64, header []string) {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for i, h := range header {
+			pdf.CellFormat(widths[i], 7, tr(h), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	tableRow := func(widths []float64, row []string) {
+		pdf.SetFont("Helvetica", "", 9)
+		for i, cell := range row {
+			pdf.CellFormat(widths[i], 6, tr(cell), "1", 0, "C", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	table := func(widths []float64, header []string, rows [][]string) {
+		tableHeader(widths, header)
+		for _, row := range rows {
+			tableRow(widths, row)
+		}
+	}
+	breakdownRow := func(b session.SummaryBreakdownResponse, first string) []string {
+		return []string{
+			first,
+			fmt.Sprintf("%d", b.Attempts),
+			fmt.Sprintf("%d", b.Correct),
+			fmt.Sprintf("%.0f%%", b.Accuracy*100),
+			fmt.Sprintf("%.1f", b.AvgTimeSeconds),
+			fmt.Sprintf("%+.3f", b.ThetaChange),
+		}
+	}
+	breakdownWidths := []float64{60, 20, 20, 25, 25, 30}
+
+	heading(labels["by_section"])
+	rows := make([][]string, 0, len(summary.Sections))
+	for _, b := range summary.Sections {
+		rows = append(rows, breakdownRow(b, b.SectionName))
+	}
+	table(breakdownWidths, []string{labels["section"], labels["count"], labels["correct"], labels["accuracy"], labels["avg_time"], labels["theta_change"]}, rows)
+
+	if len(summary.SubTypes) > 0 {
+		heading(labels["by_subtype"])
+		rows = make([][]string, 0, len(summary.SubTypes))
+		for _, b := range summary.SubTypes {
+			rows = append(rows, breakdownRow(b, b.Section+" - "+*b.SubType))
+		}
+		table(breakdownWidths, []string{labels["subtype"], labels["count"], labels["correct"], labels["accuracy"], labels["avg_time"], labels["theta_change"]}, rows)
+	}
+
+	heading(labels["attempts"])
+	attemptWidths := []float64{10, 18, 42, 16, 16, 18, 16, 22, 22}
+	attemptHeader := []string{"#", labels["section"], labels["subtype"], labels["answer"], labels["key"], labels["result"], labels["time"], labels["theta_change"], labels["feedback"]}
+	tableHeader(attemptWidths, attemptHeader)
+	for _, a := range summary.Attempts {
+		result := labels["wrong"]
+		if a.IsCorrect {
+			result = labels["right"]
+		}
+		subType := "-"
+		if a.SubType != nil {
+			subType = *a.SubType
+		}
+		thetaChange := "-"
+		if a.ThetaChange != nil {
+			thetaChange = fmt.Sprintf("%+.3f", *a.ThetaChange)
+		}
+
+		// Keep an attempt's row and its question snippet on the same page
+		if pdf.GetY() > 260 {
+			pdf.AddPage()
+			tableHeader(attemptWidths, attemptHeader)
+		}
+		tableRow(attemptWidths, []string{
+			fmt.Sprintf("%d", a.Number),
+			a.Section,
+			subType,
+			a.SelectedAnswer,
+			a.CorrectAnswer,
+			result,
+			fmt.Sprintf("%ds", a.TimeSpentSeconds),
+			thetaChange,
+			labels["feedback_"+a.FeedbackStatus],
+		})
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.MultiCell(0, 4, tr(truncateRunes(strings.Join(strings.Fields(a.QuestionText), " "), summaryQuestionSnippetRunes)), "LRB", "L", false)
+	}
+
+	return outputPDF(pdf)
+}
+
+func outputPDF(pdf *fpdf.Fpdf) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
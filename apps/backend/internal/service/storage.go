@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/storage"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/media"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+// StorageService serves presigned URLs for the filesystem and memory backends, which
+// cannot serve them themselves. With S3 the URLs point at the bucket and never reach it.
+type StorageService struct {
+	server *server.Server
+}
+
+func NewStorageService(server *server.Server) *StorageService {
+	return &StorageService{server: server}
+}
+
+// OpenSigned opens the object a presigned download URL grants; the caller closes it
+func (s *StorageService) OpenSigned(ctx echo.Context, req *media.SignedDownloadRequest) (io.ReadCloser, *storage.Object, error) {
+	key, err := url.PathUnescape(req.Key)
+	if err != nil {
+		return nil, nil, errs.NewNotFoundError("object not found", false, nil)
+	}
+
+	if err := s.verify(ctx, storage.Grant{Method: http.MethodGet, Key: key, Expires: req.Expires}, req.Signature); err != nil {
+		return nil, nil, err
+	}
+
+	body, obj, err := s.server.Storage.Open(ctx.Request().Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, errs.NewNotFoundError("object not found", false, nil)
+		}
+		middleware.GetLogger(ctx).Error().Err(err).Str("key", key).Msg("failed to open object")
+		return nil, nil, err
+	}
+
+	return body, obj, nil
+}
+
+// AcceptSignedUpload stores a file sent with a presigned upload form. Like an S3 POST
+// policy, the form fixes the key, the content type and the maximum size; the type is
+// sniffed from the file rather than trusted from the form.
+func (s *StorageService) AcceptSignedUpload(ctx echo.Context, req *media.SignedUploadRequest, file io.Reader, size int64) error {
+	logger := middleware.GetLogger(ctx)
+
+	grant := storage.Grant{
+		Method:      http.MethodPost,
+		Key:         req.Key,
+		ContentType: req.ContentType,
+		MaxBytes:    req.MaxBytes,
+		Expires:     req.Expires,
+	}
+	if err := s.verify(ctx, grant, req.Signature); err != nil {
+		return err
+	}
+
+	if size > req.MaxBytes {
+		return errs.NewBadRequestError("file is too large", false, nil, nil, nil)
+	}
+
+	contentType, body, err := storage.Sniff(file)
+	if err != nil {
+		return errs.NewBadRequestError("failed to read file", false, nil, nil, nil)
+	}
+	if contentType != req.ContentType {
+		return errs.NewBadRequestError("file does not match the signed content type", false, nil,
+			[]errs.FieldError{{Field: "file", Error: "must be " + req.ContentType}}, nil)
+	}
+
+	if _, err := s.server.Storage.Put(ctx.Request().Context(), req.Key, body, size, contentType); err != nil {
+		logger.Error().Err(err).Str("key", req.Key).Msg("failed to store signed upload")
+		return err
+	}
+
+	logger.Info().
+		Str("event", "signed_upload_stored").
+		Str("key", req.Key).
+		Str("content_type", contentType).
+		Int64("size_bytes", size).
+		Msg("Signed upload stored")
+
+	return nil
+}
+
+func (s *StorageService) verify(ctx echo.Context, g storage.Grant, signature string) error {
+	verifier, ok := s.server.Storage.(storage.Verifier)
+	if !ok {
+		return errs.NewNotFoundError("object not found", false, nil)
+	}
+
+	if err := verifier.Verify(g, signature); err != nil {
+		middleware.GetLogger(ctx).Warn().Err(err).Str("key", g.Key).Str("method", g.Method).Msg("rejected presigned storage request")
+		if errors.Is(err, storage.ErrSignatureExpired) {
+			return errs.NewForbiddenError("link has expired", false)
+		}
+		return errs.NewForbiddenError("invalid signature", false)
+	}
+	return nil
+}
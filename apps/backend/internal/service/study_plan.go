@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/lib/planner"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/readiness"
+	"github.com/manikandareas/genta/internal/model/studyplan"
+	"github.com/manikandareas/genta/internal/model/user"
+	"github.com/manikandareas/genta/internal/repository"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+const (
+	// behindMinMissedDays is how many past days without any practice trigger a re-plan
+	behindMinMissedDays = 2
+	// behindCompletionRate is the share of past planned questions below which the user is behind
+	behindCompletionRate = 0.5
+)
+
+type StudyPlanService struct {
+	server        *server.Server
+	studyPlanRepo *repository.StudyPlanRepository
+	readinessRepo *repository.ReadinessRepository
+	userRepo      *repository.UserRepository
+}
+
+func NewStudyPlanService(
+	server *server.Server,
+	studyPlanRepo *repository.StudyPlanRepository,
+	readinessRepo *repository.ReadinessRepository,
+	userRepo *repository.UserRepository,
+) *StudyPlanService {
+	return &StudyPlanService{
+		server:        server,
+		studyPlanRepo: studyPlanRepo,
+		readinessRepo: readinessRepo,
+		userRepo:      userRepo,
+	}
+}
+
+// GetActive returns the user's active plan with progress. A plan is generated when the user
+// has none, and regenerated from today when it has expired, the exam date or study hours
+// changed, or the user has fallen behind.
+func (s *StudyPlanService) GetActive(ctx echo.Context, clerkID string) (*studyplan.StudyPlanResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	u, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	today := planner.Date(time.Now())
+
+	plan, err := s.studyPlanRepo.GetActive(ctx.Request().Context(), u.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get active study plan")
+		return nil, err
+	}
+
+	reason := studyplan.ReasonInitial
+	var items []studyplan.Item
+	var progress studyplan.StudyPlanProgress
+	var completed map[string]int
+
+	if plan != nil {
+		items, err = s.studyPlanRepo.GetItems(ctx.Request().Context(), plan.ID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get study plan items")
+			return nil, err
+		}
+
+		completed, progress, err = s.trackProgress(ctx.Request().Context(), plan, items, today)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to track study plan progress")
+			return nil, err
+		}
+
+		reason = replanReason(plan, u, progress, today)
+		if reason == "" {
+			return buildStudyPlanResponse(plan, items, completed, progress, today, false), nil
+		}
+	}
+
+	plan, items, err = s.generate(ctx.Request().Context(), u, reason, today)
+	if err != nil {
+		logger.Error().Err(err).Str("reason", reason).Msg("failed to generate study plan")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "study_plan_generated").
+		Str("user_id", u.ID.String()).
+		Str("reason", reason).
+		Int("items", len(items)).
+		Msg("Study plan generated")
+
+	return buildStudyPlanResponse(plan, items, nil, studyplan.StudyPlanProgress{}, today, reason != studyplan.ReasonInitial), nil
+}
+
+// Generate replaces the user's active plan with a fresh one
+func (s *StudyPlanService) Generate(ctx echo.Context, clerkID string, req *studyplan.GenerateStudyPlanRequest) (*studyplan.StudyPlanResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	u, err := s.userRepo.GetUserByClerkID(ctx.Request().Context(), clerkID)
+	if err != nil {
+		logger.Error().Err(err).Str("clerk_id", clerkID).Msg("failed to get user")
+		return nil, errs.NewNotFoundError("user not found", false, nil)
+	}
+
+	today := planner.Date(time.Now())
+	start := today
+	if req.StartDate != nil {
+		start, _ = time.Parse("2006-01-02", *req.StartDate)
+		if start.Before(today) {
+			return nil, errs.NewBadRequestError("start_date cannot be in the past", false, nil, nil, nil)
+		}
+	}
+
+	plan, items, err := s.generate(ctx.Request().Context(), u, studyplan.ReasonManual, start)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to generate study plan")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "study_plan_generated").
+		Str("user_id", u.ID.String()).
+		Str("reason", studyplan.ReasonManual).
+		Int("items", len(items)).
+		Msg("Study plan generated")
+
+	return buildStudyPlanResponse(plan, items, nil, studyplan.StudyPlanProgress{}, today, false), nil
+}
+
+// generate plans from the user's current readiness and subtype accuracy and stores the plan
+func (s *StudyPlanService) generate(ctx context.Context, u *user.User, reason string, start time.Time) (*studyplan.StudyPlan, []studyplan.Item, error) {
+	rows, err := s.readinessRepo.GetUserReadiness(ctx, u.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rows) == 0 {
+		if rows, err = s.readinessRepo.CreateInitialReadiness(ctx, u.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	subtypes, err := s.studyPlanRepo.GetSectionSubtypes(ctx, u.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bySection := make(map[string]*planner.SectionStat)
+	input := planner.Input{
+		Start:        start,
+		ExamDate:     u.ExamDate,
+		HoursPerWeek: studyHoursPerWeek(u),
+	}
+	for _, section := range readiness.DefaultSections() {
+		input.Sections = append(input.Sections, planner.SectionStat{Section: section})
+	}
+	for i := range input.Sections {
+		bySection[input.Sections[i].Section] = &input.Sections[i]
+	}
+	for _, r := range rows {
+		if stat, ok := bySection[r.Section]; ok && r.ReadinessPercentage != nil {
+			stat.ReadinessPercentage = *r.ReadinessPercentage
+		}
+	}
+	for _, st := range subtypes {
+		if stat, ok := bySection[st.Section]; ok {
+			stat.Subtypes = append(stat.Subtypes, planner.SubtypeStat{
+				SubType:  st.SubType,
+				Attempts: st.Attempts,
+				Correct:  st.Correct,
+			})
+		}
+	}
+
+	blocks := planner.Generate(input)
+	if len(blocks) == 0 {
+		return nil, nil, errs.NewInternalServerError()
+	}
+
+	plan, err := s.studyPlanRepo.Replace(ctx, &studyplan.StudyPlan{
+		UserID:        u.ID,
+		Reason:        reason,
+		StartDate:     blocks[0].Date,
+		EndDate:       blocks[len(blocks)-1].Date,
+		ExamDate:      u.ExamDate,
+		MinutesPerDay: planner.MinutesPerDay(studyHoursPerWeek(u)),
+	}, blocks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := s.studyPlanRepo.GetItems(ctx, plan.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return plan, items, nil
+}
+
+// trackProgress credits the questions answered each day to that day's blocks of the same
+// section, in schedule order, and summarizes completion of the days before today
+func (s *StudyPlanService) trackProgress(
+	ctx context.Context,
+	plan *studyplan.StudyPlan,
+	items []studyplan.Item,
+	today time.Time,
+) (map[string]int, studyplan.StudyPlanProgress, error) {
+	var progress studyplan.StudyPlanProgress
+
+	counts, err := s.studyPlanRepo.GetDailySectionCounts(ctx, plan.UserID, plan.StartDate, plan.EndDate)
+	if err != nil {
+		return nil, progress, err
+	}
+
+	available := make(map[string]int, len(counts))
+	for _, c := range counts {
+		available[dailySectionKey(c.Date, c.Section)] = c.Attempts
+	}
+
+	completed := make(map[string]int, len(items))
+	pastDays := make(map[time.Time]int)
+	for _, item := range items {
+		key := dailySectionKey(item.ScheduledDate, item.Section)
+		done := min(available[key], item.TargetQuestions)
+		available[key] -= done
+		completed[item.ID.String()] = done
+
+		if item.ScheduledDate.Before(today) {
+			progress.PlannedQuestions += item.TargetQuestions
+			progress.CompletedQuestions += done
+			pastDays[item.ScheduledDate] += done
+		}
+	}
+
+	for _, done := range pastDays {
+		if done == 0 {
+			progress.MissedDays++
+		}
+	}
+	if progress.PlannedQuestions > 0 {
+		progress.CompletionRate = math.Round(float64(progress.CompletedQuestions)/float64(progress.PlannedQuestions)*1000) / 1000
+		progress.IsBehind = progress.MissedDays >= behindMinMissedDays && progress.CompletionRate < behindCompletionRate
+	}
+
+	return completed, progress, nil
+}
+
+// replanReason returns why the active plan should be regenerated, or "" to keep it
+func replanReason(plan *studyplan.StudyPlan, u *user.User, progress studyplan.StudyPlanProgress, today time.Time) string {
+	switch {
+	case today.After(plan.EndDate):
+		return studyplan.ReasonExpired
+	case !sameDate(plan.ExamDate, u.ExamDate) || plan.MinutesPerDay != planner.MinutesPerDay(studyHoursPerWeek(u)):
+		return studyplan.ReasonProfileChanged
+	case progress.IsBehind:
+		return studyplan.ReasonBehind
+	}
+	return ""
+}
+
+func buildStudyPlanResponse(
+	plan *studyplan.StudyPlan,
+	items []studyplan.Item,
+	completed map[string]int,
+	progress studyplan.StudyPlanProgress,
+	today time.Time,
+	replanned bool,
+) *studyplan.StudyPlanResponse {
+	response := &studyplan.StudyPlanResponse{
+		ID:            plan.ID.String(),
+		Reason:        plan.Reason,
+		StartDate:     plan.StartDate.Format("2006-01-02"),
+		EndDate:       plan.EndDate.Format("2006-01-02"),
+		MinutesPerDay: plan.MinutesPerDay,
+		Replanned:     replanned,
+		Progress:      progress,
+		Days:          []studyplan.StudyPlanDayResponse{},
+		CreatedAt:     plan.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if plan.ExamDate != nil {
+		examDate := plan.ExamDate.Format("2006-01-02")
+		response.ExamDate = &examDate
+	}
+
+	for _, item := range items {
+		date := item.ScheduledDate.Format("2006-01-02")
+		if n := len(response.Days); n == 0 || response.Days[n-1].Date != date {
+			response.Days = append(response.Days, studyplan.StudyPlanDayResponse{
+				Date:    date,
+				IsToday: item.ScheduledDate.Equal(today),
+				Items:   []studyplan.StudyPlanItemResponse{},
+			})
+		}
+
+		done := completed[item.ID.String()]
+		day := &response.Days[len(response.Days)-1]
+		day.Minutes += item.Minutes
+		day.Items = append(day.Items, studyplan.StudyPlanItemResponse{
+			ID:                 item.ID.String(),
+			Position:           item.Position,
+			Kind:               item.Kind,
+			Section:            item.Section,
+			SubType:            item.SubType,
+			TargetQuestions:    item.TargetQuestions,
+			CompletedQuestions: done,
+			Minutes:            item.Minutes,
+			Completed:          done >= item.TargetQuestions,
+		})
+	}
+
+	return response
+}
+
+func studyHoursPerWeek(u *user.User) int {
+	if u.StudyHoursPerWeek == nil {
+		return 0
+	}
+	return int(*u.StudyHoursPerWeek)
+}
+
+func dailySectionKey(date time.Time, section string) string {
+	return date.Format("2006-01-02") + "|" + section
+}
+
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return planner.Date(*a).Equal(planner.Date(*b))
+}
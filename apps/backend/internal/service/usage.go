@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/manikandareas/genta/internal/errs"
+	"github.com/manikandareas/genta/internal/middleware"
+	"github.com/manikandareas/genta/internal/model/usage"
+	"github.com/manikandareas/genta/internal/model/user"
+	"github.com/manikandareas/genta/internal/repository"
+	"github.com/manikandareas/genta/internal/server"
+)
+
+const usageDateLayout = "2006-01-02"
+
+type UsageService struct {
+	server    *server.Server
+	usageRepo *repository.UsageRepository
+}
+
+func NewUsageService(server *server.Server, usageRepo *repository.UsageRepository) *UsageService {
+	return &UsageService{
+		server:    server,
+		usageRepo: usageRepo,
+	}
+}
+
+// CheckQuota reports whether a new LLM generation for the user would exceed the
+// user's tier quota or the global spend budget. Returns nil when generation is allowed.
+func (s *UsageService) CheckQuota(ctx context.Context, u *user.User) (*usage.ExceededReason, error) {
+	today, monthStart := usagePeriodStarts(time.Now())
+	quota := usage.QuotaForTier(u.SubscriptionTier)
+
+	userMonth, err := s.usageRepo.GetUserTotals(ctx, u.ID, monthStart)
+	if err != nil {
+		return nil, err
+	}
+	if quota.MonthlyTokens > 0 && userMonth.TotalTokens() >= quota.MonthlyTokens {
+		return exceeded(usage.ExceededUserMonthly), nil
+	}
+
+	userToday, err := s.usageRepo.GetUserTotals(ctx, u.ID, today)
+	if err != nil {
+		return nil, err
+	}
+	if quota.DailyTokens > 0 && userToday.TotalTokens() >= quota.DailyTokens {
+		return exceeded(usage.ExceededUserDaily), nil
+	}
+
+	return s.CheckGlobalBudget(ctx)
+}
+
+// CheckGlobalBudget reports whether the platform's daily or monthly LLM spend budget is
+// used up. Returns nil when generation is allowed.
+func (s *UsageService) CheckGlobalBudget(ctx context.Context) (*usage.ExceededReason, error) {
+	today, monthStart := usagePeriodStarts(time.Now())
+
+	budget := s.server.Config.LLM
+	if budget.MonthlyBudgetUSD > 0 {
+		globalMonth, err := s.usageRepo.GetGlobalTotals(ctx, monthStart)
+		if err != nil {
+			return nil, err
+		}
+		if globalMonth.CostUSD >= budget.MonthlyBudgetUSD {
+			return exceeded(usage.ExceededGlobalMonthly), nil
+		}
+	}
+
+	if budget.DailyBudgetUSD > 0 {
+		globalToday, err := s.usageRepo.GetGlobalTotals(ctx, today)
+		if err != nil {
+			return nil, err
+		}
+		if globalToday.CostUSD >= budget.DailyBudgetUSD {
+			return exceeded(usage.ExceededGlobalDaily), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// RemainingGlobalBudget returns how much of the daily and monthly LLM budgets is left,
+// whichever is less. limited is false when no budget is configured.
+func (s *UsageService) RemainingGlobalBudget(ctx context.Context) (remaining float64, limited bool, err error) {
+	today, monthStart := usagePeriodStarts(time.Now())
+	budget := s.server.Config.LLM
+
+	for _, period := range []struct {
+		budget float64
+		since  time.Time
+	}{
+		{budget.DailyBudgetUSD, today},
+		{budget.MonthlyBudgetUSD, monthStart},
+	} {
+		if period.budget <= 0 {
+			continue
+		}
+		spent, err := s.usageRepo.GetGlobalTotals(ctx, period.since)
+		if err != nil {
+			return 0, false, err
+		}
+		left := max(period.budget-spent.CostUSD, 0)
+		if !limited || left < remaining {
+			remaining = left
+		}
+		limited = true
+	}
+
+	return remaining, limited, nil
+}
+
+// GetReport returns LLM spend grouped by day, model or user for admins
+func (s *UsageService) GetReport(ctx echo.Context, req *usage.GetUsageReportRequest) (*usage.UsageReportResponse, error) {
+	logger := middleware.GetLogger(ctx)
+
+	now := time.Now()
+	today, monthStart := usagePeriodStarts(now)
+
+	from := monthStart
+	to := today
+	var err error
+	if req.From != "" {
+		if from, err = time.Parse(usageDateLayout, req.From); err != nil {
+			return nil, errs.NewBadRequestError("invalid from date", false, nil, nil, nil)
+		}
+	}
+	if req.To != "" {
+		if to, err = time.Parse(usageDateLayout, req.To); err != nil {
+			return nil, errs.NewBadRequestError("invalid to date", false, nil, nil, nil)
+		}
+	}
+	if to.Before(from) {
+		return nil, errs.NewBadRequestError("to must not be before from", false, nil, nil, nil)
+	}
+
+	rows, err := s.usageRepo.GetReport(ctx.Request().Context(), from, to, req.GroupBy, req.Limit)
+	if err != nil {
+		logger.Error().Err(err).Str("group_by", req.GroupBy).Msg("failed to get llm usage report")
+		return nil, err
+	}
+
+	globalToday, err := s.usageRepo.GetGlobalTotals(ctx.Request().Context(), today)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get today's llm spend")
+		return nil, err
+	}
+
+	globalMonth, err := s.usageRepo.GetGlobalTotals(ctx.Request().Context(), monthStart)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get this month's llm spend")
+		return nil, err
+	}
+
+	// Totals cover the whole range, not just the rows within the limit
+	totals, err := s.usageRepo.GetTotalsBetween(ctx.Request().Context(), from, to)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get llm usage totals")
+		return nil, err
+	}
+
+	rowResponses := make([]usage.UsageReportRowResponse, 0, len(rows))
+	for i := range rows {
+		rowResponses = append(rowResponses, rows[i].ToResponse())
+	}
+
+	logger.Info().
+		Str("event", "llm_usage_report_viewed").
+		Str("group_by", req.GroupBy).
+		Float64("cost_usd", totals.CostUSD).
+		Msg("LLM usage report retrieved")
+
+	return &usage.UsageReportResponse{
+		From:    from.Format(usageDateLayout),
+		To:      to.Format(usageDateLayout),
+		GroupBy: req.GroupBy,
+		Totals:  *totals,
+		Rows:    rowResponses,
+		Budget: usage.BudgetStatusResponse{
+			DailyBudgetUSD:    s.server.Config.LLM.DailyBudgetUSD,
+			SpentTodayUSD:     globalToday.CostUSD,
+			MonthlyBudgetUSD:  s.server.Config.LLM.MonthlyBudgetUSD,
+			SpentThisMonthUSD: globalMonth.CostUSD,
+		},
+	}, nil
+}
+
+// usagePeriodStarts returns the start of the current day and month in UTC, the time zone
+// the ledger buckets usage_date in
+func usagePeriodStarts(now time.Time) (today, monthStart time.Time) {
+	now = now.UTC()
+	today = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return today, monthStart
+}
+
+func exceeded(reason usage.ExceededReason) *usage.ExceededReason {
+	return &reason
+}
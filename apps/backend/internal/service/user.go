@@ -3,6 +3,7 @@ package service
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/manikandareas/genta/internal/errs"
@@ -15,21 +16,49 @@ import (
 )
 
 type UserService struct {
-	server        *server.Server
-	userRepo      *repository.UserRepository
-	readinessRepo *repository.ReadinessRepository
-	clerkClient   *clerk.Clerk
+	server           *server.Server
+	userRepo         *repository.UserRepository
+	readinessRepo    *repository.ReadinessRepository
+	clerkClient      *clerk.Clerk
+	admissionService *AdmissionService
 }
 
-func NewUserService(server *server.Server, userRepo *repository.UserRepository, readinessRepo *repository.ReadinessRepository, clerkClient *clerk.Clerk) *UserService {
+func NewUserService(server *server.Server, userRepo *repository.UserRepository, readinessRepo *repository.ReadinessRepository, clerkClient *clerk.Clerk, admissionService *AdmissionService) *UserService {
 	return &UserService{
-		server:        server,
-		userRepo:      userRepo,
-		readinessRepo: readinessRepo,
-		clerkClient:   clerkClient,
+		server:           server,
+		userRepo:         userRepo,
+		readinessRepo:    readinessRepo,
+		clerkClient:      clerkClient,
+		admissionService: admissionService,
 	}
 }
 
+// applyTarget validates the requested target PTN/program against the catalogue and
+// stores the canonical PTN name and IDs on the update request
+func (s *UserService) applyTarget(ctx echo.Context, existing *user.User, request *user.PutUserRequest) error {
+	if request.TargetPtn == nil && request.TargetProgramID == nil {
+		return nil
+	}
+
+	target, err := s.admissionService.ResolveTarget(ctx.Request().Context(), request.TargetPtn, request.TargetProgramID)
+	if err != nil {
+		return err
+	}
+
+	request.TargetPtn = target.PtnName
+	request.TargetPtnID = target.PtnID
+	// Free text outside the catalogue must not stay linked to the previous PTN
+	if target.PtnID == nil && request.TargetPtn != nil && existing != nil && existing.TargetPtnID != nil {
+		request.ClearTargetPtnID = true
+	}
+	// A new PTN without a program invalidates a program chosen at the previous PTN
+	if target.ProgramID == nil && existing != nil && existing.TargetProgramID != nil &&
+		(target.PtnID == nil || existing.TargetPtnID == nil || *existing.TargetPtnID != *target.PtnID) {
+		request.ClearTargetProgram = true
+	}
+	return nil
+}
+
 func (s *UserService) UpdateUser(ctx echo.Context, clerkID string, request *user.PutUserRequest) (*user.User, error) {
 	logger := middleware.GetLogger(ctx)
 
@@ -41,6 +70,11 @@ func (s *UserService) UpdateUser(ctx echo.Context, clerkID string, request *user
 		return nil, err
 	}
 
+	if err := s.applyTarget(ctx, existingUser, request); err != nil {
+		logger.Warn().Err(err).Msg("invalid target ptn")
+		return nil, err
+	}
+
 	// use database UUID for update
 	updatedUser, err := s.userRepo.PutUser(ctx.Request().Context(), existingUser.ID.String(), request)
 	if err != nil {
@@ -59,6 +93,31 @@ func (s *UserService) UpdateUser(ctx echo.Context, clerkID string, request *user
 	return updatedUser, nil
 }
 
+// UpdateSubscription sets a user's subscription tier (admin)
+func (s *UserService) UpdateSubscription(ctx echo.Context, req *user.UpdateSubscriptionRequest) (*user.User, error) {
+	logger := middleware.GetLogger(ctx)
+
+	if req.Tier != "free" && req.EndDate != nil && !req.EndDate.After(time.Now()) {
+		return nil, errs.NewBadRequestError("end date must be in the future", false, nil,
+			[]errs.FieldError{{Field: "endDate", Error: "must be in the future"}}, nil)
+	}
+
+	updatedUser, err := s.userRepo.UpdateSubscription(ctx.Request().Context(), req)
+	if err != nil {
+		logger.Error().Err(err).Str("user_id", req.UserID).Msg("failed to update subscription")
+		return nil, err
+	}
+
+	logger.Info().
+		Str("event", "subscription_updated").
+		Str("user_id", req.UserID).
+		Str("tier", updatedUser.SubscriptionTier).
+		Bool("active", updatedUser.IsSubscriptionActive).
+		Msg("Subscription updated")
+
+	return updatedUser, nil
+}
+
 func (s *UserService) GetUser(ctx echo.Context, clerkID string) (*user.User, error) {
 	logger := middleware.GetLogger(ctx)
 
@@ -129,13 +188,20 @@ func (s *UserService) CompleteOnboarding(ctx echo.Context, clerkID string, reque
 
 	// Update user with onboarding data using PutUser
 	onboardingCompleted := true
-	updatedUser, err := s.userRepo.PutUser(ctx.Request().Context(), existingUser.ID.String(), &user.PutUserRequest{
+	putRequest := &user.PutUserRequest{
 		TargetPtn:           request.TargetPtn,
+		TargetProgramID:     request.TargetProgramID,
 		TargetScore:         request.TargetScore,
 		ExamDate:            request.ExamDate,
 		StudyHoursPerWeek:   request.StudyHoursPerWeek,
 		OnboardingCompleted: &onboardingCompleted,
-	})
+	}
+	if err := s.applyTarget(ctx, existingUser, putRequest); err != nil {
+		logger.Warn().Err(err).Msg("invalid target ptn")
+		return nil, err
+	}
+
+	updatedUser, err := s.userRepo.PutUser(ctx.Request().Context(), existingUser.ID.String(), putRequest)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to complete onboarding")
 		return nil, err
@@ -158,6 +224,8 @@ func (s *UserService) CompleteOnboarding(ctx echo.Context, clerkID string, reque
 		ID:                  updatedUser.ID,
 		OnboardingCompleted: updatedUser.OnboardingCompleted,
 		TargetPtn:           updatedUser.TargetPtn,
+		TargetPtnID:         updatedUser.TargetPtnID,
+		TargetProgramID:     updatedUser.TargetProgramID,
 		TargetScore:         updatedUser.TargetScore,
 		ExamDate:            updatedUser.ExamDate,
 		StudyHoursPerWeek:   updatedUser.StudyHoursPerWeek,
@@ -93,7 +93,8 @@ func SetupTestDB(t *testing.T) (*TestDB, func()) {
 			Address: "localhost:6379",
 		},
 		Auth: config.AuthConfig{
-			SecretKey: "test-secret",
+			SecretKey:        "test-secret",
+			CursorSigningKey: "test-cursor-signing-key-0123456789abcdef",
 		},
 	}
 